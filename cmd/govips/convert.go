@@ -0,0 +1,164 @@
+package main
+
+import (
+	"flag"
+
+	"github.com/davidbyttow/govips/v2/vips"
+)
+
+// exportFlags are the export parameters shared by convert, thumbnail and
+// transcode.
+type exportFlags struct {
+	format string
+	params *vips.ExportParams
+}
+
+// register binds the flags to p, whose values are the defaults.
+func (f *exportFlags) register(fs *flag.FlagSet, p *vips.ExportParams) {
+	f.params = p
+	fs.StringVar(&f.format, "format", "", "output format: jpeg, png, webp, avif, gif, jxl, heif, tiff (default: from the output extension, else the input format)")
+	fs.IntVar(&p.Quality, "quality", p.Quality, "quality of lossy formats, 1-100")
+	fs.IntVar(&p.Compression, "compression", p.Compression, "PNG compression level, 0-9")
+	fs.IntVar(&p.Effort, "effort", p.Effort, "encoder CPU effort (WebP 0-6, HEIF/AVIF/JXL, GIF)")
+	fs.IntVar(&p.Speed, "speed", p.Speed, "AVIF encoder speed")
+	fs.BoolVar(&p.Interlaced, "interlace", p.Interlaced, "write interlaced (progressive) JPEG/PNG")
+	fs.BoolVar(&p.Lossless, "lossless", p.Lossless, "use lossless compression (WebP, AVIF, HEIF, JXL)")
+	fs.BoolVar(&p.StripMetadata, "strip", p.StripMetadata, "strip metadata")
+}
+
+// exportParams resolves the output format for output and img and returns
+// the export params.
+func (f *exportFlags) exportParams(output string, img *vips.ImageRef) (*vips.ExportParams, error) {
+	params := *f.params
+	switch {
+	case f.format != "":
+		format, err := parseFormat(f.format)
+		if err != nil {
+			return nil, err
+		}
+		params.Format = format
+	case vips.ImageTypeFromExt(output) != vips.ImageTypeUnknown:
+		params.Format = vips.ImageTypeFromExt(output)
+	default:
+		params.Format = img.Format()
+	}
+	return &params, nil
+}
+
+// conversionResult is the -json report of convert and thumbnail.
+type conversionResult struct {
+	Input  string `json:"input"`
+	Output string `json:"output"`
+	Format string `json:"format"`
+	Width  int    `json:"width"`
+	Height int    `json:"height"`
+	Bytes  int    `json:"bytes"`
+}
+
+// export encodes img to output and reports the result.
+func (e *env) export(img *vips.ImageRef, input, output string, f *exportFlags) error {
+	params, err := f.exportParams(output, img)
+	if err != nil {
+		return err
+	}
+	buf, _, err := img.Export(params)
+	if err != nil {
+		return err
+	}
+	if err := e.writeOutput(output, buf); err != nil {
+		return err
+	}
+	return e.report(output, conversionResult{
+		Input:  input,
+		Output: output,
+		Format: params.Format.Name(),
+		Width:  img.Width(),
+		Height: img.PageHeight(),
+		Bytes:  len(buf),
+	}, "")
+}
+
+func runConvert(e *env, args []string) error {
+	fs := e.newFlagSet("convert", "INPUT OUTPUT", "Convert an image to another format.")
+	var ef exportFlags
+	ef.register(fs, vips.NewDefaultExportParams())
+	autoRotate := fs.Bool("autorotate", false, "apply the EXIF orientation")
+	allPages := fs.Bool("all-pages", false, "load every page of multi-page and animated images")
+	positional, err := parseArgs(fs, args, 2, 2)
+	if err != nil {
+		return err
+	}
+	input, output := positional[0], positional[1]
+
+	buf, err := e.readInput(input)
+	if err != nil {
+		return err
+	}
+	params := vips.NewImportParams()
+	if *allPages {
+		params.NumPages.Set(-1)
+	}
+	img, err := vips.LoadImageFromBuffer(buf, params)
+	if err != nil {
+		return inputError(err)
+	}
+	defer img.Close()
+
+	if *autoRotate {
+		if err := img.AutoRotate(); err != nil {
+			return err
+		}
+	}
+	return e.export(img, input, output, &ef)
+}
+
+func runThumbnail(e *env, args []string) error {
+	fs := e.newFlagSet("thumbnail", "INPUT OUTPUT", "Make a thumbnail, using shrink-on-load where the format supports it.\nThe EXIF orientation is applied.")
+	var ef exportFlags
+	ef.register(fs, vips.NewDefaultExportParams())
+	width := fs.Int("width", 0, "target width (0: unconstrained)")
+	height := fs.Int("height", 0, "target height (0: unconstrained)")
+	crop := fs.String("crop", "none", "crop to fill the target: none, centre, entropy, attention, low, high, all")
+	size := fs.String("size", "down", "which way to resize: both, up, down, force")
+	positional, err := parseArgs(fs, args, 2, 2)
+	if err != nil {
+		return err
+	}
+	input, output := positional[0], positional[1]
+
+	if *width < 0 || *height < 0 || (*width == 0 && *height == 0) {
+		return usageErrorf("-width or -height must be positive")
+	}
+	w, h := *width, *height
+	if w == 0 {
+		w = vips.MaxCoord
+	}
+	if h == 0 {
+		h = vips.MaxCoord
+	}
+	interesting, err := vips.ParseInteresting(*crop)
+	if err != nil {
+		return usageErrorf("unknown crop %q", *crop)
+	}
+	sz, err := vips.ParseSize(*size)
+	if err != nil {
+		return usageErrorf("unknown size %q", *size)
+	}
+
+	var img *vips.ImageRef
+	if input == "-" {
+		var buf []byte
+		if buf, err = e.readInput(input); err != nil {
+			return err
+		}
+		img, err = vips.LoadThumbnailFromBuffer(buf, w, h, interesting, sz, nil)
+	} else {
+		img, err = vips.LoadThumbnailFromFile(input, w, h, interesting, sz, nil)
+	}
+	if err != nil {
+		return inputError(err)
+	}
+	defer img.Close()
+
+	return e.export(img, input, output, &ef)
+}
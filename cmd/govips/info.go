@@ -0,0 +1,83 @@
+package main
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/davidbyttow/govips/v2/vips"
+)
+
+// imageInfo is the header dump printed by info.
+type imageInfo struct {
+	File           string   `json:"file"`
+	Format         string   `json:"format"`
+	Width          int      `json:"width"`
+	Height         int      `json:"height"`
+	Bands          int      `json:"bands"`
+	BandFormat     string   `json:"band_format"`
+	Interpretation string   `json:"interpretation"`
+	Pages          int      `json:"pages"`
+	PageHeight     int      `json:"page_height"`
+	Orientation    int      `json:"orientation"`
+	HasAlpha       bool     `json:"has_alpha"`
+	HasICCProfile  bool     `json:"has_icc_profile"`
+	HasExif        bool     `json:"has_exif"`
+	HasIPTC        bool     `json:"has_iptc"`
+	XRes           float64  `json:"xres"`
+	YRes           float64  `json:"yres"`
+	Fields         []string `json:"fields"`
+}
+
+func runInfo(e *env, args []string) error {
+	fs := e.newFlagSet("info", "INPUT", "Print the header of an image. Pixels are not decoded.")
+	positional, err := parseArgs(fs, args, 1, 1)
+	if err != nil {
+		return err
+	}
+
+	buf, err := e.readInput(positional[0])
+	if err != nil {
+		return err
+	}
+	img, err := vips.NewImageFromBuffer(buf)
+	if err != nil {
+		return inputError(err)
+	}
+	defer img.Close()
+
+	info := imageInfo{
+		File:           positional[0],
+		Format:         img.Format().Name(),
+		Width:          img.Width(),
+		Height:         img.Height(),
+		Bands:          img.Bands(),
+		BandFormat:     img.BandFormat().String(),
+		Interpretation: img.Interpretation().String(),
+		Pages:          img.Pages(),
+		PageHeight:     img.PageHeight(),
+		Orientation:    img.Orientation(),
+		HasAlpha:       img.HasAlpha(),
+		HasICCProfile:  img.HasICCProfile(),
+		HasExif:        img.HasExif(),
+		HasIPTC:        img.HasIPTC(),
+		XRes:           img.ResX(),
+		YRes:           img.ResY(),
+		Fields:         img.ImageFields(),
+	}
+
+	var b strings.Builder
+	fmt.Fprintf(&b, "file:           %s\n", info.File)
+	fmt.Fprintf(&b, "format:         %s\n", info.Format)
+	fmt.Fprintf(&b, "size:           %dx%d\n", info.Width, info.Height)
+	fmt.Fprintf(&b, "bands:          %d (%s)\n", info.Bands, info.BandFormat)
+	fmt.Fprintf(&b, "interpretation: %s\n", info.Interpretation)
+	fmt.Fprintf(&b, "pages:          %d (page height %d)\n", info.Pages, info.PageHeight)
+	fmt.Fprintf(&b, "orientation:    %d\n", info.Orientation)
+	fmt.Fprintf(&b, "alpha:          %t\n", info.HasAlpha)
+	fmt.Fprintf(&b, "icc profile:    %t\n", info.HasICCProfile)
+	fmt.Fprintf(&b, "exif:           %t\n", info.HasExif)
+	fmt.Fprintf(&b, "iptc:           %t\n", info.HasIPTC)
+	fmt.Fprintf(&b, "resolution:     %g x %g px/mm\n", info.XRes, info.YRes)
+	fmt.Fprintf(&b, "fields:         %s\n", strings.Join(info.Fields, ", "))
+	return e.report("", info, b.String())
+}
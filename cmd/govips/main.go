@@ -0,0 +1,261 @@
+// Command govips runs govips image operations from the command line, so
+// that a production transformation can be reproduced without writing Go.
+//
+// Usage:
+//
+//	govips info [-json] INPUT
+//	govips convert [flags] INPUT OUTPUT
+//	govips thumbnail [flags] INPUT OUTPUT
+//	govips transcode [flags] < INPUT > OUTPUT
+//	govips ops [flags] INPUT OUTPUT CHAIN
+//
+// INPUT and OUTPUT may be "-" for stdin and stdout. Run a subcommand with
+// -h for its flags.
+//
+// Exit codes: 0 on success, 1 when processing fails, 2 for usage errors,
+// 3 when the input cannot be read or decoded and 4 when the output cannot
+// be written. With -json, results are printed as JSON on stdout (stderr
+// when the image itself goes to stdout) and errors as a JSON object on
+// stderr.
+package main
+
+import (
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+
+	"github.com/davidbyttow/govips/v2/vips"
+)
+
+// Exit codes.
+const (
+	exitOK      = 0
+	exitFailure = 1
+	exitUsage   = 2
+	exitInput   = 3
+	exitOutput  = 4
+)
+
+// exitError carries the exit code for err.
+type exitError struct {
+	code int
+	err  error
+}
+
+func (e *exitError) Error() string { return e.err.Error() }
+func (e *exitError) Unwrap() error { return e.err }
+
+func usageErrorf(format string, args ...interface{}) error {
+	return &exitError{code: exitUsage, err: fmt.Errorf(format, args...)}
+}
+
+func inputError(err error) error {
+	return &exitError{code: exitInput, err: err}
+}
+
+func outputError(err error) error {
+	return &exitError{code: exitOutput, err: err}
+}
+
+type command struct {
+	name    string
+	summary string
+	run     func(e *env, args []string) error
+}
+
+var commands = []command{
+	{"info", "print the image header", runInfo},
+	{"convert", "convert an image to another format", runConvert},
+	{"thumbnail", "make a thumbnail with shrink-on-load", runThumbnail},
+	{"transcode", "stream stdin to stdout in another format", runTranscode},
+	{"ops", `apply an operation chain such as "resize 0.5 | sharpen | webp q=80"`, runOps},
+}
+
+// env is what a command runs against: the standard streams, and whether
+// results are printed as JSON, which the -json flag of every subcommand
+// sets.
+type env struct {
+	stdin  io.Reader
+	stdout io.Writer
+	stderr io.Writer
+	json   bool
+}
+
+func main() {
+	code := run(&env{stdin: os.Stdin, stdout: os.Stdout, stderr: os.Stderr}, os.Args[1:])
+	vips.Shutdown()
+	os.Exit(code)
+}
+
+// run runs the command in args and returns the exit code. It starts
+// libvips but leaves shutting it down to the caller.
+func run(e *env, args []string) int {
+	if len(args) == 0 || args[0] == "-h" || args[0] == "-help" || args[0] == "--help" {
+		usage(e.stderr)
+		if len(args) == 0 {
+			return exitUsage
+		}
+		return exitOK
+	}
+
+	var cmd *command
+	for i := range commands {
+		if commands[i].name == args[0] {
+			cmd = &commands[i]
+		}
+	}
+	if cmd == nil {
+		fmt.Fprintf(e.stderr, "govips: unknown command %q\n\n", args[0])
+		usage(e.stderr)
+		return exitUsage
+	}
+
+	// Library warnings go to stderr; stdout may carry image data.
+	vips.LoggingSettings(nil, vips.LogLevelWarning)
+	if err := vips.Startup(nil); err != nil {
+		return e.fail(cmd.name, err)
+	}
+
+	if err := cmd.run(e, args[1:]); err != nil {
+		if errors.Is(err, flag.ErrHelp) {
+			return exitOK
+		}
+		return e.fail(cmd.name, err)
+	}
+	return exitOK
+}
+
+func usage(w io.Writer) {
+	fmt.Fprintln(w, "usage: govips <command> [flags] [arguments]")
+	fmt.Fprintln(w)
+	fmt.Fprintln(w, "commands:")
+	for _, c := range commands {
+		fmt.Fprintf(w, "  %-10s %s\n", c.name, c.summary)
+	}
+	fmt.Fprintln(w)
+	fmt.Fprintln(w, `INPUT and OUTPUT may be "-" for stdin and stdout.`)
+	fmt.Fprintln(w, "Run govips <command> -h for the flags of a command.")
+}
+
+// fail reports err on stderr and returns its exit code.
+func (e *env) fail(name string, err error) int {
+	code := exitFailure
+	var ee *exitError
+	if errors.As(err, &ee) {
+		code = ee.code
+	}
+
+	if e.json {
+		enc := json.NewEncoder(e.stderr)
+		enc.Encode(struct {
+			Command  string `json:"command"`
+			Error    string `json:"error"`
+			ExitCode int    `json:"exit_code"`
+		}{name, err.Error(), code})
+	} else {
+		fmt.Fprintf(e.stderr, "govips %s: %v\n", name, err)
+	}
+	return code
+}
+
+// newFlagSet returns a flag set for a subcommand with the shared -json
+// flag registered.
+func (e *env) newFlagSet(name, args, description string) *flag.FlagSet {
+	fs := flag.NewFlagSet(name, flag.ContinueOnError)
+	fs.SetOutput(e.stderr)
+	fs.BoolVar(&e.json, "json", false, "print results and errors as JSON")
+	fs.Usage = func() {
+		fmt.Fprintf(fs.Output(), "usage: govips %s [flags] %s\n\n%s\n\nflags:\n", name, args, description)
+		fs.PrintDefaults()
+	}
+	return fs
+}
+
+// parseArgs parses flags that may appear before, between or after the
+// positional arguments, and checks the number of positional arguments.
+func parseArgs(fs *flag.FlagSet, args []string, min, max int) ([]string, error) {
+	var positional []string
+	for {
+		if err := fs.Parse(args); err != nil {
+			if errors.Is(err, flag.ErrHelp) {
+				return nil, err
+			}
+			return nil, &exitError{code: exitUsage, err: err}
+		}
+		args = fs.Args()
+		if len(args) == 0 {
+			break
+		}
+		positional = append(positional, args[0])
+		args = args[1:]
+	}
+
+	if len(positional) < min || (max >= 0 && len(positional) > max) {
+		fs.Usage()
+		return nil, usageErrorf("wrong number of arguments")
+	}
+	return positional, nil
+}
+
+// readInput reads the file at path, or stdin for "-".
+func (e *env) readInput(path string) ([]byte, error) {
+	var buf []byte
+	var err error
+	if path == "-" {
+		buf, err = io.ReadAll(e.stdin)
+	} else {
+		buf, err = os.ReadFile(path)
+	}
+	if err != nil {
+		return nil, inputError(err)
+	}
+	if len(buf) == 0 {
+		return nil, inputError(errors.New("input is empty"))
+	}
+	return buf, nil
+}
+
+// writeOutput writes buf to the file at path, or stdout for "-".
+func (e *env) writeOutput(path string, buf []byte) error {
+	var err error
+	if path == "-" {
+		_, err = e.stdout.Write(buf)
+	} else {
+		err = os.WriteFile(path, buf, 0o644)
+	}
+	if err != nil {
+		return outputError(err)
+	}
+	return nil
+}
+
+// report prints a command result. Without -json nothing is printed, unless
+// text is set. Results go to stdout, or stderr when output is "-".
+func (e *env) report(output string, result interface{}, text string) error {
+	w := e.stdout
+	if output == "-" {
+		w = e.stderr
+	}
+	if e.json {
+		enc := json.NewEncoder(w)
+		enc.SetIndent("", "  ")
+		return enc.Encode(result)
+	}
+	if text != "" {
+		_, err := io.WriteString(w, text)
+		return err
+	}
+	return nil
+}
+
+// parseFormat returns the image type for a format name.
+func parseFormat(name string) (vips.ImageType, error) {
+	format := vips.ParseImageType(name)
+	if format == vips.ImageTypeUnknown {
+		return format, usageErrorf("unknown format %q", name)
+	}
+	return format, nil
+}
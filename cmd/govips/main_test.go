@@ -0,0 +1,153 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const resources = "../../resources/"
+
+// runCommand runs govips with args and stdin and returns the exit code
+// and what was written to stdout and stderr.
+func runCommand(t *testing.T, stdin []byte, args ...string) (int, string, string) {
+	t.Helper()
+	var stdout, stderr bytes.Buffer
+	e := &env{stdin: bytes.NewReader(stdin), stdout: &stdout, stderr: &stderr}
+	code := run(e, args)
+	return code, stdout.String(), stderr.String()
+}
+
+func TestInfo(t *testing.T) {
+	code, stdout, stderr := runCommand(t, nil, "info", resources+"jpg-24bit.jpg")
+	require.Equal(t, exitOK, code, stderr)
+	assert.Contains(t, stdout, "format:         jpeg\n")
+	assert.Contains(t, stdout, "size:           100x100\n")
+	assert.Contains(t, stdout, "bands:          3 (uchar)\n")
+
+	code, stdout, stderr = runCommand(t, nil, "info", "-json", resources+"png-24bit+alpha.png")
+	require.Equal(t, exitOK, code, stderr)
+	var info imageInfo
+	require.NoError(t, json.Unmarshal([]byte(stdout), &info))
+	assert.Equal(t, resources+"png-24bit+alpha.png", info.File)
+	assert.Equal(t, "png", info.Format)
+	assert.Equal(t, 512, info.Width)
+	assert.Equal(t, 512, info.Height)
+	assert.Equal(t, 4, info.Bands)
+	assert.True(t, info.HasAlpha)
+}
+
+func TestInfo_Stdin(t *testing.T) {
+	buf, err := os.ReadFile(resources + "jpg-24bit.jpg")
+	require.NoError(t, err)
+
+	code, stdout, stderr := runCommand(t, buf, "info", "-json", "-")
+	require.Equal(t, exitOK, code, stderr)
+	var info imageInfo
+	require.NoError(t, json.Unmarshal([]byte(stdout), &info))
+	assert.Equal(t, "-", info.File)
+	assert.Equal(t, "jpeg", info.Format)
+}
+
+func TestConvert(t *testing.T) {
+	output := filepath.Join(t.TempDir(), "out.png")
+
+	code, stdout, stderr := runCommand(t, nil, "convert", resources+"jpg-24bit.jpg", output)
+	require.Equal(t, exitOK, code, stderr)
+	assert.Empty(t, stdout)
+
+	code, stdout, stderr = runCommand(t, nil, "convert", "-json", resources+"jpg-24bit.jpg", output)
+	require.Equal(t, exitOK, code, stderr)
+	var result conversionResult
+	require.NoError(t, json.Unmarshal([]byte(stdout), &result))
+	assert.Equal(t, conversionResult{
+		Input:  resources + "jpg-24bit.jpg",
+		Output: output,
+		Format: "png",
+		Width:  100,
+		Height: 100,
+		Bytes:  result.Bytes,
+	}, result)
+
+	buf, err := os.ReadFile(output)
+	require.NoError(t, err)
+	assert.Equal(t, len(buf), result.Bytes)
+	assert.True(t, bytes.HasPrefix(buf, []byte("\x89PNG")))
+}
+
+func TestConvert_Stdout(t *testing.T) {
+	// With the image on stdout, the -json report goes to stderr.
+	code, stdout, stderr := runCommand(t, nil, "convert", "-json", "-format", "webp", resources+"jpg-24bit.jpg", "-")
+	require.Equal(t, exitOK, code, stderr)
+	assert.True(t, strings.HasPrefix(stdout, "RIFF"))
+
+	var result conversionResult
+	require.NoError(t, json.Unmarshal([]byte(stderr), &result))
+	assert.Equal(t, "webp", result.Format)
+	assert.Equal(t, len(stdout), result.Bytes)
+}
+
+func TestThumbnail(t *testing.T) {
+	output := filepath.Join(t.TempDir(), "thumb.png")
+
+	code, stdout, stderr := runCommand(t, nil, "thumbnail", "-json", "-width", "50", resources+"jpg-24bit.jpg", output)
+	require.Equal(t, exitOK, code, stderr)
+	var result conversionResult
+	require.NoError(t, json.Unmarshal([]byte(stdout), &result))
+	assert.Equal(t, "png", result.Format)
+	assert.Equal(t, 50, result.Width)
+	assert.Equal(t, 50, result.Height)
+}
+
+func TestExitCodes(t *testing.T) {
+	dir := t.TempDir()
+	for _, tt := range []struct {
+		name string
+		args []string
+		code int
+	}{
+		{"no command", nil, exitUsage},
+		{"help", []string{"-h"}, exitOK},
+		{"command help", []string{"info", "-h"}, exitOK},
+		{"unknown command", []string{"resize"}, exitUsage},
+		{"unknown flag", []string{"info", "-bogus", resources + "jpg-24bit.jpg"}, exitUsage},
+		{"missing argument", []string{"convert", resources + "jpg-24bit.jpg"}, exitUsage},
+		{"unknown format", []string{"convert", "-format", "bmp", resources + "jpg-24bit.jpg", filepath.Join(dir, "out")}, exitUsage},
+		{"unknown crop", []string{"thumbnail", "-width", "10", "-crop", "middle", resources + "jpg-24bit.jpg", filepath.Join(dir, "out.jpg")}, exitUsage},
+		{"missing input", []string{"info", filepath.Join(dir, "missing.jpg")}, exitInput},
+		{"undecodable input", []string{"info", "main.go"}, exitInput},
+		{"unwritable output", []string{"convert", resources + "jpg-24bit.jpg", filepath.Join(dir, "missing", "out.png")}, exitOutput},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			code, _, stderr := runCommand(t, nil, tt.args...)
+			assert.Equal(t, tt.code, code, stderr)
+		})
+	}
+}
+
+func TestExitCodes_JSON(t *testing.T) {
+	code, stdout, stderr := runCommand(t, nil, "info", "-json", "missing.jpg")
+	assert.Equal(t, exitInput, code)
+	assert.Empty(t, stdout)
+
+	var failure struct {
+		Command  string `json:"command"`
+		Error    string `json:"error"`
+		ExitCode int    `json:"exit_code"`
+	}
+	require.NoError(t, json.Unmarshal([]byte(stderr), &failure))
+	assert.Equal(t, "info", failure.Command)
+	assert.Equal(t, exitInput, failure.ExitCode)
+	assert.Contains(t, failure.Error, "missing.jpg")
+
+	// Without -json the error is a line of text.
+	code, _, stderr = runCommand(t, nil, "info", "missing.jpg")
+	assert.Equal(t, exitInput, code)
+	assert.True(t, strings.HasPrefix(stderr, "govips info: "), stderr)
+}
@@ -0,0 +1,203 @@
+package main
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math"
+	"os"
+	"strconv"
+	"strings"
+
+	"github.com/davidbyttow/govips/v2/vips"
+)
+
+// positionalFields lists, per pipeline op, the fields that positional
+// arguments in an ops chain fill in order.
+var positionalFields = map[string][]string{
+	"resize":     {"scale", "vscale"},
+	"thumbnail":  {"width", "height"},
+	"crop":       {"left", "top", "width", "height"},
+	"smartcrop":  {"width", "height", "interesting"},
+	"rotate":     {"angle"},
+	"autorotate": nil,
+	"flip":       {"direction"},
+	"sharpen":    {"sigma", "x1", "m2"},
+	"modulate":   {"brightness", "saturation", "hue"},
+	"composite":  {"overlay", "x", "y"},
+	"export":     {"format"},
+}
+
+// fieldAliases are short names accepted for pipeline fields.
+var fieldAliases = map[string]string{
+	"q":     "quality",
+	"strip": "strip_metadata",
+}
+
+// parseChain parses an ops chain into a pipeline. Steps are separated by
+// "|"; each step is an op name followed by positional arguments and
+// key=value pairs, for example
+//
+//	resize 0.5 kernel=linear | sharpen | webp q=80
+//
+// A format name as the op is short for an export step in that format. The
+// chain is checked with the same schema as vips.ParsePipeline.
+func parseChain(chain string) (*vips.Pipeline, error) {
+	var steps []map[string]interface{}
+	for i, segment := range strings.Split(chain, "|") {
+		fields := strings.Fields(segment)
+		if len(fields) == 0 {
+			return nil, fmt.Errorf("step %d is empty", i)
+		}
+
+		op := strings.ToLower(fields[0])
+		step := map[string]interface{}{}
+		if vips.ParseImageType(op) != vips.ImageTypeUnknown {
+			step["format"] = op
+			op = "export"
+		}
+		step["op"] = op
+
+		names, known := positionalFields[op]
+		if !known {
+			return nil, fmt.Errorf("step %d: unknown op %q", i, op)
+		}
+		for _, arg := range fields[1:] {
+			key, value, ok := strings.Cut(arg, "=")
+			if !ok {
+				if len(names) == 0 {
+					return nil, fmt.Errorf("step %d (%s): unexpected argument %q", i, op, arg)
+				}
+				key, value, names = names[0], arg, names[1:]
+			}
+			if alias, ok := fieldAliases[key]; ok {
+				key = alias
+			}
+			if _, dup := step[key]; dup {
+				return nil, fmt.Errorf("step %d (%s): %s given twice", i, op, key)
+			}
+			step[key] = chainValue(value)
+		}
+		steps = append(steps, step)
+	}
+
+	doc, err := json.Marshal(map[string]interface{}{"steps": steps})
+	if err != nil {
+		return nil, err
+	}
+	return vips.ParsePipeline(doc)
+}
+
+// chainValue types a chain argument for the JSON schema: numbers and
+// booleans as such, anything else as a string.
+func chainValue(s string) interface{} {
+	if v, err := strconv.ParseFloat(s, 64); err == nil && !math.IsInf(v, 0) && !math.IsNaN(v) {
+		return v
+	}
+	if s == "true" || s == "false" {
+		return s == "true"
+	}
+	return s
+}
+
+// opsResult is the -json report of ops.
+type opsResult struct {
+	Input  string   `json:"input"`
+	Output string   `json:"output"`
+	Steps  []string `json:"steps"`
+	Bytes  int64    `json:"bytes"`
+}
+
+func runOps(e *env, args []string) error {
+	fs := e.newFlagSet("ops", "INPUT OUTPUT CHAIN",
+		`Apply an operation chain, for example "resize 0.5 | sharpen | webp q=80".
+
+Steps are separated by "|" and take positional arguments or key=value
+pairs (q is short for quality, strip for strip_metadata):
+
+  resize SCALE [VSCALE] [kernel=...]
+  thumbnail WIDTH [HEIGHT] [crop=...] [size=...]
+  crop LEFT TOP WIDTH HEIGHT
+  smartcrop WIDTH HEIGHT [INTERESTING]
+  rotate DEGREES
+  autorotate
+  flip horizontal|vertical
+  sharpen [SIGMA [X1 [M2]]]
+  modulate [BRIGHTNESS [SATURATION [HUE]]]
+  composite OVERLAY-FILE [X [Y]] [mode=...]
+  FORMAT [q=...] [strip=true]    (or: export FORMAT ...)
+
+Quote the chain so that "|" and negative numbers reach govips intact.
+Without an export step the format comes from the output extension, else
+the input format. The image is streamed from INPUT to OUTPUT and only held
+in memory from the first step that needs random access.`)
+	positional, err := parseArgs(fs, args, 3, -1)
+	if err != nil {
+		return err
+	}
+	input, output := positional[0], positional[1]
+
+	p, err := parseChain(strings.Join(positional[2:], " "))
+	if err != nil {
+		return &exitError{code: exitUsage, err: err}
+	}
+	// Overlays on the command line are files the user named themselves.
+	p.LoadOverlay = vips.NewImageFromFile
+	if len(p.Steps) == 0 || !isExport(p.Steps[len(p.Steps)-1]) {
+		if format := vips.ImageTypeFromExt(output); format != vips.ImageTypeUnknown {
+			p.Steps = append(p.Steps, vips.ExportStep{Format: format})
+		}
+	}
+
+	in := e.stdin
+	if input != "-" {
+		f, err := os.Open(input)
+		if err != nil {
+			return inputError(err)
+		}
+		defer f.Close()
+		in = f
+	}
+
+	out := &errWriter{w: e.stdout}
+	var file *os.File
+	if output != "-" {
+		file, err = os.Create(output)
+		if err != nil {
+			return outputError(err)
+		}
+		out.w = file
+	}
+
+	r := &errReader{r: in}
+	err = p.RunStream(r, out)
+	if file != nil {
+		if cerr := file.Close(); err == nil && cerr != nil {
+			err = outputError(cerr)
+		}
+		if err != nil {
+			os.Remove(output)
+		}
+	}
+	switch {
+	case r.err != nil:
+		return inputError(err)
+	case out.err != nil:
+		return outputError(err)
+	case errors.Is(err, vips.ErrInvalidPipeline):
+		return &exitError{code: exitUsage, err: err}
+	case err != nil:
+		return err
+	}
+
+	names := make([]string, len(p.Steps))
+	for i, step := range p.Steps {
+		names[i] = step.Op()
+	}
+	return e.report(output, opsResult{Input: input, Output: output, Steps: names, Bytes: out.n}, "")
+}
+
+func isExport(step vips.Step) bool {
+	_, ok := step.(vips.ExportStep)
+	return ok
+}
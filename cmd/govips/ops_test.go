@@ -0,0 +1,52 @@
+package main
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/davidbyttow/govips/v2/vips"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseChain(t *testing.T) {
+	p, err := parseChain("resize 0.5 kernel=linear | rotate -90 | sharpen | modulate hue=30 | webp q=80 strip=true")
+	require.NoError(t, err)
+	assert.Equal(t, []vips.Step{
+		vips.ResizeStep{Scale: 0.5, Kernel: vips.KernelLinear},
+		vips.RotateStep{Angle: vips.Angle270},
+		vips.SharpenStep{Sigma: 0.5, X1: 2, M2: 3},
+		vips.ModulateStep{Brightness: 1, Saturation: 1, Hue: 30},
+		vips.ExportStep{Format: vips.ImageTypeWEBP, Quality: 80, StripMetadata: true},
+	}, p.Steps)
+
+	p, err = parseChain("thumbnail 200 100 crop=attention|export tif")
+	require.NoError(t, err)
+	assert.Equal(t, []vips.Step{
+		vips.ThumbnailStep{Width: 200, Height: 100, Crop: vips.InterestingAttention, Size: vips.SizeBoth},
+		vips.ExportStep{Format: vips.ImageTypeTIFF},
+	}, p.Steps)
+}
+
+func TestParseChain_Invalid(t *testing.T) {
+	for _, chain := range []string{
+		"",
+		"resize 0.5 |",
+		"resize 0.5 0.5 0.5",
+		"resize 0.5 scale=1",
+		"autorotate 90",
+		"blur 2",
+	} {
+		_, err := parseChain(chain)
+		assert.Error(t, err, chain)
+	}
+
+	for _, chain := range []string{
+		"resize 0.5 kernel=bogus",
+		"webp | resize 0.5",
+		"crop 1 2 3 4.5",
+	} {
+		_, err := parseChain(chain)
+		assert.True(t, errors.Is(err, vips.ErrInvalidPipeline), "%s: %v", chain, err)
+	}
+}
@@ -0,0 +1,90 @@
+package main
+
+import (
+	"io"
+
+	"github.com/davidbyttow/govips/v2/vips"
+)
+
+// errReader and errWriter remember the first I/O error, so that failures
+// can be attributed to the input or the output.
+type errReader struct {
+	r   io.Reader
+	err error
+}
+
+func (e *errReader) Read(p []byte) (int, error) {
+	n, err := e.r.Read(p)
+	if err != nil && err != io.EOF && e.err == nil {
+		e.err = err
+	}
+	return n, err
+}
+
+type errWriter struct {
+	w   io.Writer
+	n   int64
+	err error
+}
+
+func (e *errWriter) Write(p []byte) (int, error) {
+	n, err := e.w.Write(p)
+	e.n += int64(n)
+	if err != nil && e.err == nil {
+		e.err = err
+	}
+	return n, err
+}
+
+// transcodeResult is the -json report of transcode, printed on stderr.
+type transcodeResult struct {
+	Format string `json:"format,omitempty"`
+	Bytes  int64  `json:"bytes"`
+}
+
+func runTranscode(e *env, args []string) error {
+	fs := e.newFlagSet("transcode", "< INPUT > OUTPUT",
+		"Stream an image from stdin to stdout. Pixels flow in one pass without\n"+
+			"holding the image in memory, unless -autorotate needs a rotation.")
+	// Interlaced output is buffered by the encoder, which defeats
+	// streaming, so it is off by default here.
+	defaults := vips.NewDefaultExportParams()
+	defaults.Interlaced = false
+	var ef exportFlags
+	ef.register(fs, defaults)
+	autoRotate := fs.Bool("autorotate", false, "apply the EXIF orientation")
+	if _, err := parseArgs(fs, args, 0, 0); err != nil {
+		return err
+	}
+
+	params := *ef.params
+	if ef.format != "" {
+		format, err := parseFormat(ef.format)
+		if err != nil {
+			return err
+		}
+		params.Format = format
+	}
+
+	in := &errReader{r: e.stdin}
+	out := &errWriter{w: e.stdout}
+	err := vips.TranscodeStream(in, out, &vips.TranscodeOptions{
+		Format:       params.Format,
+		ExportParams: &params,
+		AutoRotate:   *autoRotate,
+	})
+	switch {
+	case in.err != nil:
+		return inputError(err)
+	case out.err != nil:
+		return outputError(err)
+	case err != nil:
+		return err
+	}
+
+	var format string
+	if params.Format != vips.ImageTypeUnknown {
+		format = params.Format.Name()
+	}
+	return e.report("-", transcodeResult{Format: format, Bytes: out.n}, "")
+}
@@ -0,0 +1,333 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"go/ast"
+	"go/parser"
+	"go/token"
+	"go/types"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// The check mode renders the generated files in memory and compares them
+// with the ones in the output directory, so CI can tell whether the
+// committed bindings match the libvips it runs against. Besides the files
+// that differ, it reports how the API they describe changes. Both sides
+// of that comparison are read back from generated.go and
+// generated_foreign.go, so the report is in terms of the Go bridges
+// rather than raw introspection data.
+
+// checkReport is the machine-readable result of vipsgen -check.
+type checkReport struct {
+	// Drift lists the generated files whose content would change.
+	Drift []string `json:"drift"`
+	// Categories maps an operation category to its API changes.
+	Categories map[string]*categoryChanges `json:"categories"`
+}
+
+type categoryChanges struct {
+	Added   []string    `json:"added,omitempty"`
+	Removed []string    `json:"removed,omitempty"`
+	Changed []opChanges `json:"changed,omitempty"`
+}
+
+type opChanges struct {
+	Operation string    `json:"operation"`
+	Added     []apiArg  `json:"added,omitempty"`
+	Removed   []apiArg  `json:"removed,omitempty"`
+	Retyped   []retyped `json:"retyped,omitempty"`
+}
+
+type retyped struct {
+	Name string `json:"name"`
+	Kind string `json:"kind"`
+	From string `json:"from"`
+	To   string `json:"to"`
+}
+
+// apiArg is an argument of a generated bridge. Kind is "required",
+// "optional" or "output"; Type is the Go type.
+type apiArg struct {
+	Name string `json:"name"`
+	Kind string `json:"kind"`
+	Type string `json:"type"`
+}
+
+// hasDrift reports whether anything differs.
+func (r *checkReport) hasDrift() bool {
+	return len(r.Drift) > 0 || len(r.Categories) > 0
+}
+
+// Check renders the generated files for ops, compares them with the ones
+// in outputDir and writes the report to w as JSON.
+func Check(ops []OpDef, outputDir string, w io.Writer) (*checkReport, error) {
+	enums, err := IntrospectEnums(enumTypeNames())
+	if err != nil {
+		return nil, err
+	}
+	r, err := render(ops, enums, outputDir)
+	if err != nil {
+		return nil, err
+	}
+
+	report := &checkReport{
+		Drift:      []string{},
+		Categories: map[string]*categoryChanges{},
+	}
+	committed := map[string][]byte{}
+	fresh := map[string][]byte{}
+	for _, f := range r.files {
+		existing, err := os.ReadFile(filepath.Join(outputDir, f.name))
+		if err != nil && !os.IsNotExist(err) {
+			return nil, err
+		}
+		if !bytes.Equal(existing, f.content) {
+			report.Drift = append(report.Drift, f.name)
+		}
+		committed[f.name] = existing
+		fresh[f.name] = f.content
+	}
+
+	oldAPI, err := describeAPI(committed)
+	if err != nil {
+		return nil, fmt.Errorf("reading committed bindings: %w", err)
+	}
+	newAPI, err := describeAPI(fresh)
+	if err != nil {
+		return nil, fmt.Errorf("reading rendered bindings: %w", err)
+	}
+	diffAPI(report, oldAPI, newAPI, opCategories(ops))
+
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	if err := enc.Encode(report); err != nil {
+		return nil, err
+	}
+	return report, nil
+}
+
+// opCategories maps operation names to their normalized category.
+func opCategories(ops []OpDef) map[string]string {
+	cats := make(map[string]string, len(ops))
+	for _, op := range ops {
+		cats[op.Name] = normalizeCategoryForOp(op.Name, op.Category)
+	}
+	return cats
+}
+
+// categoryOf returns the category of an operation. Operations libvips no
+// longer has fall back to their override, if any.
+func categoryOf(cats map[string]string, opName string) string {
+	if cat, ok := cats[opName]; ok {
+		return cat
+	}
+	if cat, ok := opCategoryOverride[opName]; ok {
+		return cat
+	}
+	return "unknown"
+}
+
+func diffAPI(report *checkReport, oldAPI, newAPI map[string][]apiArg, cats map[string]string) {
+	changes := func(opName string) *categoryChanges {
+		cat := categoryOf(cats, opName)
+		c, ok := report.Categories[cat]
+		if !ok {
+			c = &categoryChanges{}
+			report.Categories[cat] = c
+		}
+		return c
+	}
+
+	for _, name := range sortedKeys(newAPI) {
+		oldArgs, ok := oldAPI[name]
+		if !ok {
+			c := changes(name)
+			c.Added = append(c.Added, name)
+			continue
+		}
+		if oc, changed := diffArgs(name, oldArgs, newAPI[name]); changed {
+			c := changes(name)
+			c.Changed = append(c.Changed, oc)
+		}
+	}
+	for _, name := range sortedKeys(oldAPI) {
+		if _, ok := newAPI[name]; !ok {
+			c := changes(name)
+			c.Removed = append(c.Removed, name)
+		}
+	}
+}
+
+func diffArgs(opName string, oldArgs, newArgs []apiArg) (opChanges, bool) {
+	key := func(a apiArg) string { return a.Kind + " " + a.Name }
+	oldByKey := make(map[string]apiArg, len(oldArgs))
+	for _, a := range oldArgs {
+		oldByKey[key(a)] = a
+	}
+	newByKey := make(map[string]apiArg, len(newArgs))
+	for _, a := range newArgs {
+		newByKey[key(a)] = a
+	}
+
+	oc := opChanges{Operation: opName}
+	for _, a := range newArgs {
+		old, ok := oldByKey[key(a)]
+		switch {
+		case !ok:
+			oc.Added = append(oc.Added, a)
+		case old.Type != a.Type:
+			oc.Retyped = append(oc.Retyped, retyped{Name: a.Name, Kind: a.Kind, From: old.Type, To: a.Type})
+		}
+	}
+	for _, a := range oldArgs {
+		if _, ok := newByKey[key(a)]; !ok {
+			oc.Removed = append(oc.Removed, a)
+		}
+	}
+	return oc, len(oc.Added)+len(oc.Removed)+len(oc.Retyped) > 0
+}
+
+// describeAPI reads the arguments of every operation from the Go
+// bridges in generated.go and the option structs in generated_foreign.go.
+// Missing files describe no operations.
+func describeAPI(files map[string][]byte) (map[string][]apiArg, error) {
+	api := map[string][]apiArg{}
+	fset := token.NewFileSet()
+	for _, name := range []string{"generated.go", "generated_foreign.go"} {
+		src := files[name]
+		if len(src) == 0 {
+			continue
+		}
+		f, err := parser.ParseFile(fset, name, src, parser.SkipObjectResolution)
+		if err != nil {
+			return nil, err
+		}
+
+		structs := map[string][]apiArg{}
+		for _, decl := range f.Decls {
+			d, ok := decl.(*ast.GenDecl)
+			if !ok || d.Tok != token.TYPE {
+				continue
+			}
+			for _, spec := range d.Specs {
+				ts := spec.(*ast.TypeSpec)
+				st, ok := ts.Type.(*ast.StructType)
+				if !ok {
+					continue
+				}
+				var fields []apiArg
+				for _, field := range st.Fields.List {
+					for _, n := range field.Names {
+						fields = append(fields, apiArg{Name: n.Name, Kind: "optional", Type: types.ExprString(field.Type)})
+					}
+				}
+				structs[ts.Name.Name] = fields
+			}
+		}
+
+		for _, decl := range f.Decls {
+			d, ok := decl.(*ast.FuncDecl)
+			if !ok || d.Body == nil {
+				continue
+			}
+			switch {
+			case d.Recv == nil && strings.HasPrefix(d.Name.Name, "vipsGen"):
+				opName, args := describeBridge(d, structs)
+				if opName != "" {
+					api[opName] = args
+				}
+			case d.Recv != nil && (d.Name.Name == "saveOperation" || d.Name.Name == "loadOperation"):
+				opName := returnedString(d)
+				recv := strings.TrimPrefix(types.ExprString(d.Recv.List[0].Type), "*")
+				if opName != "" {
+					api[opName] = structs[recv]
+				}
+			}
+		}
+	}
+	return api, nil
+}
+
+// describeBridge returns the operation a vipsGen* bridge calls and its
+// arguments: the parameters after the context, the fields of its options
+// struct and the results before the error, named after the out_ variables
+// that receive them.
+func describeBridge(d *ast.FuncDecl, structs map[string][]apiArg) (string, []apiArg) {
+	var opName string
+	var outNames []string
+	ast.Inspect(d.Body, func(n ast.Node) bool {
+		switch n := n.(type) {
+		case *ast.CallExpr:
+			if id, ok := n.Fun.(*ast.Ident); ok && id.Name == "startOp" && len(n.Args) > 1 {
+				if lit, ok := n.Args[1].(*ast.BasicLit); ok {
+					opName, _ = strconv.Unquote(lit.Value)
+				}
+			}
+		case *ast.ValueSpec:
+			for _, id := range n.Names {
+				if name, ok := strings.CutPrefix(id.Name, "out_"); ok {
+					outNames = append(outNames, name)
+				}
+			}
+		}
+		return true
+	})
+
+	var args []apiArg
+	for i, field := range d.Type.Params.List {
+		if i == 0 {
+			continue // ctx
+		}
+		typ := types.ExprString(field.Type)
+		for _, n := range field.Names {
+			if n.Name == "opts" {
+				args = append(args, structs[strings.TrimPrefix(typ, "*")]...)
+				continue
+			}
+			args = append(args, apiArg{Name: n.Name, Kind: "required", Type: typ})
+		}
+	}
+	if d.Type.Results != nil {
+		results := d.Type.Results.List
+		results = results[:len(results)-1] // error
+		for i, field := range results {
+			name := fmt.Sprintf("out%d", i)
+			if len(outNames) == len(results) {
+				name = outNames[i]
+			}
+			args = append(args, apiArg{Name: name, Kind: "output", Type: types.ExprString(field.Type)})
+		}
+	}
+	return opName, args
+}
+
+// returnedString returns the string literal a method returns, or "".
+func returnedString(d *ast.FuncDecl) string {
+	for _, stmt := range d.Body.List {
+		ret, ok := stmt.(*ast.ReturnStmt)
+		if !ok || len(ret.Results) != 1 {
+			continue
+		}
+		if lit, ok := ret.Results[0].(*ast.BasicLit); ok {
+			s, _ := strconv.Unquote(lit.Value)
+			return s
+		}
+	}
+	return ""
+}
+
+func sortedKeys(m map[string][]apiArg) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
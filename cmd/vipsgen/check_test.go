@@ -0,0 +1,229 @@
+package main
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const gammaBridge = `
+// GammaOptions are optional parameters for gamma.
+type GammaOptions struct {
+	Exponent *float64
+}
+
+func vipsGenGamma(ctx context.Context, input *C.VipsImage, opts *GammaOptions) (*C.VipsImage, error) {
+	op := startOp(ctx, "gamma", input)
+	defer op.end()
+
+	var out_out *C.VipsImage
+	ret := C.gen_vips_gamma(input, &out_out, nil)
+	if ret != 0 {
+		return nil, op.fail(handleImageError(out_out))
+	}
+	return out_out, nil
+}
+`
+
+const findTrimBridge = `
+func vipsGenFindTrim(ctx context.Context, input *C.VipsImage, threshold float64) (int, int, error) {
+	op := startOp(ctx, "find_trim", input)
+	defer op.end()
+
+	var out_left C.int
+	var out_top C.int
+	if C.gen_vips_find_trim(input, &out_left, &out_top) != 0 {
+		return 0, 0, op.fail(handleVipsError())
+	}
+	return int(out_left), int(out_top), nil
+}
+`
+
+const jxlsaveOptions = `
+type JxlsaveOptions struct {
+	Tier     *int
+	Distance *float64
+}
+
+func (o *JxlsaveOptions) saveOperation() string {
+	return "jxlsave"
+}
+`
+
+// bindings returns generated.go and generated_foreign.go holding the
+// given declarations.
+func bindings(generated, foreign string) map[string][]byte {
+	files := map[string][]byte{}
+	if generated != "" {
+		files["generated.go"] = []byte("package vips\n" + generated)
+	}
+	if foreign != "" {
+		files["generated_foreign.go"] = []byte("package vips\n" + foreign)
+	}
+	return files
+}
+
+func TestDescribeAPI(t *testing.T) {
+	api, err := describeAPI(bindings(gammaBridge+findTrimBridge, jxlsaveOptions))
+	require.NoError(t, err)
+	assert.Equal(t, map[string][]apiArg{
+		"gamma": {
+			{Name: "input", Kind: "required", Type: "*C.VipsImage"},
+			{Name: "Exponent", Kind: "optional", Type: "*float64"},
+			{Name: "out", Kind: "output", Type: "*C.VipsImage"},
+		},
+		"find_trim": {
+			{Name: "input", Kind: "required", Type: "*C.VipsImage"},
+			{Name: "threshold", Kind: "required", Type: "float64"},
+			{Name: "left", Kind: "output", Type: "int"},
+			{Name: "top", Kind: "output", Type: "int"},
+		},
+		"jxlsave": {
+			{Name: "Tier", Kind: "optional", Type: "*int"},
+			{Name: "Distance", Kind: "optional", Type: "*float64"},
+		},
+	}, api)
+
+	api, err = describeAPI(nil)
+	require.NoError(t, err)
+	assert.Empty(t, api)
+
+	_, err = describeAPI(map[string][]byte{"generated.go": []byte("package vips\nfunc {")})
+	assert.Error(t, err)
+}
+
+func TestDescribeBridge_UnnamedOutputs(t *testing.T) {
+	// Without one out_ variable per result, outputs are numbered.
+	src := `
+func vipsGenMax(ctx context.Context, input *C.VipsImage) (float64, int, error) {
+	op := startOp(ctx, "max", input)
+	defer op.end()
+
+	var out_out C.double
+	return float64(out_out), 0, nil
+}
+`
+	api, err := describeAPI(bindings(src, ""))
+	require.NoError(t, err)
+	assert.Equal(t, []apiArg{
+		{Name: "input", Kind: "required", Type: "*C.VipsImage"},
+		{Name: "out0", Kind: "output", Type: "float64"},
+		{Name: "out1", Kind: "output", Type: "int"},
+	}, api["max"])
+}
+
+func TestDiffAPI(t *testing.T) {
+	cats := map[string]string{"gamma": "arithmetic", "find_trim": "arithmetic", "jxlsave": "foreign"}
+	image := apiArg{Name: "input", Kind: "required", Type: "*C.VipsImage"}
+	exponent := apiArg{Name: "Exponent", Kind: "optional", Type: "*float64"}
+	out := apiArg{Name: "out", Kind: "output", Type: "*C.VipsImage"}
+
+	for _, tt := range []struct {
+		name     string
+		old, new map[string][]apiArg
+		want     map[string]*categoryChanges
+	}{
+		{
+			name: "unchanged",
+			old:  map[string][]apiArg{"gamma": {image, exponent, out}},
+			new:  map[string][]apiArg{"gamma": {image, exponent, out}},
+			want: map[string]*categoryChanges{},
+		},
+		{
+			name: "new operation",
+			old:  map[string][]apiArg{"gamma": {image, out}},
+			new:  map[string][]apiArg{"gamma": {image, out}, "find_trim": {image}, "jxlsave": nil},
+			want: map[string]*categoryChanges{
+				"arithmetic": {Added: []string{"find_trim"}},
+				"foreign":    {Added: []string{"jxlsave"}},
+			},
+		},
+		{
+			name: "removed operation",
+			old:  map[string][]apiArg{"gamma": {image, out}, "oldop": {image}},
+			new:  map[string][]apiArg{"gamma": {image, out}},
+			want: map[string]*categoryChanges{
+				"unknown": {Removed: []string{"oldop"}},
+			},
+		},
+		{
+			name: "added argument",
+			old:  map[string][]apiArg{"gamma": {image, out}},
+			new:  map[string][]apiArg{"gamma": {image, exponent, out}},
+			want: map[string]*categoryChanges{
+				"arithmetic": {Changed: []opChanges{{Operation: "gamma", Added: []apiArg{exponent}}}},
+			},
+		},
+		{
+			name: "removed argument",
+			old:  map[string][]apiArg{"gamma": {image, exponent, out}},
+			new:  map[string][]apiArg{"gamma": {image, out}},
+			want: map[string]*categoryChanges{
+				"arithmetic": {Changed: []opChanges{{Operation: "gamma", Removed: []apiArg{exponent}}}},
+			},
+		},
+		{
+			name: "retyped argument",
+			old:  map[string][]apiArg{"gamma": {image, exponent, out}},
+			new:  map[string][]apiArg{"gamma": {image, {Name: "Exponent", Kind: "optional", Type: "*int"}, out}},
+			want: map[string]*categoryChanges{
+				"arithmetic": {Changed: []opChanges{{
+					Operation: "gamma",
+					Retyped:   []retyped{{Name: "Exponent", Kind: "optional", From: "*float64", To: "*int"}},
+				}}},
+			},
+		},
+		{
+			name: "argument changed kind",
+			old:  map[string][]apiArg{"gamma": {image, exponent, out}},
+			new:  map[string][]apiArg{"gamma": {image, {Name: "Exponent", Kind: "required", Type: "*float64"}, out}},
+			want: map[string]*categoryChanges{
+				"arithmetic": {Changed: []opChanges{{
+					Operation: "gamma",
+					Added:     []apiArg{{Name: "Exponent", Kind: "required", Type: "*float64"}},
+					Removed:   []apiArg{exponent},
+				}}},
+			},
+		},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			report := &checkReport{Drift: []string{}, Categories: map[string]*categoryChanges{}}
+			diffAPI(report, tt.old, tt.new, cats)
+			assert.Equal(t, tt.want, report.Categories)
+			assert.Equal(t, len(tt.want) > 0, report.hasDrift())
+		})
+	}
+}
+
+func TestDiffAPI_FromBindings(t *testing.T) {
+	// The committed bindings lack find_trim and an option gamma gained;
+	// the rendered ones have both.
+	oldAPI, err := describeAPI(bindings(`
+type GammaOptions struct {
+}
+
+func vipsGenGamma(ctx context.Context, input *C.VipsImage, opts *GammaOptions) (*C.VipsImage, error) {
+	op := startOp(ctx, "gamma", input)
+	defer op.end()
+
+	var out_out *C.VipsImage
+	return out_out, nil
+}
+`, jxlsaveOptions))
+	require.NoError(t, err)
+	newAPI, err := describeAPI(bindings(gammaBridge+findTrimBridge, jxlsaveOptions))
+	require.NoError(t, err)
+
+	report := &checkReport{Drift: []string{}, Categories: map[string]*categoryChanges{}}
+	diffAPI(report, oldAPI, newAPI, map[string]string{"gamma": "arithmetic", "find_trim": "arithmetic", "jxlsave": "foreign"})
+	assert.Equal(t, map[string]*categoryChanges{
+		"arithmetic": {
+			Added: []string{"find_trim"},
+			Changed: []opChanges{{
+				Operation: "gamma",
+				Added:     []apiArg{{Name: "Exponent", Kind: "optional", Type: "*float64"}},
+			}},
+		},
+	}, report.Categories)
+}
@@ -106,8 +106,6 @@ var excludeOps = map[string]bool{
 	"thumbnail_source": true,
 
 	// Other special-cased operations.
-	"switch":       true, // reserved word in Go
-	"case":         true, // reserved word in Go
 	"system":       true, // shell execution, not an image op
 	"profile_load": true, // internal
 
@@ -123,20 +121,23 @@ var excludeOps = map[string]bool{
 	"find_trim":       true, // arithmetic.go (custom logic)
 	"getpoint":        true, // arithmetic.go (custom logic)
 
-	// Complex output params not supported by the generator.
-	"max":     true, // value+x+y+array outputs
-	"min":     true, // value+x+y+array outputs
-	"measure": true, // returns matrix
+	// Complex output params not supported by the generator; hand-written
+	// in operations.go.
+	"max":     true, // value+x+y+array outputs (MaxN, BandMaxN)
+	"min":     true, // value+x+y+array outputs (MinN, BandMinN)
+	"measure": true, // returns matrix (MeasurePatches)
 
-	// Operations requiring libvips 8.16+ (not registered as VipsOperation in older versions).
-	"sdf":      true, // VipsSdfShape enum added in 8.16
-	"addalpha": true, // only a VipsOperation class since 8.16
+	// Image-or-constant branches; hand-written in operations.go and
+	// image_select.go with IfThenElse.
+	"switch": true,
+	"case":   true,
 
 	// Hand-written multi-page handling.
 	"embed": true,
 	"crop":  true,
 
-	// Draw operations (in-place mutation, array double ink).
+	// Draw operations (in-place mutation, array double ink); hand-written
+	// in operations.go with copy-on-write.
 	"draw_rect":   true,
 	"draw_image":  true,
 	"draw_mask":   true,
@@ -291,6 +292,47 @@ var enumGoName = map[string]string{
 	"VipsDemandStyle":            "DemandStyle",
 	"VipsFailOn":                 "FailOn",
 	"VipsForeignKeep":            "ForeignKeep",
+	"VipsSdfShape":               "SdfShape",
+	"VipsForeignWebpPreset":      "WebpPreset",
+	"VipsForeignTiffResunit":     "TiffResunit",
+	"VipsForeignHeifEncoder":     "HeifEncoder",
+}
+
+// lateEnums lists enum types that are missing from the headers of older
+// supported libvips versions. Generated C code passes them as int so that
+// it still compiles; the Go types are declared with literal values in
+// gen_enum_extras.go.
+var lateEnums = map[string]bool{
+	"VipsForeignKeep":        true,
+	"VipsSdfShape":           true,
+	"VipsForeignHeifEncoder": true,
+}
+
+// foreignOptionOps lists the savers and loaders whose options are
+// generated into generated_foreign.{c,h,go}. The _buffer, _source and
+// _target variants share the options of the file operation listed here,
+// so only the file operation is introspected.
+var foreignOptionOps = map[string]bool{
+	"gifload":    true,
+	"gifsave":    true,
+	"heifload":   true,
+	"heifsave":   true,
+	"jp2kload":   true,
+	"jp2ksave":   true,
+	"jpegload":   true,
+	"jpegsave":   true,
+	"jxlload":    true,
+	"jxlsave":    true,
+	"magickload": true,
+	"magicksave": true,
+	"pdfload":    true,
+	"pngload":    true,
+	"pngsave":    true,
+	"svgload":    true,
+	"tiffload":   true,
+	"tiffsave":   true,
+	"webpload":   true,
+	"webpsave":   true,
 }
 
 // goEnumName returns the Go type name for a C enum type.
@@ -303,12 +345,57 @@ func goEnumName(cName string) string {
 
 // versionIntroduced maps operation names to the libvips version that
 // introduced them. Only operations added after 8.10 need to be listed.
+// Their generated bridges return ErrUnsupportedByLibvips on an older
+// libvips instead of calling it.
 var versionIntroduced = map[string]struct{ Major, Minor int }{
+	"addalpha":        {8, 16},
 	"jp2kload":        {8, 11},
 	"jp2kload_buffer": {8, 11},
 	"jp2kload_source": {8, 11},
 	"jp2ksave":        {8, 11},
 	"jp2ksave_buffer": {8, 11},
 	"jp2ksave_target": {8, 11},
+	"sdf":             {8, 16},
 	"transpose3d":     {8, 14},
 }
+
+// publicOp configures the exported wrapper of an operation. By default,
+// an operation on an image gets an ImageRef method named after it that
+// replaces the receiver's image with its single image output, and an
+// operation without an image input gets a package-level constructor.
+// Names that clash with a hand-written method or identifier, ignoring
+// case, are skipped automatically.
+type publicOp struct {
+	Name    string // exported name, goName(op) when empty
+	Skip    bool   // no exported wrapper
+	Returns bool   // return the result as a new *ImageRef
+}
+
+// publicOps overrides the exported wrapper of individual operations.
+var publicOps = map[string]publicOp{
+	// Wrapped by hand-written methods under another name.
+	"autorot":       {Skip: true}, // ImageRef.AutoRotate
+	"avg":           {Skip: true}, // ImageRef.Average
+	"composite2":    {Skip: true}, // ImageRef.Composite
+	"gaussblur":     {Skip: true}, // ImageRef.GaussianBlur
+	"hist_cum":      {Skip: true}, // ImageRef.HistogramCumulative
+	"hist_entropy":  {Skip: true}, // ImageRef.HistogramEntropy
+	"hist_find":     {Skip: true}, // ImageRef.HistogramFind
+	"hist_norm":     {Skip: true}, // ImageRef.HistogramNormalise
+	"premultiply":   {Skip: true}, // ImageRef.PremultiplyAlpha
+	"rot":           {Skip: true}, // ImageRef.Rotate
+	"unpremultiply": {Skip: true}, // ImageRef.UnpremultiplyAlpha
+
+	// Internal plumbing rather than image processing.
+	"cache":      {Skip: true},
+	"linecache":  {Skip: true},
+	"sequential": {Skip: true},
+	"tilecache":  {Skip: true},
+
+	// Analyses whose output replaces the meaning of the image.
+	"hist_find_indexed": {Returns: true},
+	"hist_find_ndim":    {Returns: true},
+	"hough_circle":      {Returns: true},
+	"hough_line":        {Returns: true},
+	"fwfft":             {Returns: true},
+}
@@ -0,0 +1,146 @@
+package main
+
+import (
+	"bytes"
+	"fmt"
+	"go/ast"
+	"go/format"
+	"go/parser"
+	"go/token"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+)
+
+// The text form of an enum is its libvips nickname, so generated_enums.go
+// gives every Go type in enumGoName a table of the introspected nicknames
+// and the String, Parse, MarshalText and UnmarshalText methods built on
+// the helpers in vips/enums.go. The tables use the integer values rather
+// than the C constants, which keeps the file free of cgo and of values
+// newer than the headers it is compiled against; libvips never renumbers
+// them.
+
+// enumTypeNames returns the C names in enumGoName, sorted.
+func enumTypeNames() []string {
+	names := make([]string, 0, len(enumGoName))
+	for name := range enumGoName {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// declaredTypes returns the type names declared by the Go files of dir,
+// other than the tests and generated_enums.go itself. Enums whose Go type
+// is not declared yet get no methods.
+func declaredTypes(dir string) (map[string]bool, error) {
+	files, err := filepath.Glob(filepath.Join(dir, "*.go"))
+	if err != nil {
+		return nil, err
+	}
+	declared := map[string]bool{}
+	fset := token.NewFileSet()
+	for _, path := range files {
+		if strings.HasSuffix(path, "_test.go") || filepath.Base(path) == "generated_enums.go" {
+			continue
+		}
+		src, err := os.ReadFile(path)
+		if err != nil {
+			return nil, err
+		}
+		f, err := parser.ParseFile(fset, path, src, parser.SkipObjectResolution)
+		if err != nil {
+			return nil, err
+		}
+		for _, decl := range f.Decls {
+			d, ok := decl.(*ast.GenDecl)
+			if !ok || d.Tok != token.TYPE {
+				continue
+			}
+			for _, spec := range d.Specs {
+				declared[spec.(*ast.TypeSpec).Name.Name] = true
+			}
+		}
+	}
+	return declared, nil
+}
+
+// nickTableName is the unexported variable holding the nicknames of an
+// enum, e.g. blendModeNicks.
+func nickTableName(goType string) string {
+	return strings.ToLower(goType[:1]) + goType[1:] + "Nicks"
+}
+
+func genGoEnums(enums []EnumDef, declared map[string]bool) ([]byte, int) {
+	var w bytes.Buffer
+	fmt.Fprintf(&w, "// Code generated by vipsgen. DO NOT EDIT.\n")
+	fmt.Fprintf(&w, "package vips\n\n")
+
+	count := 0
+	for _, e := range enums {
+		goType := goEnumName(e.CName)
+		if goType == "" || !declared[goType] || len(e.Values) == 0 {
+			continue
+		}
+		genGoEnum(&w, e, goType)
+		count++
+	}
+
+	if formatted, err := format.Source(w.Bytes()); err == nil {
+		return formatted, count
+	}
+	return w.Bytes(), count
+}
+
+func genGoEnum(w *bytes.Buffer, e EnumDef, goType string) {
+	table := nickTableName(goType)
+	kind := "Enum"
+	if e.Flags {
+		kind = "Flags"
+	}
+
+	fmt.Fprintf(w, "// %s holds the libvips nicknames of %s, from %s.\n", table, goType, e.CName)
+	fmt.Fprintf(w, "var %s = []enumNick[%s]{\n", table, goType)
+	for _, v := range e.Values {
+		fmt.Fprintf(w, "\t{%d, %q}, // %s\n", v.Value, v.Nick, v.CName)
+	}
+	fmt.Fprintf(w, "}\n\n")
+
+	example := e.Values[0].Nick
+	if e.Flags {
+		fmt.Fprintf(w, "// String returns the libvips nicknames of the flags set in v, joined\n")
+		fmt.Fprintf(w, "// with \"|\".\n")
+	} else {
+		fmt.Fprintf(w, "// String returns the libvips nickname of v, such as %q.\n", example)
+	}
+	fmt.Fprintf(w, "func (v %s) String() string {\n", goType)
+	fmt.Fprintf(w, "\treturn format%s(%q, %s, v)\n", kind, goType, table)
+	fmt.Fprintf(w, "}\n\n")
+
+	if e.Flags {
+		fmt.Fprintf(w, "// Parse%s returns the %s named by s, one or more libvips\n", goType, goType)
+		fmt.Fprintf(w, "// nicknames separated by \"|\" or \",\". Case is ignored.\n")
+	} else {
+		fmt.Fprintf(w, "// Parse%s returns the %s with the libvips nickname s.\n", goType, goType)
+		fmt.Fprintf(w, "// Case is ignored.\n")
+	}
+	fmt.Fprintf(w, "func Parse%s(s string) (%s, error) {\n", goType, goType)
+	fmt.Fprintf(w, "\treturn parse%s(%q, %s, s)\n", kind, goType, table)
+	fmt.Fprintf(w, "}\n\n")
+
+	fmt.Fprintf(w, "// MarshalText implements encoding.TextMarshaler.\n")
+	fmt.Fprintf(w, "func (v %s) MarshalText() ([]byte, error) {\n", goType)
+	fmt.Fprintf(w, "\treturn marshal%s(%q, %s, v)\n", kind, goType, table)
+	fmt.Fprintf(w, "}\n\n")
+
+	fmt.Fprintf(w, "// UnmarshalText implements encoding.TextUnmarshaler.\n")
+	fmt.Fprintf(w, "func (v *%s) UnmarshalText(text []byte) error {\n", goType)
+	fmt.Fprintf(w, "\tparsed, err := Parse%s(string(text))\n", goType)
+	fmt.Fprintf(w, "\tif err != nil {\n")
+	fmt.Fprintf(w, "\t\treturn err\n")
+	fmt.Fprintf(w, "\t}\n")
+	fmt.Fprintf(w, "\t*v = parsed\n")
+	fmt.Fprintf(w, "\treturn nil\n")
+	fmt.Fprintf(w, "}\n\n")
+}
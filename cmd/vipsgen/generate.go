@@ -11,14 +11,62 @@ import (
 )
 
 // Generate creates C wrapper, C header, and Go bridge files from the
-// discovered operations, outputting a single generated.{c,h,go} triplet.
+// discovered operations, outputting a single generated.{c,h,go} triplet,
+// and the exported ImageRef methods wrapping the bridges in
+// generated_image.go.
 func Generate(ops []OpDef, outputDir string) error {
 	// Clean up old per-category generated files.
 	cleanOldGenFiles(outputDir)
 
+	enums, err := IntrospectEnums(enumTypeNames())
+	if err != nil {
+		return err
+	}
+	r, err := render(ops, enums, outputDir)
+	if err != nil {
+		return err
+	}
+	for _, f := range r.files {
+		if err := writeIfChanged(filepath.Join(outputDir, f.name), f.content); err != nil {
+			return err
+		}
+	}
+
+	fmt.Printf("  generated.{c,h,go}: %d operations\n", r.ops)
+	fmt.Printf("  generated_image.go: %d exported wrappers\n", r.wrappers)
+	fmt.Printf("  generated_foreign.{c,h,go}: %d savers and loaders\n", r.foreign)
+	fmt.Printf("  generated_enums.go: %d enum and flags types\n", r.enums)
+	return nil
+}
+
+// genFile is a generated file, named relative to the output directory.
+type genFile struct {
+	name    string
+	content []byte
+}
+
+// rendered holds the generated files in memory, with the counts Generate
+// reports.
+type rendered struct {
+	files    []genFile
+	ops      int
+	wrappers int
+	foreign  int
+	enums    int
+}
+
+// render generates every file in memory. outputDir is only read, to
+// find the hand-written names the public layer must not clash with and
+// the enum types that are declared.
+func render(ops []OpDef, enums []EnumDef, outputDir string) (rendered, error) {
 	// Filter to generatable, non-foreign operations and sort alphabetically.
-	var allOps []OpDef
+	// The savers and loaders in foreignOptionOps only get option structs.
+	var allOps, foreignOps []OpDef
 	for _, op := range ops {
+		if foreignOptionOps[op.Name] {
+			foreignOps = append(foreignOps, op)
+			continue
+		}
 		if excludeOps[op.Name] {
 			continue
 		}
@@ -26,32 +74,56 @@ func Generate(ops []OpDef, outputDir string) error {
 		if cat == "foreign" {
 			continue
 		}
+		op.Args = withoutDeprecated(op.Args)
 		allOps = append(allOps, op)
 	}
 	sort.Slice(allOps, func(i, j int) bool {
 		return allOps[i].Name < allOps[j].Name
 	})
+	sort.Slice(foreignOps, func(i, j int) bool {
+		return foreignOps[i].Name < foreignOps[j].Name
+	})
 
-	cCode := genCSource(allOps)
-	hCode := genCHeader(allOps)
-	goCode := genGoBridge(allOps)
-
-	cPath := filepath.Join(outputDir, "generated.c")
-	hPath := filepath.Join(outputDir, "generated.h")
-	goPath := filepath.Join(outputDir, "generated.go")
+	hw, err := scanHandWritten(outputDir)
+	if err != nil {
+		return rendered{}, err
+	}
+	publicDefs := resolvePublicOps(allOps, hw)
+
+	declared, err := declaredTypes(outputDir)
+	if err != nil {
+		return rendered{}, err
+	}
+	enumCode, enumCount := genGoEnums(enums, declared)
+
+	return rendered{
+		files: []genFile{
+			{"generated.c", genCSource(allOps)},
+			{"generated.h", genCHeader(allOps)},
+			{"generated.go", genGoBridge(allOps)},
+			{"generated_image.go", genGoPublic(publicDefs)},
+			{"generated_foreign.c", genForeignCSource(foreignOps)},
+			{"generated_foreign.h", genForeignCHeader(foreignOps)},
+			{"generated_foreign.go", genForeignGo(foreignOps)},
+			{"generated_enums.go", enumCode},
+		},
+		ops:      len(allOps),
+		wrappers: len(publicDefs),
+		foreign:  len(foreignOps),
+		enums:    enumCount,
+	}, nil
+}
 
-	if err := writeIfChanged(cPath, cCode); err != nil {
-		return err
-	}
-	if err := writeIfChanged(hPath, hCode); err != nil {
-		return err
-	}
-	if err := writeIfChanged(goPath, goCode); err != nil {
-		return err
+// withoutDeprecated returns args without the ones libvips marks
+// deprecated. Operation wrappers only expose the current arguments.
+func withoutDeprecated(args []ArgDef) []ArgDef {
+	var result []ArgDef
+	for _, a := range args {
+		if !a.IsDeprecated() {
+			result = append(result, a)
+		}
 	}
-
-	fmt.Printf("  generated.{c,h,go}: %d operations\n", len(allOps))
-	return nil
+	return result
 }
 
 // cleanOldGenFiles removes legacy per-category gen_*.{c,h,go} and gen_helpers.go
@@ -195,6 +267,9 @@ func goTypeName(arg ArgDef) string {
 		}
 		return "int"
 	case ArgTypeFlags:
+		if name := goEnumName(arg.EnumType); name != "" {
+			return name
+		}
 		return "int"
 	case ArgTypeArrayDouble:
 		return "[]float64"
@@ -227,6 +302,9 @@ func goOptTypeName(arg ArgDef) string {
 		}
 		return "*int"
 	case ArgTypeFlags:
+		if name := goEnumName(arg.EnumType); name != "" {
+			return "*" + name
+		}
 		return "*int"
 	case ArgTypeArrayDouble:
 		return "[]float64"
@@ -237,7 +315,9 @@ func goOptTypeName(arg ArgDef) string {
 	case ArgTypeImage:
 		return "*C.VipsImage"
 	case ArgTypeInterpolate:
-		return "*C.VipsInterpolate"
+		// An interpolator nickname; the bridge makes the VipsInterpolate,
+		// so that no cgo type appears in the options.
+		return "*string"
 	case ArgTypeBlob:
 		return "[]byte"
 	default:
@@ -258,7 +338,7 @@ func cTypeName(arg ArgDef) string {
 	case ArgTypeString:
 		return "const char *"
 	case ArgTypeEnum:
-		return arg.EnumType
+		return cEnumTypeName(arg.EnumType)
 	case ArgTypeFlags:
 		return "int"
 	case ArgTypeArrayDouble:
@@ -293,6 +373,14 @@ func cOutputTypeName(arg ArgDef) string {
 	}
 }
 
+// cEnumTypeName is the C type an enum is passed as, int for lateEnums.
+func cEnumTypeName(enumType string) string {
+	if lateEnums[enumType] {
+		return "int"
+	}
+	return enumType
+}
+
 // isNilCheckType returns true if the opt arg uses nil check (pointer/slice).
 func isNilCheckType(arg ArgDef) bool {
 	return arg.Type == ArgTypeArrayDouble || arg.Type == ArgTypeArrayInt ||
@@ -478,34 +566,7 @@ func genCHeaderOp(w *bytes.Buffer, op OpDef) {
 
 	// Generate opts struct if needed.
 	if hasOpts {
-		fmt.Fprintf(w, "typedef struct {\n")
-		for _, a := range optInputs {
-			argName := goArgName(a.Name)
-			fmt.Fprintf(w, "    int has_%s;\n", argName)
-			switch a.Type {
-			case ArgTypeImage:
-				fmt.Fprintf(w, "    VipsImage *%s;\n", argName)
-			case ArgTypeDouble:
-				fmt.Fprintf(w, "    double %s;\n", argName)
-			case ArgTypeInt:
-				fmt.Fprintf(w, "    int %s;\n", argName)
-			case ArgTypeBool:
-				fmt.Fprintf(w, "    int %s;\n", argName)
-			case ArgTypeString:
-				fmt.Fprintf(w, "    const char *%s;\n", argName)
-			case ArgTypeEnum:
-				fmt.Fprintf(w, "    %s %s;\n", a.EnumType, argName)
-			case ArgTypeArrayDouble:
-				fmt.Fprintf(w, "    double *%s; int %s_n;\n", argName, argName)
-			case ArgTypeArrayInt:
-				fmt.Fprintf(w, "    int *%s; int %s_n;\n", argName, argName)
-			case ArgTypeInterpolate:
-				fmt.Fprintf(w, "    VipsInterpolate *%s;\n", argName)
-			default:
-				fmt.Fprintf(w, "    int %s;\n", argName)
-			}
-		}
-		fmt.Fprintf(w, "} %s;\n\n", cStructName(op.Name))
+		genCOptsStruct(w, cStructName(op.Name), optInputs)
 	}
 
 	// Function prototype.
@@ -532,6 +593,39 @@ func genCHeaderOp(w *bytes.Buffer, op OpDef) {
 	fmt.Fprintf(w, "int %s(%s);\n\n", cFuncName(op.Name), strings.Join(params, ", "))
 }
 
+// genCOptsStruct writes the typedef of an options struct holding args,
+// each with a has_ flag.
+func genCOptsStruct(w *bytes.Buffer, name string, args []ArgDef) {
+	fmt.Fprintf(w, "typedef struct {\n")
+	for _, a := range args {
+		argName := goArgName(a.Name)
+		fmt.Fprintf(w, "    int has_%s;\n", argName)
+		switch a.Type {
+		case ArgTypeImage:
+			fmt.Fprintf(w, "    VipsImage *%s;\n", argName)
+		case ArgTypeDouble:
+			fmt.Fprintf(w, "    double %s;\n", argName)
+		case ArgTypeInt:
+			fmt.Fprintf(w, "    int %s;\n", argName)
+		case ArgTypeBool:
+			fmt.Fprintf(w, "    int %s;\n", argName)
+		case ArgTypeString:
+			fmt.Fprintf(w, "    const char *%s;\n", argName)
+		case ArgTypeEnum:
+			fmt.Fprintf(w, "    %s %s;\n", cEnumTypeName(a.EnumType), argName)
+		case ArgTypeArrayDouble:
+			fmt.Fprintf(w, "    double *%s; int %s_n;\n", argName, argName)
+		case ArgTypeArrayInt:
+			fmt.Fprintf(w, "    int *%s; int %s_n;\n", argName, argName)
+		case ArgTypeInterpolate:
+			fmt.Fprintf(w, "    VipsInterpolate *%s;\n", argName)
+		default:
+			fmt.Fprintf(w, "    int %s;\n", argName)
+		}
+	}
+	fmt.Fprintf(w, "} %s;\n\n", name)
+}
+
 // --- Go Bridge Generation ---
 
 // opHasArrayOpts returns true if the operation has any optional array params.
@@ -560,9 +654,9 @@ func genGoBridge(ops []OpDef) []byte {
 		}
 	}
 	if needsRuntime {
-		fmt.Fprintf(&w, "import (\n\t\"runtime\"\n\t\"unsafe\"\n)\n\n")
+		fmt.Fprintf(&w, "import (\n\t\"context\"\n\t\"runtime\"\n\t\"unsafe\"\n)\n\n")
 	} else {
-		fmt.Fprintf(&w, "import \"unsafe\"\n\n")
+		fmt.Fprintf(&w, "import (\n\t\"context\"\n\t\"unsafe\"\n)\n\n")
 	}
 
 	fmt.Fprintf(&w, "// Ensure imports are used.\n")
@@ -586,6 +680,9 @@ func genGoFunc(w *bytes.Buffer, op OpDef) {
 		fmt.Fprintf(w, "// %s are optional parameters for %s.\n", goOptsTypeName(op.Name), op.Name)
 		fmt.Fprintf(w, "type %s struct {\n", goOptsTypeName(op.Name))
 		for _, a := range optInputs {
+			if a.Type == ArgTypeInterpolate {
+				fmt.Fprintf(w, "\t// %s names an interpolator, such as \"bicubic\" or \"nohalo\".\n", goExportedArgName(a.Name))
+			}
 			fmt.Fprintf(w, "\t%s %s\n", goExportedArgName(a.Name), goOptTypeName(a))
 		}
 		fmt.Fprintf(w, "}\n\n")
@@ -595,10 +692,14 @@ func genGoFunc(w *bytes.Buffer, op OpDef) {
 	funcName := goFuncName(op.Name)
 	fmt.Fprintf(w, "// %s calls the vips %s operation.\n", funcName, op.Name)
 	fmt.Fprintf(w, "// %s\n", op.Description)
+	version, gated := versionIntroduced[op.Name]
+	if gated {
+		fmt.Fprintf(w, "// Requires libvips %d.%d+.\n", version.Major, version.Minor)
+	}
 	fmt.Fprintf(w, "func %s(", funcName)
 
 	// Parameters.
-	goParams := []string{}
+	goParams := []string{"ctx context.Context"}
 	for _, a := range reqInputs {
 		goParams = append(goParams, fmt.Sprintf("%s %s", goArgName(a.Name), goTypeName(a)))
 	}
@@ -621,7 +722,25 @@ func genGoFunc(w *bytes.Buffer, op OpDef) {
 	fmt.Fprintf(w, ") {\n")
 
 	// Body.
-	fmt.Fprintf(w, "\tincOpCounter(\"%s\")\n\n", op.Name)
+	if gated {
+		errVals := []string{}
+		for _, a := range outputs {
+			errVals = append(errVals, goZeroValue(a))
+		}
+		errVals = append(errVals, "err")
+		fmt.Fprintf(w, "\tif err := requireLibvips(\"%s\", %d, %d, 0); err != nil {\n", op.Name, version.Major, version.Minor)
+		fmt.Fprintf(w, "\t\treturn %s\n", strings.Join(errVals, ", "))
+		fmt.Fprintf(w, "\t}\n\n")
+	}
+	traceInput := "nil"
+	for _, a := range reqInputs {
+		if a.Type == ArgTypeImage {
+			traceInput = goArgName(a.Name)
+			break
+		}
+	}
+	fmt.Fprintf(w, "\top := startOp(ctx, \"%s\", %s)\n", op.Name, traceInput)
+	fmt.Fprintf(w, "\tdefer op.end()\n\n")
 
 	// Declare string temp vars for required string inputs.
 	for _, a := range reqInputs {
@@ -676,7 +795,12 @@ func genGoFunc(w *bytes.Buffer, op OpDef) {
 				case ArgTypeImage:
 					fmt.Fprintf(w, "\t\t\tcOpts.%s = opts.%s\n", argName, exportedName)
 				case ArgTypeInterpolate:
-					fmt.Fprintf(w, "\t\t\tcOpts.%s = opts.%s\n", argName, exportedName)
+					fmt.Fprintf(w, "\t\t\tinterp_%s, err := NewInterpolate(*opts.%s)\n", argName, exportedName)
+					fmt.Fprintf(w, "\t\t\tif err != nil {\n")
+					fmt.Fprintf(w, "\t\t\t\treturn %s\n", goErrReturn(outputs, "op.fail(err)"))
+					fmt.Fprintf(w, "\t\t\t}\n")
+					fmt.Fprintf(w, "\t\t\tdefer C.g_object_unref(C.gpointer(unsafe.Pointer(interp_%s)))\n", argName)
+					fmt.Fprintf(w, "\t\t\tcOpts.%s = interp_%s\n", argName, argName)
 				}
 				fmt.Fprintf(w, "\t\t}\n")
 			} else {
@@ -694,7 +818,7 @@ func genGoFunc(w *bytes.Buffer, op OpDef) {
 					fmt.Fprintf(w, "\t\t\tdefer C.free(unsafe.Pointer(tmp_%s))\n", argName)
 					fmt.Fprintf(w, "\t\t\tcOpts.%s = tmp_%s\n", argName, argName)
 				case ArgTypeEnum:
-					fmt.Fprintf(w, "\t\t\tcOpts.%s = C.%s(*opts.%s)\n", argName, a.EnumType, exportedName)
+					fmt.Fprintf(w, "\t\t\tcOpts.%s = C.%s(*opts.%s)\n", argName, cEnumTypeName(a.EnumType), exportedName)
 				default:
 					fmt.Fprintf(w, "\t\t\tcOpts.%s = C.int(*opts.%s)\n", argName, exportedName)
 				}
@@ -721,7 +845,7 @@ func genGoFunc(w *bytes.Buffer, op OpDef) {
 		case ArgTypeString:
 			cArgs = append(cArgs, fmt.Sprintf("cStr_%s", argName))
 		case ArgTypeEnum:
-			cArgs = append(cArgs, fmt.Sprintf("C.%s(%s)", a.EnumType, argName))
+			cArgs = append(cArgs, fmt.Sprintf("C.%s(%s)", cEnumTypeName(a.EnumType), argName))
 		case ArgTypeArrayDouble:
 			cArgs = append(cArgs, fmt.Sprintf("(*C.double)(unsafe.Pointer(&%s[0]))", argName))
 			cArgs = append(cArgs, fmt.Sprintf("C.int(len(%s))", argName))
@@ -752,13 +876,13 @@ func genGoFunc(w *bytes.Buffer, op OpDef) {
 		}
 		imgOut := primaryImageOutput(op)
 		if imgOut != nil {
-			errVals = append(errVals, fmt.Sprintf("handleImageError(out_%s)", goArgName(imgOut.Name)))
+			errVals = append(errVals, fmt.Sprintf("op.fail(handleImageError(out_%s))", goArgName(imgOut.Name)))
 		} else {
-			errVals = append(errVals, "handleVipsError()")
+			errVals = append(errVals, "op.fail(handleVipsError())")
 		}
 		fmt.Fprintf(w, "\t\treturn %s\n", strings.Join(errVals, ", "))
 	} else {
-		fmt.Fprintf(w, "\t\treturn handleVipsError()\n")
+		fmt.Fprintf(w, "\t\treturn op.fail(handleVipsError())\n")
 	}
 	fmt.Fprintf(w, "\t}\n\n")
 
@@ -794,6 +918,15 @@ func genGoFunc(w *bytes.Buffer, op OpDef) {
 	fmt.Fprintf(w, "}\n\n")
 }
 
+// goErrReturn returns the values a bridge with outputs returns on error.
+func goErrReturn(outputs []ArgDef, errExpr string) string {
+	vals := []string{}
+	for _, a := range outputs {
+		vals = append(vals, goZeroValue(a))
+	}
+	return strings.Join(append(vals, errExpr), ", ")
+}
+
 func goZeroValue(a ArgDef) string {
 	switch a.Type {
 	case ArgTypeImage:
@@ -29,6 +29,7 @@ enum {
     ARG_FLAG_OUTPUT   = 1 << 1,
     ARG_FLAG_REQUIRED = 1 << 2,
     ARG_FLAG_MODIFY   = 1 << 3,
+    ARG_FLAG_DEPRECATED = 1 << 4,
 };
 
 static int classify_gtype(GType type) {
@@ -78,6 +79,8 @@ static int convert_flags(VipsArgumentFlags vflags) {
         flags |= ARG_FLAG_REQUIRED;
     if (vflags & VIPS_ARGUMENT_MODIFY)
         flags |= ARG_FLAG_MODIFY;
+    if (vflags & VIPS_ARGUMENT_DEPRECATED)
+        flags |= ARG_FLAG_DEPRECATED;
     return flags;
 }
 
@@ -93,9 +96,9 @@ static void *collect_args(VipsObject *object, GParamSpec *pspec,
     ArgMapData *data = (ArgMapData *)a;
     OpInfo *op = data->op_info;
 
-    // Skip deprecated arguments.
-    if (argument_class->flags & VIPS_ARGUMENT_DEPRECATED)
-        return NULL;
+    // Deprecated arguments are kept and flagged: the generator drops them
+    // from operation wrappers but still exposes them on foreign options,
+    // where they are the only spelling older libvips understands.
 
     // Skip non-construct arguments (internal vips bookkeeping).
     if (!(argument_class->flags & VIPS_ARGUMENT_CONSTRUCT))
@@ -259,40 +262,62 @@ int vipsgen_introspect(IntrospectResult *result) {
     return 0;
 }
 
+static void add_enum_value(EnumInfo *result, const char *c_name, const char *nick, int value) {
+    if (result->n_values >= MAX_ENUM_VALUES)
+        return;
+
+    // Skip the "last" sentinel value that vips uses.
+    if (nick && strcmp(nick, "last") == 0)
+        return;
+
+    EnumValueInfo *ev = &result->values[result->n_values];
+    if (c_name)
+        strncpy(ev->c_name, c_name, sizeof(ev->c_name) - 1);
+    if (nick)
+        strncpy(ev->nick, nick, sizeof(ev->nick) - 1);
+    ev->value = value;
+    result->n_values++;
+}
+
 int vipsgen_introspect_enum(const char *type_name, EnumInfo *result) {
     memset(result, 0, sizeof(EnumInfo));
 
     GType type = g_type_from_name(type_name);
-    if (!type || !G_TYPE_IS_ENUM(type))
+    if (!type)
         return -1;
 
-    GEnumClass *eclass = g_type_class_ref(type);
-    if (!eclass)
-        return -1;
+    if (G_TYPE_IS_ENUM(type)) {
+        GEnumClass *eclass = g_type_class_ref(type);
+        if (!eclass)
+            return -1;
 
-    strncpy(result->c_name, type_name, sizeof(result->c_name) - 1);
+        strncpy(result->c_name, type_name, sizeof(result->c_name) - 1);
+        for (guint i = 0; i < eclass->n_values; i++) {
+            GEnumValue *v = &eclass->values[i];
+            add_enum_value(result, v->value_name, v->value_nick, v->value);
+        }
 
-    for (guint i = 0; i < eclass->n_values; i++) {
-        if (result->n_values >= MAX_ENUM_VALUES)
-            break;
+        g_type_class_unref(eclass);
+        return 0;
+    }
 
-        GEnumValue *v = &eclass->values[i];
+    if (G_TYPE_IS_FLAGS(type)) {
+        GFlagsClass *fclass = g_type_class_ref(type);
+        if (!fclass)
+            return -1;
 
-        // Skip the "last" sentinel value that vips uses.
-        if (v->value_nick && strcmp(v->value_nick, "last") == 0)
-            continue;
+        strncpy(result->c_name, type_name, sizeof(result->c_name) - 1);
+        result->is_flags = 1;
+        for (guint i = 0; i < fclass->n_values; i++) {
+            GFlagsValue *v = &fclass->values[i];
+            add_enum_value(result, v->value_name, v->value_nick, (int)v->value);
+        }
 
-        EnumValueInfo *ev = &result->values[result->n_values];
-        if (v->value_name)
-            strncpy(ev->c_name, v->value_name, sizeof(ev->c_name) - 1);
-        if (v->value_nick)
-            strncpy(ev->nick, v->value_nick, sizeof(ev->nick) - 1);
-        ev->value = v->value;
-        result->n_values++;
+        g_type_class_unref(fclass);
+        return 0;
     }
 
-    g_type_class_unref(eclass);
-    return 0;
+    return -1;
 }
 
 int vipsgen_introspect_enums(const char **enum_names, int n, EnumInfo *results) {
@@ -82,6 +82,7 @@ func IntrospectEnum(typeName string) (*EnumDef, error) {
 
 	def := &EnumDef{
 		CName: C.GoString(&result.c_name[0]),
+		Flags: result.is_flags != 0,
 	}
 
 	for i := 0; i < int(result.n_values); i++ {
@@ -121,6 +122,7 @@ func IntrospectEnums(typeNames []string) ([]EnumDef, error) {
 	for i := range typeNames {
 		r := &results[i]
 		defs[i].CName = C.GoString(&r.c_name[0])
+		defs[i].Flags = r.is_flags != 0
 		for j := 0; j < int(r.n_values); j++ {
 			ev := &r.values[j]
 			defs[i].Values = append(defs[i].Values, EnumValue{
@@ -36,9 +36,11 @@ typedef struct {
     int value;
 } EnumValueInfo;
 
-// Enum type info.
+// Enum type info. Flags types are described the same way, with
+// is_flags set.
 typedef struct {
     char c_name[256];
+    int is_flags;
     int n_values;
     EnumValueInfo values[MAX_ENUM_VALUES];
 } EnumInfo;
@@ -13,6 +13,7 @@ func main() {
 	enumsFlag := flag.Bool("enums", false, "List all discovered enum types")
 	coverageFlag := flag.Bool("coverage", false, "Show coverage report of generated vs hand-written vs missing ops")
 	generateFlag := flag.Bool("generate", false, "Generate C and Go bridge code")
+	checkFlag := flag.Bool("check", false, "Compare the generated files with the installed libvips and report API changes as JSON; exits 1 on drift")
 	outputDir := flag.String("output", "", "Output directory for generated files (default: vips/)")
 	flag.Parse()
 
@@ -37,11 +38,25 @@ func main() {
 		return
 	}
 
-	if *generateFlag {
-		dir := *outputDir
-		if dir == "" {
-			dir = "vips"
+	dir := *outputDir
+	if dir == "" {
+		dir = "vips"
+	}
+
+	if *checkFlag {
+		report, err := Check(ops, dir, os.Stdout)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error checking: %v\n", err)
+			os.Exit(2)
+		}
+		if report.hasDrift() {
+			fmt.Fprintf(os.Stderr, "Generated files in %s do not match the installed libvips; run go generate ./vips/\n", dir)
+			os.Exit(1)
 		}
+		return
+	}
+
+	if *generateFlag {
 		if err := Generate(ops, dir); err != nil {
 			fmt.Fprintf(os.Stderr, "Error generating: %v\n", err)
 			os.Exit(1)
@@ -0,0 +1,338 @@
+package main
+
+import (
+	"bytes"
+	"fmt"
+	"go/ast"
+	"go/parser"
+	"go/token"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// publicMode is how an operation is exposed in the public layer.
+type publicMode int
+
+const (
+	// publicMutate replaces the receiver's image with the result.
+	publicMutate publicMode = iota
+	// publicReturn returns the result as a new *ImageRef.
+	publicReturn
+	// publicCreate is a package-level constructor for create operations.
+	publicCreate
+)
+
+// publicOpDef is an operation resolved for the public layer.
+type publicOpDef struct {
+	op   OpDef
+	name string
+	mode publicMode
+	// receiver is the required input bound to the receiver, nil for
+	// constructors.
+	receiver *ArgDef
+	params   []ArgDef
+}
+
+// handWritten holds the lower-cased names declared by the hand-written
+// files of the vips package, so that generated names never clash with,
+// or differ only in case from, an existing method or identifier.
+type handWritten struct {
+	methods map[string]bool
+	names   map[string]bool
+}
+
+// scanHandWritten parses the non-generated, non-test Go files in dir.
+func scanHandWritten(dir string) (handWritten, error) {
+	hw := handWritten{methods: map[string]bool{}, names: map[string]bool{}}
+
+	files, err := filepath.Glob(filepath.Join(dir, "*.go"))
+	if err != nil {
+		return hw, err
+	}
+	fset := token.NewFileSet()
+	for _, path := range files {
+		if strings.HasSuffix(path, "_test.go") {
+			continue
+		}
+		src, err := os.ReadFile(path)
+		if err != nil {
+			return hw, err
+		}
+		if bytes.HasPrefix(src, []byte("// Code generated")) {
+			continue
+		}
+		f, err := parser.ParseFile(fset, path, src, parser.SkipObjectResolution)
+		if err != nil {
+			return hw, err
+		}
+		for _, decl := range f.Decls {
+			switch d := decl.(type) {
+			case *ast.FuncDecl:
+				if d.Recv != nil {
+					hw.methods[strings.ToLower(d.Name.Name)] = true
+				} else {
+					hw.names[strings.ToLower(d.Name.Name)] = true
+				}
+			case *ast.GenDecl:
+				for _, spec := range d.Specs {
+					switch s := spec.(type) {
+					case *ast.TypeSpec:
+						hw.names[strings.ToLower(s.Name.Name)] = true
+					case *ast.ValueSpec:
+						for _, n := range s.Names {
+							hw.names[strings.ToLower(n.Name)] = true
+						}
+					}
+				}
+			}
+		}
+	}
+	return hw, nil
+}
+
+// isPublicArgType returns true if a required input of type t can be
+// passed through an exported signature.
+func isPublicArgType(t ArgType) bool {
+	switch t {
+	case ArgTypeImage, ArgTypeArrayImage, ArgTypeDouble, ArgTypeInt, ArgTypeBool,
+		ArgTypeString, ArgTypeEnum, ArgTypeFlags, ArgTypeArrayDouble,
+		ArgTypeArrayInt, ArgTypeBlob:
+		return true
+	}
+	return false
+}
+
+// resolvePublicOps decides the exported name and mode of every operation,
+// leaving out those that are skipped by publicOps, clash with a
+// hand-written name or have arguments the public layer can't express.
+func resolvePublicOps(ops []OpDef, hw handWritten) []publicOpDef {
+	taken := map[string]bool{}
+	var result []publicOpDef
+	for _, op := range ops {
+		cfg := publicOps[op.Name]
+		if cfg.Skip || !hasOutputs(op) {
+			continue
+		}
+
+		supported := true
+		for _, a := range op.RequiredInputs() {
+			if !isPublicArgType(a.Type) {
+				supported = false
+			}
+		}
+		if !supported {
+			continue
+		}
+
+		def := publicOpDef{op: op, name: cfg.Name}
+		if def.name == "" {
+			def.name = goName(op.Name)
+		}
+		for _, a := range op.RequiredInputs() {
+			a := a
+			if def.receiver == nil && (a.Type == ArgTypeImage || a.Type == ArgTypeArrayImage) {
+				def.receiver = &a
+				continue
+			}
+			def.params = append(def.params, a)
+		}
+
+		imageOutputs := 0
+		for _, a := range op.Outputs() {
+			if a.Type == ArgTypeImage {
+				imageOutputs++
+			}
+		}
+		switch {
+		case def.receiver == nil:
+			if imageOutputs == 0 {
+				continue
+			}
+			def.mode = publicCreate
+		case imageOutputs == 1 && !cfg.Returns:
+			def.mode = publicMutate
+		default:
+			def.mode = publicReturn
+		}
+
+		key := strings.ToLower(def.name)
+		if def.mode == publicCreate {
+			if hw.names[key] || taken["func "+key] {
+				continue
+			}
+			taken["func "+key] = true
+		} else {
+			if hw.methods[key] || taken["method "+key] {
+				continue
+			}
+			taken["method "+key] = true
+		}
+		result = append(result, def)
+	}
+	return result
+}
+
+// genGoPublic generates the exported ImageRef methods and constructors
+// that wrap the vipsGen* bridges.
+func genGoPublic(defs []publicOpDef) []byte {
+	var w bytes.Buffer
+	fmt.Fprintf(&w, "// Code generated by vipsgen. DO NOT EDIT.\n")
+	fmt.Fprintf(&w, "package vips\n\n")
+	fmt.Fprintf(&w, "// #include <vips/vips.h>\n")
+	fmt.Fprintf(&w, "import \"C\"\n\n")
+	fmt.Fprintf(&w, "import (\n\t\"context\"\n\t\"runtime\"\n)\n\n")
+	fmt.Fprintf(&w, "// Ensure imports are used.\n")
+	fmt.Fprintf(&w, "var _ = context.Background\n\n")
+
+	for _, def := range defs {
+		genGoPublicFunc(&w, def)
+	}
+	return bytes.TrimSuffix(w.Bytes(), []byte("\n"))
+}
+
+func genGoPublicFunc(w *bytes.Buffer, def publicOpDef) {
+	op := def.op
+	hasOpts := len(op.OptionalInputs()) > 0
+
+	// Doc comment.
+	switch def.mode {
+	case publicMutate:
+		fmt.Fprintf(w, "// %s applies the vips %s operation to the image, replacing it.\n", def.name, op.Name)
+	case publicReturn:
+		fmt.Fprintf(w, "// %s returns the result of the vips %s operation on the image.\n", def.name, op.Name)
+	case publicCreate:
+		fmt.Fprintf(w, "// %s creates an image with the vips %s operation.\n", def.name, op.Name)
+	}
+	fmt.Fprintf(w, "// %s\n", op.Description)
+	if version, ok := versionIntroduced[op.Name]; ok {
+		fmt.Fprintf(w, "// Requires libvips %d.%d+; returns ErrUnsupportedByLibvips otherwise.\n", version.Major, version.Minor)
+	}
+
+	// Signature.
+	params := []string{}
+	for _, a := range def.params {
+		params = append(params, fmt.Sprintf("%s %s", goArgName(a.Name), publicTypeName(a)))
+	}
+	if def.receiver != nil && def.receiver.Type == ArgTypeArrayImage {
+		params = append([]string{"images []*ImageRef"}, params...)
+	}
+	if hasOpts {
+		params = append(params, fmt.Sprintf("opts *%s", goOptsTypeName(op.Name)))
+	}
+
+	var rets, zeros []string
+	for _, a := range op.Outputs() {
+		if a.Type == ArgTypeImage && def.mode == publicMutate {
+			continue
+		}
+		rets = append(rets, publicTypeName(a))
+		zeros = append(zeros, goZeroValue(a))
+	}
+	rets = append(rets, "error")
+	zeros = append(zeros, "err")
+	retSig := strings.Join(rets, ", ")
+	if len(rets) > 1 {
+		retSig = "(" + retSig + ")"
+	}
+
+	if def.mode == publicCreate {
+		fmt.Fprintf(w, "func %s(%s) %s {\n", def.name, strings.Join(params, ", "), retSig)
+	} else {
+		fmt.Fprintf(w, "func (r *ImageRef) %s(%s) %s {\n", def.name, strings.Join(params, ", "), retSig)
+		fmt.Fprintf(w, "\tdefer runtime.KeepAlive(r)\n")
+	}
+
+	// Keep the image arguments alive and convert image arrays.
+	for _, a := range def.params {
+		if a.Type == ArgTypeImage || a.Type == ArgTypeArrayImage {
+			fmt.Fprintf(w, "\tdefer runtime.KeepAlive(%s)\n", goArgName(a.Name))
+		}
+	}
+	if def.receiver != nil && def.receiver.Type == ArgTypeArrayImage {
+		fmt.Fprintf(w, "\tdefer runtime.KeepAlive(images)\n")
+		fmt.Fprintf(w, "\tvipsImages := []*C.VipsImage{r.image}\n")
+		fmt.Fprintf(w, "\tfor _, img := range images {\n")
+		fmt.Fprintf(w, "\t\tvipsImages = append(vipsImages, img.image)\n")
+		fmt.Fprintf(w, "\t}\n")
+	}
+	for _, a := range def.params {
+		if a.Type == ArgTypeArrayImage {
+			name := goArgName(a.Name)
+			fmt.Fprintf(w, "\tvips%s := make([]*C.VipsImage, len(%s))\n", goName(a.Name), name)
+			fmt.Fprintf(w, "\tfor i, img := range %s {\n", name)
+			fmt.Fprintf(w, "\t\tvips%s[i] = img.image\n", goName(a.Name))
+			fmt.Fprintf(w, "\t}\n")
+		}
+	}
+
+	// Bridge call.
+	ctx := "r.Context()"
+	if def.mode == publicCreate {
+		ctx = "context.Background()"
+	}
+	args := []string{ctx}
+	for _, a := range op.RequiredInputs() {
+		name := goArgName(a.Name)
+		switch {
+		case def.receiver != nil && a.Name == def.receiver.Name:
+			if a.Type == ArgTypeArrayImage {
+				args = append(args, "vipsImages")
+			} else {
+				args = append(args, "r.image")
+			}
+		case a.Type == ArgTypeImage:
+			args = append(args, name+".image")
+		case a.Type == ArgTypeArrayImage:
+			args = append(args, "vips"+goName(a.Name))
+		default:
+			args = append(args, name)
+		}
+	}
+	if hasOpts {
+		args = append(args, "opts")
+	}
+	outs := []string{}
+	for _, a := range op.Outputs() {
+		outs = append(outs, goArgName(a.Name))
+	}
+	outs = append(outs, "err")
+	fmt.Fprintf(w, "\t%s := %s(%s)\n", strings.Join(outs, ", "), goFuncName(op.Name), strings.Join(args, ", "))
+	fmt.Fprintf(w, "\tif err != nil {\n")
+	fmt.Fprintf(w, "\t\treturn %s\n", strings.Join(zeros, ", "))
+	fmt.Fprintf(w, "\t}\n")
+
+	// Results.
+	results := []string{}
+	for _, a := range op.Outputs() {
+		name := goArgName(a.Name)
+		if a.Type != ArgTypeImage {
+			results = append(results, name)
+			continue
+		}
+		switch def.mode {
+		case publicMutate:
+			fmt.Fprintf(w, "\tr.setImage(%s)\n", name)
+		case publicReturn:
+			fmt.Fprintf(w, "\t%sRef := newImageRef(%s, r.format, r.originalFormat, nil)\n", name, name)
+			fmt.Fprintf(w, "\t%sRef.ctx = r.ctx\n", name)
+			results = append(results, name+"Ref")
+		case publicCreate:
+			results = append(results, fmt.Sprintf("newImageRef(%s, ImageTypeUnknown, ImageTypeUnknown, nil)", name))
+		}
+	}
+	results = append(results, "nil")
+	fmt.Fprintf(w, "\treturn %s\n", strings.Join(results, ", "))
+	fmt.Fprintf(w, "}\n\n")
+}
+
+// publicTypeName is goTypeName with images as *ImageRef.
+func publicTypeName(arg ArgDef) string {
+	switch arg.Type {
+	case ArgTypeImage:
+		return "*ImageRef"
+	case ArgTypeArrayImage:
+		return "[]*ImageRef"
+	}
+	return goTypeName(arg)
+}
@@ -8,6 +8,10 @@ const (
 	ArgOutput   ArgFlags = 1 << 1
 	ArgRequired ArgFlags = 1 << 2
 	ArgModify   ArgFlags = 1 << 3
+
+	// ArgDeprecated marks an argument libvips keeps only for
+	// compatibility, such as the "strip" option of the savers.
+	ArgDeprecated ArgFlags = 1 << 4
 )
 
 // ArgType represents the GType category of an argument.
@@ -58,6 +62,11 @@ func (a *ArgDef) IsRequired() bool {
 	return a.Flags&ArgRequired != 0
 }
 
+// IsDeprecated returns true if libvips marks this argument deprecated.
+func (a *ArgDef) IsDeprecated() bool {
+	return a.Flags&ArgDeprecated != 0
+}
+
 // OpDef describes a single vips operation discovered by introspection.
 type OpDef struct {
 	Name        string   // vips operation name (e.g. "gaussblur", "resize")
@@ -106,8 +115,9 @@ type EnumValue struct {
 	Value int
 }
 
-// EnumDef describes a vips enum type.
+// EnumDef describes a vips enum or flags type.
 type EnumDef struct {
 	CName  string      // e.g. "VipsKernel"
+	Flags  bool        // a GFlags type, whose values combine with |
 	Values []EnumValue // enum members
 }
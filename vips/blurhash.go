@@ -0,0 +1,213 @@
+package vips
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"strings"
+)
+
+// BlurHash encoding and decoding, following the reference implementation at
+// https://github.com/woltapp/blurhash. The codec works on packed 8-bit sRGB
+// pixels; the ImageRef entry points in placeholder.go take care of getting an
+// image into that shape.
+
+const blurHashCharacters = "0123456789ABCDEFGHIJKLMNOPQRSTUVWXYZabcdefghijklmnopqrstuvwxyz#$%*+,-.:;=?@[]^_{|}~"
+
+// ErrInvalidBlurHash is returned when a BlurHash string cannot be decoded.
+var ErrInvalidBlurHash = errors.New("invalid blurhash")
+
+// blurHashEncode encodes packed RGB pixels (3 bytes per pixel, no padding).
+func blurHashEncode(pixels []byte, width, height, xComponents, yComponents int) (string, error) {
+	if xComponents < 1 || xComponents > 9 || yComponents < 1 || yComponents > 9 {
+		return "", fmt.Errorf("blurhash components must be between 1 and 9, got %dx%d", xComponents, yComponents)
+	}
+	if width <= 0 || height <= 0 || len(pixels) < width*height*3 {
+		return "", fmt.Errorf("blurhash: invalid pixel buffer for %dx%d image", width, height)
+	}
+
+	// Linearise once up front instead of per component.
+	linear := make([]float64, width*height*3)
+	for i := range linear {
+		linear[i] = sRGBToLinear(pixels[i])
+	}
+
+	factors := make([][3]float64, 0, xComponents*yComponents)
+	cosX := make([]float64, width)
+	for y := 0; y < yComponents; y++ {
+		for x := 0; x < xComponents; x++ {
+			normalisation := 2.0
+			if x == 0 && y == 0 {
+				normalisation = 1
+			}
+			for i := 0; i < width; i++ {
+				cosX[i] = math.Cos(math.Pi * float64(x) * float64(i) / float64(width))
+			}
+			var r, g, b float64
+			for j := 0; j < height; j++ {
+				cosY := math.Cos(math.Pi * float64(y) * float64(j) / float64(height))
+				row := linear[j*width*3:]
+				for i := 0; i < width; i++ {
+					basis := cosX[i] * cosY
+					r += basis * row[i*3]
+					g += basis * row[i*3+1]
+					b += basis * row[i*3+2]
+				}
+			}
+			scale := normalisation / float64(width*height)
+			factors = append(factors, [3]float64{r * scale, g * scale, b * scale})
+		}
+	}
+
+	var sb strings.Builder
+	sb.Grow(4 + 2*len(factors))
+	encode83(&sb, (xComponents-1)+(yComponents-1)*9, 1)
+
+	maximumValue := 1.0
+	if ac := factors[1:]; len(ac) > 0 {
+		actualMax := 0.0
+		for _, f := range ac {
+			actualMax = math.Max(actualMax, math.Max(math.Abs(f[0]), math.Max(math.Abs(f[1]), math.Abs(f[2]))))
+		}
+		quantisedMax := int(math.Max(0, math.Min(82, math.Floor(actualMax*166-0.5))))
+		maximumValue = float64(quantisedMax+1) / 166
+		encode83(&sb, quantisedMax, 1)
+	} else {
+		encode83(&sb, 0, 1)
+	}
+
+	dc := factors[0]
+	encode83(&sb, linearToSRGB(dc[0])<<16|linearToSRGB(dc[1])<<8|linearToSRGB(dc[2]), 4)
+
+	for _, f := range factors[1:] {
+		quant := func(v float64) int {
+			return int(math.Max(0, math.Min(18, math.Floor(signPow(v/maximumValue, 0.5)*9+9.5))))
+		}
+		encode83(&sb, quant(f[0])*19*19+quant(f[1])*19+quant(f[2]), 2)
+	}
+
+	return sb.String(), nil
+}
+
+// blurHashDecode renders hash into packed RGB pixels of the given size.
+// punch scales the contrast of the AC components; 1 is the reference look.
+func blurHashDecode(hash string, width, height int, punch float64) ([]byte, error) {
+	if len(hash) < 6 {
+		return nil, fmt.Errorf("%w: too short", ErrInvalidBlurHash)
+	}
+	if width <= 0 || height <= 0 {
+		return nil, fmt.Errorf("blurhash: invalid dimensions %dx%d", width, height)
+	}
+	if punch <= 0 {
+		punch = 1
+	}
+
+	sizeFlag, err := decode83(hash[0:1])
+	if err != nil {
+		return nil, err
+	}
+	numY := sizeFlag/9 + 1
+	numX := sizeFlag%9 + 1
+	if len(hash) != 4+2*numX*numY {
+		return nil, fmt.Errorf("%w: expected length %d, got %d", ErrInvalidBlurHash, 4+2*numX*numY, len(hash))
+	}
+
+	quantisedMax, err := decode83(hash[1:2])
+	if err != nil {
+		return nil, err
+	}
+	maximumValue := float64(quantisedMax+1) / 166 * punch
+
+	colors := make([][3]float64, numX*numY)
+	dc, err := decode83(hash[2:6])
+	if err != nil {
+		return nil, err
+	}
+	colors[0] = [3]float64{
+		sRGBToLinear(uint8(dc >> 16)),
+		sRGBToLinear(uint8(dc >> 8)),
+		sRGBToLinear(uint8(dc)),
+	}
+	for i := 1; i < len(colors); i++ {
+		v, err := decode83(hash[4+i*2 : 6+i*2])
+		if err != nil {
+			return nil, err
+		}
+		colors[i] = [3]float64{
+			signPow(float64(v/(19*19)-9)/9, 2) * maximumValue,
+			signPow(float64((v/19)%19-9)/9, 2) * maximumValue,
+			signPow(float64(v%19-9)/9, 2) * maximumValue,
+		}
+	}
+
+	pixels := make([]byte, width*height*3)
+	cosX := make([]float64, width*numX)
+	for i := 0; i < numX; i++ {
+		for x := 0; x < width; x++ {
+			cosX[i*width+x] = math.Cos(math.Pi * float64(x) * float64(i) / float64(width))
+		}
+	}
+	cosY := make([]float64, numY)
+	for y := 0; y < height; y++ {
+		for j := 0; j < numY; j++ {
+			cosY[j] = math.Cos(math.Pi * float64(y) * float64(j) / float64(height))
+		}
+		for x := 0; x < width; x++ {
+			var r, g, b float64
+			for j := 0; j < numY; j++ {
+				for i := 0; i < numX; i++ {
+					basis := cosX[i*width+x] * cosY[j]
+					c := colors[i+j*numX]
+					r += c[0] * basis
+					g += c[1] * basis
+					b += c[2] * basis
+				}
+			}
+			p := (y*width + x) * 3
+			pixels[p] = uint8(linearToSRGB(r))
+			pixels[p+1] = uint8(linearToSRGB(g))
+			pixels[p+2] = uint8(linearToSRGB(b))
+		}
+	}
+
+	return pixels, nil
+}
+
+func encode83(sb *strings.Builder, value, length int) {
+	for i := 1; i <= length; i++ {
+		digit := (value / int(math.Pow(83, float64(length-i)))) % 83
+		sb.WriteByte(blurHashCharacters[digit])
+	}
+}
+
+func decode83(s string) (int, error) {
+	value := 0
+	for i := 0; i < len(s); i++ {
+		idx := strings.IndexByte(blurHashCharacters, s[i])
+		if idx < 0 {
+			return 0, fmt.Errorf("%w: unexpected character %q", ErrInvalidBlurHash, s[i])
+		}
+		value = value*83 + idx
+	}
+	return value, nil
+}
+
+func sRGBToLinear(v uint8) float64 {
+	f := float64(v) / 255
+	if f <= 0.04045 {
+		return f / 12.92
+	}
+	return math.Pow((f+0.055)/1.055, 2.4)
+}
+
+func linearToSRGB(v float64) int {
+	v = math.Max(0, math.Min(1, v))
+	if v <= 0.0031308 {
+		return int(v*12.92*255 + 0.5)
+	}
+	return int((1.055*math.Pow(v, 1/2.4)-0.055)*255 + 0.5)
+}
+
+func signPow(v, exp float64) float64 {
+	return math.Copysign(math.Pow(math.Abs(v), exp), v)
+}
@@ -0,0 +1,425 @@
+#include "call.h"
+
+#include <string.h>
+
+static int classify_type(GType type) {
+  if (g_type_is_a(type, VIPS_TYPE_IMAGE)) return CALL_ARG_IMAGE;
+  if (g_type_is_a(type, VIPS_TYPE_BLOB)) return CALL_ARG_BLOB;
+  if (g_type_is_a(type, VIPS_TYPE_ARRAY_DOUBLE)) return CALL_ARG_ARRAY_DOUBLE;
+  if (g_type_is_a(type, VIPS_TYPE_ARRAY_INT)) return CALL_ARG_ARRAY_INT;
+  if (g_type_is_a(type, VIPS_TYPE_ARRAY_IMAGE)) return CALL_ARG_ARRAY_IMAGE;
+  if (G_TYPE_IS_ENUM(type)) return CALL_ARG_ENUM;
+  if (G_TYPE_IS_FLAGS(type)) return CALL_ARG_FLAGS;
+
+  switch (G_TYPE_FUNDAMENTAL(type)) {
+    case G_TYPE_DOUBLE:
+    case G_TYPE_FLOAT:
+      return CALL_ARG_DOUBLE;
+    case G_TYPE_INT:
+    case G_TYPE_UINT:
+    case G_TYPE_INT64:
+    case G_TYPE_UINT64:
+    case G_TYPE_LONG:
+    case G_TYPE_ULONG:
+      return CALL_ARG_INT;
+    case G_TYPE_BOOLEAN:
+      return CALL_ARG_BOOL;
+    case G_TYPE_STRING:
+      return CALL_ARG_STRING;
+  }
+
+  // Interpolators, sources and targets have no Go representation here.
+  return CALL_ARG_UNSUPPORTED;
+}
+
+VipsOperation *call_new_operation(const char *name) {
+  return vips_operation_new(name);
+}
+
+void call_free_operation(VipsOperation *operation) {
+  vips_object_unref_outputs(VIPS_OBJECT(operation));
+  g_object_unref(operation);
+}
+
+int call_build(VipsOperation **operation) {
+  VipsOperation *built = vips_cache_operation_build(*operation);
+  if (!built) return -1;
+
+  g_object_unref(*operation);
+  *operation = built;
+  return 0;
+}
+
+typedef struct {
+  CallArg *args;
+  int max;
+  int n;
+} CallArgList;
+
+static void *collect_arg(VipsObject *object, GParamSpec *pspec,
+                         VipsArgumentClass *argument_class,
+                         VipsArgumentInstance *argument_instance, void *a,
+                         void *b) {
+  CallArgList *list = (CallArgList *)a;
+
+  // Only construct arguments can be set before the operation is built.
+  if (!(argument_class->flags & VIPS_ARGUMENT_CONSTRUCT)) return NULL;
+
+  if (list->n < list->max) {
+    CallArg *arg = &list->args[list->n];
+    arg->name = g_param_spec_get_name(pspec);
+    arg->blurb = g_param_spec_get_blurb(pspec);
+    arg->kind = classify_type(G_PARAM_SPEC_VALUE_TYPE(pspec));
+    arg->value_type = G_PARAM_SPEC_VALUE_TYPE(pspec);
+    arg->flags = argument_class->flags;
+    arg->priority = argument_class->priority;
+  }
+  list->n++;
+
+  return NULL;
+}
+
+int call_args(VipsOperation *operation, CallArg *args, int max) {
+  CallArgList list = {args, max, 0};
+  vips_argument_map(VIPS_OBJECT(operation), collect_arg, &list, NULL);
+  return list.n;
+}
+
+static GParamSpec *find_pspec(VipsOperation *operation, const char *name) {
+  GParamSpec *pspec =
+      g_object_class_find_property(G_OBJECT_GET_CLASS(operation), name);
+  if (!pspec)
+    vips_error(VIPS_OBJECT_GET_CLASS(operation)->nickname,
+               "no argument \"%s\"", name);
+  return pspec;
+}
+
+// set_value validates value against the argument before setting it, since
+// GLib only logs a warning for an out of range value and drops it.
+static int set_value(VipsOperation *operation, GParamSpec *pspec,
+                     GValue *value) {
+  if (g_param_value_validate(pspec, value)) {
+    vips_error(VIPS_OBJECT_GET_CLASS(operation)->nickname,
+               "value out of range for \"%s\"", g_param_spec_get_name(pspec));
+    g_value_unset(value);
+    return -1;
+  }
+
+  g_object_set_property(G_OBJECT(operation), g_param_spec_get_name(pspec),
+                        value);
+  g_value_unset(value);
+  return 0;
+}
+
+int call_set_int(VipsOperation *operation, const char *name, gint64 v) {
+  GParamSpec *pspec = find_pspec(operation, name);
+  if (!pspec) return -1;
+
+  GValue value = G_VALUE_INIT;
+  g_value_init(&value, G_PARAM_SPEC_VALUE_TYPE(pspec));
+  switch (G_TYPE_FUNDAMENTAL(G_PARAM_SPEC_VALUE_TYPE(pspec))) {
+    case G_TYPE_INT:
+      g_value_set_int(&value, (gint)v);
+      break;
+    case G_TYPE_UINT:
+      g_value_set_uint(&value, (guint)v);
+      break;
+    case G_TYPE_INT64:
+      g_value_set_int64(&value, v);
+      break;
+    case G_TYPE_UINT64:
+      g_value_set_uint64(&value, (guint64)v);
+      break;
+    case G_TYPE_LONG:
+      g_value_set_long(&value, (glong)v);
+      break;
+    case G_TYPE_ULONG:
+      g_value_set_ulong(&value, (gulong)v);
+      break;
+    case G_TYPE_BOOLEAN:
+      g_value_set_boolean(&value, v != 0);
+      break;
+    case G_TYPE_ENUM:
+      g_value_set_enum(&value, (gint)v);
+      break;
+    case G_TYPE_FLAGS:
+      g_value_set_flags(&value, (guint)v);
+      break;
+    case G_TYPE_DOUBLE:
+      g_value_set_double(&value, (double)v);
+      break;
+    case G_TYPE_FLOAT:
+      g_value_set_float(&value, (float)v);
+      break;
+    default:
+      g_value_unset(&value);
+      vips_error(VIPS_OBJECT_GET_CLASS(operation)->nickname,
+                 "\"%s\" is not a number", name);
+      return -1;
+  }
+
+  return set_value(operation, pspec, &value);
+}
+
+int call_set_double(VipsOperation *operation, const char *name, double v) {
+  GParamSpec *pspec = find_pspec(operation, name);
+  if (!pspec) return -1;
+
+  GValue value = G_VALUE_INIT;
+  g_value_init(&value, G_PARAM_SPEC_VALUE_TYPE(pspec));
+  switch (G_TYPE_FUNDAMENTAL(G_PARAM_SPEC_VALUE_TYPE(pspec))) {
+    case G_TYPE_DOUBLE:
+      g_value_set_double(&value, v);
+      break;
+    case G_TYPE_FLOAT:
+      g_value_set_float(&value, (float)v);
+      break;
+    default:
+      g_value_unset(&value);
+      vips_error(VIPS_OBJECT_GET_CLASS(operation)->nickname,
+                 "\"%s\" is not a double", name);
+      return -1;
+  }
+
+  return set_value(operation, pspec, &value);
+}
+
+int call_set_string(VipsOperation *operation, const char *name,
+                    const char *v) {
+  GParamSpec *pspec = find_pspec(operation, name);
+  if (!pspec) return -1;
+
+  GValue value = G_VALUE_INIT;
+  g_value_init(&value, G_TYPE_STRING);
+  g_value_set_string(&value, v);
+  return set_value(operation, pspec, &value);
+}
+
+int call_set_image(VipsOperation *operation, const char *name,
+                   VipsImage *v) {
+  if (!find_pspec(operation, name)) return -1;
+
+  g_object_set(operation, name, v, NULL);
+  return 0;
+}
+
+int call_set_array_double(VipsOperation *operation, const char *name,
+                          const double *values, int n) {
+  if (!find_pspec(operation, name)) return -1;
+
+  VipsArrayDouble *array = vips_array_double_new(values, n);
+  g_object_set(operation, name, array, NULL);
+  vips_area_unref(VIPS_AREA(array));
+  return 0;
+}
+
+int call_set_array_int(VipsOperation *operation, const char *name,
+                       const int *values, int n) {
+  if (!find_pspec(operation, name)) return -1;
+
+  VipsArrayInt *array = vips_array_int_new(values, n);
+  g_object_set(operation, name, array, NULL);
+  vips_area_unref(VIPS_AREA(array));
+  return 0;
+}
+
+int call_set_array_image(VipsOperation *operation, const char *name,
+                         VipsImage **values, int n) {
+  if (!find_pspec(operation, name)) return -1;
+
+  VipsArrayImage *array = vips_array_image_new(values, n);
+  g_object_set(operation, name, array, NULL);
+  vips_area_unref(VIPS_AREA(array));
+  return 0;
+}
+
+int call_set_blob(VipsOperation *operation, const char *name,
+                  const void *data, size_t length) {
+  if (!find_pspec(operation, name)) return -1;
+
+  VipsBlob *blob = vips_blob_copy(data, length);
+  g_object_set(operation, name, blob, NULL);
+  vips_area_unref(VIPS_AREA(blob));
+  return 0;
+}
+
+int call_enum_from_nick(GType type, const char *nick, int *value) {
+  int v;
+  if (G_TYPE_IS_FLAGS(type))
+    v = vips_flags_from_nick("call", type, nick);
+  else
+    v = vips_enum_from_nick("call", type, nick);
+  if (v < 0) return -1;
+
+  *value = v;
+  return 0;
+}
+
+static int get_value(VipsOperation *operation, const char *name,
+                     GValue *value) {
+  GParamSpec *pspec = find_pspec(operation, name);
+  if (!pspec) return -1;
+
+  g_value_init(value, G_PARAM_SPEC_VALUE_TYPE(pspec));
+  g_object_get_property(G_OBJECT(operation), name, value);
+  return 0;
+}
+
+int call_get_int(VipsOperation *operation, const char *name, gint64 *v) {
+  GValue value = G_VALUE_INIT;
+  if (get_value(operation, name, &value)) return -1;
+
+  int result = 0;
+  switch (G_TYPE_FUNDAMENTAL(G_VALUE_TYPE(&value))) {
+    case G_TYPE_INT:
+      *v = g_value_get_int(&value);
+      break;
+    case G_TYPE_UINT:
+      *v = g_value_get_uint(&value);
+      break;
+    case G_TYPE_INT64:
+      *v = g_value_get_int64(&value);
+      break;
+    case G_TYPE_UINT64:
+      *v = (gint64)g_value_get_uint64(&value);
+      break;
+    case G_TYPE_LONG:
+      *v = g_value_get_long(&value);
+      break;
+    case G_TYPE_ULONG:
+      *v = (gint64)g_value_get_ulong(&value);
+      break;
+    case G_TYPE_BOOLEAN:
+      *v = g_value_get_boolean(&value);
+      break;
+    case G_TYPE_ENUM:
+      *v = g_value_get_enum(&value);
+      break;
+    case G_TYPE_FLAGS:
+      *v = g_value_get_flags(&value);
+      break;
+    default:
+      vips_error(VIPS_OBJECT_GET_CLASS(operation)->nickname,
+                 "\"%s\" is not a number", name);
+      result = -1;
+  }
+
+  g_value_unset(&value);
+  return result;
+}
+
+int call_get_double(VipsOperation *operation, const char *name, double *v) {
+  GValue value = G_VALUE_INIT;
+  if (get_value(operation, name, &value)) return -1;
+
+  int result = 0;
+  switch (G_TYPE_FUNDAMENTAL(G_VALUE_TYPE(&value))) {
+    case G_TYPE_DOUBLE:
+      *v = g_value_get_double(&value);
+      break;
+    case G_TYPE_FLOAT:
+      *v = g_value_get_float(&value);
+      break;
+    default:
+      vips_error(VIPS_OBJECT_GET_CLASS(operation)->nickname,
+                 "\"%s\" is not a double", name);
+      result = -1;
+  }
+
+  g_value_unset(&value);
+  return result;
+}
+
+int call_get_string(VipsOperation *operation, const char *name, char **v) {
+  GValue value = G_VALUE_INIT;
+  if (get_value(operation, name, &value)) return -1;
+
+  *v = g_value_dup_string(&value);
+  g_value_unset(&value);
+  return 0;
+}
+
+int call_get_image(VipsOperation *operation, const char *name,
+                   VipsImage **v) {
+  GValue value = G_VALUE_INIT;
+  if (get_value(operation, name, &value)) return -1;
+
+  *v = VIPS_IMAGE(g_value_dup_object(&value));
+  g_value_unset(&value);
+  return 0;
+}
+
+// The array and blob getters return copies the caller frees with g_free.
+// Images in an image array are returned with a reference each.
+
+int call_get_array_double(VipsOperation *operation, const char *name,
+                          double **values, int *n) {
+  GValue value = G_VALUE_INIT;
+  if (get_value(operation, name, &value)) return -1;
+
+  *n = 0;
+  *values = NULL;
+  VipsArrayDouble *array = g_value_get_boxed(&value);
+  if (array) {
+    double *data = vips_array_double_get(array, n);
+    *values = g_malloc(*n * sizeof(double));
+    memcpy(*values, data, *n * sizeof(double));
+  }
+
+  g_value_unset(&value);
+  return 0;
+}
+
+int call_get_array_int(VipsOperation *operation, const char *name,
+                       int **values, int *n) {
+  GValue value = G_VALUE_INIT;
+  if (get_value(operation, name, &value)) return -1;
+
+  *n = 0;
+  *values = NULL;
+  VipsArrayInt *array = g_value_get_boxed(&value);
+  if (array) {
+    int *data = vips_array_int_get(array, n);
+    *values = g_malloc(*n * sizeof(int));
+    memcpy(*values, data, *n * sizeof(int));
+  }
+
+  g_value_unset(&value);
+  return 0;
+}
+
+int call_get_array_image(VipsOperation *operation, const char *name,
+                         VipsImage ***values, int *n) {
+  GValue value = G_VALUE_INIT;
+  if (get_value(operation, name, &value)) return -1;
+
+  *n = 0;
+  *values = NULL;
+  VipsArrayImage *array = g_value_get_boxed(&value);
+  if (array) {
+    VipsImage **data = vips_array_image_get(array, n);
+    *values = g_malloc(*n * sizeof(VipsImage *));
+    for (int i = 0; i < *n; i++) (*values)[i] = g_object_ref(data[i]);
+  }
+
+  g_value_unset(&value);
+  return 0;
+}
+
+int call_get_blob(VipsOperation *operation, const char *name, void **data,
+                  size_t *length) {
+  GValue value = G_VALUE_INIT;
+  if (get_value(operation, name, &value)) return -1;
+
+  *length = 0;
+  *data = NULL;
+  VipsBlob *blob = g_value_get_boxed(&value);
+  if (blob) {
+    const void *bytes = vips_blob_get(blob, length);
+    *data = g_malloc(*length);
+    memcpy(*data, bytes, *length);
+  }
+
+  g_value_unset(&value);
+  return 0;
+}
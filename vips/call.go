@@ -0,0 +1,575 @@
+package vips
+
+// #include "call.h"
+import "C"
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+	"runtime"
+	"sort"
+	"unsafe"
+)
+
+// Argument kinds, matching the CALL_ARG constants in call.h.
+const (
+	callArgUnsupported = iota
+	callArgImage
+	callArgDouble
+	callArgInt
+	callArgBool
+	callArgString
+	callArgEnum
+	callArgFlags
+	callArgArrayDouble
+	callArgArrayInt
+	callArgArrayImage
+	callArgBlob
+)
+
+// callArg is an argument of an operation, as libvips reports it at runtime.
+type callArg struct {
+	name        string
+	description string
+	kind        int
+	valueType   C.GType
+	flags       C.VipsArgumentFlags
+	priority    int
+}
+
+func (a callArg) isInput() bool    { return a.flags&C.VIPS_ARGUMENT_INPUT != 0 }
+func (a callArg) isOutput() bool   { return a.flags&C.VIPS_ARGUMENT_OUTPUT != 0 }
+func (a callArg) isRequired() bool { return a.flags&C.VIPS_ARGUMENT_REQUIRED != 0 }
+func (a callArg) isModify() bool   { return a.flags&C.VIPS_ARGUMENT_MODIFY != 0 }
+
+// typeName describes the argument's type in errors.
+func (a callArg) typeName() string {
+	switch a.kind {
+	case callArgImage:
+		return "*ImageRef"
+	case callArgDouble:
+		return "float64"
+	case callArgInt:
+		return "int"
+	case callArgBool:
+		return "bool"
+	case callArgString:
+		return "string"
+	case callArgEnum, callArgFlags:
+		return a.gtypeName() + " (int or nickname)"
+	case callArgArrayDouble:
+		return "[]float64"
+	case callArgArrayInt:
+		return "[]int"
+	case callArgArrayImage:
+		return "[]*ImageRef"
+	case callArgBlob:
+		return "[]byte"
+	default:
+		return a.gtypeName()
+	}
+}
+
+func (a callArg) gtypeName() string {
+	return C.GoString((*C.char)(unsafe.Pointer(C.g_type_name(a.valueType))))
+}
+
+// CallOutputs holds the outputs of an operation run by Call, by argument
+// name. Images are *ImageRef and []*ImageRef, numbers float64 or int,
+// enums and flags int, arrays []float64 or []int and blobs []byte. The
+// accessors check the type.
+type CallOutputs map[string]any
+
+// Image returns the image output name.
+func (o CallOutputs) Image(name string) (*ImageRef, error) {
+	return callOutput[*ImageRef](o, name)
+}
+
+// Images returns the image array output name.
+func (o CallOutputs) Images(name string) ([]*ImageRef, error) {
+	return callOutput[[]*ImageRef](o, name)
+}
+
+// Float returns the double output name.
+func (o CallOutputs) Float(name string) (float64, error) {
+	return callOutput[float64](o, name)
+}
+
+// Floats returns the double array output name.
+func (o CallOutputs) Floats(name string) ([]float64, error) {
+	return callOutput[[]float64](o, name)
+}
+
+// Int returns the int, enum or flags output name.
+func (o CallOutputs) Int(name string) (int, error) {
+	return callOutput[int](o, name)
+}
+
+// Ints returns the int array output name.
+func (o CallOutputs) Ints(name string) ([]int, error) {
+	return callOutput[[]int](o, name)
+}
+
+// Bool returns the bool output name.
+func (o CallOutputs) Bool(name string) (bool, error) {
+	return callOutput[bool](o, name)
+}
+
+// String returns the string output name.
+func (o CallOutputs) String(name string) (string, error) {
+	return callOutput[string](o, name)
+}
+
+// Bytes returns the blob output name.
+func (o CallOutputs) Bytes(name string) ([]byte, error) {
+	return callOutput[[]byte](o, name)
+}
+
+func callOutput[T any](o CallOutputs, name string) (T, error) {
+	var zero T
+	v, ok := o[name]
+	if !ok {
+		return zero, fmt.Errorf("no output %q", name)
+	}
+	t, ok := v.(T)
+	if !ok {
+		return zero, fmt.Errorf("output %q is %T, not %T", name, v, zero)
+	}
+	return t, nil
+}
+
+// close closes the images among the outputs.
+func (o CallOutputs) close() {
+	for _, v := range o {
+		switch v := v.(type) {
+		case *ImageRef:
+			v.Close()
+		case []*ImageRef:
+			for _, ref := range v {
+				ref.Close()
+			}
+		}
+	}
+}
+
+// Call runs the libvips operation opName, for operations govips does not
+// wrap. Arguments are looked up by name at runtime and converted from
+// *ImageRef, []*ImageRef, []float64, []int, []byte, bool, string and
+// numbers; enums and flags take their Go type, an int or the libvips
+// nickname, such as "horizontal". Required outputs are always returned.
+// An optional output is returned when args maps its name to true:
+//
+//	out, err := vips.Call("max", map[string]any{"in": image, "x": true, "y": true})
+//
+// The operation is traced and counted like wrapped ones, under the
+// context of its first input image.
+func Call(opName string, args map[string]any) (CallOutputs, error) {
+	return call(nil, opName, args)
+}
+
+// CallContext is like Call, but traces the operation under ctx, which the
+// output images also inherit.
+func CallContext(ctx context.Context, opName string, args map[string]any) (CallOutputs, error) {
+	if ctx == nil {
+		ctx = context.Background()
+	}
+	return call(ctx, opName, args)
+}
+
+func call(ctx context.Context, opName string, args map[string]any) (CallOutputs, error) {
+	if err := startupIfNeeded(); err != nil {
+		return nil, err
+	}
+	defer runtime.KeepAlive(args)
+
+	cName := C.CString(opName)
+	defer freeCString(cName)
+	operation := C.call_new_operation(cName)
+	if operation == nil {
+		return nil, handleVipsError()
+	}
+	defer func() { C.call_free_operation(operation) }()
+
+	opArgs := callArgs(operation)
+	if err := checkCallArgs(opName, opArgs, args); err != nil {
+		return nil, err
+	}
+
+	// The first input image stands for the operation in traces, like the
+	// receiver of a wrapped one.
+	var first *ImageRef
+	for _, a := range opArgs {
+		if ref, ok := args[a.name].(*ImageRef); ok && ref != nil && a.isInput() {
+			first = ref
+			break
+		}
+	}
+	format, originalFormat := ImageTypeUnknown, ImageTypeUnknown
+	var in *C.VipsImage
+	if first != nil {
+		format, originalFormat, in = first.format, first.originalFormat, first.image
+		if ctx == nil {
+			ctx = first.ctx
+		}
+	}
+	traceCtx := ctx
+	if traceCtx == nil {
+		traceCtx = context.Background()
+	}
+
+	op := startOp(traceCtx, opName, in)
+	defer op.end()
+
+	for _, a := range opArgs {
+		v, ok := args[a.name]
+		if !ok || !a.isInput() {
+			continue
+		}
+		if err := setCallArg(operation, opName, a, v); err != nil {
+			return nil, op.fail(err)
+		}
+	}
+
+	if C.call_build(&operation) != 0 {
+		return nil, op.fail(handleVipsError())
+	}
+
+	wrap := func(image *C.VipsImage) *ImageRef {
+		ref := newImageRef(image, format, originalFormat, nil)
+		ref.ctx = ctx
+		return ref
+	}
+	outputs := CallOutputs{}
+	for _, a := range opArgs {
+		if !a.isOutput() || (!a.isRequired() && args[a.name] != true) {
+			continue
+		}
+		v, err := getCallOutput(operation, a, wrap)
+		if err != nil {
+			outputs.close()
+			return nil, op.fail(err)
+		}
+		outputs[a.name] = v
+	}
+	return outputs, nil
+}
+
+// callArgs lists the arguments of operation in libvips priority order.
+func callArgs(operation *C.VipsOperation) []callArg {
+	cArgs := make([]C.CallArg, C.CALL_MAX_ARGS)
+	n := int(C.call_args(operation, &cArgs[0], C.CALL_MAX_ARGS))
+	if n > len(cArgs) {
+		n = len(cArgs)
+	}
+	result := make([]callArg, n)
+	for i, a := range cArgs[:n] {
+		result[i] = callArg{
+			name:        C.GoString(a.name),
+			description: C.GoString(a.blurb),
+			kind:        int(a.kind),
+			valueType:   a.value_type,
+			flags:       a.flags,
+			priority:    int(a.priority),
+		}
+	}
+	return result
+}
+
+// checkCallArgs rejects names the operation does not have, outputs given
+// anything but true and missing required inputs, before anything is set.
+func checkCallArgs(opName string, opArgs []callArg, args map[string]any) error {
+	byName := make(map[string]callArg, len(opArgs))
+	for _, a := range opArgs {
+		byName[a.name] = a
+	}
+
+	names := make([]string, 0, len(args))
+	for name := range args {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	for _, name := range names {
+		a, ok := byName[name]
+		switch {
+		case !ok:
+			return fmt.Errorf("%s: unknown argument %q", opName, name)
+		case a.isModify():
+			return fmt.Errorf("%s: argument %q is modified in place, which Call does not support", opName, name)
+		case a.isOutput():
+			if args[name] != true {
+				return fmt.Errorf("%s: argument %q is an output, pass true to request it", opName, name)
+			}
+		case a.kind == callArgUnsupported:
+			return fmt.Errorf("%s: argument %q has unsupported type %s", opName, name, a.typeName())
+		}
+	}
+
+	for _, a := range opArgs {
+		if a.isInput() && a.isRequired() {
+			if _, ok := args[a.name]; !ok {
+				return fmt.Errorf("%s: missing required argument %q", opName, a.name)
+			}
+		}
+	}
+	return nil
+}
+
+func setCallArg(operation *C.VipsOperation, opName string, a callArg, v any) error {
+	mismatch := func() error {
+		return fmt.Errorf("%s: argument %q: cannot use %T as %s", opName, a.name, v, a.typeName())
+	}
+
+	cName := C.CString(a.name)
+	defer freeCString(cName)
+
+	var rc C.int
+	switch a.kind {
+	case callArgImage:
+		ref, ok := v.(*ImageRef)
+		if !ok || ref == nil {
+			return mismatch()
+		}
+		rc = C.call_set_image(operation, cName, ref.image)
+
+	case callArgDouble:
+		f, ok := callFloat(v)
+		if !ok {
+			return mismatch()
+		}
+		rc = C.call_set_double(operation, cName, C.double(f))
+
+	case callArgInt:
+		n, ok := callInt(v)
+		if !ok {
+			return mismatch()
+		}
+		rc = C.call_set_int(operation, cName, C.gint64(n))
+
+	case callArgBool:
+		b, ok := v.(bool)
+		if !ok {
+			return mismatch()
+		}
+		rc = C.call_set_int(operation, cName, C.gint64(boolToInt(b)))
+
+	case callArgString:
+		s, ok := v.(string)
+		if !ok {
+			return mismatch()
+		}
+		cValue := C.CString(s)
+		defer freeCString(cValue)
+		rc = C.call_set_string(operation, cName, cValue)
+
+	case callArgEnum, callArgFlags:
+		if nick, ok := v.(string); ok {
+			cNick := C.CString(nick)
+			defer freeCString(cNick)
+			var value C.int
+			if C.call_enum_from_nick(a.valueType, cNick, &value) != 0 {
+				return fmt.Errorf("%s: argument %q: %w", opName, a.name, handleVipsError())
+			}
+			rc = C.call_set_int(operation, cName, C.gint64(value))
+			break
+		}
+		n, ok := callInt(v)
+		if !ok {
+			return mismatch()
+		}
+		rc = C.call_set_int(operation, cName, C.gint64(n))
+
+	case callArgArrayDouble:
+		values, ok := callFloats(v)
+		if !ok {
+			return mismatch()
+		}
+		cValues := toCArrayDouble(values)
+		defer C.free(unsafe.Pointer(cValues))
+		rc = C.call_set_array_double(operation, cName, cValues, C.int(len(values)))
+
+	case callArgArrayInt:
+		values, ok := callInts(v)
+		if !ok {
+			return mismatch()
+		}
+		cValues := toCArrayInt(values)
+		defer C.free(unsafe.Pointer(cValues))
+		rc = C.call_set_array_int(operation, cName, cValues, C.int(len(values)))
+
+	case callArgArrayImage:
+		refs, ok := v.([]*ImageRef)
+		if !ok {
+			return mismatch()
+		}
+		images := (**C.VipsImage)(C.malloc(C.size_t(len(refs)+1) * C.size_t(unsafe.Sizeof((*C.VipsImage)(nil)))))
+		defer C.free(unsafe.Pointer(images))
+		dst := unsafe.Slice(images, len(refs))
+		for i, ref := range refs {
+			if ref == nil {
+				return mismatch()
+			}
+			dst[i] = ref.image
+		}
+		rc = C.call_set_array_image(operation, cName, images, C.int(len(refs)))
+
+	case callArgBlob:
+		data, ok := v.([]byte)
+		if !ok {
+			return mismatch()
+		}
+		var ptr unsafe.Pointer
+		if len(data) > 0 {
+			ptr = unsafe.Pointer(&data[0])
+		}
+		rc = C.call_set_blob(operation, cName, ptr, C.size_t(len(data)))
+
+	default:
+		return mismatch()
+	}
+
+	if rc != 0 {
+		return fmt.Errorf("%s: argument %q: %w", opName, a.name, handleVipsError())
+	}
+	return nil
+}
+
+func getCallOutput(operation *C.VipsOperation, a callArg, wrap func(*C.VipsImage) *ImageRef) (any, error) {
+	cName := C.CString(a.name)
+	defer freeCString(cName)
+
+	var rc C.int
+	var result any
+	switch a.kind {
+	case callArgImage:
+		var out *C.VipsImage
+		if rc = C.call_get_image(operation, cName, &out); rc == 0 {
+			result = wrap(out)
+		}
+
+	case callArgDouble:
+		var out C.double
+		if rc = C.call_get_double(operation, cName, &out); rc == 0 {
+			result = float64(out)
+		}
+
+	case callArgInt, callArgEnum, callArgFlags:
+		var out C.gint64
+		if rc = C.call_get_int(operation, cName, &out); rc == 0 {
+			result = int(out)
+		}
+
+	case callArgBool:
+		var out C.gint64
+		if rc = C.call_get_int(operation, cName, &out); rc == 0 {
+			result = out != 0
+		}
+
+	case callArgString:
+		var out *C.char
+		if rc = C.call_get_string(operation, cName, &out); rc == 0 {
+			result = C.GoString(out)
+			gFreePointer(unsafe.Pointer(out))
+		}
+
+	case callArgArrayDouble:
+		var out *C.double
+		var n C.int
+		if rc = C.call_get_array_double(operation, cName, &out, &n); rc == 0 {
+			result = fromCArrayDouble(out, int(n))
+			gFreePointer(unsafe.Pointer(out))
+		}
+
+	case callArgArrayInt:
+		var out *C.int
+		var n C.int
+		if rc = C.call_get_array_int(operation, cName, &out, &n); rc == 0 {
+			result = fromCArrayInt(out, int(n))
+			gFreePointer(unsafe.Pointer(out))
+		}
+
+	case callArgArrayImage:
+		var out **C.VipsImage
+		var n C.int
+		if rc = C.call_get_array_image(operation, cName, &out, &n); rc == 0 {
+			refs := make([]*ImageRef, int(n))
+			for i, image := range unsafe.Slice(out, int(n)) {
+				refs[i] = wrap(image)
+			}
+			result = refs
+			gFreePointer(unsafe.Pointer(out))
+		}
+
+	case callArgBlob:
+		var out unsafe.Pointer
+		var n C.size_t
+		if rc = C.call_get_blob(operation, cName, &out, &n); rc == 0 {
+			result = C.GoBytes(out, C.int(n))
+			gFreePointer(out)
+		}
+
+	default:
+		return nil, fmt.Errorf("output %q has unsupported type %s", a.name, a.typeName())
+	}
+
+	if rc != 0 {
+		return nil, handleVipsError()
+	}
+	return result, nil
+}
+
+// callInt accepts any integer, including the enum types.
+func callInt(v any) (int64, bool) {
+	rv := reflect.ValueOf(v)
+	switch rv.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return rv.Int(), true
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return int64(rv.Uint()), true
+	}
+	return 0, false
+}
+
+// callFloat accepts any float or integer.
+func callFloat(v any) (float64, bool) {
+	rv := reflect.ValueOf(v)
+	switch rv.Kind() {
+	case reflect.Float32, reflect.Float64:
+		return rv.Float(), true
+	}
+	if n, ok := callInt(v); ok {
+		return float64(n), true
+	}
+	return 0, false
+}
+
+// callFloats accepts []float64, []int or a single number, as libvips does
+// for constants.
+func callFloats(v any) ([]float64, bool) {
+	switch v := v.(type) {
+	case []float64:
+		return v, true
+	case []int:
+		values := make([]float64, len(v))
+		for i, n := range v {
+			values[i] = float64(n)
+		}
+		return values, true
+	}
+	if f, ok := callFloat(v); ok {
+		return []float64{f}, true
+	}
+	return nil, false
+}
+
+// callInts accepts []int or a single integer.
+func callInts(v any) ([]int, bool) {
+	if values, ok := v.([]int); ok {
+		return values, true
+	}
+	if n, ok := callInt(v); ok {
+		return []int{int(n)}, true
+	}
+	return nil, false
+}
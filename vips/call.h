@@ -0,0 +1,74 @@
+// https://www.libvips.org/API/current/VipsOperation.html
+
+#include <stdlib.h>
+#include <vips/vips.h>
+
+// Argument kinds. These must match the callArg constants in call.go.
+enum {
+  CALL_ARG_UNSUPPORTED = 0,
+  CALL_ARG_IMAGE,
+  CALL_ARG_DOUBLE,
+  CALL_ARG_INT,
+  CALL_ARG_BOOL,
+  CALL_ARG_STRING,
+  CALL_ARG_ENUM,
+  CALL_ARG_FLAGS,
+  CALL_ARG_ARRAY_DOUBLE,
+  CALL_ARG_ARRAY_INT,
+  CALL_ARG_ARRAY_IMAGE,
+  CALL_ARG_BLOB,
+};
+
+// CallArg describes one construct argument of an operation. name and
+// blurb are owned by the operation class.
+typedef struct {
+  const char *name;
+  const char *blurb;
+  int kind;
+  GType value_type;
+  VipsArgumentFlags flags;
+  int priority;
+} CallArg;
+
+#define CALL_MAX_ARGS 128
+
+VipsOperation *call_new_operation(const char *name);
+void call_free_operation(VipsOperation *operation);
+int call_build(VipsOperation **operation);
+
+// call_args fills args with up to max arguments and returns how many the
+// operation has.
+int call_args(VipsOperation *operation, CallArg *args, int max);
+
+int call_set_int(VipsOperation *operation, const char *name, gint64 value);
+int call_set_double(VipsOperation *operation, const char *name, double value);
+int call_set_string(VipsOperation *operation, const char *name,
+                    const char *value);
+int call_set_image(VipsOperation *operation, const char *name,
+                   VipsImage *value);
+int call_set_array_double(VipsOperation *operation, const char *name,
+                          const double *values, int n);
+int call_set_array_int(VipsOperation *operation, const char *name,
+                       const int *values, int n);
+int call_set_array_image(VipsOperation *operation, const char *name,
+                         VipsImage **values, int n);
+int call_set_blob(VipsOperation *operation, const char *name,
+                  const void *data, size_t length);
+
+int call_enum_from_nick(GType type, const char *nick, int *value);
+
+int call_get_int(VipsOperation *operation, const char *name, gint64 *value);
+int call_get_double(VipsOperation *operation, const char *name,
+                    double *value);
+int call_get_string(VipsOperation *operation, const char *name,
+                    char **value);
+int call_get_image(VipsOperation *operation, const char *name,
+                   VipsImage **value);
+int call_get_array_double(VipsOperation *operation, const char *name,
+                          double **values, int *n);
+int call_get_array_int(VipsOperation *operation, const char *name,
+                       int **values, int *n);
+int call_get_array_image(VipsOperation *operation, const char *name,
+                         VipsImage ***values, int *n);
+int call_get_blob(VipsOperation *operation, const char *name, void **data,
+                  size_t *length);
@@ -0,0 +1,144 @@
+package vips
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCall_Image(t *testing.T) {
+	require.NoError(t, Startup(nil))
+
+	image, err := NewImageFromFile(resources + "png-24bit.png")
+	require.NoError(t, err)
+	defer image.Close()
+
+	out, err := Call("flip", map[string]any{"in": image, "direction": "horizontal"})
+	require.NoError(t, err)
+	flipped, err := out.Image("out")
+	require.NoError(t, err)
+	defer flipped.Close()
+	assert.Equal(t, image.Width(), flipped.Width())
+	assert.Equal(t, image.Format(), flipped.Format())
+
+	expected, err := image.Copy()
+	require.NoError(t, err)
+	defer expected.Close()
+	require.NoError(t, expected.Flip(DirectionHorizontal))
+	assertSamePixels(t, expected, flipped)
+
+	// Enums also take their Go type.
+	out, err = Call("flip", map[string]any{"in": image, "direction": DirectionHorizontal})
+	require.NoError(t, err)
+	again, err := out.Image("out")
+	require.NoError(t, err)
+	defer again.Close()
+	assertSamePixels(t, expected, again)
+}
+
+func TestCall_Scalars(t *testing.T) {
+	require.NoError(t, Startup(nil))
+
+	image, err := Black(10, 10)
+	require.NoError(t, err)
+	defer image.Close()
+	require.NoError(t, image.DrawRect(ColorRGBA{R: 200}, 3, 4, 1, 1, true))
+
+	out, err := Call("max", map[string]any{"in": image, "x": true, "y": true})
+	require.NoError(t, err)
+	value, err := out.Float("out")
+	require.NoError(t, err)
+	assert.Equal(t, float64(200), value)
+	x, err := out.Int("x")
+	require.NoError(t, err)
+	y, err := out.Int("y")
+	require.NoError(t, err)
+	assert.Equal(t, 3, x)
+	assert.Equal(t, 4, y)
+
+	// Optional outputs that were not requested are left out.
+	out, err = Call("max", map[string]any{"in": image})
+	require.NoError(t, err)
+	_, err = out.Int("x")
+	assert.Error(t, err)
+
+	out, err = Call("linear", map[string]any{"in": image, "a": []float64{2}, "b": 1})
+	require.NoError(t, err)
+	scaled, err := out.Image("out")
+	require.NoError(t, err)
+	defer scaled.Close()
+	value, _, _, err = scaled.Max()
+	require.NoError(t, err)
+	assert.Equal(t, float64(401), value)
+}
+
+func TestCall_Errors(t *testing.T) {
+	require.NoError(t, Startup(nil))
+
+	image, err := Black(10, 10)
+	require.NoError(t, err)
+	defer image.Close()
+
+	_, err = Call("no_such_operation", nil)
+	assert.Error(t, err)
+
+	_, err = Call("invert", map[string]any{"in": image, "colour": 1})
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), `unknown argument "colour"`)
+
+	_, err = Call("invert", map[string]any{})
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), `missing required argument "in"`)
+
+	_, err = Call("invert", map[string]any{"in": "image.png"})
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), `argument "in": cannot use string as *ImageRef`)
+
+	_, err = Call("invert", map[string]any{"in": image, "out": image})
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), `argument "out" is an output`)
+
+	_, err = Call("flip", map[string]any{"in": image, "direction": "sideways"})
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), `argument "direction"`)
+
+	_, err = Call("gaussblur", map[string]any{"in": image, "sigma": "wide"})
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), `argument "sigma": cannot use string as float64`)
+}
+
+func TestCall_Traced(t *testing.T) {
+	require.NoError(t, Startup(nil))
+	tracer := useTracer(t)
+
+	image, err := Black(10, 10)
+	require.NoError(t, err)
+	defer image.Close()
+	ctx := context.WithValue(context.Background(), traceKey{}, "request")
+	image.SetContext(ctx)
+
+	out, err := Call("invert", map[string]any{"in": image})
+	require.NoError(t, err)
+	inverted, err := out.Image("out")
+	require.NoError(t, err)
+	defer inverted.Close()
+	assert.Equal(t, ctx, inverted.Context())
+
+	span := tracer.find("invert")
+	require.NotNil(t, span)
+	assert.Equal(t, "request", span.parent)
+	assert.Equal(t, 10, span.start.Width)
+	assert.True(t, span.ended)
+	assert.NoError(t, span.err)
+}
+
+func assertSamePixels(t *testing.T, expected, actual *ImageRef) {
+	t.Helper()
+	want, err := expected.ToBytes()
+	require.NoError(t, err)
+	got, err := actual.ToBytes()
+	require.NoError(t, err)
+	assert.Equal(t, want, got)
+}
@@ -0,0 +1,112 @@
+package vips
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// enumNick pairs an enum value with its libvips nickname. The tables in
+// generated_enums.go are made of them, in the order libvips lists the
+// values.
+type enumNick[T ~int] struct {
+	value T
+	nick  string
+}
+
+func lookupNick[T ~int](nicks []enumNick[T], v T) (string, bool) {
+	for _, n := range nicks {
+		if n.value == v {
+			return n.nick, true
+		}
+	}
+	return "", false
+}
+
+func lookupValue[T ~int](nicks []enumNick[T], s string) (T, bool) {
+	for _, n := range nicks {
+		if strings.EqualFold(n.nick, s) {
+			return n.value, true
+		}
+	}
+	return 0, false
+}
+
+// formatEnum returns the nickname of v, or TypeName(v) for a value
+// libvips has no name for, such as KernelAuto.
+func formatEnum[T ~int](typeName string, nicks []enumNick[T], v T) string {
+	if nick, ok := lookupNick(nicks, v); ok {
+		return nick
+	}
+	return typeName + "(" + strconv.Itoa(int(v)) + ")"
+}
+
+func parseEnum[T ~int](typeName string, nicks []enumNick[T], s string) (T, error) {
+	if v, ok := lookupValue(nicks, strings.TrimSpace(s)); ok {
+		return v, nil
+	}
+	return 0, fmt.Errorf("unknown %s %q", typeName, s)
+}
+
+func marshalEnum[T ~int](typeName string, nicks []enumNick[T], v T) ([]byte, error) {
+	if nick, ok := lookupNick(nicks, v); ok {
+		return []byte(nick), nil
+	}
+	return nil, fmt.Errorf("cannot marshal %s(%d)", typeName, int(v))
+}
+
+// flagsNicks spells v as nicknames joined with "|". A value with a
+// nickname of its own, such as ForeignKeepAll, keeps it; otherwise v is
+// split into its single-bit flags. It fails if a bit has no nickname.
+func flagsNicks[T ~int](nicks []enumNick[T], v T) (string, bool) {
+	if nick, ok := lookupNick(nicks, v); ok {
+		return nick, true
+	}
+	var parts []string
+	rest := v
+	for _, n := range nicks {
+		single := n.value != 0 && n.value&(n.value-1) == 0
+		if single && rest&n.value == n.value {
+			parts = append(parts, n.nick)
+			rest &^= n.value
+		}
+	}
+	if rest != 0 || len(parts) == 0 {
+		return "", false
+	}
+	return strings.Join(parts, "|"), true
+}
+
+func formatFlags[T ~int](typeName string, nicks []enumNick[T], v T) string {
+	if s, ok := flagsNicks(nicks, v); ok {
+		return s
+	}
+	return typeName + "(" + strconv.Itoa(int(v)) + ")"
+}
+
+// parseFlags accepts the separators libvips itself accepts for flags on
+// the command line, so "exif|icc", "exif,icc" and "exif icc" are equal.
+func parseFlags[T ~int](typeName string, nicks []enumNick[T], s string) (T, error) {
+	fields := strings.FieldsFunc(s, func(r rune) bool {
+		return strings.ContainsRune("|,;: \t", r)
+	})
+	if len(fields) == 0 {
+		return 0, fmt.Errorf("unknown %s %q", typeName, s)
+	}
+	var v T
+	for _, field := range fields {
+		flag, ok := lookupValue(nicks, field)
+		if !ok {
+			return 0, fmt.Errorf("unknown %s %q", typeName, field)
+		}
+		v |= flag
+	}
+	return v, nil
+}
+
+func marshalFlags[T ~int](typeName string, nicks []enumNick[T], v T) ([]byte, error) {
+	if s, ok := flagsNicks(nicks, v); ok {
+		return []byte(s), nil
+	}
+	return nil, fmt.Errorf("cannot marshal %s(%d)", typeName, int(v))
+}
@@ -0,0 +1,111 @@
+package vips
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestEnum_String(t *testing.T) {
+	assert.Equal(t, "over", BlendModeOver.String())
+	assert.Equal(t, "colour-dodge", BlendModeColorDodge.String())
+	assert.Equal(t, "lanczos3", KernelLanczos3.String())
+	assert.Equal(t, "srgb", InterpretationSRGB.String())
+	assert.Equal(t, "north-east", GravityNorthEast.String())
+	assert.Equal(t, "uchar", BandFormatUchar.String())
+	assert.Equal(t, "Kernel(-1)", KernelAuto.String())
+}
+
+func TestEnum_Parse(t *testing.T) {
+	mode, err := ParseBlendMode("multiply")
+	require.NoError(t, err)
+	assert.Equal(t, BlendModeMultiply, mode)
+
+	interesting, err := ParseInteresting(" Attention ")
+	require.NoError(t, err)
+	assert.Equal(t, InterestingAttention, interesting)
+
+	_, err = ParseBlendMode("nope")
+	assert.EqualError(t, err, `unknown BlendMode "nope"`)
+}
+
+func TestEnum_RoundTrip(t *testing.T) {
+	for _, n := range blendModeNicks {
+		parsed, err := ParseBlendMode(n.value.String())
+		require.NoError(t, err)
+		assert.Equal(t, n.value, parsed)
+	}
+	for _, n := range interpretationNicks {
+		parsed, err := ParseInterpretation(n.value.String())
+		require.NoError(t, err)
+		assert.Equal(t, n.value, parsed)
+	}
+}
+
+func TestEnum_JSON(t *testing.T) {
+	type config struct {
+		Kernel Kernel                `json:"kernel"`
+		Crop   Interesting           `json:"crop"`
+		Modes  map[BlendMode]float64 `json:"modes"`
+	}
+
+	in := config{
+		Kernel: KernelMitchell,
+		Crop:   InterestingEntropy,
+		Modes:  map[BlendMode]float64{BlendModeScreen: 0.5},
+	}
+	data, err := json.Marshal(in)
+	require.NoError(t, err)
+	assert.JSONEq(t, `{"kernel":"mitchell","crop":"entropy","modes":{"screen":0.5}}`, string(data))
+
+	var out config
+	require.NoError(t, json.Unmarshal(data, &out))
+	assert.Equal(t, in, out)
+
+	_, err = json.Marshal(config{Kernel: KernelAuto})
+	assert.Error(t, err)
+	assert.Error(t, json.Unmarshal([]byte(`{"kernel":"blurry"}`), &out))
+}
+
+func TestFlags_String(t *testing.T) {
+	assert.Equal(t, "none", ForeignKeepNone.String())
+	assert.Equal(t, "icc", ForeignKeepIcc.String())
+	assert.Equal(t, "exif|icc", (ForeignKeepExif | ForeignKeepIcc).String())
+	assert.Equal(t, "all", ForeignKeepAll.String())
+	assert.Equal(t, "ForeignKeep(64)", ForeignKeep(64).String())
+	assert.Equal(t, "sub|paeth", (PngFilterSub | PngFilterPaeth).String())
+}
+
+func TestFlags_Parse(t *testing.T) {
+	keep, err := ParseForeignKeep("exif|icc")
+	require.NoError(t, err)
+	assert.Equal(t, ForeignKeepExif|ForeignKeepIcc, keep)
+
+	keep, err = ParseForeignKeep("XMP, iptc")
+	require.NoError(t, err)
+	assert.Equal(t, ForeignKeepXmp|ForeignKeepIptc, keep)
+
+	keep, err = ParseForeignKeep("all")
+	require.NoError(t, err)
+	assert.Equal(t, ForeignKeepAll, keep)
+
+	_, err = ParseForeignKeep("exif|gps")
+	assert.EqualError(t, err, `unknown ForeignKeep "gps"`)
+	_, err = ParseForeignKeep("")
+	assert.Error(t, err)
+}
+
+func TestFlags_Text(t *testing.T) {
+	text, err := (ForeignKeepXmp | ForeignKeepOther).MarshalText()
+	require.NoError(t, err)
+	assert.Equal(t, "xmp|other", string(text))
+
+	var keep ForeignKeep
+	require.NoError(t, keep.UnmarshalText(text))
+	assert.Equal(t, ForeignKeepXmp|ForeignKeepOther, keep)
+
+	_, err = ForeignKeep(64).MarshalText()
+	assert.Error(t, err)
+}
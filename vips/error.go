@@ -13,8 +13,30 @@ import (
 var (
 	// ErrUnsupportedImageFormat when image type is unsupported
 	ErrUnsupportedImageFormat = errors.New("unsupported image format")
+
+	// ErrUnsupportedByLibvips when a feature needs a newer libvips than the
+	// one govips is running against. The returned error is an
+	// *UnsupportedError; test for it with errors.Is.
+	ErrUnsupportedByLibvips = errors.New("unsupported by libvips")
 )
 
+// UnsupportedError reports a feature that needs a newer libvips.
+type UnsupportedError struct {
+	// Feature is the operation or option that is unavailable.
+	Feature string
+	// Required is the first libvips version that supports Feature.
+	Required string
+}
+
+func (e *UnsupportedError) Error() string {
+	return fmt.Sprintf("%s requires libvips %s+, found %s", e.Feature, e.Required, Version)
+}
+
+// Is reports whether target is ErrUnsupportedByLibvips.
+func (e *UnsupportedError) Is(target error) bool {
+	return target == ErrUnsupportedByLibvips
+}
+
 func handleImageError(out *C.VipsImage) error {
 	if out != nil {
 		clearImage(out)
@@ -0,0 +1,349 @@
+package vips
+
+// #include "image.h"
+import "C"
+
+import (
+	"errors"
+	"fmt"
+	"runtime"
+	"unsafe"
+)
+
+// ErrTargetNotMet is returned by ExportWithTarget when no quality within the
+// searched range satisfies the target. The closest encoding found is still
+// returned alongside the error, so callers can decide whether to use it.
+var ErrTargetNotMet = errors.New("export target not met")
+
+// DistanceFunc computes a perceptual distance between the reference image and
+// a decoded candidate encoding, where lower is better (Butteraugli-style).
+// It must not modify either image.
+type DistanceFunc func(reference, candidate *ImageRef) (float64, error)
+
+// ExportTarget describes what ExportWithTarget should search for. At least
+// one of MaxBytes, MinSSIM or MaxDistance must be set.
+//
+// With only MaxBytes set, the highest quality whose output fits in the budget
+// is chosen. With a perceptual floor (MinSSIM or MaxDistance), the lowest
+// quality meeting the floor is chosen, which is also the smallest output; if
+// MaxBytes is set too, that output must additionally fit in the budget.
+type ExportTarget struct {
+	// MaxBytes is the byte budget for the encoded image.
+	MaxBytes int
+	// MinSSIM is the minimum structural similarity (0-1) between the image
+	// and the decoded encoding. Scores are computed on a downscaled
+	// greyscale copy, so values are comparable across image sizes.
+	MinSSIM float64
+	// MaxDistance is the maximum perceptual distance returned by Distance.
+	MaxDistance float64
+	// Distance scores candidates against MaxDistance. Required when
+	// MaxDistance is set.
+	Distance DistanceFunc
+
+	// MinQuality and MaxQuality bound the search. They default to 1 and 100.
+	MinQuality int
+	MaxQuality int
+	// MaxAttempts caps the number of encodes. It defaults to 7, enough for
+	// an exhaustive binary search over 1-100.
+	MaxAttempts int
+
+	// StripMetadata removes metadata such as EXIF and ICC profiles from
+	// every candidate, so the byte budget applies to the pixels alone.
+	StripMetadata bool
+}
+
+// ExportTargetResult reports the encoding chosen by ExportWithTarget.
+type ExportTargetResult struct {
+	Quality  int
+	Size     int
+	Attempts int
+	// SSIM and Distance are the scores of the chosen encoding, set only when
+	// the corresponding floor was requested.
+	SSIM     float64
+	Distance float64
+	Metadata *ImageMetadata
+}
+
+const (
+	defaultTargetMaxAttempts = 7
+
+	// ssimSize is the longest side images are reduced to before computing
+	// SSIM. Quality search only needs a relative score, and this keeps each
+	// scoring pass cheap regardless of the input size.
+	ssimSize = 256
+)
+
+// ExportWithTarget encodes the image as JPEG, AVIF, JXL or HEIF, binary
+// searching the quality parameter until the output meets target. It returns
+// the chosen encoding and a report of the quality, size and scores. If the
+// target cannot be met within the quality range or attempt budget, the
+// closest encoding is returned together with ErrTargetNotMet.
+func (r *ImageRef) ExportWithTarget(format ImageType, target ExportTarget) ([]byte, *ExportTargetResult, error) {
+	switch format {
+	case ImageTypeJPEG, ImageTypeAVIF, ImageTypeJXL, ImageTypeHEIF:
+	default:
+		return nil, nil, fmt.Errorf("%w: quality search is not available for %s", ErrUnsupportedImageFormat, ImageTypes[format])
+	}
+	if !IsTypeSupported(format) {
+		return nil, nil, fmt.Errorf("cannot save to %#v", ImageTypes[format])
+	}
+
+	perceptual := target.MinSSIM > 0 || target.MaxDistance > 0
+	if target.MaxBytes <= 0 && !perceptual {
+		return nil, nil, errors.New("export target requires MaxBytes, MinSSIM or MaxDistance")
+	}
+	if target.MaxDistance > 0 && target.Distance == nil {
+		return nil, nil, errors.New("export target MaxDistance requires a Distance function")
+	}
+
+	lo, hi := target.MinQuality, target.MaxQuality
+	if lo <= 0 {
+		lo = 1
+	}
+	if hi <= 0 || hi > 100 {
+		hi = 100
+	}
+	if lo > hi {
+		return nil, nil, fmt.Errorf("invalid quality range %d-%d", lo, hi)
+	}
+	maxAttempts := target.MaxAttempts
+	if maxAttempts <= 0 {
+		maxAttempts = defaultTargetMaxAttempts
+	}
+
+	var reference []byte
+	var refWidth, refHeight int
+	if target.MinSSIM > 0 {
+		var err error
+		if reference, refWidth, refHeight, err = r.ssimPixels(); err != nil {
+			return nil, nil, err
+		}
+	}
+
+	encode := func(quality int) (*targetCandidate, error) {
+		buf, err := r.exportAtQuality(format, quality, target.StripMetadata)
+		if err != nil {
+			return nil, err
+		}
+		c := &targetCandidate{quality: quality, buf: buf}
+		if !perceptual {
+			return c, nil
+		}
+
+		decoded, err := NewImageFromBuffer(buf)
+		if err != nil {
+			return nil, err
+		}
+		defer decoded.Close()
+
+		if target.MinSSIM > 0 {
+			pixels, width, height, err := decoded.ssimPixels()
+			if err != nil {
+				return nil, err
+			}
+			if width != refWidth || height != refHeight {
+				return nil, fmt.Errorf("decoded size %dx%d does not match %dx%d", width, height, refWidth, refHeight)
+			}
+			c.ssim = ssim(reference, pixels, width, height)
+		}
+		if target.MaxDistance > 0 {
+			if c.distance, err = target.Distance(r, decoded); err != nil {
+				return nil, err
+			}
+		}
+		return c, nil
+	}
+
+	best, attempts, err := searchQuality(lo, hi, maxAttempts, target, encode)
+	if best == nil {
+		return nil, nil, err
+	}
+
+	result := &ExportTargetResult{
+		Quality:  best.quality,
+		Size:     len(best.buf),
+		Attempts: attempts,
+		SSIM:     best.ssim,
+		Distance: best.distance,
+		Metadata: r.newMetadata(format),
+	}
+	return best.buf, result, err
+}
+
+type targetCandidate struct {
+	quality  int
+	buf      []byte
+	ssim     float64
+	distance float64
+}
+
+func (c *targetCandidate) fits(target ExportTarget) bool {
+	return target.MaxBytes <= 0 || len(c.buf) <= target.MaxBytes
+}
+
+func (c *targetCandidate) perceptuallyOK(target ExportTarget) bool {
+	if target.MinSSIM > 0 && c.ssim < target.MinSSIM {
+		return false
+	}
+	if target.MaxDistance > 0 && c.distance > target.MaxDistance {
+		return false
+	}
+	return true
+}
+
+// searchQuality binary searches [lo, hi] for the candidate that satisfies
+// target, as described on ExportTarget. Both output size and perceptual
+// quality are assumed to be monotonic in the quality parameter. When nothing
+// satisfies the target, the closest candidate is returned with
+// ErrTargetNotMet: the smallest encoding for a byte budget, the best scoring
+// one for a perceptual floor.
+func searchQuality(lo, hi, maxAttempts int, target ExportTarget, encode func(int) (*targetCandidate, error)) (*targetCandidate, int, error) {
+	perceptual := target.MinSSIM > 0 || target.MaxDistance > 0
+
+	var best, closest *targetCandidate
+	attempts := 0
+	for lo <= hi && attempts < maxAttempts {
+		mid := lo + (hi-lo)/2
+		c, err := encode(mid)
+		attempts++
+		if err != nil {
+			return nil, attempts, err
+		}
+
+		if perceptual {
+			// Look for the lowest quality that meets the floor.
+			if c.perceptuallyOK(target) {
+				best = c
+				hi = mid - 1
+			} else {
+				closest = c
+				lo = mid + 1
+			}
+			continue
+		}
+
+		// Look for the highest quality that fits the budget.
+		if c.fits(target) {
+			best = c
+			lo = mid + 1
+		} else {
+			closest = c
+			hi = mid - 1
+		}
+	}
+
+	if best == nil {
+		if closest == nil {
+			return nil, attempts, ErrTargetNotMet
+		}
+		return closest, attempts, ErrTargetNotMet
+	}
+	if !best.fits(target) {
+		return best, attempts, ErrTargetNotMet
+	}
+	return best, attempts, nil
+}
+
+func (r *ImageRef) exportAtQuality(format ImageType, quality int, stripMetadata bool) ([]byte, error) {
+	var buf []byte
+	var err error
+	switch format {
+	case ImageTypeJPEG:
+		params := NewJpegExportParams()
+		params.Quality = quality
+		params.StripMetadata = stripMetadata
+		buf, _, err = r.ExportJpeg(params)
+	case ImageTypeAVIF:
+		params := NewAvifExportParams()
+		params.Quality = quality
+		params.StripMetadata = stripMetadata
+		buf, _, err = r.ExportAvif(params)
+	case ImageTypeJXL:
+		params := *NewJxlExportParams()
+		params.Quality = quality
+		params.StripMetadata = stripMetadata
+		o := jxlsaveOptions(params)
+		// libvips only derives the distance from Q when distance is unset.
+		o.Distance = nil
+		buf, _, err = r.ExportWithOptions(o)
+	case ImageTypeHEIF:
+		params := NewHeifExportParams()
+		params.Quality = quality
+		params.StripMetadata = stripMetadata
+		buf, _, err = r.ExportHeif(params)
+	default:
+		err = ErrUnsupportedImageFormat
+	}
+	return buf, err
+}
+
+// SSIM returns the mean structural similarity of two images, between 0 and
+// 1 where 1 means identical. Both images are reduced to greyscale thumbnails
+// of at most 256 pixels on the longest side before comparison, so they must
+// have the same aspect ratio.
+func SSIM(a, b *ImageRef) (float64, error) {
+	pa, wa, ha, err := a.ssimPixels()
+	if err != nil {
+		return 0, err
+	}
+	pb, wb, hb, err := b.ssimPixels()
+	if err != nil {
+		return 0, err
+	}
+	if wa != wb || ha != hb {
+		return 0, fmt.Errorf("image sizes differ: %dx%d vs %dx%d", wa, ha, wb, hb)
+	}
+	return ssim(pa, pb, wa, ha), nil
+}
+
+// ssimPixels returns the 8-bit greyscale pixels the SSIM score is computed
+// on: the image flattened onto white and shrunk to fit ssimSize.
+func (r *ImageRef) ssimPixels() ([]byte, int, int, error) {
+	defer runtime.KeepAlive(r)
+
+	tmp, err := vipsThumbnail(r.Context(), r.image, ssimSize, ssimSize, InterestingNone, SizeDown)
+	if err != nil {
+		return nil, 0, 0, err
+	}
+	defer func() { clearImage(tmp) }()
+
+	if vipsHasAlpha(tmp) {
+		out, err := vipsGenFlatten(r.Context(), tmp, &FlattenOptions{Background: []float64{255, 255, 255}})
+		if err != nil {
+			return nil, 0, 0, err
+		}
+		clearImage(tmp)
+		tmp = out
+	}
+
+	if Interpretation(int(tmp.Type)) != InterpretationBW {
+		out, err := vipsToColorSpace(r.Context(), tmp, InterpretationBW)
+		if err != nil {
+			return nil, 0, 0, err
+		}
+		clearImage(tmp)
+		tmp = out
+	}
+
+	if BandFormat(int(tmp.BandFmt)) != BandFormatUchar {
+		out, err := vipsGenCast(r.Context(), tmp, BandFormatUchar, nil)
+		if err != nil {
+			return nil, 0, 0, err
+		}
+		clearImage(tmp)
+		tmp = out
+	}
+
+	if int(tmp.Bands) != 1 {
+		return nil, 0, 0, fmt.Errorf("unsupported number of bands: %d", int(tmp.Bands))
+	}
+
+	var cSize C.size_t
+	cData := C.vips_image_write_to_memory(tmp, &cSize)
+	if cData == nil {
+		return nil, 0, 0, errors.New("failed to write image to memory")
+	}
+	defer C.free(cData)
+
+	return C.GoBytes(unsafe.Pointer(cData), C.int(cSize)), int(tmp.Xsize), int(tmp.Ysize), nil
+}
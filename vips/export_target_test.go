@@ -0,0 +1,220 @@
+package vips
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// fakeEncoder produces candidates whose size and score grow linearly with
+// quality, and counts how often it was called.
+type fakeEncoder struct {
+	calls int
+}
+
+func (f *fakeEncoder) encode(quality int) (*targetCandidate, error) {
+	f.calls++
+	return &targetCandidate{
+		quality: quality,
+		buf:     make([]byte, quality*100),
+		ssim:    float64(quality) / 100,
+	}, nil
+}
+
+func TestSearchQuality_MaxBytes(t *testing.T) {
+	enc := &fakeEncoder{}
+	best, attempts, err := searchQuality(1, 100, 7, ExportTarget{MaxBytes: 4250}, enc.encode)
+	require.NoError(t, err)
+	assert.Equal(t, 42, best.quality)
+	assert.Equal(t, enc.calls, attempts)
+	assert.LessOrEqual(t, attempts, 7)
+}
+
+func TestSearchQuality_MinSSIM(t *testing.T) {
+	enc := &fakeEncoder{}
+	best, _, err := searchQuality(1, 100, 7, ExportTarget{MinSSIM: 0.615}, enc.encode)
+	require.NoError(t, err)
+	assert.Equal(t, 62, best.quality)
+}
+
+func TestSearchQuality_BudgetAndFloorConflict(t *testing.T) {
+	enc := &fakeEncoder{}
+	best, _, err := searchQuality(1, 100, 7, ExportTarget{MinSSIM: 0.8, MaxBytes: 5000}, enc.encode)
+	assert.True(t, errors.Is(err, ErrTargetNotMet))
+	require.NotNil(t, best)
+	assert.Equal(t, 80, best.quality)
+}
+
+func TestSearchQuality_Unreachable(t *testing.T) {
+	enc := &fakeEncoder{}
+	best, _, err := searchQuality(10, 100, 7, ExportTarget{MaxBytes: 50}, enc.encode)
+	assert.True(t, errors.Is(err, ErrTargetNotMet))
+	require.NotNil(t, best)
+	assert.Equal(t, 10, best.quality, "closest candidate should be the smallest encoding")
+}
+
+func TestSearchQuality_MaxAttempts(t *testing.T) {
+	enc := &fakeEncoder{}
+	_, attempts, _ := searchQuality(1, 100, 3, ExportTarget{MaxBytes: 4250}, enc.encode)
+	assert.Equal(t, 3, attempts)
+	assert.Equal(t, 3, enc.calls)
+}
+
+func TestSSIM_Pixels(t *testing.T) {
+	width, height := 32, 32
+	a := make([]byte, width*height)
+	inverted := make([]byte, width*height)
+	for i := range a {
+		a[i] = byte((i * 7) % 256)
+		inverted[i] = 255 - a[i]
+	}
+
+	assert.InDelta(t, 1, ssim(a, a, width, height), 1e-9)
+	assert.Less(t, ssim(a, inverted, width, height), 0.5)
+}
+
+func TestImageRef_ExportWithTarget_MaxBytes(t *testing.T) {
+	Startup(nil)
+
+	img, err := NewImageFromFile(resources + "jpg-8bit-grey-icc-dot-gain.jpg")
+	require.NoError(t, err)
+	defer img.Close()
+
+	full, _, err := img.ExportJpeg(&JpegExportParams{Quality: 95})
+	require.NoError(t, err)
+	budget := len(full) / 2
+
+	buf, result, err := img.ExportWithTarget(ImageTypeJPEG, ExportTarget{MaxBytes: budget})
+	require.NoError(t, err)
+	assert.LessOrEqual(t, len(buf), budget)
+	assert.Equal(t, len(buf), result.Size)
+	assert.Greater(t, result.Quality, 1)
+	assert.Less(t, result.Quality, 95)
+	assert.LessOrEqual(t, result.Attempts, defaultTargetMaxAttempts)
+	assert.Equal(t, ImageTypeJPEG, DetermineImageType(buf))
+}
+
+func TestImageRef_ExportWithTarget_MinSSIM(t *testing.T) {
+	Startup(nil)
+
+	img, err := NewImageFromFile(resources + "jpg-24bit.jpg")
+	require.NoError(t, err)
+	defer img.Close()
+
+	_, result, err := img.ExportWithTarget(ImageTypeJPEG, ExportTarget{MinSSIM: 0.95})
+	require.NoError(t, err)
+	assert.GreaterOrEqual(t, result.SSIM, 0.95)
+
+	_, loose, err := img.ExportWithTarget(ImageTypeJPEG, ExportTarget{MinSSIM: 0.8})
+	require.NoError(t, err)
+	assert.LessOrEqual(t, loose.Quality, result.Quality)
+}
+
+func TestImageRef_ExportWithTarget_Distance(t *testing.T) {
+	Startup(nil)
+
+	img, err := NewImageFromFile(resources + "jpg-24bit.jpg")
+	require.NoError(t, err)
+	defer img.Close()
+
+	distance := func(reference, candidate *ImageRef) (float64, error) {
+		score, err := SSIM(reference, candidate)
+		return 1 - score, err
+	}
+	_, result, err := img.ExportWithTarget(ImageTypeJPEG, ExportTarget{MaxDistance: 0.05, Distance: distance})
+	require.NoError(t, err)
+	assert.LessOrEqual(t, result.Distance, 0.05)
+}
+
+func TestImageRef_ExportWithTarget_InvalidTarget(t *testing.T) {
+	Startup(nil)
+
+	img, err := NewImageFromFile(resources + "jpg-24bit.jpg")
+	require.NoError(t, err)
+	defer img.Close()
+
+	_, _, err = img.ExportWithTarget(ImageTypeJPEG, ExportTarget{})
+	assert.Error(t, err)
+
+	_, _, err = img.ExportWithTarget(ImageTypeJPEG, ExportTarget{MaxDistance: 1})
+	assert.Error(t, err)
+
+	_, _, err = img.ExportWithTarget(ImageTypePNG, ExportTarget{MaxBytes: 1000})
+	assert.True(t, errors.Is(err, ErrUnsupportedImageFormat))
+}
+
+// The savers behind each format ExportWithTarget can search, for skipping
+// tests on libvips builds without them.
+var targetSavers = map[ImageType]string{
+	ImageTypeAVIF: "heifsave",
+	ImageTypeJXL:  "jxlsave",
+	ImageTypeHEIF: "heifsave",
+}
+
+func TestImageRef_ExportWithTarget_MaxBytesFormats(t *testing.T) {
+	Startup(nil)
+
+	for _, format := range []ImageType{ImageTypeAVIF, ImageTypeJXL, ImageTypeHEIF} {
+		t.Run(ImageTypes[format], func(t *testing.T) {
+			if !HasOperation(targetSavers[format]) || !IsTypeSupported(format) {
+				t.Skipf("%s is not supported by this libvips", targetSavers[format])
+			}
+
+			img, err := NewImageFromFile(resources + "jpg-24bit.jpg")
+			require.NoError(t, err)
+			defer img.Close()
+
+			full, err := img.exportAtQuality(format, 95, false)
+			require.NoError(t, err)
+			low, err := img.exportAtQuality(format, 5, false)
+			require.NoError(t, err)
+			require.Less(t, len(low), len(full), "quality must change the encoding")
+			budget := (len(full) + len(low)) / 2
+
+			buf, result, err := img.ExportWithTarget(format, ExportTarget{MaxBytes: budget})
+			require.NoError(t, err)
+			assert.LessOrEqual(t, len(buf), budget)
+			assert.Equal(t, len(buf), result.Size)
+			assert.Greater(t, result.Quality, 5)
+			assert.Less(t, result.Quality, 95)
+		})
+	}
+}
+
+func TestImageRef_ExportWithTarget_StripMetadata(t *testing.T) {
+	Startup(nil)
+
+	for _, format := range []ImageType{ImageTypeJPEG, ImageTypeAVIF, ImageTypeJXL, ImageTypeHEIF} {
+		t.Run(ImageTypes[format], func(t *testing.T) {
+			if saver, ok := targetSavers[format]; ok && (!HasOperation(saver) || !IsTypeSupported(format)) {
+				t.Skipf("%s is not supported by this libvips", saver)
+			}
+
+			img, err := NewImageFromFile(resources + "with_exif_orientation_top_left.jpg")
+			require.NoError(t, err)
+			defer img.Close()
+			require.True(t, img.HasExif())
+
+			target := ExportTarget{MaxBytes: 1 << 30, MaxAttempts: 1}
+			kept, _, err := img.ExportWithTarget(format, target)
+			require.NoError(t, err)
+			target.StripMetadata = true
+			stripped, _, err := img.ExportWithTarget(format, target)
+			require.NoError(t, err)
+
+			keptImage, err := NewImageFromBuffer(kept)
+			require.NoError(t, err)
+			defer keptImage.Close()
+			if !keptImage.HasExif() {
+				t.Skip("the saver does not write EXIF on this libvips")
+			}
+
+			strippedImage, err := NewImageFromBuffer(stripped)
+			require.NoError(t, err)
+			defer strippedImage.Close()
+			assert.False(t, strippedImage.HasExif())
+		})
+	}
+}
@@ -0,0 +1,192 @@
+package vips
+
+// #include "foreign.h"
+import "C"
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strings"
+)
+
+// fileSniffSize is how much of a file is read to determine its format.
+const fileSniffSize = 4096
+
+// vipsLoadFromFile opens filename with the libvips file loader for its
+// format, so that libvips reads the file itself and no pixels are decoded
+// until they are needed. It returns ImageTypeUnknown, and no error, when
+// the format cannot be told from the start of the file.
+func vipsLoadFromFile(ctx context.Context, filename string, params *ImportParams) (*C.VipsImage, ImageType, ImageType, error) {
+	f, err := os.Open(filename)
+	if err != nil {
+		return nil, ImageTypeUnknown, ImageTypeUnknown, err
+	}
+	header := make([]byte, fileSniffSize)
+	n, err := io.ReadFull(f, header)
+	var size int64
+	if info, statErr := f.Stat(); statErr == nil {
+		size = info.Size()
+	}
+	f.Close()
+	if err != nil && !errors.Is(err, io.ErrUnexpectedEOF) && !errors.Is(err, io.EOF) {
+		return nil, ImageTypeUnknown, ImageTypeUnknown, err
+	}
+
+	originalType := DetermineImageType(header[:n])
+	if originalType == ImageTypeUnknown {
+		return nil, ImageTypeUnknown, ImageTypeUnknown, nil
+	}
+	currentType := originalType
+	if isNeedToChangeLoaderToMagick(originalType) {
+		currentType = ImageTypeMagick
+	}
+
+	op := startOp(ctx, "load_file", nil)
+	defer op.end()
+
+	if !IsTypeSupported(currentType) {
+		op.loaded(nil, currentType, size)
+		govipsLog("govips", LogLevelInfo, fmt.Sprintf("failed to understand image format of %s", filename))
+		return nil, currentType, originalType, op.fail(ErrUnsupportedImageFormat)
+	}
+
+	importParams, cleanup := createImportParams(currentType, params)
+	defer cleanup()
+	cFileName := C.CString(filename)
+	defer freeCString(cFileName)
+
+	if err := C.load_from_file(&importParams, cFileName); err != 0 {
+		return nil, currentType, originalType, op.fail(handleImageError(importParams.outputImage))
+	}
+	op.loaded(importParams.outputImage, currentType, size)
+
+	return importParams.outputImage, currentType, originalType, nil
+}
+
+// SaveToFile encodes the image and writes it to the file at path with the
+// libvips file saver, without holding the encoded image in Go memory. The
+// format is params.Format, or when that is unset, the format of the
+// extension of path, such as ".jpg" or ".webp". params may be nil for the
+// format's default export settings.
+//
+// Supported formats: ImageTypeJPEG, ImageTypePNG, ImageTypeWEBP,
+// ImageTypeHEIF, ImageTypeAVIF, ImageTypeTIFF, ImageTypeGIF,
+// ImageTypeJP2K and ImageTypeJXL.
+func (r *ImageRef) SaveToFile(path string, params *ExportParams) error {
+	format := ImageTypeUnknown
+	if params != nil {
+		format = params.Format
+	}
+	if format == ImageTypeUnknown {
+		format = ImageTypeFromExt(path)
+	}
+	if format == ImageTypeUnknown {
+		return fmt.Errorf("cannot determine the format to save %s", path)
+	}
+	if !IsTypeSupported(format) {
+		return fmt.Errorf("cannot save to %#v", ImageTypes[format])
+	}
+
+	r.lock.Lock()
+	defer r.lock.Unlock()
+	defer runtime.KeepAlive(r)
+
+	if r.image == nil {
+		return errors.New("attempt to save a closed ImageRef")
+	}
+
+	opts, err := fileSaveOptions(format, params)
+	if err != nil {
+		return err
+	}
+
+	return vipsSaveToFile(r.Context(), r.image, opts, format, path)
+}
+
+// vipsSaveToFile saves to a temporary file next to path and renames it
+// over path once the save succeeds, so that a failed save leaves no
+// partial file and an image can be saved over the file it was lazily
+// loaded from. The file keeps the permissions of the file it replaces.
+func vipsSaveToFile(ctx context.Context, in *C.VipsImage, opts SaveOptions, format ImageType, path string) error {
+	op := startOp(ctx, "save_"+ImageTypes[format]+"_file", in)
+	defer op.end()
+	op.attrs.Format = format
+
+	mode := os.FileMode(0o644)
+	if info, err := os.Stat(path); err == nil {
+		mode = info.Mode().Perm()
+	}
+	tmp, err := os.CreateTemp(filepath.Dir(path), "."+filepath.Base(path)+".*")
+	if err != nil {
+		return op.fail(err)
+	}
+	tmpName := tmp.Name()
+	tmp.Close()
+
+	cFileName := C.CString(tmpName)
+	defer freeCString(cFileName)
+
+	params, cleanup := newSaveParams(in, opts)
+	defer cleanup()
+
+	if err := C.save_to_file(&params, cFileName); err != 0 {
+		os.Remove(tmpName)
+		return op.fail(handleVipsError())
+	}
+	if info, err := os.Stat(tmpName); err == nil {
+		op.attrs.BytesOut = info.Size()
+	}
+	if err := os.Chmod(tmpName, mode); err != nil {
+		os.Remove(tmpName)
+		return op.fail(err)
+	}
+	if err := os.Rename(tmpName, path); err != nil {
+		os.Remove(tmpName)
+		return op.fail(err)
+	}
+	return nil
+}
+
+// fileSaveOptions builds the saver options for SaveToFile with the same
+// ExportParams mapping as Export.
+func fileSaveOptions(format ImageType, params *ExportParams) (SaveOptions, error) {
+	switch format {
+	case ImageTypeAVIF:
+		return avifsaveOptions(*avifParamsFromExport(params)), nil
+	case ImageTypeJP2K:
+		return jp2ksaveOptions(*jp2kParamsFromExport(params)), nil
+	case ImageTypeJXL:
+		return jxlsaveOptions(*jxlParamsFromExport(params)), nil
+	default:
+		return streamSaveOptions(format, params)
+	}
+}
+
+// fileExtImageTypes maps file extensions to the formats SaveToFile can
+// write, including the common aliases of the canonical FileExt.
+var fileExtImageTypes = map[string]ImageType{
+	".jpg":  ImageTypeJPEG,
+	".jpeg": ImageTypeJPEG,
+	".png":  ImageTypePNG,
+	".webp": ImageTypeWEBP,
+	".heic": ImageTypeHEIF,
+	".heif": ImageTypeHEIF,
+	".avif": ImageTypeAVIF,
+	".tif":  ImageTypeTIFF,
+	".tiff": ImageTypeTIFF,
+	".gif":  ImageTypeGIF,
+	".jp2":  ImageTypeJP2K,
+	".j2k":  ImageTypeJP2K,
+	".jxl":  ImageTypeJXL,
+}
+
+// ImageTypeFromExt returns the format SaveToFile writes for path, chosen by
+// its extension. It returns ImageTypeUnknown for other extensions.
+func ImageTypeFromExt(path string) ImageType {
+	return fileExtImageTypes[strings.ToLower(filepath.Ext(path))]
+}
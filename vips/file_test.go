@@ -0,0 +1,164 @@
+package vips
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestLoadImageFromFile_MatchesBuffer(t *testing.T) {
+	require.NoError(t, Startup(nil))
+
+	for _, name := range []string{"jpg-24bit.jpg", "png-24bit.png"} {
+		t.Run(name, func(t *testing.T) {
+			fromFile, err := LoadImageFromFile(resources+name, nil)
+			require.NoError(t, err)
+			defer fromFile.Close()
+
+			fromBuffer, err := NewImageFromBuffer(mustReadFile(t, resources+name))
+			require.NoError(t, err)
+			defer fromBuffer.Close()
+
+			assert.Equal(t, fromBuffer.Format(), fromFile.Format())
+			assert.Equal(t, fromBuffer.OriginalFormat(), fromFile.OriginalFormat())
+			assert.Equal(t, fromBuffer.Width(), fromFile.Width())
+			assert.Equal(t, fromBuffer.Height(), fromFile.Height())
+			assert.Equal(t, fromBuffer.Bands(), fromFile.Bands())
+
+			a, err := fromFile.ToBytes()
+			require.NoError(t, err)
+			b, err := fromBuffer.ToBytes()
+			require.NoError(t, err)
+			assert.Equal(t, b, a)
+		})
+	}
+}
+
+func TestLoadImageFromFile_Sequential(t *testing.T) {
+	require.NoError(t, Startup(nil))
+
+	params := NewImportParams()
+	params.Access.Set(AccessSequential)
+	image, err := LoadImageFromFile(resources+"png-24bit.png", params)
+	require.NoError(t, err)
+	defer image.Close()
+
+	require.NoError(t, image.Resize(0.25, KernelLinear))
+	_, _, err = image.ExportJpeg(NewJpegExportParams())
+	require.NoError(t, err)
+}
+
+func TestLoadImageFromFile_Errors(t *testing.T) {
+	require.NoError(t, Startup(nil))
+
+	_, err := LoadImageFromFile(filepath.Join(t.TempDir(), "missing.jpg"), nil)
+	assert.True(t, os.IsNotExist(err))
+
+	path := filepath.Join(t.TempDir(), "text.jpg")
+	require.NoError(t, os.WriteFile(path, []byte("not an image"), 0o644))
+	_, err = LoadImageFromFile(path, nil)
+	assert.Error(t, err)
+}
+
+func TestImageRef_SaveToFile(t *testing.T) {
+	require.NoError(t, Startup(nil))
+
+	image, err := NewImageFromFile(resources + "png-24bit.png")
+	require.NoError(t, err)
+	defer image.Close()
+	require.NoError(t, image.Resize(0.25, KernelLinear))
+
+	dir := t.TempDir()
+	for name, format := range map[string]ImageType{
+		"out.jpg":  ImageTypeJPEG,
+		"out.PNG":  ImageTypePNG,
+		"out.webp": ImageTypeWEBP,
+		"out.tif":  ImageTypeTIFF,
+	} {
+		t.Run(name, func(t *testing.T) {
+			path := filepath.Join(dir, name)
+			require.NoError(t, image.SaveToFile(path, nil))
+
+			saved, err := NewImageFromFile(path)
+			require.NoError(t, err)
+			defer saved.Close()
+			assert.Equal(t, format, saved.Format())
+			assert.Equal(t, image.Width(), saved.Width())
+			assert.Equal(t, image.Height(), saved.Height())
+		})
+	}
+}
+
+func TestImageRef_SaveToFile_ExplicitFormat(t *testing.T) {
+	require.NoError(t, Startup(nil))
+
+	image, err := NewImageFromFile(resources + "jpg-24bit.jpg")
+	require.NoError(t, err)
+	defer image.Close()
+
+	path := filepath.Join(t.TempDir(), "image")
+	params := NewDefaultPNGExportParams()
+	require.NoError(t, image.SaveToFile(path, params))
+
+	saved, err := NewImageFromFile(path)
+	require.NoError(t, err)
+	defer saved.Close()
+	assert.Equal(t, ImageTypePNG, saved.Format())
+
+	err = image.SaveToFile(filepath.Join(t.TempDir(), "image.xyz"), nil)
+	assert.Error(t, err)
+}
+
+func TestFileSaveOptions(t *testing.T) {
+	params := &ExportParams{Quality: 60, StripMetadata: true, Effort: 3}
+
+	o, err := fileSaveOptions(ImageTypeAVIF, params)
+	require.NoError(t, err)
+	avif := o.(*HeifsaveOptions)
+	assert.Equal(t, 60, *avif.Q)
+	assert.True(t, *avif.Strip)
+
+	o, err = fileSaveOptions(ImageTypeJXL, params)
+	require.NoError(t, err)
+	jxl := o.(*JxlsaveOptions)
+	assert.Equal(t, 60, *jxl.Q)
+	assert.Equal(t, 3, *jxl.Effort)
+	assert.True(t, *jxl.Strip)
+
+	// JPEG2000 keeps its default tiling, which ExportParams cannot set.
+	o, err = fileSaveOptions(ImageTypeJP2K, params)
+	require.NoError(t, err)
+	jp2k := o.(*Jp2ksaveOptions)
+	assert.Equal(t, 60, *jp2k.Q)
+	assert.Equal(t, 512, *jp2k.TileWidth)
+}
+
+func TestImageRef_SaveToFile_OverSource(t *testing.T) {
+	require.NoError(t, Startup(nil))
+
+	path := filepath.Join(t.TempDir(), "image.jpg")
+	require.NoError(t, os.WriteFile(path, mustReadFile(t, resources+"jpg-24bit.jpg"), 0o600))
+
+	image, err := NewImageFromFile(path)
+	require.NoError(t, err)
+	defer image.Close()
+	width := image.Width()
+	require.NoError(t, image.Resize(0.5, KernelLinear))
+	require.NoError(t, image.SaveToFile(path, nil))
+
+	info, err := os.Stat(path)
+	require.NoError(t, err)
+	assert.Equal(t, os.FileMode(0o600), info.Mode().Perm())
+
+	saved, err := NewImageFromFile(path)
+	require.NoError(t, err)
+	defer saved.Close()
+	assert.Equal(t, width/2, saved.Width())
+
+	entries, err := os.ReadDir(filepath.Dir(path))
+	require.NoError(t, err)
+	assert.Len(t, entries, 1)
+}
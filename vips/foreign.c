@@ -175,6 +175,16 @@ int set_magickload_options(VipsOperation *operation, LoadParams *params) {
   return 0;
 }
 
+// apply_load_options applies the generated options of params when
+// operationName is a variant of their loader, and ignores them otherwise.
+int apply_load_options(VipsOperation *operation, const char *operationName,
+                       LoadParams *params) {
+  if (!params->options || !g_str_has_prefix(operationName, params->loader)) {
+    return 0;
+  }
+  return params->setOptions(operation, params->options);
+}
+
 int load_buffer(const char *operationName, void *buf, size_t len,
                 LoadParams *params, SetLoadOptionsFn setLoadOptions) {
   VipsBlob *blob = vips_blob_new(NULL, buf, len);
@@ -191,7 +201,8 @@ int load_buffer(const char *operationName, void *buf, size_t len,
 
   vips_area_unref(VIPS_AREA(blob));
 
-  if (setLoadOptions(operation, params)) {
+  if (setLoadOptions(operation, params) ||
+      apply_load_options(operation, operationName, params)) {
     vips_object_unref_outputs(VIPS_OBJECT(operation));
     g_object_unref(operation);
     return 1;
@@ -211,232 +222,106 @@ int load_buffer(const char *operationName, void *buf, size_t len,
   return 0;
 }
 
-typedef int (*SetSaveOptionsFn)(VipsOperation *operation, SaveParams *params);
-
-int save_buffer(const char *operationName, SaveParams *params,
-                SetSaveOptionsFn setSaveOptions) {
-  VipsBlob *blob;
+// load_file is like load_buffer for a file. The load is not cached: the
+// operation cache is keyed on the filename, so a file changed on disk
+// would otherwise load as its old contents.
+int load_file(const char *operationName, const char *filename,
+              LoadParams *params, SetLoadOptionsFn setLoadOptions) {
   VipsOperation *operation = vips_operation_new(operationName);
   if (!operation) {
     return 1;
   }
 
-  if (vips_object_set(VIPS_OBJECT(operation), "in", params->inputImage, NULL)) {
+  if (vips_object_set(VIPS_OBJECT(operation), "filename", filename, NULL)) {
+    g_object_unref(operation);
     return 1;
   }
 
-  if (setSaveOptions(operation, params)) {
+  if (setLoadOptions(operation, params) ||
+      apply_load_options(operation, operationName, params)) {
+    vips_object_unref_outputs(VIPS_OBJECT(operation));
     g_object_unref(operation);
     return 1;
   }
 
-  if (vips_cache_operation_buildp(&operation)) {
+  if (vips_object_build(VIPS_OBJECT(operation))) {
     vips_object_unref_outputs(VIPS_OBJECT(operation));
     g_object_unref(operation);
     return 1;
   }
 
-  g_object_get(VIPS_OBJECT(operation), "buffer", &blob, NULL);
-  g_object_unref(operation);
-
-  VipsArea *area = VIPS_AREA(blob);
+  g_object_get(VIPS_OBJECT(operation), "out", &params->outputImage, NULL);
 
-  params->outputBuffer = (char *)(area->data);
-  params->outputLen = area->length;
-  area->free_fn = NULL;
-  vips_area_unref(area);
+  vips_object_unref_outputs(VIPS_OBJECT(operation));
+  g_object_unref(operation);
 
   return 0;
 }
 
-// https://libvips.github.io/libvips/API/current/VipsForeignSave.html#vips-jpegsave-buffer
-int set_jpegsave_options(VipsOperation *operation, SaveParams *params) {
-  int ret = vips_object_set(
-      VIPS_OBJECT(operation), "strip", params->stripMetadata, "optimize_coding",
-      params->jpegOptimizeCoding, "interlace", params->interlace,
-      "subsample_mode", params->jpegSubsample, "trellis_quant",
-      params->jpegTrellisQuant, "overshoot_deringing",
-      params->jpegOvershootDeringing, "optimize_scans",
-      params->jpegOptimizeScans, "quant_table", params->jpegQuantTable, NULL);
-
-  if (!ret && params->quality) {
-    ret = vips_object_set(VIPS_OBJECT(operation), "Q", params->quality, NULL);
-  }
-
-  return ret;
-}
-
-// https://libvips.github.io/libvips/API/current/VipsForeignSave.html#vips-pngsave-buffer
-int set_pngsave_options(VipsOperation *operation, SaveParams *params) {
-  int ret =
-      vips_object_set(VIPS_OBJECT(operation), "strip", params->stripMetadata,
-                      "compression", params->pngCompression, "interlace",
-                      params->interlace, "filter", params->pngFilter, "palette",
-                      params->pngPalette, NULL);
-
-  if (!ret && params->quality) {
-    ret = vips_object_set(VIPS_OBJECT(operation), "Q", params->quality, NULL);
-  }
-
-  if (!ret && params->pngDither) {
-    ret = vips_object_set(VIPS_OBJECT(operation), "dither", params->pngDither, NULL);
-  }
-
-  if (!ret && params->pngBitdepth) {
-    ret = vips_object_set(VIPS_OBJECT(operation), "bitdepth", params->pngBitdepth, NULL);
+static int save_buffer(const char *operationName, SaveParams *params) {
+  VipsBlob *blob;
+  VipsOperation *operation = vips_operation_new(operationName);
+  if (!operation) {
+    return 1;
   }
 
-  // TODO: Handle `profile` param.
-
-  return ret;
-}
-
-// https://github.com/libvips/libvips/blob/master/libvips/foreign/webpsave.c#L524
-// https://libvips.github.io/libvips/API/current/VipsForeignSave.html#vips-webpsave-buffer
-int set_webpsave_options(VipsOperation *operation, SaveParams *params) {
-  int ret =
-      vips_object_set(VIPS_OBJECT(operation),
-                      "strip", params->stripMetadata,
-                      "lossless", params->webpLossless,
-                      "near_lossless", params->webpNearLossless,
-                      "reduction_effort", params->webpReductionEffort,
-                      "profile", params->webpIccProfile ? params->webpIccProfile : "none",
-                      "min_size", params->webpMinSize,
-                      "kmin", params->webpKMin,
-                      "kmax", params->webpKMax,
-                      NULL);
-
-  if (!ret && params->quality) {
-    ret = vips_object_set(VIPS_OBJECT(operation), "Q", params->quality, NULL);
+  if (vips_object_set(VIPS_OBJECT(operation), "in", params->inputImage, NULL)) {
+    g_object_unref(operation);
+    return 1;
   }
 
-  if (!ret && params->webpTargetSize) {
-    ret = vips_object_set(VIPS_OBJECT(operation), "target_size", params->webpTargetSize, NULL);
+  if (params->setOptions(operation, params->options)) {
+    g_object_unref(operation);
+    return 1;
   }
 
-  return ret;
-}
-
-// https://libvips.github.io/libvips/API/current/VipsForeignSave.html#vips-tiffsave-buffer
-int set_tiffsave_options(VipsOperation *operation, SaveParams *params) {
-  int ret = vips_object_set(
-      VIPS_OBJECT(operation), "strip", params->stripMetadata, "compression",
-      params->tiffCompression, "predictor", params->tiffPredictor, "pyramid",
-      params->tiffPyramid, "tile_height", params->tiffTileHeight, "tile_width",
-      params->tiffTileWidth, "tile", params->tiffTile, NULL);
-
-  if (!ret && params->quality) {
-    ret = vips_object_set(VIPS_OBJECT(operation), "Q", params->quality, NULL);
+  if (vips_cache_operation_buildp(&operation)) {
+    vips_object_unref_outputs(VIPS_OBJECT(operation));
+    g_object_unref(operation);
+    return 1;
   }
 
-  return ret;
-}
+  g_object_get(VIPS_OBJECT(operation), "buffer", &blob, NULL);
+  g_object_unref(operation);
 
-// https://libvips.github.io/libvips/API/current/VipsForeignSave.html#vips-magicksave-buffer
-int set_magicksave_options(VipsOperation *operation, SaveParams *params) {
-  int ret = vips_object_set(VIPS_OBJECT(operation), "format", params->magickFormat,
-                            "optimize_gif_frames", params->magickOptimizeGifFrames,
-                            "optimize_gif_transparency", params->magickOptimizeGifTransparency,
-                            "bitdepth", params->magickBitDepth, NULL);
+  VipsArea *area = VIPS_AREA(blob);
 
-  if (!ret && params->quality) {
-    ret = vips_object_set(VIPS_OBJECT(operation), "quality", params->quality,
-                          NULL);
-  }
-  return ret;
-}
+  params->outputBuffer = (char *)(area->data);
+  params->outputLen = area->length;
+  area->free_fn = NULL;
+  vips_area_unref(area);
 
-// https://libvips.github.io/libvips/API/current/VipsForeignSave.html#vips-gifsave-buffer
-int set_gifsave_options(VipsOperation *operation, SaveParams *params) {
-  int ret = 0;
-  // See for argument values: https://www.libvips.org/API/current/VipsForeignSave.html#vips-gifsave
-  if (params->gifDither > 0.0 && params->gifDither <= 10) {
-    ret = vips_object_set(VIPS_OBJECT(operation), "dither", params->gifDither, NULL);
-  }
-  if (params->gifEffort >= 1 && params->gifEffort <= 10) {
-    ret = vips_object_set(VIPS_OBJECT(operation), "effort", params->gifEffort, NULL);
-  }
-  if (params->gifBitdepth >= 1 && params->gifBitdepth <= 8) {
-      ret = vips_object_set(VIPS_OBJECT(operation), "bitdepth", params->gifBitdepth, NULL);
-  }
-  return ret;
+  return 0;
 }
 
-// https://github.com/libvips/libvips/blob/master/libvips/foreign/heifsave.c#L653
-int set_heifsave_options(VipsOperation *operation, SaveParams *params) {
-  int ret = vips_object_set(VIPS_OBJECT(operation), "lossless",
-                            params->heifLossless, NULL);
-
-#if (VIPS_MAJOR_VERSION >= 8) && (VIPS_MINOR_VERSION >= 13)
-  if (!ret && params->heifBitdepth && params->heifEffort) {
-    ret = vips_object_set(VIPS_OBJECT(operation), "bitdepth",
-                          params->heifBitdepth, "effort", params->heifEffort,
-                          NULL);
-  }
-#else
-  if (!ret && params->heifEffort) {
-    ret = vips_object_set(VIPS_OBJECT(operation), "speed", params->heifEffort,
-                          NULL);
-  }
-#endif
-
-  if (!ret && params->quality) {
-    ret = vips_object_set(VIPS_OBJECT(operation), "Q", params->quality, NULL);
+static int save_file(const char *operationName, const char *filename,
+                     SaveParams *params) {
+  VipsOperation *operation = vips_operation_new(operationName);
+  if (!operation) {
+    return 1;
   }
 
-  return ret;
-}
-
-// https://github.com/libvips/libvips/blob/master/libvips/foreign/heifsave.c#L653
-int set_avifsave_options(VipsOperation *operation, SaveParams *params) {
-  int ret = vips_object_set(VIPS_OBJECT(operation), "strip", params->stripMetadata, "compression",
-                            VIPS_FOREIGN_HEIF_COMPRESSION_AV1, "lossless",
-                            params->heifLossless, NULL);
-
-#if (VIPS_MAJOR_VERSION >= 8) && (VIPS_MINOR_VERSION >= 13)
-  if (!ret && params->heifBitdepth && params->heifEffort) {
-    ret = vips_object_set(VIPS_OBJECT(operation), "bitdepth",
-                          params->heifBitdepth, "effort", params->heifEffort,
-                          NULL);
-  }
-#else
-  if (!ret && params->heifEffort) {
-    ret = vips_object_set(VIPS_OBJECT(operation), "speed", params->heifEffort,
-                          NULL);
+  if (vips_object_set(VIPS_OBJECT(operation), "in", params->inputImage,
+                      "filename", filename, NULL)) {
+    g_object_unref(operation);
+    return 1;
   }
-#endif
 
-  if (!ret && params->quality) {
-    ret = vips_object_set(VIPS_OBJECT(operation), "Q", params->quality, NULL);
+  if (params->setOptions(operation, params->options)) {
+    g_object_unref(operation);
+    return 1;
   }
 
-  return ret;
-}
-
-int set_jp2ksave_options(VipsOperation *operation, SaveParams *params) {
-  int ret = vips_object_set(
-      VIPS_OBJECT(operation), "subsample_mode", params->jpegSubsample,
-      "tile_height", params->jp2kTileHeight, "tile_width", params->jp2kTileWidth,
-      "lossless", params->jp2kLossless, NULL);
-
-  if (!ret && params->quality) {
-    ret = vips_object_set(VIPS_OBJECT(operation), "Q", params->quality, NULL);
+  if (vips_cache_operation_buildp(&operation)) {
+    vips_object_unref_outputs(VIPS_OBJECT(operation));
+    g_object_unref(operation);
+    return 1;
   }
 
-  return ret;
-}
-
-int set_jxlsave_options(VipsOperation *operation, SaveParams *params) {
-  int ret = vips_object_set(
-      VIPS_OBJECT(operation), "tier", params->jxlTier,
-      "distance", params->jxlDistance, "effort", params->jxlEffort,
-      "lossless", params->jxlLossless, NULL);
-
-  if (!ret && params->quality) {
-    ret = vips_object_set(VIPS_OBJECT(operation), "Q", params->quality, NULL);
-  }
+  vips_object_unref_outputs(VIPS_OBJECT(operation));
+  g_object_unref(operation);
 
-  return ret;
+  return 0;
 }
 
 int load_from_buffer(LoadParams *params, void *buf, size_t len) {
@@ -483,43 +368,56 @@ int load_from_buffer(LoadParams *params, void *buf, size_t len) {
   return 1;
 }
 
-int save_to_buffer(SaveParams *params) {
-  switch (params->outputFormat) {
+int load_from_file(LoadParams *params, const char *filename) {
+  switch (params->inputFormat) {
     case JPEG:
-      return save_buffer("jpegsave_buffer", params, set_jpegsave_options);
+      return load_file("jpegload", filename, params, set_jpegload_options);
     case PNG:
-      return save_buffer("pngsave_buffer", params, set_pngsave_options);
+      return load_file("pngload", filename, params, set_pngload_options);
     case WEBP:
-      return save_buffer("webpsave_buffer", params, set_webpsave_options);
+      return load_file("webpload", filename, params, set_webpload_options);
     case HEIF:
-      return save_buffer("heifsave_buffer", params, set_heifsave_options);
+      return load_file("heifload", filename, params, set_heifload_options);
     case TIFF:
-      return save_buffer("tiffsave_buffer", params, set_tiffsave_options);
+      return load_file("tiffload", filename, params, set_tiffload_options);
+    case SVG:
+      return load_file("svgload", filename, params, set_svgload_options);
     case GIF:
-#if (VIPS_MAJOR_VERSION >= 8) && (VIPS_MINOR_VERSION >= 12)
-      return save_buffer("gifsave_buffer", params, set_gifsave_options);
-#else
-      // Gif save through ImageMagick below Vips Version 8.12
-      params->magickFormat="GIF";
-      params->magickOptimizeGifFrames=FALSE;
-      params->magickOptimizeGifTransparency=FALSE;
-      params->magickBitDepth=params->gifBitdepth;
-      return save_buffer("magicksave_buffer", params, set_magicksave_options);
-#endif
+      return load_file("gifload", filename, params, set_gifload_options);
+    case PDF:
+      return load_file("pdfload", filename, params, set_pdfload_options);
+    case MAGICK:
+      return load_file("magickload", filename, params, set_magickload_options);
     case AVIF:
-      return save_buffer("heifsave_buffer", params, set_avifsave_options);
+      return load_file("heifload", filename, params, set_heifload_options);
     case JP2K:
-      return save_buffer("jp2ksave_buffer", params, set_jp2ksave_options);
+      return load_file("jp2kload", filename, params, set_jp2kload_options);
     case JXL:
-      return save_buffer("jxlsave_buffer", params, set_jxlsave_options);
-    case MAGICK:
-          return save_buffer("magicksave_buffer", params, set_magicksave_options);
+      return load_file("jxlload", filename, params, set_jxlload_options);
     default:
-      g_warning("Unsupported output type given: %d", params->outputFormat);
+      g_warning("Unsupported input type given: %d", params->inputFormat);
   }
   return 1;
 }
 
+// load_header_from_file opens filename, which may carry load options in
+// brackets, without decoding any pixels.
+int load_header_from_file(const char *filename, VipsImage **out) {
+  *out = vips_image_new_from_file(filename, NULL);
+  return *out == NULL;
+}
+
+int save_to_buffer(SaveParams *params) {
+  char *operationName = g_strdup_printf("%s_buffer", params->saver);
+  int code = save_buffer(operationName, params);
+  g_free(operationName);
+  return code;
+}
+
+int save_to_file(SaveParams *params, const char *filename) {
+  return save_file(params->saver, filename, params);
+}
+
 LoadParams create_load_params(ImageType inputFormat) {
   Param defaultParam = {};
   LoadParams p = {
@@ -536,71 +434,9 @@ LoadParams create_load_params(ImageType inputFormat) {
       .heifThumbnail = defaultParam,
       .svgUnlimited = defaultParam,
       .access = defaultParam,
+      .loader = NULL,
+      .options = NULL,
+      .setOptions = NULL,
   };
   return p;
 }
-
-// TODO: Change to same pattern as ImportParams
-
-static SaveParams defaultSaveParams = {
-    .inputImage = NULL,
-    .outputBuffer = NULL,
-    .outputFormat = JPEG,
-    .outputLen = 0,
-
-    .interlace = FALSE,
-    .quality = 0,
-    .stripMetadata = FALSE,
-
-    .jpegOptimizeCoding = FALSE,
-    .jpegSubsample = VIPS_FOREIGN_SUBSAMPLE_ON,
-    .jpegTrellisQuant = FALSE,
-    .jpegOvershootDeringing = FALSE,
-    .jpegOptimizeScans = FALSE,
-    .jpegQuantTable = 0,
-
-    .pngCompression = 6,
-    .pngPalette = FALSE,
-    .pngBitdepth = 0,
-    .pngDither = 0,
-    .pngFilter = VIPS_FOREIGN_PNG_FILTER_NONE,
-
-    .gifDither = 0.0,
-    .gifEffort = 0,
-    .gifBitdepth = 0,
-
-    .webpLossless = FALSE,
-    .webpNearLossless = FALSE,
-    .webpReductionEffort = 4,
-    .webpIccProfile = NULL,
-    .webpKMax = 0,
-    .webpKMin = 0,
-    .webpMinSize = FALSE,
-    .webpTargetSize = 0,
-
-    .heifBitdepth = 8,
-    .heifLossless = FALSE,
-    .heifEffort = 5,
-
-    .tiffCompression = VIPS_FOREIGN_TIFF_COMPRESSION_LZW,
-    .tiffPredictor = VIPS_FOREIGN_TIFF_PREDICTOR_HORIZONTAL,
-    .tiffPyramid = FALSE,
-    .tiffTile = FALSE,
-    .tiffTileHeight = 256,
-    .tiffTileWidth = 256,
-
-    .jp2kLossless = FALSE,
-    .jp2kTileHeight = 512,
-    .jp2kTileWidth = 512,
-
-    .jxlTier = 0,
-    .jxlDistance = 1.0,
-    .jxlEffort = 7,
-    .jxlLossless = FALSE,
-    };
-
-SaveParams create_save_params(ImageType outputFormat) {
-  SaveParams params = defaultSaveParams;
-  params.outputFormat = outputFormat;
-  return params;
-}
@@ -4,11 +4,14 @@ package vips
 import "C"
 import (
 	"bytes"
+	"context"
 	"encoding/xml"
 	"errors"
 	"fmt"
 	"math"
+	"mime"
 	"runtime"
+	"strings"
 	"unsafe"
 
 	"golang.org/x/net/html/charset"
@@ -70,6 +73,54 @@ var imageTypeExtensionMap = map[ImageType]string{
 	ImageTypePSD:    ".psd",
 }
 
+var imageTypeMimeTypeMap = map[ImageType]string{
+	ImageTypeGIF:  "image/gif",
+	ImageTypeJPEG: "image/jpeg",
+	ImageTypePDF:  "application/pdf",
+	ImageTypePNG:  "image/png",
+	ImageTypeSVG:  "image/svg+xml",
+	ImageTypeTIFF: "image/tiff",
+	ImageTypeWEBP: "image/webp",
+	ImageTypeHEIF: "image/heic",
+	ImageTypeBMP:  "image/bmp",
+	ImageTypeAVIF: "image/avif",
+	ImageTypeJP2K: "image/jp2",
+	ImageTypeJXL:  "image/jxl",
+	ImageTypePSD:  "image/vnd.adobe.photoshop",
+}
+
+// mimeTypeAliases maps non-canonical and legacy MIME types seen in the wild
+// to their ImageType.
+var mimeTypeAliases = map[string]ImageType{
+	"image/jpg":           ImageTypeJPEG,
+	"image/pjpeg":         ImageTypeJPEG,
+	"image/x-png":         ImageTypePNG,
+	"image/heif":          ImageTypeHEIF,
+	"image/heic-sequence": ImageTypeHEIF,
+	"image/heif-sequence": ImageTypeHEIF,
+	"image/x-ms-bmp":      ImageTypeBMP,
+	"image/jpx":           ImageTypeJP2K,
+	"image/x-tiff":        ImageTypeTIFF,
+	"image/x-psd":         ImageTypePSD,
+	"application/x-pdf":   ImageTypePDF,
+}
+
+// imageTypeNames maps the names ParseImageType accepts to the formats
+// ImageRef.Export writes.
+var imageTypeNames = map[string]ImageType{
+	"jpeg": ImageTypeJPEG,
+	"jpg":  ImageTypeJPEG,
+	"png":  ImageTypePNG,
+	"webp": ImageTypeWEBP,
+	"avif": ImageTypeAVIF,
+	"gif":  ImageTypeGIF,
+	"jxl":  ImageTypeJXL,
+	"heif": ImageTypeHEIF,
+	"heic": ImageTypeHEIF,
+	"tiff": ImageTypeTIFF,
+	"tif":  ImageTypeTIFF,
+}
+
 // ImageTypes defines the various image types supported by govips
 var ImageTypes = map[ImageType]string{
 	ImageTypeGIF:    "gif",
@@ -144,6 +195,50 @@ func (i ImageType) FileExt() string {
 	return ""
 }
 
+// MimeType returns the MIME type for the ImageType, suitable for a
+// Content-Type header. It returns an empty string for ImageTypeUnknown and
+// ImageTypeMagick, which have no single MIME type.
+func (i ImageType) MimeType() string {
+	return imageTypeMimeTypeMap[i]
+}
+
+// ParseMimeType returns the ImageType for a MIME type such as "image/webp".
+// Parameters (e.g. "; charset=...") and case are ignored, and common aliases
+// such as "image/jpg" are recognised. It returns ImageTypeUnknown if the MIME
+// type does not correspond to a known image type.
+func ParseMimeType(mimeType string) ImageType {
+	mediaType, _, err := mime.ParseMediaType(mimeType)
+	if err != nil {
+		mediaType = strings.ToLower(strings.TrimSpace(mimeType))
+	}
+	for imageType, m := range imageTypeMimeTypeMap {
+		if m == mediaType {
+			return imageType
+		}
+	}
+	if imageType, ok := mimeTypeAliases[mediaType]; ok {
+		return imageType
+	}
+	return ImageTypeUnknown
+}
+
+// ParseImageType returns the output format named name, such as "webp" or
+// "jpg". Case is ignored. It returns ImageTypeUnknown for names of formats
+// ImageRef.Export cannot write.
+func ParseImageType(name string) ImageType {
+	return imageTypeNames[strings.ToLower(name)]
+}
+
+// Name returns the short name of the ImageType, such as "jpeg" or "avif",
+// which ParseImageType accepts for the formats it knows.
+func (i ImageType) Name() string {
+	if i == ImageTypeAVIF {
+		// AVIF is loaded and saved by heifload, so ImageTypes names it heif.
+		return "avif"
+	}
+	return ImageTypes[i]
+}
+
 // IsTypeSupported checks whether given image type is supported by govips
 func IsTypeSupported(imageType ImageType) bool {
 	if err := startupIfNeeded(); err != nil {
@@ -334,7 +429,7 @@ func isNeedToChangeLoaderToMagick(t ImageType) bool {
 	return imageMagickTypes[t]
 }
 
-func vipsLoadFromBuffer(buf []byte, params *ImportParams) (*C.VipsImage, ImageType, ImageType, error) {
+func vipsLoadFromBuffer(ctx context.Context, buf []byte, params *ImportParams) (*C.VipsImage, ImageType, ImageType, error) {
 	src := buf
 	// Reference src here so it's not garbage collected during image initialization.
 	defer runtime.KeepAlive(src)
@@ -347,16 +442,22 @@ func vipsLoadFromBuffer(buf []byte, params *ImportParams) (*C.VipsImage, ImageTy
 		currentType = ImageTypeMagick
 	}
 
+	op := startOp(ctx, "load_buffer", nil)
+	defer op.end()
+
 	if !IsTypeSupported(currentType) {
 		govipsLog("govips", LogLevelInfo, fmt.Sprintf("failed to understand image format size=%d", len(src)))
-		return nil, currentType, originalType, ErrUnsupportedImageFormat
+		op.loaded(nil, currentType, int64(len(src)))
+		return nil, currentType, originalType, op.fail(ErrUnsupportedImageFormat)
 	}
 
-	importParams := createImportParams(currentType, params)
+	importParams, cleanup := createImportParams(currentType, params)
+	defer cleanup()
 
 	if err := C.load_from_buffer(&importParams, unsafe.Pointer(&src[0]), C.size_t(len(src))); err != 0 {
-		return nil, currentType, originalType, handleImageError(importParams.outputImage)
+		return nil, currentType, originalType, op.fail(handleImageError(importParams.outputImage))
 	}
+	op.loaded(importParams.outputImage, currentType, int64(len(src)))
 
 	return importParams.outputImage, currentType, originalType, nil
 }
@@ -379,7 +480,9 @@ func maybeSetDoubleParam(p Float64Parameter, cp *C.Param) {
 	}
 }
 
-func createImportParams(format ImageType, params *ImportParams) C.LoadParams {
+// createImportParams maps params onto the C load params. The returned
+// cleanup must be called once the load has completed.
+func createImportParams(format ImageType, params *ImportParams) (C.LoadParams, func()) {
 	p := C.create_load_params(C.ImageType(format))
 
 	maybeSetBoolParam(params.AutoRotate, &p.autorotate)
@@ -395,108 +498,123 @@ func createImportParams(format ImageType, params *ImportParams) C.LoadParams {
 	if params.Density.IsSet() {
 		C.set_double_param(&p.dpi, C.gdouble(params.Density.Get()))
 	}
-	return p
-}
-
-func newSaveParamsJPEG(in *C.VipsImage, params JpegExportParams) C.struct_SaveParams {
-	p := C.create_save_params(C.JPEG)
-	p.inputImage = in
-	p.stripMetadata = C.int(boolToInt(params.StripMetadata))
-	p.quality = C.int(params.Quality)
-	p.interlace = C.int(boolToInt(params.Interlace))
-	p.jpegOptimizeCoding = C.int(boolToInt(params.OptimizeCoding))
-	p.jpegSubsample = C.VipsForeignSubsample(params.SubsampleMode)
-	p.jpegTrellisQuant = C.int(boolToInt(params.TrellisQuant))
-	p.jpegOvershootDeringing = C.int(boolToInt(params.OvershootDeringing))
-	p.jpegOptimizeScans = C.int(boolToInt(params.OptimizeScans))
-	p.jpegQuantTable = C.int(params.QuantTable)
-	return p
-}
-
-func vipsSaveJPEGToBuffer(in *C.VipsImage, params JpegExportParams) ([]byte, error) {
-	incOpCounter("save_jpeg_buffer")
-
-	return vipsSaveToBuffer(newSaveParamsJPEG(in, params))
-}
-
-func newSaveParamsPNG(in *C.VipsImage, params PngExportParams) C.struct_SaveParams {
-	p := C.create_save_params(C.PNG)
-	p.inputImage = in
-	p.quality = C.int(params.Quality)
-	p.stripMetadata = C.int(boolToInt(params.StripMetadata))
-	p.interlace = C.int(boolToInt(params.Interlace))
-	p.pngCompression = C.int(params.Compression)
-	p.pngFilter = C.VipsForeignPngFilter(params.Filter)
-	p.pngPalette = C.int(boolToInt(params.Palette))
-	p.pngDither = C.double(params.Dither)
-	p.pngBitdepth = C.int(params.Bitdepth)
-	return p
-}
-
-func vipsSavePNGToBuffer(in *C.VipsImage, params PngExportParams) ([]byte, error) {
-	incOpCounter("save_png_buffer")
-
-	return vipsSaveToBuffer(newSaveParamsPNG(in, params))
-}
-
-// newSaveParamsWebP returns the populated params and a cleanup function
-// that must be called once the save has completed; it frees the ICC
-// profile C string, if one was allocated. Both the buffer and streaming
-// WebP savers go through here, so the TargetSize version guard covers
-// both paths.
-func newSaveParamsWebP(in *C.VipsImage, params WebpExportParams) (C.struct_SaveParams, func(), error) {
-	noop := func() {}
-	p := C.create_save_params(C.WEBP)
-	p.inputImage = in
-	p.stripMetadata = C.int(boolToInt(params.StripMetadata))
-	p.quality = C.int(params.Quality)
-	p.webpLossless = C.int(boolToInt(params.Lossless))
-	p.webpNearLossless = C.int(boolToInt(params.NearLossless))
-	p.webpReductionEffort = C.int(params.ReductionEffort)
-	p.webpMinSize = C.int(boolToInt(params.MinSize))
-	p.webpKMin = C.int(params.MinKeyFrames)
-	p.webpKMax = C.int(params.MaxKeyFrames)
 
+	if params.Options == nil {
+		return p, func() {}
+	}
+	options, setOptions, freeOptions := params.Options.cOptions()
+	loader := C.CString(params.Options.loadOperation())
+	p.loader = loader
+	p.options = options
+	p.setOptions = setOptions
+	return p, func() {
+		freeCString(loader)
+		freeOptions()
+	}
+}
+
+// The Export*Params types are mapped onto the generated saver options
+// below, setting the same options the hand-written C setters used to.
+
+func jpegsaveOptions(params JpegExportParams) *JpegsaveOptions {
+	o := &JpegsaveOptions{
+		Strip:              ptrTo(params.StripMetadata),
+		OptimizeCoding:     ptrTo(params.OptimizeCoding),
+		Interlace:          ptrTo(params.Interlace),
+		SubsampleMode:      ptrTo(params.SubsampleMode),
+		TrellisQuant:       ptrTo(params.TrellisQuant),
+		OvershootDeringing: ptrTo(params.OvershootDeringing),
+		OptimizeScans:      ptrTo(params.OptimizeScans),
+		QuantTable:         ptrTo(params.QuantTable),
+	}
+	if params.Quality != 0 {
+		o.Q = ptrTo(params.Quality)
+	}
+	return o
+}
+
+func vipsSaveJPEGToBuffer(ctx context.Context, in *C.VipsImage, params JpegExportParams) ([]byte, error) {
+	op := startOp(ctx, "save_jpeg_buffer", in)
+	defer op.end()
+	op.attrs.Format = ImageTypeJPEG
+
+	return op.saved(vipsSaveToBuffer(in, jpegsaveOptions(params)))
+}
+
+func pngsaveOptions(params PngExportParams) *PngsaveOptions {
+	o := &PngsaveOptions{
+		Strip:       ptrTo(params.StripMetadata),
+		Compression: ptrTo(params.Compression),
+		Interlace:   ptrTo(params.Interlace),
+		Filter:      ptrTo(params.Filter),
+		Palette:     ptrTo(params.Palette),
+	}
+	if params.Quality != 0 {
+		o.Q = ptrTo(params.Quality)
+	}
+	if params.Dither != 0 {
+		o.Dither = ptrTo(params.Dither)
+	}
+	if params.Bitdepth != 0 {
+		o.Bitdepth = ptrTo(params.Bitdepth)
+	}
+	return o
+}
+
+func vipsSavePNGToBuffer(ctx context.Context, in *C.VipsImage, params PngExportParams) ([]byte, error) {
+	op := startOp(ctx, "save_png_buffer", in)
+	defer op.end()
+	op.attrs.Format = ImageTypePNG
+
+	return op.saved(vipsSaveToBuffer(in, pngsaveOptions(params)))
+}
+
+// webpsaveOptions is shared by the buffer and streaming WebP savers, so
+// the TargetSize version guard covers both paths.
+func webpsaveOptions(params WebpExportParams) (*WebpsaveOptions, error) {
+	profile := params.IccProfile
+	if profile == "" {
+		profile = "none"
+	}
+	o := &WebpsaveOptions{
+		Strip:           ptrTo(params.StripMetadata),
+		Lossless:        ptrTo(params.Lossless),
+		NearLossless:    ptrTo(params.NearLossless),
+		ReductionEffort: ptrTo(params.ReductionEffort),
+		Profile:         ptrTo(profile),
+		MinSize:         ptrTo(params.MinSize),
+		Kmin:            ptrTo(params.MinKeyFrames),
+		Kmax:            ptrTo(params.MaxKeyFrames),
+	}
+	if params.Quality != 0 {
+		o.Q = ptrTo(params.Quality)
+	}
 	if params.TargetSize > 0 {
 		// webpsave's "target_size" property was added in libvips 8.17.4; setting
-		// it on an older libvips fails at the C layer with an opaque "no property
-		// named `target_size'" error. Fail clearly instead.
-		if MajorVersion < 8 || (MajorVersion == 8 && MinorVersion < 17) ||
-			(MajorVersion == 8 && MinorVersion == 17 && MicroVersion < 4) {
-			return p, noop, fmt.Errorf("WebpExportParams.TargetSize requires libvips 8.17.4+, found %s", Version)
+		// it on an older libvips fails at the C layer with an opaque
+		// "unsupported option" error. Fail clearly instead.
+		if err := requireLibvips("WebpExportParams.TargetSize", 8, 17, 4); err != nil {
+			return nil, err
 		}
-		p.webpTargetSize = C.int(params.TargetSize)
+		o.TargetSize = ptrTo(params.TargetSize)
 	}
-
-	cleanup := noop
-	if params.IccProfile != "" {
-		p.webpIccProfile = C.CString(params.IccProfile)
-		profile := p.webpIccProfile
-		cleanup = func() { C.free(unsafe.Pointer(profile)) }
-	}
-	return p, cleanup, nil
+	return o, nil
 }
 
-func vipsSaveWebPToBuffer(in *C.VipsImage, params WebpExportParams) ([]byte, error) {
-	incOpCounter("save_webp_buffer")
+func vipsSaveWebPToBuffer(ctx context.Context, in *C.VipsImage, params WebpExportParams) ([]byte, error) {
+	op := startOp(ctx, "save_webp_buffer", in)
+	defer op.end()
+	op.attrs.Format = ImageTypeWEBP
 
-	p, cleanup, err := newSaveParamsWebP(in, params)
+	o, err := webpsaveOptions(params)
 	if err != nil {
-		return nil, err
+		return nil, op.fail(err)
 	}
-	defer cleanup()
 
-	return vipsSaveToBuffer(p)
+	return op.saved(vipsSaveToBuffer(in, o))
 }
 
-func newSaveParamsTIFF(in *C.VipsImage, params TiffExportParams) C.struct_SaveParams {
-	p := C.create_save_params(C.TIFF)
-	p.inputImage = in
-	p.stripMetadata = C.int(boolToInt(params.StripMetadata))
-	p.quality = C.int(params.Quality)
-	p.tiffCompression = C.VipsForeignTiffCompression(params.Compression)
-	p.tiffPyramid = C.int(boolToInt(params.Pyramid))
-	p.tiffTile = C.int(boolToInt(params.Tile))
+func tiffsaveOptions(params TiffExportParams) *TiffsaveOptions {
 	tileHeight := params.TileHeight
 	tileWidth := params.TileWidth
 	if tileHeight <= 0 {
@@ -505,120 +623,223 @@ func newSaveParamsTIFF(in *C.VipsImage, params TiffExportParams) C.struct_SavePa
 	if tileWidth <= 0 {
 		tileWidth = 256
 	}
-	p.tiffTileHeight = C.int(tileHeight)
-	p.tiffTileWidth = C.int(tileWidth)
-	return p
+	predictor := params.Predictor
+	if predictor == 0 {
+		predictor = TiffPredictorHorizontal
+	}
+	o := &TiffsaveOptions{
+		Strip:       ptrTo(params.StripMetadata),
+		Compression: ptrTo(params.Compression),
+		Predictor:   ptrTo(predictor),
+		Pyramid:     ptrTo(params.Pyramid),
+		TileHeight:  ptrTo(tileHeight),
+		TileWidth:   ptrTo(tileWidth),
+		Tile:        ptrTo(params.Tile),
+	}
+	if params.Quality != 0 {
+		o.Q = ptrTo(params.Quality)
+	}
+	return o
 }
 
-func vipsSaveTIFFToBuffer(in *C.VipsImage, params TiffExportParams) ([]byte, error) {
-	incOpCounter("save_tiff_buffer")
+func vipsSaveTIFFToBuffer(ctx context.Context, in *C.VipsImage, params TiffExportParams) ([]byte, error) {
+	op := startOp(ctx, "save_tiff_buffer", in)
+	defer op.end()
+	op.attrs.Format = ImageTypeTIFF
 
-	return vipsSaveToBuffer(newSaveParamsTIFF(in, params))
+	return op.saved(vipsSaveToBuffer(in, tiffsaveOptions(params)))
 }
 
-func newSaveParamsHEIF(in *C.VipsImage, params HeifExportParams) C.struct_SaveParams {
-	p := C.create_save_params(C.HEIF)
-	p.inputImage = in
-	p.outputFormat = C.HEIF
-	p.quality = C.int(params.Quality)
-	p.heifLossless = C.int(boolToInt(params.Lossless))
-	p.heifBitdepth = C.int(params.Bitdepth)
-	p.heifEffort = C.int(params.Effort)
-	return p
+// setHeifEffort sets the encoder effort the way the running libvips
+// spells it: "effort", together with "bitdepth", from 8.13 and the
+// deprecated "speed" before.
+func setHeifEffort(o *HeifsaveOptions, bitdepth, effort int) {
+	if libvipsAtLeast(8, 13, 0) {
+		if bitdepth != 0 && effort != 0 {
+			o.Bitdepth = ptrTo(bitdepth)
+			o.Effort = ptrTo(effort)
+		}
+	} else if effort != 0 {
+		o.Speed = ptrTo(effort)
+	}
 }
 
-func vipsSaveHEIFToBuffer(in *C.VipsImage, params HeifExportParams) ([]byte, error) {
-	incOpCounter("save_heif_buffer")
-
-	return vipsSaveToBuffer(newSaveParamsHEIF(in, params))
+func heifsaveOptions(params HeifExportParams) *HeifsaveOptions {
+	o := &HeifsaveOptions{
+		Strip:    ptrTo(params.StripMetadata),
+		Lossless: ptrTo(params.Lossless),
+	}
+	setHeifEffort(o, params.Bitdepth, params.Effort)
+	if params.Quality != 0 {
+		o.Q = ptrTo(params.Quality)
+	}
+	return o
 }
 
-func vipsSaveAVIFToBuffer(in *C.VipsImage, params AvifExportParams) ([]byte, error) {
-	incOpCounter("save_heif_buffer")
+func vipsSaveHEIFToBuffer(ctx context.Context, in *C.VipsImage, params HeifExportParams) ([]byte, error) {
+	op := startOp(ctx, "save_heif_buffer", in)
+	defer op.end()
+	op.attrs.Format = ImageTypeHEIF
 
+	return op.saved(vipsSaveToBuffer(in, heifsaveOptions(params)))
+}
+
+func avifsaveOptions(params AvifExportParams) *HeifsaveOptions {
 	// Speed was deprecated but we want to avoid breaking code that still uses it:
 	effort := params.Effort
 	if params.Speed != 0 {
 		effort = params.Speed
 	}
 
-	p := C.create_save_params(C.AVIF)
-	p.inputImage = in
-	p.stripMetadata = C.int(boolToInt(params.StripMetadata))
-	p.outputFormat = C.AVIF
-	p.quality = C.int(params.Quality)
-	p.heifLossless = C.int(boolToInt(params.Lossless))
-	p.heifBitdepth = C.int(params.Bitdepth)
-	p.heifEffort = C.int(effort)
+	o := &HeifsaveOptions{
+		Strip:       ptrTo(params.StripMetadata),
+		Compression: ptrTo(HeifCompressionAV1),
+		Lossless:    ptrTo(params.Lossless),
+	}
+	setHeifEffort(o, params.Bitdepth, effort)
+	if params.Quality != 0 {
+		o.Q = ptrTo(params.Quality)
+	}
+	return o
+}
+
+func vipsSaveAVIFToBuffer(ctx context.Context, in *C.VipsImage, params AvifExportParams) ([]byte, error) {
+	op := startOp(ctx, "save_heif_buffer", in)
+	defer op.end()
+	op.attrs.Format = ImageTypeAVIF
 
-	return vipsSaveToBuffer(p)
+	return op.saved(vipsSaveToBuffer(in, avifsaveOptions(params)))
 }
 
-func vipsSaveJP2KToBuffer(in *C.VipsImage, params Jp2kExportParams) ([]byte, error) {
-	incOpCounter("save_jp2k_buffer")
+func jp2ksaveOptions(params Jp2kExportParams) *Jp2ksaveOptions {
+	o := &Jp2ksaveOptions{
+		SubsampleMode: ptrTo(params.SubsampleMode),
+		TileHeight:    ptrTo(params.TileHeight),
+		TileWidth:     ptrTo(params.TileWidth),
+		Lossless:      ptrTo(params.Lossless),
+	}
+	if params.Quality != 0 {
+		o.Q = ptrTo(params.Quality)
+	}
+	return o
+}
 
-	p := C.create_save_params(C.JP2K)
-	p.inputImage = in
-	p.outputFormat = C.JP2K
-	p.quality = C.int(params.Quality)
-	p.jp2kLossless = C.int(boolToInt(params.Lossless))
-	p.jp2kTileWidth = C.int(params.TileWidth)
-	p.jp2kTileHeight = C.int(params.TileHeight)
-	p.jpegSubsample = C.VipsForeignSubsample(params.SubsampleMode)
+func vipsSaveJP2KToBuffer(ctx context.Context, in *C.VipsImage, params Jp2kExportParams) ([]byte, error) {
+	op := startOp(ctx, "save_jp2k_buffer", in)
+	defer op.end()
+	op.attrs.Format = ImageTypeJP2K
 
-	return vipsSaveToBuffer(p)
+	return op.saved(vipsSaveToBuffer(in, jp2ksaveOptions(params)))
 }
 
-func newSaveParamsGIF(in *C.VipsImage, params GifExportParams) C.struct_SaveParams {
-	p := C.create_save_params(C.GIF)
-	p.inputImage = in
-	p.quality = C.int(params.Quality)
-	p.gifDither = C.double(params.Dither)
-	p.gifEffort = C.int(params.Effort)
-	p.gifBitdepth = C.int(params.Bitdepth)
-	return p
+// gifsaveOptions returns cgif options, or ImageMagick ones below libvips
+// 8.12, which has no gifsave.
+func gifsaveOptions(params GifExportParams) SaveOptions {
+	if !libvipsAtLeast(8, 12, 0) {
+		return &MagicksaveOptions{
+			Format:                  ptrTo("GIF"),
+			OptimizeGifFrames:       ptrTo(false),
+			OptimizeGifTransparency: ptrTo(false),
+			Bitdepth:                ptrTo(params.Bitdepth),
+		}
+	}
+
+	// See for argument values: https://www.libvips.org/API/current/VipsForeignSave.html#vips-gifsave
+	o := &GifsaveOptions{
+		Strip: ptrTo(params.StripMetadata),
+	}
+	if params.Dither > 0.0 && params.Dither <= 10 {
+		o.Dither = ptrTo(params.Dither)
+	}
+	if params.Effort >= 1 && params.Effort <= 10 {
+		o.Effort = ptrTo(params.Effort)
+	}
+	if params.Bitdepth >= 1 && params.Bitdepth <= 8 {
+		o.Bitdepth = ptrTo(params.Bitdepth)
+	}
+	return o
 }
 
-func vipsSaveGIFToBuffer(in *C.VipsImage, params GifExportParams) ([]byte, error) {
-	incOpCounter("save_gif_buffer")
+func vipsSaveGIFToBuffer(ctx context.Context, in *C.VipsImage, params GifExportParams) ([]byte, error) {
+	op := startOp(ctx, "save_gif_buffer", in)
+	defer op.end()
+	op.attrs.Format = ImageTypeGIF
 
-	return vipsSaveToBuffer(newSaveParamsGIF(in, params))
+	return op.saved(vipsSaveToBuffer(in, gifsaveOptions(params)))
 }
 
-func vipsSaveJxlToBuffer(in *C.VipsImage, params JxlExportParams) ([]byte, error) {
-	incOpCounter("save_jxl_buffer")
+func jxlsaveOptions(params JxlExportParams) *JxlsaveOptions {
+	o := &JxlsaveOptions{
+		Strip:    ptrTo(params.StripMetadata),
+		Tier:     ptrTo(params.Tier),
+		Distance: ptrTo(params.Distance),
+		Effort:   ptrTo(params.Effort),
+		Lossless: ptrTo(params.Lossless),
+	}
+	if params.Quality != 0 {
+		o.Q = ptrTo(params.Quality)
+	}
+	return o
+}
 
-	p := C.create_save_params(C.JXL)
-	p.inputImage = in
-	p.outputFormat = C.JXL
-	p.quality = C.int(params.Quality)
-	p.jxlLossless = C.int(boolToInt(params.Lossless))
-	p.jxlTier = C.int(params.Tier)
-	p.jxlDistance = C.double(params.Distance)
-	p.jxlEffort = C.int(params.Effort)
+func vipsSaveJxlToBuffer(ctx context.Context, in *C.VipsImage, params JxlExportParams) ([]byte, error) {
+	op := startOp(ctx, "save_jxl_buffer", in)
+	defer op.end()
+	op.attrs.Format = ImageTypeJXL
 
-	return vipsSaveToBuffer(p)
+	return op.saved(vipsSaveToBuffer(in, jxlsaveOptions(params)))
 }
 
-func vipsSaveMagickToBuffer(in *C.VipsImage, params MagickExportParams) ([]byte, error) {
-	incOpCounter("save_magick_buffer")
+func vipsSaveMagickToBuffer(ctx context.Context, in *C.VipsImage, params MagickExportParams) ([]byte, error) {
+	op := startOp(ctx, "save_magick_buffer", in)
+	defer op.end()
+	op.attrs.Format = ImageTypeMagick
 
 	if params.Format == "" {
-		return nil, errors.New("magick format required")
+		return nil, op.fail(errors.New("magick format required"))
+	}
+	o := &MagicksaveOptions{
+		Format:                  ptrTo(params.Format),
+		OptimizeGifFrames:       ptrTo(params.OptimizeGifFrames),
+		OptimizeGifTransparency: ptrTo(params.OptimizeGifTransparency),
+		Bitdepth:                ptrTo(params.BitDepth),
 	}
-	p := C.create_save_params(C.MAGICK)
-	p.inputImage = in
-	p.outputFormat = C.MAGICK
-	p.quality = C.int(params.Quality)
-	p.magickFormat = C.CString(params.Format)
-	p.magickOptimizeGifFrames = C.int(boolToInt(params.OptimizeGifFrames))
-	p.magickOptimizeGifTransparency = C.int(boolToInt(params.OptimizeGifTransparency))
-	p.magickBitDepth = C.int(params.BitDepth)
+	if params.Quality != 0 {
+		o.Quality = ptrTo(params.Quality)
+	}
+
+	return op.saved(vipsSaveToBuffer(in, o))
+}
+
+func vipsSaveWithOptionsToBuffer(ctx context.Context, in *C.VipsImage, opts SaveOptions, format ImageType) ([]byte, error) {
+	op := startOp(ctx, "save_"+ImageTypes[format]+"_buffer", in)
+	defer op.end()
+	op.attrs.Format = format
+
+	return op.saved(vipsSaveToBuffer(in, opts))
+}
 
-	return vipsSaveToBuffer(p)
+// newSaveParams copies opts into C memory for saving in. The returned
+// cleanup must be called once the save has completed.
+func newSaveParams(in *C.VipsImage, opts SaveOptions) (C.struct_SaveParams, func()) {
+	cOpts, setOptions, freeOpts := opts.cOptions()
+	saver := C.CString(opts.saveOperation())
+	p := C.struct_SaveParams{
+		inputImage: in,
+		saver:      saver,
+		options:    cOpts,
+		setOptions: setOptions,
+	}
+	return p, func() {
+		freeCString(saver)
+		freeOpts()
+	}
 }
 
-func vipsSaveToBuffer(params C.struct_SaveParams) ([]byte, error) {
+func vipsSaveToBuffer(in *C.VipsImage, opts SaveOptions) ([]byte, error) {
+	params, cleanup := newSaveParams(in, opts)
+	defer cleanup()
+
 	if err := C.save_to_buffer(&params); err != 0 {
 		return nil, handleSaveBufferError(params.outputBuffer)
 	}
@@ -8,6 +8,8 @@
 #include <vips/foreign.h>
 // clang-format n
 
+#include "generated_foreign.h"
+
 #ifndef BOOL
 #define BOOL int
 #endif
@@ -70,82 +72,33 @@ typedef struct LoadParams {
   Param svgUnlimited;
   Param access;
 
+  // Generated Gen*Opts options of loader, e.g. "jpegload", applied after
+  // the Params above when that loader is the one used.
+  const char *loader;
+  void *options;
+  GenSetOptionsFn setOptions;
+
 } LoadParams;
 
 LoadParams create_load_params(ImageType inputFormat);
 int load_from_buffer(LoadParams *params, void *buf, size_t len);
-
+int load_from_file(LoadParams *params, const char *filename);
+int load_header_from_file(const char *filename, VipsImage **out);
+int apply_load_options(VipsOperation *operation, const char *operationName,
+                       LoadParams *params);
+
+// SaveParams runs saver, e.g. "jpegsave", through its _buffer, file or
+// _target variant. options is one of the generated Gen*Opts structs, which
+// setOptions applies to the operation.
 typedef struct SaveParams {
   VipsImage *inputImage;
   void *outputBuffer;
-  ImageType outputFormat;
   size_t outputLen;
 
-  BOOL stripMetadata;
-  int quality;
-  BOOL interlace;
-
-  // JPEG
-  BOOL jpegOptimizeCoding;
-  VipsForeignSubsample jpegSubsample;
-  BOOL jpegTrellisQuant;
-  BOOL jpegOvershootDeringing;
-  BOOL jpegOptimizeScans;
-  int jpegQuantTable;
-
-  // PNG
-  int pngCompression;
-  VipsForeignPngFilter pngFilter;
-  BOOL pngPalette;
-  double pngDither;
-  int pngBitdepth;
-
-  // GIF (with CGIF)
-  double gifDither;
-  int gifEffort;
-  int gifBitdepth;
-
-  // WEBP
-  BOOL webpLossless;
-  BOOL webpNearLossless;
-  int webpReductionEffort;
-  char *webpIccProfile;
-  BOOL webpMinSize;
-  int webpKMin;
-  int webpKMax;
-  int webpTargetSize;
-
-  // HEIF - https://github.com/libvips/libvips/blob/master/libvips/foreign/heifsave.c#L71
-  int heifBitdepth; // Bitdepth to save at for >8 bit images
-  BOOL heifLossless; // Lossless compression
-  int heifEffort; // CPU effort (0 - 9)
-
-  // TIFF
-  VipsForeignTiffCompression tiffCompression;
-  VipsForeignTiffPredictor tiffPredictor;
-  BOOL tiffPyramid;
-  BOOL tiffTile;
-  int tiffTileHeight;
-  int tiffTileWidth;
-
-  // JPEG2000
-  BOOL jp2kLossless;
-  int jp2kTileWidth;
-  int	jp2kTileHeight;
-
-  // JXL
-  int jxlTier;
-  double jxlDistance;
-  int jxlEffort;
-  BOOL jxlLossless;
-
-  // MAGICK
-  char *magickFormat;
-  BOOL magickOptimizeGifFrames;
-  BOOL magickOptimizeGifTransparency;
-  int magickBitDepth;
+  const char *saver;
+  void *options;
+  GenSetOptionsFn setOptions;
 } SaveParams;
 
-SaveParams create_save_params(ImageType outputFormat);
 int save_to_buffer(SaveParams *params);
-
+int save_to_file(SaveParams *params, const char *filename);
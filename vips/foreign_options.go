@@ -0,0 +1,51 @@
+package vips
+
+// #include "foreign.h"
+import "C"
+
+import "unsafe"
+
+// SaveOptions are the options of a libvips saver: one of the generated
+// *saveOptions types, such as JpegsaveOptions or WebpsaveOptions. They
+// expose every option the saver has, where the Export*Params types only
+// map the common ones.
+type SaveOptions interface {
+	// saveOperation returns the name of the saver, e.g. "jpegsave".
+	saveOperation() string
+	// cOptions copies the options into C memory for the generated
+	// setter. The returned func frees the copy.
+	cOptions() (unsafe.Pointer, C.GenSetOptionsFn, func())
+}
+
+// LoadOptions are the options of a libvips loader: one of the generated
+// *loadOptions types, such as JpegloadOptions or PdfloadOptions. Set them
+// on ImportParams.Options.
+type LoadOptions interface {
+	// loadOperation returns the name of the loader, e.g. "jpegload".
+	loadOperation() string
+	// cOptions copies the options into C memory for the generated
+	// setter. The returned func frees the copy.
+	cOptions() (unsafe.Pointer, C.GenSetOptionsFn, func())
+}
+
+var saverImageTypes = map[string]ImageType{
+	"gifsave":    ImageTypeGIF,
+	"heifsave":   ImageTypeHEIF,
+	"jp2ksave":   ImageTypeJP2K,
+	"jpegsave":   ImageTypeJPEG,
+	"jxlsave":    ImageTypeJXL,
+	"magicksave": ImageTypeMagick,
+	"pngsave":    ImageTypePNG,
+	"tiffsave":   ImageTypeTIFF,
+	"webpsave":   ImageTypeWEBP,
+}
+
+// saveOptionsImageType returns the format opts save to. heifsave writes
+// AVIF when it compresses with AV1.
+func saveOptionsImageType(opts SaveOptions) ImageType {
+	if o, ok := opts.(*HeifsaveOptions); ok && o != nil &&
+		o.Compression != nil && *o.Compression == HeifCompressionAV1 {
+		return ImageTypeAVIF
+	}
+	return saverImageTypes[opts.saveOperation()]
+}
@@ -0,0 +1,74 @@
+package vips
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestImageRef_ExportWithOptions_Jpeg(t *testing.T) {
+	require.NoError(t, Startup(nil))
+
+	image, err := NewImageFromFile(resources + "png-24bit.png")
+	require.NoError(t, err)
+	defer image.Close()
+
+	buf, metadata, err := image.ExportWithOptions(&JpegsaveOptions{
+		Q:         ptrTo(60),
+		Interlace: ptrTo(true),
+	})
+	require.NoError(t, err)
+	assert.Equal(t, ImageTypeJPEG, DetermineImageType(buf))
+	assert.Equal(t, ImageTypeJPEG, metadata.Format)
+	assert.Equal(t, image.Width(), metadata.Width)
+}
+
+func TestImageRef_ExportWithOptions_Png(t *testing.T) {
+	require.NoError(t, Startup(nil))
+
+	image, err := NewImageFromFile(resources + "png-24bit.png")
+	require.NoError(t, err)
+	defer image.Close()
+
+	buf, metadata, err := image.ExportWithOptions(&PngsaveOptions{
+		Compression: ptrTo(9),
+	})
+	require.NoError(t, err)
+	assert.Equal(t, ImageTypePNG, DetermineImageType(buf))
+	assert.Equal(t, ImageTypePNG, metadata.Format)
+}
+
+func TestImageRef_ExportWithOptions_Nil(t *testing.T) {
+	require.NoError(t, Startup(nil))
+
+	image, err := NewImageFromFile(resources + "png-24bit.png")
+	require.NoError(t, err)
+	defer image.Close()
+
+	_, _, err = image.ExportWithOptions(nil)
+	assert.Error(t, err)
+}
+
+func TestImportParams_Options(t *testing.T) {
+	require.NoError(t, Startup(nil))
+
+	full, err := NewImageFromFile(resources + "jpg-24bit.jpg")
+	require.NoError(t, err)
+	defer full.Close()
+
+	params := NewImportParams()
+	params.Options = &JpegloadOptions{Shrink: ptrTo(2)}
+	shrunk, err := LoadImageFromFile(resources+"jpg-24bit.jpg", params)
+	require.NoError(t, err)
+	defer shrunk.Close()
+	assert.Equal(t, (full.Width()+1)/2, shrunk.Width())
+
+	// Options for another loader are ignored.
+	params = NewImportParams()
+	params.Options = &JpegloadOptions{Shrink: ptrTo(2)}
+	png, err := LoadImageFromFile(resources+"png-24bit.png", params)
+	require.NoError(t, err)
+	defer png.Close()
+	assert.Greater(t, png.Width(), 0)
+}
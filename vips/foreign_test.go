@@ -243,3 +243,36 @@ func TestIsPDF(t *testing.T) {
 		assert.False(t, isPDF(buf))
 	})
 }
+
+func TestImageType_MimeType(t *testing.T) {
+	assert.Equal(t, "image/jpeg", ImageTypeJPEG.MimeType())
+	assert.Equal(t, "image/avif", ImageTypeAVIF.MimeType())
+	assert.Equal(t, "image/svg+xml", ImageTypeSVG.MimeType())
+	assert.Equal(t, "", ImageTypeUnknown.MimeType())
+	assert.Equal(t, "", ImageTypeMagick.MimeType())
+}
+
+func TestParseMimeType(t *testing.T) {
+	for imageType, mimeType := range imageTypeMimeTypeMap {
+		assert.Equal(t, imageType, ParseMimeType(mimeType), mimeType)
+	}
+
+	assert.Equal(t, ImageTypeJPEG, ParseMimeType("image/jpg"))
+	assert.Equal(t, ImageTypeWEBP, ParseMimeType("Image/WebP"))
+	assert.Equal(t, ImageTypeSVG, ParseMimeType("image/svg+xml; charset=utf-8"))
+	assert.Equal(t, ImageTypeHEIF, ParseMimeType("image/heif"))
+	assert.Equal(t, ImageTypeUnknown, ParseMimeType("text/html"))
+	assert.Equal(t, ImageTypeUnknown, ParseMimeType(""))
+}
+
+func TestParseImageType(t *testing.T) {
+	for format := range formatCapabilities {
+		assert.Equal(t, format, ParseImageType(format.Name()), format.Name())
+	}
+
+	assert.Equal(t, ImageTypeJPEG, ParseImageType("JPG"))
+	assert.Equal(t, ImageTypeTIFF, ParseImageType("tif"))
+	assert.Equal(t, ImageTypeHEIF, ParseImageType("heic"))
+	assert.Equal(t, ImageTypeUnknown, ParseImageType("bmp"))
+	assert.Equal(t, ImageTypeUnknown, ParseImageType(""))
+}
@@ -133,7 +133,97 @@ const (
 	FailOnWarning   FailOn = C.VIPS_FAIL_ON_WARNING
 )
 
-// ForeignKeep represents VipsForeignKeep flags for controlling metadata retention.
+// ForeignKeep represents VipsForeignKeep flags for controlling metadata
+// retention. Values combine with |. VipsForeignKeep was added in libvips
+// 8.15, so the values are given literally.
 type ForeignKeep int
 
-// Note: ForeignKeep is a flags (bitmask) type. Values combined with |.
+const (
+	ForeignKeepNone  ForeignKeep = 0
+	ForeignKeepExif  ForeignKeep = 1 << 0
+	ForeignKeepXmp   ForeignKeep = 1 << 1
+	ForeignKeepIptc  ForeignKeep = 1 << 2
+	ForeignKeepIcc   ForeignKeep = 1 << 3
+	ForeignKeepOther ForeignKeep = 1 << 4
+	ForeignKeepAll   ForeignKeep = ForeignKeepExif | ForeignKeepXmp | ForeignKeepIptc | ForeignKeepIcc | ForeignKeepOther
+)
+
+// SdfShape represents VipsSdfShape, the shapes Sdf can draw. VipsSdfShape
+// was added in libvips 8.16, so the values are given literally for the
+// package to build against older headers.
+type SdfShape int
+
+const (
+	SdfShapeCircle     SdfShape = 0
+	SdfShapeBox        SdfShape = 1
+	SdfShapeRoundedBox SdfShape = 2
+	SdfShapeLine       SdfShape = 3
+)
+
+// HeifCompression represents VipsForeignHeifCompression, the codec
+// HeifsaveOptions encodes with.
+type HeifCompression int
+
+const (
+	HeifCompressionHEVC HeifCompression = C.VIPS_FOREIGN_HEIF_COMPRESSION_HEVC
+	HeifCompressionAVC  HeifCompression = C.VIPS_FOREIGN_HEIF_COMPRESSION_AVC
+	HeifCompressionJPEG HeifCompression = C.VIPS_FOREIGN_HEIF_COMPRESSION_JPEG
+	HeifCompressionAV1  HeifCompression = C.VIPS_FOREIGN_HEIF_COMPRESSION_AV1
+)
+
+// HeifEncoder represents VipsForeignHeifEncoder, the libheif encoder
+// plugin HeifsaveOptions selects. VipsForeignHeifEncoder was added in
+// libvips 8.16, so the values are given literally.
+type HeifEncoder int
+
+const (
+	HeifEncoderAuto  HeifEncoder = 0
+	HeifEncoderAOM   HeifEncoder = 1
+	HeifEncoderRav1e HeifEncoder = 2
+	HeifEncoderSVT   HeifEncoder = 3
+	HeifEncoderX265  HeifEncoder = 4
+)
+
+// WebpPreset represents VipsForeignWebpPreset, the libwebp tuning preset.
+type WebpPreset int
+
+const (
+	WebpPresetDefault WebpPreset = C.VIPS_FOREIGN_WEBP_PRESET_DEFAULT
+	WebpPresetPicture WebpPreset = C.VIPS_FOREIGN_WEBP_PRESET_PICTURE
+	WebpPresetPhoto   WebpPreset = C.VIPS_FOREIGN_WEBP_PRESET_PHOTO
+	WebpPresetDrawing WebpPreset = C.VIPS_FOREIGN_WEBP_PRESET_DRAWING
+	WebpPresetIcon    WebpPreset = C.VIPS_FOREIGN_WEBP_PRESET_ICON
+	WebpPresetText    WebpPreset = C.VIPS_FOREIGN_WEBP_PRESET_TEXT
+)
+
+// TiffResunit represents VipsForeignTiffResunit, the unit of the TIFF
+// resolution tags.
+type TiffResunit int
+
+const (
+	TiffResunitCm   TiffResunit = C.VIPS_FOREIGN_TIFF_RESUNIT_CM
+	TiffResunitInch TiffResunit = C.VIPS_FOREIGN_TIFF_RESUNIT_INCH
+)
+
+// RegionShrink represents VipsRegionShrink, how pyramid levels are
+// downsampled.
+type RegionShrink int
+
+const (
+	RegionShrinkMean    RegionShrink = C.VIPS_REGION_SHRINK_MEAN
+	RegionShrinkMedian  RegionShrink = C.VIPS_REGION_SHRINK_MEDIAN
+	RegionShrinkMode    RegionShrink = C.VIPS_REGION_SHRINK_MODE
+	RegionShrinkMax     RegionShrink = C.VIPS_REGION_SHRINK_MAX
+	RegionShrinkMin     RegionShrink = C.VIPS_REGION_SHRINK_MIN
+	RegionShrinkNearest RegionShrink = C.VIPS_REGION_SHRINK_NEAREST
+)
+
+// ForeignDzDepth represents VipsForeignDzDepth, how deep a pyramid is
+// built.
+type ForeignDzDepth int
+
+const (
+	ForeignDzDepthOnePixel ForeignDzDepth = C.VIPS_FOREIGN_DZ_DEPTH_ONEPIXEL
+	ForeignDzDepthOneTile  ForeignDzDepth = C.VIPS_FOREIGN_DZ_DEPTH_ONETILE
+	ForeignDzDepthOne      ForeignDzDepth = C.VIPS_FOREIGN_DZ_DEPTH_ONE
+)
@@ -5,6 +5,7 @@ package vips
 import "C"
 
 import (
+	"context"
 	"runtime"
 	"unsafe"
 )
@@ -14,14 +15,15 @@ var _ = unsafe.Pointer(nil)
 
 // vipsGenCMC2LCh calls the vips CMC2LCh operation.
 // transform LCh to CMC
-func vipsGenCMC2LCh(input *C.VipsImage) (*C.VipsImage, error) {
-	incOpCounter("CMC2LCh")
+func vipsGenCMC2LCh(ctx context.Context, input *C.VipsImage) (*C.VipsImage, error) {
+	op := startOp(ctx, "CMC2LCh", input)
+	defer op.end()
 
 	var out_out *C.VipsImage
 
 	ret := C.gen_vips_CMC2LCh(input, &out_out)
 	if ret != 0 {
-		return nil, handleImageError(out_out)
+		return nil, op.fail(handleImageError(out_out))
 	}
 
 	return out_out, nil
@@ -29,14 +31,15 @@ func vipsGenCMC2LCh(input *C.VipsImage) (*C.VipsImage, error) {
 
 // vipsGenCMYK2XYZ calls the vips CMYK2XYZ operation.
 // transform CMYK to XYZ
-func vipsGenCMYK2XYZ(input *C.VipsImage) (*C.VipsImage, error) {
-	incOpCounter("CMYK2XYZ")
+func vipsGenCMYK2XYZ(ctx context.Context, input *C.VipsImage) (*C.VipsImage, error) {
+	op := startOp(ctx, "CMYK2XYZ", input)
+	defer op.end()
 
 	var out_out *C.VipsImage
 
 	ret := C.gen_vips_CMYK2XYZ(input, &out_out)
 	if ret != 0 {
-		return nil, handleImageError(out_out)
+		return nil, op.fail(handleImageError(out_out))
 	}
 
 	return out_out, nil
@@ -44,14 +47,15 @@ func vipsGenCMYK2XYZ(input *C.VipsImage) (*C.VipsImage, error) {
 
 // vipsGenHSV2sRGB calls the vips HSV2sRGB operation.
 // transform HSV to sRGB
-func vipsGenHSV2sRGB(input *C.VipsImage) (*C.VipsImage, error) {
-	incOpCounter("HSV2sRGB")
+func vipsGenHSV2sRGB(ctx context.Context, input *C.VipsImage) (*C.VipsImage, error) {
+	op := startOp(ctx, "HSV2sRGB", input)
+	defer op.end()
 
 	var out_out *C.VipsImage
 
 	ret := C.gen_vips_HSV2sRGB(input, &out_out)
 	if ret != 0 {
-		return nil, handleImageError(out_out)
+		return nil, op.fail(handleImageError(out_out))
 	}
 
 	return out_out, nil
@@ -59,14 +63,15 @@ func vipsGenHSV2sRGB(input *C.VipsImage) (*C.VipsImage, error) {
 
 // vipsGenLCh2CMC calls the vips LCh2CMC operation.
 // transform LCh to CMC
-func vipsGenLCh2CMC(input *C.VipsImage) (*C.VipsImage, error) {
-	incOpCounter("LCh2CMC")
+func vipsGenLCh2CMC(ctx context.Context, input *C.VipsImage) (*C.VipsImage, error) {
+	op := startOp(ctx, "LCh2CMC", input)
+	defer op.end()
 
 	var out_out *C.VipsImage
 
 	ret := C.gen_vips_LCh2CMC(input, &out_out)
 	if ret != 0 {
-		return nil, handleImageError(out_out)
+		return nil, op.fail(handleImageError(out_out))
 	}
 
 	return out_out, nil
@@ -74,14 +79,15 @@ func vipsGenLCh2CMC(input *C.VipsImage) (*C.VipsImage, error) {
 
 // vipsGenLCh2Lab calls the vips LCh2Lab operation.
 // transform LCh to Lab
-func vipsGenLCh2Lab(input *C.VipsImage) (*C.VipsImage, error) {
-	incOpCounter("LCh2Lab")
+func vipsGenLCh2Lab(ctx context.Context, input *C.VipsImage) (*C.VipsImage, error) {
+	op := startOp(ctx, "LCh2Lab", input)
+	defer op.end()
 
 	var out_out *C.VipsImage
 
 	ret := C.gen_vips_LCh2Lab(input, &out_out)
 	if ret != 0 {
-		return nil, handleImageError(out_out)
+		return nil, op.fail(handleImageError(out_out))
 	}
 
 	return out_out, nil
@@ -89,14 +95,15 @@ func vipsGenLCh2Lab(input *C.VipsImage) (*C.VipsImage, error) {
 
 // vipsGenLab2LCh calls the vips Lab2LCh operation.
 // transform Lab to LCh
-func vipsGenLab2LCh(input *C.VipsImage) (*C.VipsImage, error) {
-	incOpCounter("Lab2LCh")
+func vipsGenLab2LCh(ctx context.Context, input *C.VipsImage) (*C.VipsImage, error) {
+	op := startOp(ctx, "Lab2LCh", input)
+	defer op.end()
 
 	var out_out *C.VipsImage
 
 	ret := C.gen_vips_Lab2LCh(input, &out_out)
 	if ret != 0 {
-		return nil, handleImageError(out_out)
+		return nil, op.fail(handleImageError(out_out))
 	}
 
 	return out_out, nil
@@ -104,14 +111,15 @@ func vipsGenLab2LCh(input *C.VipsImage) (*C.VipsImage, error) {
 
 // vipsGenLab2LabQ calls the vips Lab2LabQ operation.
 // transform float Lab to LabQ coding
-func vipsGenLab2LabQ(input *C.VipsImage) (*C.VipsImage, error) {
-	incOpCounter("Lab2LabQ")
+func vipsGenLab2LabQ(ctx context.Context, input *C.VipsImage) (*C.VipsImage, error) {
+	op := startOp(ctx, "Lab2LabQ", input)
+	defer op.end()
 
 	var out_out *C.VipsImage
 
 	ret := C.gen_vips_Lab2LabQ(input, &out_out)
 	if ret != 0 {
-		return nil, handleImageError(out_out)
+		return nil, op.fail(handleImageError(out_out))
 	}
 
 	return out_out, nil
@@ -119,14 +127,15 @@ func vipsGenLab2LabQ(input *C.VipsImage) (*C.VipsImage, error) {
 
 // vipsGenLab2LabS calls the vips Lab2LabS operation.
 // transform float Lab to signed short
-func vipsGenLab2LabS(input *C.VipsImage) (*C.VipsImage, error) {
-	incOpCounter("Lab2LabS")
+func vipsGenLab2LabS(ctx context.Context, input *C.VipsImage) (*C.VipsImage, error) {
+	op := startOp(ctx, "Lab2LabS", input)
+	defer op.end()
 
 	var out_out *C.VipsImage
 
 	ret := C.gen_vips_Lab2LabS(input, &out_out)
 	if ret != 0 {
-		return nil, handleImageError(out_out)
+		return nil, op.fail(handleImageError(out_out))
 	}
 
 	return out_out, nil
@@ -139,8 +148,9 @@ type Lab2XYZOptions struct {
 
 // vipsGenLab2XYZ calls the vips Lab2XYZ operation.
 // transform CIELAB to XYZ
-func vipsGenLab2XYZ(input *C.VipsImage, opts *Lab2XYZOptions) (*C.VipsImage, error) {
-	incOpCounter("Lab2XYZ")
+func vipsGenLab2XYZ(ctx context.Context, input *C.VipsImage, opts *Lab2XYZOptions) (*C.VipsImage, error) {
+	op := startOp(ctx, "Lab2XYZ", input)
+	defer op.end()
 
 	var out_out *C.VipsImage
 
@@ -158,7 +168,7 @@ func vipsGenLab2XYZ(input *C.VipsImage, opts *Lab2XYZOptions) (*C.VipsImage, err
 
 	ret := C.gen_vips_Lab2XYZ(input, &out_out, &cOpts)
 	if ret != 0 {
-		return nil, handleImageError(out_out)
+		return nil, op.fail(handleImageError(out_out))
 	}
 
 	return out_out, nil
@@ -166,14 +176,15 @@ func vipsGenLab2XYZ(input *C.VipsImage, opts *Lab2XYZOptions) (*C.VipsImage, err
 
 // vipsGenLabQ2Lab calls the vips LabQ2Lab operation.
 // unpack a LabQ image to float Lab
-func vipsGenLabQ2Lab(input *C.VipsImage) (*C.VipsImage, error) {
-	incOpCounter("LabQ2Lab")
+func vipsGenLabQ2Lab(ctx context.Context, input *C.VipsImage) (*C.VipsImage, error) {
+	op := startOp(ctx, "LabQ2Lab", input)
+	defer op.end()
 
 	var out_out *C.VipsImage
 
 	ret := C.gen_vips_LabQ2Lab(input, &out_out)
 	if ret != 0 {
-		return nil, handleImageError(out_out)
+		return nil, op.fail(handleImageError(out_out))
 	}
 
 	return out_out, nil
@@ -181,14 +192,15 @@ func vipsGenLabQ2Lab(input *C.VipsImage) (*C.VipsImage, error) {
 
 // vipsGenLabQ2LabS calls the vips LabQ2LabS operation.
 // unpack a LabQ image to short Lab
-func vipsGenLabQ2LabS(input *C.VipsImage) (*C.VipsImage, error) {
-	incOpCounter("LabQ2LabS")
+func vipsGenLabQ2LabS(ctx context.Context, input *C.VipsImage) (*C.VipsImage, error) {
+	op := startOp(ctx, "LabQ2LabS", input)
+	defer op.end()
 
 	var out_out *C.VipsImage
 
 	ret := C.gen_vips_LabQ2LabS(input, &out_out)
 	if ret != 0 {
-		return nil, handleImageError(out_out)
+		return nil, op.fail(handleImageError(out_out))
 	}
 
 	return out_out, nil
@@ -196,14 +208,15 @@ func vipsGenLabQ2LabS(input *C.VipsImage) (*C.VipsImage, error) {
 
 // vipsGenLabQ2sRGB calls the vips LabQ2sRGB operation.
 // convert a LabQ image to sRGB
-func vipsGenLabQ2sRGB(input *C.VipsImage) (*C.VipsImage, error) {
-	incOpCounter("LabQ2sRGB")
+func vipsGenLabQ2sRGB(ctx context.Context, input *C.VipsImage) (*C.VipsImage, error) {
+	op := startOp(ctx, "LabQ2sRGB", input)
+	defer op.end()
 
 	var out_out *C.VipsImage
 
 	ret := C.gen_vips_LabQ2sRGB(input, &out_out)
 	if ret != 0 {
-		return nil, handleImageError(out_out)
+		return nil, op.fail(handleImageError(out_out))
 	}
 
 	return out_out, nil
@@ -211,14 +224,15 @@ func vipsGenLabQ2sRGB(input *C.VipsImage) (*C.VipsImage, error) {
 
 // vipsGenLabS2Lab calls the vips LabS2Lab operation.
 // transform signed short Lab to float
-func vipsGenLabS2Lab(input *C.VipsImage) (*C.VipsImage, error) {
-	incOpCounter("LabS2Lab")
+func vipsGenLabS2Lab(ctx context.Context, input *C.VipsImage) (*C.VipsImage, error) {
+	op := startOp(ctx, "LabS2Lab", input)
+	defer op.end()
 
 	var out_out *C.VipsImage
 
 	ret := C.gen_vips_LabS2Lab(input, &out_out)
 	if ret != 0 {
-		return nil, handleImageError(out_out)
+		return nil, op.fail(handleImageError(out_out))
 	}
 
 	return out_out, nil
@@ -226,14 +240,15 @@ func vipsGenLabS2Lab(input *C.VipsImage) (*C.VipsImage, error) {
 
 // vipsGenLabS2LabQ calls the vips LabS2LabQ operation.
 // transform short Lab to LabQ coding
-func vipsGenLabS2LabQ(input *C.VipsImage) (*C.VipsImage, error) {
-	incOpCounter("LabS2LabQ")
+func vipsGenLabS2LabQ(ctx context.Context, input *C.VipsImage) (*C.VipsImage, error) {
+	op := startOp(ctx, "LabS2LabQ", input)
+	defer op.end()
 
 	var out_out *C.VipsImage
 
 	ret := C.gen_vips_LabS2LabQ(input, &out_out)
 	if ret != 0 {
-		return nil, handleImageError(out_out)
+		return nil, op.fail(handleImageError(out_out))
 	}
 
 	return out_out, nil
@@ -241,14 +256,15 @@ func vipsGenLabS2LabQ(input *C.VipsImage) (*C.VipsImage, error) {
 
 // vipsGenOklab2Oklch calls the vips Oklab2Oklch operation.
 // transform Oklab to Oklch
-func vipsGenOklab2Oklch(input *C.VipsImage) (*C.VipsImage, error) {
-	incOpCounter("Oklab2Oklch")
+func vipsGenOklab2Oklch(ctx context.Context, input *C.VipsImage) (*C.VipsImage, error) {
+	op := startOp(ctx, "Oklab2Oklch", input)
+	defer op.end()
 
 	var out_out *C.VipsImage
 
 	ret := C.gen_vips_Oklab2Oklch(input, &out_out)
 	if ret != 0 {
-		return nil, handleImageError(out_out)
+		return nil, op.fail(handleImageError(out_out))
 	}
 
 	return out_out, nil
@@ -256,14 +272,15 @@ func vipsGenOklab2Oklch(input *C.VipsImage) (*C.VipsImage, error) {
 
 // vipsGenOklab2XYZ calls the vips Oklab2XYZ operation.
 // transform Oklab to XYZ
-func vipsGenOklab2XYZ(input *C.VipsImage) (*C.VipsImage, error) {
-	incOpCounter("Oklab2XYZ")
+func vipsGenOklab2XYZ(ctx context.Context, input *C.VipsImage) (*C.VipsImage, error) {
+	op := startOp(ctx, "Oklab2XYZ", input)
+	defer op.end()
 
 	var out_out *C.VipsImage
 
 	ret := C.gen_vips_Oklab2XYZ(input, &out_out)
 	if ret != 0 {
-		return nil, handleImageError(out_out)
+		return nil, op.fail(handleImageError(out_out))
 	}
 
 	return out_out, nil
@@ -271,14 +288,15 @@ func vipsGenOklab2XYZ(input *C.VipsImage) (*C.VipsImage, error) {
 
 // vipsGenOklch2Oklab calls the vips Oklch2Oklab operation.
 // transform Oklch to Oklab
-func vipsGenOklch2Oklab(input *C.VipsImage) (*C.VipsImage, error) {
-	incOpCounter("Oklch2Oklab")
+func vipsGenOklch2Oklab(ctx context.Context, input *C.VipsImage) (*C.VipsImage, error) {
+	op := startOp(ctx, "Oklch2Oklab", input)
+	defer op.end()
 
 	var out_out *C.VipsImage
 
 	ret := C.gen_vips_Oklch2Oklab(input, &out_out)
 	if ret != 0 {
-		return nil, handleImageError(out_out)
+		return nil, op.fail(handleImageError(out_out))
 	}
 
 	return out_out, nil
@@ -286,14 +304,15 @@ func vipsGenOklch2Oklab(input *C.VipsImage) (*C.VipsImage, error) {
 
 // vipsGenXYZ2CMYK calls the vips XYZ2CMYK operation.
 // transform XYZ to CMYK
-func vipsGenXYZ2CMYK(input *C.VipsImage) (*C.VipsImage, error) {
-	incOpCounter("XYZ2CMYK")
+func vipsGenXYZ2CMYK(ctx context.Context, input *C.VipsImage) (*C.VipsImage, error) {
+	op := startOp(ctx, "XYZ2CMYK", input)
+	defer op.end()
 
 	var out_out *C.VipsImage
 
 	ret := C.gen_vips_XYZ2CMYK(input, &out_out)
 	if ret != 0 {
-		return nil, handleImageError(out_out)
+		return nil, op.fail(handleImageError(out_out))
 	}
 
 	return out_out, nil
@@ -306,8 +325,9 @@ type XYZ2LabOptions struct {
 
 // vipsGenXYZ2Lab calls the vips XYZ2Lab operation.
 // transform XYZ to Lab
-func vipsGenXYZ2Lab(input *C.VipsImage, opts *XYZ2LabOptions) (*C.VipsImage, error) {
-	incOpCounter("XYZ2Lab")
+func vipsGenXYZ2Lab(ctx context.Context, input *C.VipsImage, opts *XYZ2LabOptions) (*C.VipsImage, error) {
+	op := startOp(ctx, "XYZ2Lab", input)
+	defer op.end()
 
 	var out_out *C.VipsImage
 
@@ -325,7 +345,7 @@ func vipsGenXYZ2Lab(input *C.VipsImage, opts *XYZ2LabOptions) (*C.VipsImage, err
 
 	ret := C.gen_vips_XYZ2Lab(input, &out_out, &cOpts)
 	if ret != 0 {
-		return nil, handleImageError(out_out)
+		return nil, op.fail(handleImageError(out_out))
 	}
 
 	return out_out, nil
@@ -333,14 +353,15 @@ func vipsGenXYZ2Lab(input *C.VipsImage, opts *XYZ2LabOptions) (*C.VipsImage, err
 
 // vipsGenXYZ2Oklab calls the vips XYZ2Oklab operation.
 // transform XYZ to Oklab
-func vipsGenXYZ2Oklab(input *C.VipsImage) (*C.VipsImage, error) {
-	incOpCounter("XYZ2Oklab")
+func vipsGenXYZ2Oklab(ctx context.Context, input *C.VipsImage) (*C.VipsImage, error) {
+	op := startOp(ctx, "XYZ2Oklab", input)
+	defer op.end()
 
 	var out_out *C.VipsImage
 
 	ret := C.gen_vips_XYZ2Oklab(input, &out_out)
 	if ret != 0 {
-		return nil, handleImageError(out_out)
+		return nil, op.fail(handleImageError(out_out))
 	}
 
 	return out_out, nil
@@ -348,14 +369,15 @@ func vipsGenXYZ2Oklab(input *C.VipsImage) (*C.VipsImage, error) {
 
 // vipsGenXYZ2Yxy calls the vips XYZ2Yxy operation.
 // transform XYZ to Yxy
-func vipsGenXYZ2Yxy(input *C.VipsImage) (*C.VipsImage, error) {
-	incOpCounter("XYZ2Yxy")
+func vipsGenXYZ2Yxy(ctx context.Context, input *C.VipsImage) (*C.VipsImage, error) {
+	op := startOp(ctx, "XYZ2Yxy", input)
+	defer op.end()
 
 	var out_out *C.VipsImage
 
 	ret := C.gen_vips_XYZ2Yxy(input, &out_out)
 	if ret != 0 {
-		return nil, handleImageError(out_out)
+		return nil, op.fail(handleImageError(out_out))
 	}
 
 	return out_out, nil
@@ -363,14 +385,15 @@ func vipsGenXYZ2Yxy(input *C.VipsImage) (*C.VipsImage, error) {
 
 // vipsGenXYZ2scRGB calls the vips XYZ2scRGB operation.
 // transform XYZ to scRGB
-func vipsGenXYZ2scRGB(input *C.VipsImage) (*C.VipsImage, error) {
-	incOpCounter("XYZ2scRGB")
+func vipsGenXYZ2scRGB(ctx context.Context, input *C.VipsImage) (*C.VipsImage, error) {
+	op := startOp(ctx, "XYZ2scRGB", input)
+	defer op.end()
 
 	var out_out *C.VipsImage
 
 	ret := C.gen_vips_XYZ2scRGB(input, &out_out)
 	if ret != 0 {
-		return nil, handleImageError(out_out)
+		return nil, op.fail(handleImageError(out_out))
 	}
 
 	return out_out, nil
@@ -378,14 +401,15 @@ func vipsGenXYZ2scRGB(input *C.VipsImage) (*C.VipsImage, error) {
 
 // vipsGenYxy2XYZ calls the vips Yxy2XYZ operation.
 // transform Yxy to XYZ
-func vipsGenYxy2XYZ(input *C.VipsImage) (*C.VipsImage, error) {
-	incOpCounter("Yxy2XYZ")
+func vipsGenYxy2XYZ(ctx context.Context, input *C.VipsImage) (*C.VipsImage, error) {
+	op := startOp(ctx, "Yxy2XYZ", input)
+	defer op.end()
 
 	var out_out *C.VipsImage
 
 	ret := C.gen_vips_Yxy2XYZ(input, &out_out)
 	if ret != 0 {
-		return nil, handleImageError(out_out)
+		return nil, op.fail(handleImageError(out_out))
 	}
 
 	return out_out, nil
@@ -393,14 +417,15 @@ func vipsGenYxy2XYZ(input *C.VipsImage) (*C.VipsImage, error) {
 
 // vipsGenAbs calls the vips abs operation.
 // absolute value of an image
-func vipsGenAbs(input *C.VipsImage) (*C.VipsImage, error) {
-	incOpCounter("abs")
+func vipsGenAbs(ctx context.Context, input *C.VipsImage) (*C.VipsImage, error) {
+	op := startOp(ctx, "abs", input)
+	defer op.end()
 
 	var out_out *C.VipsImage
 
 	ret := C.gen_vips_abs(input, &out_out)
 	if ret != 0 {
-		return nil, handleImageError(out_out)
+		return nil, op.fail(handleImageError(out_out))
 	}
 
 	return out_out, nil
@@ -408,14 +433,36 @@ func vipsGenAbs(input *C.VipsImage) (*C.VipsImage, error) {
 
 // vipsGenAdd calls the vips add operation.
 // add two images
-func vipsGenAdd(left *C.VipsImage, right *C.VipsImage) (*C.VipsImage, error) {
-	incOpCounter("add")
+func vipsGenAdd(ctx context.Context, left *C.VipsImage, right *C.VipsImage) (*C.VipsImage, error) {
+	op := startOp(ctx, "add", left)
+	defer op.end()
 
 	var out_out *C.VipsImage
 
 	ret := C.gen_vips_add(left, right, &out_out)
 	if ret != 0 {
-		return nil, handleImageError(out_out)
+		return nil, op.fail(handleImageError(out_out))
+	}
+
+	return out_out, nil
+}
+
+// vipsGenAddalpha calls the vips addalpha operation.
+// append an alpha channel
+// Requires libvips 8.16+.
+func vipsGenAddalpha(ctx context.Context, input *C.VipsImage) (*C.VipsImage, error) {
+	if err := requireLibvips("addalpha", 8, 16, 0); err != nil {
+		return nil, err
+	}
+
+	op := startOp(ctx, "addalpha", input)
+	defer op.end()
+
+	var out_out *C.VipsImage
+
+	ret := C.gen_vips_addalpha(input, &out_out)
+	if ret != 0 {
+		return nil, op.fail(handleImageError(out_out))
 	}
 
 	return out_out, nil
@@ -423,7 +470,8 @@ func vipsGenAdd(left *C.VipsImage, right *C.VipsImage) (*C.VipsImage, error) {
 
 // AffineOptions are optional parameters for affine.
 type AffineOptions struct {
-	Interpolate *C.VipsInterpolate
+	// Interpolate names an interpolator, such as "bicubic" or "nohalo".
+	Interpolate *string
 	Oarea []int
 	Odx *float64
 	Ody *float64
@@ -436,8 +484,9 @@ type AffineOptions struct {
 
 // vipsGenAffine calls the vips affine operation.
 // affine transform of an image
-func vipsGenAffine(input *C.VipsImage, matrix []float64, opts *AffineOptions) (*C.VipsImage, error) {
-	incOpCounter("affine")
+func vipsGenAffine(ctx context.Context, input *C.VipsImage, matrix []float64, opts *AffineOptions) (*C.VipsImage, error) {
+	op := startOp(ctx, "affine", input)
+	defer op.end()
 
 	var out_out *C.VipsImage
 
@@ -447,7 +496,12 @@ func vipsGenAffine(input *C.VipsImage, matrix []float64, opts *AffineOptions) (*
 	if opts != nil {
 		if opts.Interpolate != nil {
 			cOpts.has_interpolate = 1
-			cOpts.interpolate = opts.Interpolate
+			interp_interpolate, err := NewInterpolate(*opts.Interpolate)
+			if err != nil {
+				return nil, op.fail(err)
+			}
+			defer C.g_object_unref(C.gpointer(unsafe.Pointer(interp_interpolate)))
+			cOpts.interpolate = interp_interpolate
 		}
 		if opts.Oarea != nil {
 			cOpts.has_oarea = 1
@@ -489,7 +543,7 @@ func vipsGenAffine(input *C.VipsImage, matrix []float64, opts *AffineOptions) (*
 
 	ret := C.gen_vips_affine(input, (*C.double)(unsafe.Pointer(&matrix[0])), C.int(len(matrix)), &out_out, &cOpts)
 	if ret != 0 {
-		return nil, handleImageError(out_out)
+		return nil, op.fail(handleImageError(out_out))
 	}
 
 	return out_out, nil
@@ -508,8 +562,9 @@ type ArrayjoinOptions struct {
 
 // vipsGenArrayjoin calls the vips arrayjoin operation.
 // join an array of images
-func vipsGenArrayjoin(input []*C.VipsImage, opts *ArrayjoinOptions) (*C.VipsImage, error) {
-	incOpCounter("arrayjoin")
+func vipsGenArrayjoin(ctx context.Context, input []*C.VipsImage, opts *ArrayjoinOptions) (*C.VipsImage, error) {
+	op := startOp(ctx, "arrayjoin", nil)
+	defer op.end()
 
 	var out_out *C.VipsImage
 
@@ -551,7 +606,7 @@ func vipsGenArrayjoin(input []*C.VipsImage, opts *ArrayjoinOptions) (*C.VipsImag
 
 	ret := C.gen_vips_arrayjoin((**C.VipsImage)(unsafe.Pointer(&input[0])), C.int(len(input)), &out_out, &cOpts)
 	if ret != 0 {
-		return nil, handleImageError(out_out)
+		return nil, op.fail(handleImageError(out_out))
 	}
 
 	return out_out, nil
@@ -559,8 +614,9 @@ func vipsGenArrayjoin(input []*C.VipsImage, opts *ArrayjoinOptions) (*C.VipsImag
 
 // vipsGenAutorot calls the vips autorot operation.
 // autorotate image by exif tag
-func vipsGenAutorot(input *C.VipsImage) (*C.VipsImage, Angle, bool, error) {
-	incOpCounter("autorot")
+func vipsGenAutorot(ctx context.Context, input *C.VipsImage) (*C.VipsImage, Angle, bool, error) {
+	op := startOp(ctx, "autorot", input)
+	defer op.end()
 
 	var out_out *C.VipsImage
 	var out_angle C.int
@@ -568,7 +624,7 @@ func vipsGenAutorot(input *C.VipsImage) (*C.VipsImage, Angle, bool, error) {
 
 	ret := C.gen_vips_autorot(input, &out_out, &out_angle, &out_flip)
 	if ret != 0 {
-		return nil, 0, false, handleImageError(out_out)
+		return nil, 0, false, op.fail(handleImageError(out_out))
 	}
 
 	return out_out, Angle(out_angle), out_flip != 0, nil
@@ -576,14 +632,15 @@ func vipsGenAutorot(input *C.VipsImage) (*C.VipsImage, Angle, bool, error) {
 
 // vipsGenAvg calls the vips avg operation.
 // find image average
-func vipsGenAvg(input *C.VipsImage) (float64, error) {
-	incOpCounter("avg")
+func vipsGenAvg(ctx context.Context, input *C.VipsImage) (float64, error) {
+	op := startOp(ctx, "avg", input)
+	defer op.end()
 
 	var out_out C.double
 
 	ret := C.gen_vips_avg(input, &out_out)
 	if ret != 0 {
-		return 0, handleVipsError()
+		return 0, op.fail(handleVipsError())
 	}
 
 	return float64(out_out), nil
@@ -591,14 +648,15 @@ func vipsGenAvg(input *C.VipsImage) (float64, error) {
 
 // vipsGenBandbool calls the vips bandbool operation.
 // boolean operation across image bands
-func vipsGenBandbool(input *C.VipsImage, boolean OperationBoolean) (*C.VipsImage, error) {
-	incOpCounter("bandbool")
+func vipsGenBandbool(ctx context.Context, input *C.VipsImage, boolean OperationBoolean) (*C.VipsImage, error) {
+	op := startOp(ctx, "bandbool", input)
+	defer op.end()
 
 	var out_out *C.VipsImage
 
 	ret := C.gen_vips_bandbool(input, C.VipsOperationBoolean(boolean), &out_out)
 	if ret != 0 {
-		return nil, handleImageError(out_out)
+		return nil, op.fail(handleImageError(out_out))
 	}
 
 	return out_out, nil
@@ -611,8 +669,9 @@ type BandfoldOptions struct {
 
 // vipsGenBandfold calls the vips bandfold operation.
 // fold up x axis into bands
-func vipsGenBandfold(input *C.VipsImage, opts *BandfoldOptions) (*C.VipsImage, error) {
-	incOpCounter("bandfold")
+func vipsGenBandfold(ctx context.Context, input *C.VipsImage, opts *BandfoldOptions) (*C.VipsImage, error) {
+	op := startOp(ctx, "bandfold", input)
+	defer op.end()
 
 	var out_out *C.VipsImage
 
@@ -626,7 +685,7 @@ func vipsGenBandfold(input *C.VipsImage, opts *BandfoldOptions) (*C.VipsImage, e
 
 	ret := C.gen_vips_bandfold(input, &out_out, &cOpts)
 	if ret != 0 {
-		return nil, handleImageError(out_out)
+		return nil, op.fail(handleImageError(out_out))
 	}
 
 	return out_out, nil
@@ -634,14 +693,15 @@ func vipsGenBandfold(input *C.VipsImage, opts *BandfoldOptions) (*C.VipsImage, e
 
 // vipsGenBandjoin calls the vips bandjoin operation.
 // bandwise join a set of images
-func vipsGenBandjoin(input []*C.VipsImage) (*C.VipsImage, error) {
-	incOpCounter("bandjoin")
+func vipsGenBandjoin(ctx context.Context, input []*C.VipsImage) (*C.VipsImage, error) {
+	op := startOp(ctx, "bandjoin", nil)
+	defer op.end()
 
 	var out_out *C.VipsImage
 
 	ret := C.gen_vips_bandjoin((**C.VipsImage)(unsafe.Pointer(&input[0])), C.int(len(input)), &out_out)
 	if ret != 0 {
-		return nil, handleImageError(out_out)
+		return nil, op.fail(handleImageError(out_out))
 	}
 
 	return out_out, nil
@@ -649,14 +709,15 @@ func vipsGenBandjoin(input []*C.VipsImage) (*C.VipsImage, error) {
 
 // vipsGenBandjoinConst calls the vips bandjoin_const operation.
 // append a constant band to an image
-func vipsGenBandjoinConst(input *C.VipsImage, c []float64) (*C.VipsImage, error) {
-	incOpCounter("bandjoin_const")
+func vipsGenBandjoinConst(ctx context.Context, input *C.VipsImage, c []float64) (*C.VipsImage, error) {
+	op := startOp(ctx, "bandjoin_const", input)
+	defer op.end()
 
 	var out_out *C.VipsImage
 
 	ret := C.gen_vips_bandjoin_const(input, (*C.double)(unsafe.Pointer(&c[0])), C.int(len(c)), &out_out)
 	if ret != 0 {
-		return nil, handleImageError(out_out)
+		return nil, op.fail(handleImageError(out_out))
 	}
 
 	return out_out, nil
@@ -664,14 +725,15 @@ func vipsGenBandjoinConst(input *C.VipsImage, c []float64) (*C.VipsImage, error)
 
 // vipsGenBandmean calls the vips bandmean operation.
 // band-wise average
-func vipsGenBandmean(input *C.VipsImage) (*C.VipsImage, error) {
-	incOpCounter("bandmean")
+func vipsGenBandmean(ctx context.Context, input *C.VipsImage) (*C.VipsImage, error) {
+	op := startOp(ctx, "bandmean", input)
+	defer op.end()
 
 	var out_out *C.VipsImage
 
 	ret := C.gen_vips_bandmean(input, &out_out)
 	if ret != 0 {
-		return nil, handleImageError(out_out)
+		return nil, op.fail(handleImageError(out_out))
 	}
 
 	return out_out, nil
@@ -684,8 +746,9 @@ type BandrankOptions struct {
 
 // vipsGenBandrank calls the vips bandrank operation.
 // band-wise rank of a set of images
-func vipsGenBandrank(input []*C.VipsImage, opts *BandrankOptions) (*C.VipsImage, error) {
-	incOpCounter("bandrank")
+func vipsGenBandrank(ctx context.Context, input []*C.VipsImage, opts *BandrankOptions) (*C.VipsImage, error) {
+	op := startOp(ctx, "bandrank", nil)
+	defer op.end()
 
 	var out_out *C.VipsImage
 
@@ -699,7 +762,7 @@ func vipsGenBandrank(input []*C.VipsImage, opts *BandrankOptions) (*C.VipsImage,
 
 	ret := C.gen_vips_bandrank((**C.VipsImage)(unsafe.Pointer(&input[0])), C.int(len(input)), &out_out, &cOpts)
 	if ret != 0 {
-		return nil, handleImageError(out_out)
+		return nil, op.fail(handleImageError(out_out))
 	}
 
 	return out_out, nil
@@ -712,8 +775,9 @@ type BandunfoldOptions struct {
 
 // vipsGenBandunfold calls the vips bandunfold operation.
 // unfold image bands into x axis
-func vipsGenBandunfold(input *C.VipsImage, opts *BandunfoldOptions) (*C.VipsImage, error) {
-	incOpCounter("bandunfold")
+func vipsGenBandunfold(ctx context.Context, input *C.VipsImage, opts *BandunfoldOptions) (*C.VipsImage, error) {
+	op := startOp(ctx, "bandunfold", input)
+	defer op.end()
 
 	var out_out *C.VipsImage
 
@@ -727,7 +791,7 @@ func vipsGenBandunfold(input *C.VipsImage, opts *BandunfoldOptions) (*C.VipsImag
 
 	ret := C.gen_vips_bandunfold(input, &out_out, &cOpts)
 	if ret != 0 {
-		return nil, handleImageError(out_out)
+		return nil, op.fail(handleImageError(out_out))
 	}
 
 	return out_out, nil
@@ -740,8 +804,9 @@ type BlackOptions struct {
 
 // vipsGenBlack calls the vips black operation.
 // make a black image
-func vipsGenBlack(width int, height int, opts *BlackOptions) (*C.VipsImage, error) {
-	incOpCounter("black")
+func vipsGenBlack(ctx context.Context, width int, height int, opts *BlackOptions) (*C.VipsImage, error) {
+	op := startOp(ctx, "black", nil)
+	defer op.end()
 
 	var out_out *C.VipsImage
 
@@ -755,7 +820,7 @@ func vipsGenBlack(width int, height int, opts *BlackOptions) (*C.VipsImage, erro
 
 	ret := C.gen_vips_black(C.int(width), C.int(height), &out_out, &cOpts)
 	if ret != 0 {
-		return nil, handleImageError(out_out)
+		return nil, op.fail(handleImageError(out_out))
 	}
 
 	return out_out, nil
@@ -763,14 +828,15 @@ func vipsGenBlack(width int, height int, opts *BlackOptions) (*C.VipsImage, erro
 
 // vipsGenBoolean calls the vips boolean operation.
 // boolean operation on two images
-func vipsGenBoolean(left *C.VipsImage, right *C.VipsImage, boolean OperationBoolean) (*C.VipsImage, error) {
-	incOpCounter("boolean")
+func vipsGenBoolean(ctx context.Context, left *C.VipsImage, right *C.VipsImage, boolean OperationBoolean) (*C.VipsImage, error) {
+	op := startOp(ctx, "boolean", left)
+	defer op.end()
 
 	var out_out *C.VipsImage
 
 	ret := C.gen_vips_boolean(left, right, C.VipsOperationBoolean(boolean), &out_out)
 	if ret != 0 {
-		return nil, handleImageError(out_out)
+		return nil, op.fail(handleImageError(out_out))
 	}
 
 	return out_out, nil
@@ -778,14 +844,15 @@ func vipsGenBoolean(left *C.VipsImage, right *C.VipsImage, boolean OperationBool
 
 // vipsGenBooleanConst calls the vips boolean_const operation.
 // boolean operations against a constant
-func vipsGenBooleanConst(input *C.VipsImage, boolean OperationBoolean, c []float64) (*C.VipsImage, error) {
-	incOpCounter("boolean_const")
+func vipsGenBooleanConst(ctx context.Context, input *C.VipsImage, boolean OperationBoolean, c []float64) (*C.VipsImage, error) {
+	op := startOp(ctx, "boolean_const", input)
+	defer op.end()
 
 	var out_out *C.VipsImage
 
 	ret := C.gen_vips_boolean_const(input, C.VipsOperationBoolean(boolean), (*C.double)(unsafe.Pointer(&c[0])), C.int(len(c)), &out_out)
 	if ret != 0 {
-		return nil, handleImageError(out_out)
+		return nil, op.fail(handleImageError(out_out))
 	}
 
 	return out_out, nil
@@ -793,14 +860,15 @@ func vipsGenBooleanConst(input *C.VipsImage, boolean OperationBoolean, c []float
 
 // vipsGenBuildlut calls the vips buildlut operation.
 // build a look-up table
-func vipsGenBuildlut(input *C.VipsImage) (*C.VipsImage, error) {
-	incOpCounter("buildlut")
+func vipsGenBuildlut(ctx context.Context, input *C.VipsImage) (*C.VipsImage, error) {
+	op := startOp(ctx, "buildlut", input)
+	defer op.end()
 
 	var out_out *C.VipsImage
 
 	ret := C.gen_vips_buildlut(input, &out_out)
 	if ret != 0 {
-		return nil, handleImageError(out_out)
+		return nil, op.fail(handleImageError(out_out))
 	}
 
 	return out_out, nil
@@ -808,14 +876,15 @@ func vipsGenBuildlut(input *C.VipsImage) (*C.VipsImage, error) {
 
 // vipsGenByteswap calls the vips byteswap operation.
 // byteswap an image
-func vipsGenByteswap(input *C.VipsImage) (*C.VipsImage, error) {
-	incOpCounter("byteswap")
+func vipsGenByteswap(ctx context.Context, input *C.VipsImage) (*C.VipsImage, error) {
+	op := startOp(ctx, "byteswap", input)
+	defer op.end()
 
 	var out_out *C.VipsImage
 
 	ret := C.gen_vips_byteswap(input, &out_out)
 	if ret != 0 {
-		return nil, handleImageError(out_out)
+		return nil, op.fail(handleImageError(out_out))
 	}
 
 	return out_out, nil
@@ -830,8 +899,9 @@ type CacheOptions struct {
 
 // vipsGenCache calls the vips cache operation.
 // cache an image
-func vipsGenCache(input *C.VipsImage, opts *CacheOptions) (*C.VipsImage, error) {
-	incOpCounter("cache")
+func vipsGenCache(ctx context.Context, input *C.VipsImage, opts *CacheOptions) (*C.VipsImage, error) {
+	op := startOp(ctx, "cache", input)
+	defer op.end()
 
 	var out_out *C.VipsImage
 
@@ -853,7 +923,7 @@ func vipsGenCache(input *C.VipsImage, opts *CacheOptions) (*C.VipsImage, error)
 
 	ret := C.gen_vips_cache(input, &out_out, &cOpts)
 	if ret != 0 {
-		return nil, handleImageError(out_out)
+		return nil, op.fail(handleImageError(out_out))
 	}
 
 	return out_out, nil
@@ -867,8 +937,9 @@ type CannyOptions struct {
 
 // vipsGenCanny calls the vips canny operation.
 // Canny edge detector
-func vipsGenCanny(input *C.VipsImage, opts *CannyOptions) (*C.VipsImage, error) {
-	incOpCounter("canny")
+func vipsGenCanny(ctx context.Context, input *C.VipsImage, opts *CannyOptions) (*C.VipsImage, error) {
+	op := startOp(ctx, "canny", input)
+	defer op.end()
 
 	var out_out *C.VipsImage
 
@@ -886,7 +957,7 @@ func vipsGenCanny(input *C.VipsImage, opts *CannyOptions) (*C.VipsImage, error)
 
 	ret := C.gen_vips_canny(input, &out_out, &cOpts)
 	if ret != 0 {
-		return nil, handleImageError(out_out)
+		return nil, op.fail(handleImageError(out_out))
 	}
 
 	return out_out, nil
@@ -899,8 +970,9 @@ type CastOptions struct {
 
 // vipsGenCast calls the vips cast operation.
 // cast an image
-func vipsGenCast(input *C.VipsImage, format BandFormat, opts *CastOptions) (*C.VipsImage, error) {
-	incOpCounter("cast")
+func vipsGenCast(ctx context.Context, input *C.VipsImage, format BandFormat, opts *CastOptions) (*C.VipsImage, error) {
+	op := startOp(ctx, "cast", input)
+	defer op.end()
 
 	var out_out *C.VipsImage
 
@@ -914,7 +986,7 @@ func vipsGenCast(input *C.VipsImage, format BandFormat, opts *CastOptions) (*C.V
 
 	ret := C.gen_vips_cast(input, C.VipsBandFormat(format), &out_out, &cOpts)
 	if ret != 0 {
-		return nil, handleImageError(out_out)
+		return nil, op.fail(handleImageError(out_out))
 	}
 
 	return out_out, nil
@@ -928,8 +1000,9 @@ type ClampOptions struct {
 
 // vipsGenClamp calls the vips clamp operation.
 // clamp values of an image
-func vipsGenClamp(input *C.VipsImage, opts *ClampOptions) (*C.VipsImage, error) {
-	incOpCounter("clamp")
+func vipsGenClamp(ctx context.Context, input *C.VipsImage, opts *ClampOptions) (*C.VipsImage, error) {
+	op := startOp(ctx, "clamp", input)
+	defer op.end()
 
 	var out_out *C.VipsImage
 
@@ -947,7 +1020,7 @@ func vipsGenClamp(input *C.VipsImage, opts *ClampOptions) (*C.VipsImage, error)
 
 	ret := C.gen_vips_clamp(input, &out_out, &cOpts)
 	if ret != 0 {
-		return nil, handleImageError(out_out)
+		return nil, op.fail(handleImageError(out_out))
 	}
 
 	return out_out, nil
@@ -965,8 +1038,9 @@ type CompassOptions struct {
 
 // vipsGenCompass calls the vips compass operation.
 // convolve with rotating mask
-func vipsGenCompass(input *C.VipsImage, mask *C.VipsImage, opts *CompassOptions) (*C.VipsImage, error) {
-	incOpCounter("compass")
+func vipsGenCompass(ctx context.Context, input *C.VipsImage, mask *C.VipsImage, opts *CompassOptions) (*C.VipsImage, error) {
+	op := startOp(ctx, "compass", input)
+	defer op.end()
 
 	var out_out *C.VipsImage
 
@@ -1000,7 +1074,7 @@ func vipsGenCompass(input *C.VipsImage, mask *C.VipsImage, opts *CompassOptions)
 
 	ret := C.gen_vips_compass(input, mask, &out_out, &cOpts)
 	if ret != 0 {
-		return nil, handleImageError(out_out)
+		return nil, op.fail(handleImageError(out_out))
 	}
 
 	return out_out, nil
@@ -1008,14 +1082,15 @@ func vipsGenCompass(input *C.VipsImage, mask *C.VipsImage, opts *CompassOptions)
 
 // vipsGenComplex calls the vips complex operation.
 // perform a complex operation on an image
-func vipsGenComplex(input *C.VipsImage, cmplx OperationComplex) (*C.VipsImage, error) {
-	incOpCounter("complex")
+func vipsGenComplex(ctx context.Context, input *C.VipsImage, cmplx OperationComplex) (*C.VipsImage, error) {
+	op := startOp(ctx, "complex", input)
+	defer op.end()
 
 	var out_out *C.VipsImage
 
 	ret := C.gen_vips_complex(input, C.VipsOperationComplex(cmplx), &out_out)
 	if ret != 0 {
-		return nil, handleImageError(out_out)
+		return nil, op.fail(handleImageError(out_out))
 	}
 
 	return out_out, nil
@@ -1023,14 +1098,15 @@ func vipsGenComplex(input *C.VipsImage, cmplx OperationComplex) (*C.VipsImage, e
 
 // vipsGenComplex2 calls the vips complex2 operation.
 // complex binary operations on two images
-func vipsGenComplex2(left *C.VipsImage, right *C.VipsImage, cmplx OperationComplex2) (*C.VipsImage, error) {
-	incOpCounter("complex2")
+func vipsGenComplex2(ctx context.Context, left *C.VipsImage, right *C.VipsImage, cmplx OperationComplex2) (*C.VipsImage, error) {
+	op := startOp(ctx, "complex2", left)
+	defer op.end()
 
 	var out_out *C.VipsImage
 
 	ret := C.gen_vips_complex2(left, right, C.VipsOperationComplex2(cmplx), &out_out)
 	if ret != 0 {
-		return nil, handleImageError(out_out)
+		return nil, op.fail(handleImageError(out_out))
 	}
 
 	return out_out, nil
@@ -1038,14 +1114,15 @@ func vipsGenComplex2(left *C.VipsImage, right *C.VipsImage, cmplx OperationCompl
 
 // vipsGenComplexform calls the vips complexform operation.
 // form a complex image from two real images
-func vipsGenComplexform(left *C.VipsImage, right *C.VipsImage) (*C.VipsImage, error) {
-	incOpCounter("complexform")
+func vipsGenComplexform(ctx context.Context, left *C.VipsImage, right *C.VipsImage) (*C.VipsImage, error) {
+	op := startOp(ctx, "complexform", left)
+	defer op.end()
 
 	var out_out *C.VipsImage
 
 	ret := C.gen_vips_complexform(left, right, &out_out)
 	if ret != 0 {
-		return nil, handleImageError(out_out)
+		return nil, op.fail(handleImageError(out_out))
 	}
 
 	return out_out, nil
@@ -1053,14 +1130,15 @@ func vipsGenComplexform(left *C.VipsImage, right *C.VipsImage) (*C.VipsImage, er
 
 // vipsGenComplexget calls the vips complexget operation.
 // get a component from a complex image
-func vipsGenComplexget(input *C.VipsImage, get OperationComplexget) (*C.VipsImage, error) {
-	incOpCounter("complexget")
+func vipsGenComplexget(ctx context.Context, input *C.VipsImage, get OperationComplexget) (*C.VipsImage, error) {
+	op := startOp(ctx, "complexget", input)
+	defer op.end()
 
 	var out_out *C.VipsImage
 
 	ret := C.gen_vips_complexget(input, C.VipsOperationComplexget(get), &out_out)
 	if ret != 0 {
-		return nil, handleImageError(out_out)
+		return nil, op.fail(handleImageError(out_out))
 	}
 
 	return out_out, nil
@@ -1076,8 +1154,9 @@ type Composite2Options struct {
 
 // vipsGenComposite2 calls the vips composite2 operation.
 // blend a pair of images with a blend mode
-func vipsGenComposite2(base *C.VipsImage, overlay *C.VipsImage, mode BlendMode, opts *Composite2Options) (*C.VipsImage, error) {
-	incOpCounter("composite2")
+func vipsGenComposite2(ctx context.Context, base *C.VipsImage, overlay *C.VipsImage, mode BlendMode, opts *Composite2Options) (*C.VipsImage, error) {
+	op := startOp(ctx, "composite2", base)
+	defer op.end()
 
 	var out_out *C.VipsImage
 
@@ -1103,7 +1182,7 @@ func vipsGenComposite2(base *C.VipsImage, overlay *C.VipsImage, mode BlendMode,
 
 	ret := C.gen_vips_composite2(base, overlay, C.VipsBlendMode(mode), &out_out, &cOpts)
 	if ret != 0 {
-		return nil, handleImageError(out_out)
+		return nil, op.fail(handleImageError(out_out))
 	}
 
 	return out_out, nil
@@ -1118,8 +1197,9 @@ type ConvOptions struct {
 
 // vipsGenConv calls the vips conv operation.
 // convolution operation
-func vipsGenConv(input *C.VipsImage, mask *C.VipsImage, opts *ConvOptions) (*C.VipsImage, error) {
-	incOpCounter("conv")
+func vipsGenConv(ctx context.Context, input *C.VipsImage, mask *C.VipsImage, opts *ConvOptions) (*C.VipsImage, error) {
+	op := startOp(ctx, "conv", input)
+	defer op.end()
 
 	var out_out *C.VipsImage
 
@@ -1141,7 +1221,7 @@ func vipsGenConv(input *C.VipsImage, mask *C.VipsImage, opts *ConvOptions) (*C.V
 
 	ret := C.gen_vips_conv(input, mask, &out_out, &cOpts)
 	if ret != 0 {
-		return nil, handleImageError(out_out)
+		return nil, op.fail(handleImageError(out_out))
 	}
 
 	return out_out, nil
@@ -1155,8 +1235,9 @@ type ConvaOptions struct {
 
 // vipsGenConva calls the vips conva operation.
 // approximate integer convolution
-func vipsGenConva(input *C.VipsImage, mask *C.VipsImage, opts *ConvaOptions) (*C.VipsImage, error) {
-	incOpCounter("conva")
+func vipsGenConva(ctx context.Context, input *C.VipsImage, mask *C.VipsImage, opts *ConvaOptions) (*C.VipsImage, error) {
+	op := startOp(ctx, "conva", input)
+	defer op.end()
 
 	var out_out *C.VipsImage
 
@@ -1174,7 +1255,7 @@ func vipsGenConva(input *C.VipsImage, mask *C.VipsImage, opts *ConvaOptions) (*C
 
 	ret := C.gen_vips_conva(input, mask, &out_out, &cOpts)
 	if ret != 0 {
-		return nil, handleImageError(out_out)
+		return nil, op.fail(handleImageError(out_out))
 	}
 
 	return out_out, nil
@@ -1187,8 +1268,9 @@ type ConvasepOptions struct {
 
 // vipsGenConvasep calls the vips convasep operation.
 // approximate separable integer convolution
-func vipsGenConvasep(input *C.VipsImage, mask *C.VipsImage, opts *ConvasepOptions) (*C.VipsImage, error) {
-	incOpCounter("convasep")
+func vipsGenConvasep(ctx context.Context, input *C.VipsImage, mask *C.VipsImage, opts *ConvasepOptions) (*C.VipsImage, error) {
+	op := startOp(ctx, "convasep", input)
+	defer op.end()
 
 	var out_out *C.VipsImage
 
@@ -1202,7 +1284,7 @@ func vipsGenConvasep(input *C.VipsImage, mask *C.VipsImage, opts *ConvasepOption
 
 	ret := C.gen_vips_convasep(input, mask, &out_out, &cOpts)
 	if ret != 0 {
-		return nil, handleImageError(out_out)
+		return nil, op.fail(handleImageError(out_out))
 	}
 
 	return out_out, nil
@@ -1210,14 +1292,15 @@ func vipsGenConvasep(input *C.VipsImage, mask *C.VipsImage, opts *ConvasepOption
 
 // vipsGenConvf calls the vips convf operation.
 // float convolution operation
-func vipsGenConvf(input *C.VipsImage, mask *C.VipsImage) (*C.VipsImage, error) {
-	incOpCounter("convf")
+func vipsGenConvf(ctx context.Context, input *C.VipsImage, mask *C.VipsImage) (*C.VipsImage, error) {
+	op := startOp(ctx, "convf", input)
+	defer op.end()
 
 	var out_out *C.VipsImage
 
 	ret := C.gen_vips_convf(input, mask, &out_out)
 	if ret != 0 {
-		return nil, handleImageError(out_out)
+		return nil, op.fail(handleImageError(out_out))
 	}
 
 	return out_out, nil
@@ -1225,14 +1308,15 @@ func vipsGenConvf(input *C.VipsImage, mask *C.VipsImage) (*C.VipsImage, error) {
 
 // vipsGenConvi calls the vips convi operation.
 // int convolution operation
-func vipsGenConvi(input *C.VipsImage, mask *C.VipsImage) (*C.VipsImage, error) {
-	incOpCounter("convi")
+func vipsGenConvi(ctx context.Context, input *C.VipsImage, mask *C.VipsImage) (*C.VipsImage, error) {
+	op := startOp(ctx, "convi", input)
+	defer op.end()
 
 	var out_out *C.VipsImage
 
 	ret := C.gen_vips_convi(input, mask, &out_out)
 	if ret != 0 {
-		return nil, handleImageError(out_out)
+		return nil, op.fail(handleImageError(out_out))
 	}
 
 	return out_out, nil
@@ -1247,8 +1331,9 @@ type ConvsepOptions struct {
 
 // vipsGenConvsep calls the vips convsep operation.
 // separable convolution operation
-func vipsGenConvsep(input *C.VipsImage, mask *C.VipsImage, opts *ConvsepOptions) (*C.VipsImage, error) {
-	incOpCounter("convsep")
+func vipsGenConvsep(ctx context.Context, input *C.VipsImage, mask *C.VipsImage, opts *ConvsepOptions) (*C.VipsImage, error) {
+	op := startOp(ctx, "convsep", input)
+	defer op.end()
 
 	var out_out *C.VipsImage
 
@@ -1270,7 +1355,7 @@ func vipsGenConvsep(input *C.VipsImage, mask *C.VipsImage, opts *ConvsepOptions)
 
 	ret := C.gen_vips_convsep(input, mask, &out_out, &cOpts)
 	if ret != 0 {
-		return nil, handleImageError(out_out)
+		return nil, op.fail(handleImageError(out_out))
 	}
 
 	return out_out, nil
@@ -1292,8 +1377,9 @@ type CopyOptions struct {
 
 // vipsGenCopy calls the vips copy operation.
 // copy an image
-func vipsGenCopy(input *C.VipsImage, opts *CopyOptions) (*C.VipsImage, error) {
-	incOpCounter("copy")
+func vipsGenCopy(ctx context.Context, input *C.VipsImage, opts *CopyOptions) (*C.VipsImage, error) {
+	op := startOp(ctx, "copy", input)
+	defer op.end()
 
 	var out_out *C.VipsImage
 
@@ -1343,7 +1429,7 @@ func vipsGenCopy(input *C.VipsImage, opts *CopyOptions) (*C.VipsImage, error) {
 
 	ret := C.gen_vips_copy(input, &out_out, &cOpts)
 	if ret != 0 {
-		return nil, handleImageError(out_out)
+		return nil, op.fail(handleImageError(out_out))
 	}
 
 	return out_out, nil
@@ -1351,14 +1437,15 @@ func vipsGenCopy(input *C.VipsImage, opts *CopyOptions) (*C.VipsImage, error) {
 
 // vipsGenCountlines calls the vips countlines operation.
 // count lines in an image
-func vipsGenCountlines(input *C.VipsImage, direction Direction) (float64, error) {
-	incOpCounter("countlines")
+func vipsGenCountlines(ctx context.Context, input *C.VipsImage, direction Direction) (float64, error) {
+	op := startOp(ctx, "countlines", input)
+	defer op.end()
 
 	var out_nolines C.double
 
 	ret := C.gen_vips_countlines(input, C.VipsDirection(direction), &out_nolines)
 	if ret != 0 {
-		return 0, handleVipsError()
+		return 0, op.fail(handleVipsError())
 	}
 
 	return float64(out_nolines), nil
@@ -1366,14 +1453,15 @@ func vipsGenCountlines(input *C.VipsImage, direction Direction) (float64, error)
 
 // vipsGenDE00 calls the vips dE00 operation.
 // calculate dE00
-func vipsGenDE00(left *C.VipsImage, right *C.VipsImage) (*C.VipsImage, error) {
-	incOpCounter("dE00")
+func vipsGenDE00(ctx context.Context, left *C.VipsImage, right *C.VipsImage) (*C.VipsImage, error) {
+	op := startOp(ctx, "dE00", left)
+	defer op.end()
 
 	var out_out *C.VipsImage
 
 	ret := C.gen_vips_dE00(left, right, &out_out)
 	if ret != 0 {
-		return nil, handleImageError(out_out)
+		return nil, op.fail(handleImageError(out_out))
 	}
 
 	return out_out, nil
@@ -1381,14 +1469,15 @@ func vipsGenDE00(left *C.VipsImage, right *C.VipsImage) (*C.VipsImage, error) {
 
 // vipsGenDE76 calls the vips dE76 operation.
 // calculate dE76
-func vipsGenDE76(left *C.VipsImage, right *C.VipsImage) (*C.VipsImage, error) {
-	incOpCounter("dE76")
+func vipsGenDE76(ctx context.Context, left *C.VipsImage, right *C.VipsImage) (*C.VipsImage, error) {
+	op := startOp(ctx, "dE76", left)
+	defer op.end()
 
 	var out_out *C.VipsImage
 
 	ret := C.gen_vips_dE76(left, right, &out_out)
 	if ret != 0 {
-		return nil, handleImageError(out_out)
+		return nil, op.fail(handleImageError(out_out))
 	}
 
 	return out_out, nil
@@ -1396,14 +1485,15 @@ func vipsGenDE76(left *C.VipsImage, right *C.VipsImage) (*C.VipsImage, error) {
 
 // vipsGenDECMC calls the vips dECMC operation.
 // calculate dECMC
-func vipsGenDECMC(left *C.VipsImage, right *C.VipsImage) (*C.VipsImage, error) {
-	incOpCounter("dECMC")
+func vipsGenDECMC(ctx context.Context, left *C.VipsImage, right *C.VipsImage) (*C.VipsImage, error) {
+	op := startOp(ctx, "dECMC", left)
+	defer op.end()
 
 	var out_out *C.VipsImage
 
 	ret := C.gen_vips_dECMC(left, right, &out_out)
 	if ret != 0 {
-		return nil, handleImageError(out_out)
+		return nil, op.fail(handleImageError(out_out))
 	}
 
 	return out_out, nil
@@ -1411,14 +1501,15 @@ func vipsGenDECMC(left *C.VipsImage, right *C.VipsImage) (*C.VipsImage, error) {
 
 // vipsGenDeviate calls the vips deviate operation.
 // find image standard deviation
-func vipsGenDeviate(input *C.VipsImage) (float64, error) {
-	incOpCounter("deviate")
+func vipsGenDeviate(ctx context.Context, input *C.VipsImage) (float64, error) {
+	op := startOp(ctx, "deviate", input)
+	defer op.end()
 
 	var out_out C.double
 
 	ret := C.gen_vips_deviate(input, &out_out)
 	if ret != 0 {
-		return 0, handleVipsError()
+		return 0, op.fail(handleVipsError())
 	}
 
 	return float64(out_out), nil
@@ -1426,14 +1517,15 @@ func vipsGenDeviate(input *C.VipsImage) (float64, error) {
 
 // vipsGenDivide calls the vips divide operation.
 // divide two images
-func vipsGenDivide(left *C.VipsImage, right *C.VipsImage) (*C.VipsImage, error) {
-	incOpCounter("divide")
+func vipsGenDivide(ctx context.Context, left *C.VipsImage, right *C.VipsImage) (*C.VipsImage, error) {
+	op := startOp(ctx, "divide", left)
+	defer op.end()
 
 	var out_out *C.VipsImage
 
 	ret := C.gen_vips_divide(left, right, &out_out)
 	if ret != 0 {
-		return nil, handleImageError(out_out)
+		return nil, op.fail(handleImageError(out_out))
 	}
 
 	return out_out, nil
@@ -1441,14 +1533,15 @@ func vipsGenDivide(left *C.VipsImage, right *C.VipsImage) (*C.VipsImage, error)
 
 // vipsGenExtractArea calls the vips extract_area operation.
 // extract an area from an image
-func vipsGenExtractArea(input *C.VipsImage, left int, top int, width int, height int) (*C.VipsImage, error) {
-	incOpCounter("extract_area")
+func vipsGenExtractArea(ctx context.Context, input *C.VipsImage, left int, top int, width int, height int) (*C.VipsImage, error) {
+	op := startOp(ctx, "extract_area", input)
+	defer op.end()
 
 	var out_out *C.VipsImage
 
 	ret := C.gen_vips_extract_area(input, C.int(left), C.int(top), C.int(width), C.int(height), &out_out)
 	if ret != 0 {
-		return nil, handleImageError(out_out)
+		return nil, op.fail(handleImageError(out_out))
 	}
 
 	return out_out, nil
@@ -1461,8 +1554,9 @@ type ExtractBandOptions struct {
 
 // vipsGenExtractBand calls the vips extract_band operation.
 // extract band from an image
-func vipsGenExtractBand(input *C.VipsImage, band int, opts *ExtractBandOptions) (*C.VipsImage, error) {
-	incOpCounter("extract_band")
+func vipsGenExtractBand(ctx context.Context, input *C.VipsImage, band int, opts *ExtractBandOptions) (*C.VipsImage, error) {
+	op := startOp(ctx, "extract_band", input)
+	defer op.end()
 
 	var out_out *C.VipsImage
 
@@ -1476,7 +1570,7 @@ func vipsGenExtractBand(input *C.VipsImage, band int, opts *ExtractBandOptions)
 
 	ret := C.gen_vips_extract_band(input, C.int(band), &out_out, &cOpts)
 	if ret != 0 {
-		return nil, handleImageError(out_out)
+		return nil, op.fail(handleImageError(out_out))
 	}
 
 	return out_out, nil
@@ -1490,8 +1584,9 @@ type EyeOptions struct {
 
 // vipsGenEye calls the vips eye operation.
 // make an image showing the eye's spatial response
-func vipsGenEye(width int, height int, opts *EyeOptions) (*C.VipsImage, error) {
-	incOpCounter("eye")
+func vipsGenEye(ctx context.Context, width int, height int, opts *EyeOptions) (*C.VipsImage, error) {
+	op := startOp(ctx, "eye", nil)
+	defer op.end()
 
 	var out_out *C.VipsImage
 
@@ -1509,7 +1604,7 @@ func vipsGenEye(width int, height int, opts *EyeOptions) (*C.VipsImage, error) {
 
 	ret := C.gen_vips_eye(C.int(width), C.int(height), &out_out, &cOpts)
 	if ret != 0 {
-		return nil, handleImageError(out_out)
+		return nil, op.fail(handleImageError(out_out))
 	}
 
 	return out_out, nil
@@ -1517,14 +1612,15 @@ func vipsGenEye(width int, height int, opts *EyeOptions) (*C.VipsImage, error) {
 
 // vipsGenFalsecolour calls the vips falsecolour operation.
 // false-color an image
-func vipsGenFalsecolour(input *C.VipsImage) (*C.VipsImage, error) {
-	incOpCounter("falsecolour")
+func vipsGenFalsecolour(ctx context.Context, input *C.VipsImage) (*C.VipsImage, error) {
+	op := startOp(ctx, "falsecolour", input)
+	defer op.end()
 
 	var out_out *C.VipsImage
 
 	ret := C.gen_vips_falsecolour(input, &out_out)
 	if ret != 0 {
-		return nil, handleImageError(out_out)
+		return nil, op.fail(handleImageError(out_out))
 	}
 
 	return out_out, nil
@@ -1532,14 +1628,15 @@ func vipsGenFalsecolour(input *C.VipsImage) (*C.VipsImage, error) {
 
 // vipsGenFastcor calls the vips fastcor operation.
 // fast correlation
-func vipsGenFastcor(input *C.VipsImage, ref *C.VipsImage) (*C.VipsImage, error) {
-	incOpCounter("fastcor")
+func vipsGenFastcor(ctx context.Context, input *C.VipsImage, ref *C.VipsImage) (*C.VipsImage, error) {
+	op := startOp(ctx, "fastcor", input)
+	defer op.end()
 
 	var out_out *C.VipsImage
 
 	ret := C.gen_vips_fastcor(input, ref, &out_out)
 	if ret != 0 {
-		return nil, handleImageError(out_out)
+		return nil, op.fail(handleImageError(out_out))
 	}
 
 	return out_out, nil
@@ -1547,15 +1644,16 @@ func vipsGenFastcor(input *C.VipsImage, ref *C.VipsImage) (*C.VipsImage, error)
 
 // vipsGenFillNearest calls the vips fill_nearest operation.
 // fill image zeros with nearest non-zero pixel
-func vipsGenFillNearest(input *C.VipsImage) (*C.VipsImage, *C.VipsImage, error) {
-	incOpCounter("fill_nearest")
+func vipsGenFillNearest(ctx context.Context, input *C.VipsImage) (*C.VipsImage, *C.VipsImage, error) {
+	op := startOp(ctx, "fill_nearest", input)
+	defer op.end()
 
 	var out_out *C.VipsImage
 	var out_distance *C.VipsImage
 
 	ret := C.gen_vips_fill_nearest(input, &out_out, &out_distance)
 	if ret != 0 {
-		return nil, nil, handleImageError(out_out)
+		return nil, nil, op.fail(handleImageError(out_out))
 	}
 
 	return out_out, out_distance, nil
@@ -1569,8 +1667,9 @@ type FlattenOptions struct {
 
 // vipsGenFlatten calls the vips flatten operation.
 // flatten alpha out of an image
-func vipsGenFlatten(input *C.VipsImage, opts *FlattenOptions) (*C.VipsImage, error) {
-	incOpCounter("flatten")
+func vipsGenFlatten(ctx context.Context, input *C.VipsImage, opts *FlattenOptions) (*C.VipsImage, error) {
+	op := startOp(ctx, "flatten", input)
+	defer op.end()
 
 	var out_out *C.VipsImage
 
@@ -1592,7 +1691,7 @@ func vipsGenFlatten(input *C.VipsImage, opts *FlattenOptions) (*C.VipsImage, err
 
 	ret := C.gen_vips_flatten(input, &out_out, &cOpts)
 	if ret != 0 {
-		return nil, handleImageError(out_out)
+		return nil, op.fail(handleImageError(out_out))
 	}
 
 	return out_out, nil
@@ -1600,14 +1699,15 @@ func vipsGenFlatten(input *C.VipsImage, opts *FlattenOptions) (*C.VipsImage, err
 
 // vipsGenFlip calls the vips flip operation.
 // flip an image
-func vipsGenFlip(input *C.VipsImage, direction Direction) (*C.VipsImage, error) {
-	incOpCounter("flip")
+func vipsGenFlip(ctx context.Context, input *C.VipsImage, direction Direction) (*C.VipsImage, error) {
+	op := startOp(ctx, "flip", input)
+	defer op.end()
 
 	var out_out *C.VipsImage
 
 	ret := C.gen_vips_flip(input, C.VipsDirection(direction), &out_out)
 	if ret != 0 {
-		return nil, handleImageError(out_out)
+		return nil, op.fail(handleImageError(out_out))
 	}
 
 	return out_out, nil
@@ -1615,14 +1715,15 @@ func vipsGenFlip(input *C.VipsImage, direction Direction) (*C.VipsImage, error)
 
 // vipsGenFloat2rad calls the vips float2rad operation.
 // transform float RGB to Radiance coding
-func vipsGenFloat2rad(input *C.VipsImage) (*C.VipsImage, error) {
-	incOpCounter("float2rad")
+func vipsGenFloat2rad(ctx context.Context, input *C.VipsImage) (*C.VipsImage, error) {
+	op := startOp(ctx, "float2rad", input)
+	defer op.end()
 
 	var out_out *C.VipsImage
 
 	ret := C.gen_vips_float2rad(input, &out_out)
 	if ret != 0 {
-		return nil, handleImageError(out_out)
+		return nil, op.fail(handleImageError(out_out))
 	}
 
 	return out_out, nil
@@ -1630,14 +1731,15 @@ func vipsGenFloat2rad(input *C.VipsImage) (*C.VipsImage, error) {
 
 // vipsGenFractsurf calls the vips fractsurf operation.
 // make a fractal surface
-func vipsGenFractsurf(width int, height int, fractalDimension float64) (*C.VipsImage, error) {
-	incOpCounter("fractsurf")
+func vipsGenFractsurf(ctx context.Context, width int, height int, fractalDimension float64) (*C.VipsImage, error) {
+	op := startOp(ctx, "fractsurf", nil)
+	defer op.end()
 
 	var out_out *C.VipsImage
 
 	ret := C.gen_vips_fractsurf(C.int(width), C.int(height), C.double(fractalDimension), &out_out)
 	if ret != 0 {
-		return nil, handleImageError(out_out)
+		return nil, op.fail(handleImageError(out_out))
 	}
 
 	return out_out, nil
@@ -1645,14 +1747,15 @@ func vipsGenFractsurf(width int, height int, fractalDimension float64) (*C.VipsI
 
 // vipsGenFreqmult calls the vips freqmult operation.
 // frequency-domain filtering
-func vipsGenFreqmult(input *C.VipsImage, mask *C.VipsImage) (*C.VipsImage, error) {
-	incOpCounter("freqmult")
+func vipsGenFreqmult(ctx context.Context, input *C.VipsImage, mask *C.VipsImage) (*C.VipsImage, error) {
+	op := startOp(ctx, "freqmult", input)
+	defer op.end()
 
 	var out_out *C.VipsImage
 
 	ret := C.gen_vips_freqmult(input, mask, &out_out)
 	if ret != 0 {
-		return nil, handleImageError(out_out)
+		return nil, op.fail(handleImageError(out_out))
 	}
 
 	return out_out, nil
@@ -1660,14 +1763,15 @@ func vipsGenFreqmult(input *C.VipsImage, mask *C.VipsImage) (*C.VipsImage, error
 
 // vipsGenFwfft calls the vips fwfft operation.
 // forward FFT
-func vipsGenFwfft(input *C.VipsImage) (*C.VipsImage, error) {
-	incOpCounter("fwfft")
+func vipsGenFwfft(ctx context.Context, input *C.VipsImage) (*C.VipsImage, error) {
+	op := startOp(ctx, "fwfft", input)
+	defer op.end()
 
 	var out_out *C.VipsImage
 
 	ret := C.gen_vips_fwfft(input, &out_out)
 	if ret != 0 {
-		return nil, handleImageError(out_out)
+		return nil, op.fail(handleImageError(out_out))
 	}
 
 	return out_out, nil
@@ -1680,8 +1784,9 @@ type GammaOptions struct {
 
 // vipsGenGamma calls the vips gamma operation.
 // gamma an image
-func vipsGenGamma(input *C.VipsImage, opts *GammaOptions) (*C.VipsImage, error) {
-	incOpCounter("gamma")
+func vipsGenGamma(ctx context.Context, input *C.VipsImage, opts *GammaOptions) (*C.VipsImage, error) {
+	op := startOp(ctx, "gamma", input)
+	defer op.end()
 
 	var out_out *C.VipsImage
 
@@ -1695,7 +1800,7 @@ func vipsGenGamma(input *C.VipsImage, opts *GammaOptions) (*C.VipsImage, error)
 
 	ret := C.gen_vips_gamma(input, &out_out, &cOpts)
 	if ret != 0 {
-		return nil, handleImageError(out_out)
+		return nil, op.fail(handleImageError(out_out))
 	}
 
 	return out_out, nil
@@ -1709,8 +1814,9 @@ type GaussblurOptions struct {
 
 // vipsGenGaussblur calls the vips gaussblur operation.
 // gaussian blur
-func vipsGenGaussblur(input *C.VipsImage, sigma float64, opts *GaussblurOptions) (*C.VipsImage, error) {
-	incOpCounter("gaussblur")
+func vipsGenGaussblur(ctx context.Context, input *C.VipsImage, sigma float64, opts *GaussblurOptions) (*C.VipsImage, error) {
+	op := startOp(ctx, "gaussblur", input)
+	defer op.end()
 
 	var out_out *C.VipsImage
 
@@ -1728,7 +1834,7 @@ func vipsGenGaussblur(input *C.VipsImage, sigma float64, opts *GaussblurOptions)
 
 	ret := C.gen_vips_gaussblur(input, C.double(sigma), &out_out, &cOpts)
 	if ret != 0 {
-		return nil, handleImageError(out_out)
+		return nil, op.fail(handleImageError(out_out))
 	}
 
 	return out_out, nil
@@ -1742,8 +1848,9 @@ type GaussmatOptions struct {
 
 // vipsGenGaussmat calls the vips gaussmat operation.
 // make a gaussian image
-func vipsGenGaussmat(sigma float64, minAmpl float64, opts *GaussmatOptions) (*C.VipsImage, error) {
-	incOpCounter("gaussmat")
+func vipsGenGaussmat(ctx context.Context, sigma float64, minAmpl float64, opts *GaussmatOptions) (*C.VipsImage, error) {
+	op := startOp(ctx, "gaussmat", nil)
+	defer op.end()
 
 	var out_out *C.VipsImage
 
@@ -1761,7 +1868,7 @@ func vipsGenGaussmat(sigma float64, minAmpl float64, opts *GaussmatOptions) (*C.
 
 	ret := C.gen_vips_gaussmat(C.double(sigma), C.double(minAmpl), &out_out, &cOpts)
 	if ret != 0 {
-		return nil, handleImageError(out_out)
+		return nil, op.fail(handleImageError(out_out))
 	}
 
 	return out_out, nil
@@ -1776,8 +1883,9 @@ type GaussnoiseOptions struct {
 
 // vipsGenGaussnoise calls the vips gaussnoise operation.
 // make a gaussnoise image
-func vipsGenGaussnoise(width int, height int, opts *GaussnoiseOptions) (*C.VipsImage, error) {
-	incOpCounter("gaussnoise")
+func vipsGenGaussnoise(ctx context.Context, width int, height int, opts *GaussnoiseOptions) (*C.VipsImage, error) {
+	op := startOp(ctx, "gaussnoise", nil)
+	defer op.end()
 
 	var out_out *C.VipsImage
 
@@ -1799,7 +1907,7 @@ func vipsGenGaussnoise(width int, height int, opts *GaussnoiseOptions) (*C.VipsI
 
 	ret := C.gen_vips_gaussnoise(C.int(width), C.int(height), &out_out, &cOpts)
 	if ret != 0 {
-		return nil, handleImageError(out_out)
+		return nil, op.fail(handleImageError(out_out))
 	}
 
 	return out_out, nil
@@ -1813,8 +1921,9 @@ type GlobalbalanceOptions struct {
 
 // vipsGenGlobalbalance calls the vips globalbalance operation.
 // global balance an image mosaic
-func vipsGenGlobalbalance(input *C.VipsImage, opts *GlobalbalanceOptions) (*C.VipsImage, error) {
-	incOpCounter("globalbalance")
+func vipsGenGlobalbalance(ctx context.Context, input *C.VipsImage, opts *GlobalbalanceOptions) (*C.VipsImage, error) {
+	op := startOp(ctx, "globalbalance", input)
+	defer op.end()
 
 	var out_out *C.VipsImage
 
@@ -1832,7 +1941,7 @@ func vipsGenGlobalbalance(input *C.VipsImage, opts *GlobalbalanceOptions) (*C.Vi
 
 	ret := C.gen_vips_globalbalance(input, &out_out, &cOpts)
 	if ret != 0 {
-		return nil, handleImageError(out_out)
+		return nil, op.fail(handleImageError(out_out))
 	}
 
 	return out_out, nil
@@ -1846,8 +1955,9 @@ type GravityOptions struct {
 
 // vipsGenGravity calls the vips gravity operation.
 // place an image within a larger image with a certain gravity
-func vipsGenGravity(input *C.VipsImage, direction Gravity, width int, height int, opts *GravityOptions) (*C.VipsImage, error) {
-	incOpCounter("gravity")
+func vipsGenGravity(ctx context.Context, input *C.VipsImage, direction Gravity, width int, height int, opts *GravityOptions) (*C.VipsImage, error) {
+	op := startOp(ctx, "gravity", input)
+	defer op.end()
 
 	var out_out *C.VipsImage
 
@@ -1869,7 +1979,7 @@ func vipsGenGravity(input *C.VipsImage, direction Gravity, width int, height int
 
 	ret := C.gen_vips_gravity(input, C.VipsCompassDirection(direction), C.int(width), C.int(height), &out_out, &cOpts)
 	if ret != 0 {
-		return nil, handleImageError(out_out)
+		return nil, op.fail(handleImageError(out_out))
 	}
 
 	return out_out, nil
@@ -1882,8 +1992,9 @@ type GreyOptions struct {
 
 // vipsGenGrey calls the vips grey operation.
 // make a grey ramp image
-func vipsGenGrey(width int, height int, opts *GreyOptions) (*C.VipsImage, error) {
-	incOpCounter("grey")
+func vipsGenGrey(ctx context.Context, width int, height int, opts *GreyOptions) (*C.VipsImage, error) {
+	op := startOp(ctx, "grey", nil)
+	defer op.end()
 
 	var out_out *C.VipsImage
 
@@ -1897,7 +2008,7 @@ func vipsGenGrey(width int, height int, opts *GreyOptions) (*C.VipsImage, error)
 
 	ret := C.gen_vips_grey(C.int(width), C.int(height), &out_out, &cOpts)
 	if ret != 0 {
-		return nil, handleImageError(out_out)
+		return nil, op.fail(handleImageError(out_out))
 	}
 
 	return out_out, nil
@@ -1905,14 +2016,15 @@ func vipsGenGrey(width int, height int, opts *GreyOptions) (*C.VipsImage, error)
 
 // vipsGenGrid calls the vips grid operation.
 // grid an image
-func vipsGenGrid(input *C.VipsImage, tileHeight int, across int, down int) (*C.VipsImage, error) {
-	incOpCounter("grid")
+func vipsGenGrid(ctx context.Context, input *C.VipsImage, tileHeight int, across int, down int) (*C.VipsImage, error) {
+	op := startOp(ctx, "grid", input)
+	defer op.end()
 
 	var out_out *C.VipsImage
 
 	ret := C.gen_vips_grid(input, C.int(tileHeight), C.int(across), C.int(down), &out_out)
 	if ret != 0 {
-		return nil, handleImageError(out_out)
+		return nil, op.fail(handleImageError(out_out))
 	}
 
 	return out_out, nil
@@ -1920,14 +2032,15 @@ func vipsGenGrid(input *C.VipsImage, tileHeight int, across int, down int) (*C.V
 
 // vipsGenHistCum calls the vips hist_cum operation.
 // form cumulative histogram
-func vipsGenHistCum(input *C.VipsImage) (*C.VipsImage, error) {
-	incOpCounter("hist_cum")
+func vipsGenHistCum(ctx context.Context, input *C.VipsImage) (*C.VipsImage, error) {
+	op := startOp(ctx, "hist_cum", input)
+	defer op.end()
 
 	var out_out *C.VipsImage
 
 	ret := C.gen_vips_hist_cum(input, &out_out)
 	if ret != 0 {
-		return nil, handleImageError(out_out)
+		return nil, op.fail(handleImageError(out_out))
 	}
 
 	return out_out, nil
@@ -1935,14 +2048,15 @@ func vipsGenHistCum(input *C.VipsImage) (*C.VipsImage, error) {
 
 // vipsGenHistEntropy calls the vips hist_entropy operation.
 // estimate image entropy
-func vipsGenHistEntropy(input *C.VipsImage) (float64, error) {
-	incOpCounter("hist_entropy")
+func vipsGenHistEntropy(ctx context.Context, input *C.VipsImage) (float64, error) {
+	op := startOp(ctx, "hist_entropy", input)
+	defer op.end()
 
 	var out_out C.double
 
 	ret := C.gen_vips_hist_entropy(input, &out_out)
 	if ret != 0 {
-		return 0, handleVipsError()
+		return 0, op.fail(handleVipsError())
 	}
 
 	return float64(out_out), nil
@@ -1955,8 +2069,9 @@ type HistEqualOptions struct {
 
 // vipsGenHistEqual calls the vips hist_equal operation.
 // histogram equalisation
-func vipsGenHistEqual(input *C.VipsImage, opts *HistEqualOptions) (*C.VipsImage, error) {
-	incOpCounter("hist_equal")
+func vipsGenHistEqual(ctx context.Context, input *C.VipsImage, opts *HistEqualOptions) (*C.VipsImage, error) {
+	op := startOp(ctx, "hist_equal", input)
+	defer op.end()
 
 	var out_out *C.VipsImage
 
@@ -1970,7 +2085,7 @@ func vipsGenHistEqual(input *C.VipsImage, opts *HistEqualOptions) (*C.VipsImage,
 
 	ret := C.gen_vips_hist_equal(input, &out_out, &cOpts)
 	if ret != 0 {
-		return nil, handleImageError(out_out)
+		return nil, op.fail(handleImageError(out_out))
 	}
 
 	return out_out, nil
@@ -1983,8 +2098,9 @@ type HistFindOptions struct {
 
 // vipsGenHistFind calls the vips hist_find operation.
 // find image histogram
-func vipsGenHistFind(input *C.VipsImage, opts *HistFindOptions) (*C.VipsImage, error) {
-	incOpCounter("hist_find")
+func vipsGenHistFind(ctx context.Context, input *C.VipsImage, opts *HistFindOptions) (*C.VipsImage, error) {
+	op := startOp(ctx, "hist_find", input)
+	defer op.end()
 
 	var out_out *C.VipsImage
 
@@ -1998,7 +2114,7 @@ func vipsGenHistFind(input *C.VipsImage, opts *HistFindOptions) (*C.VipsImage, e
 
 	ret := C.gen_vips_hist_find(input, &out_out, &cOpts)
 	if ret != 0 {
-		return nil, handleImageError(out_out)
+		return nil, op.fail(handleImageError(out_out))
 	}
 
 	return out_out, nil
@@ -2011,8 +2127,9 @@ type HistFindIndexedOptions struct {
 
 // vipsGenHistFindIndexed calls the vips hist_find_indexed operation.
 // find indexed image histogram
-func vipsGenHistFindIndexed(input *C.VipsImage, index *C.VipsImage, opts *HistFindIndexedOptions) (*C.VipsImage, error) {
-	incOpCounter("hist_find_indexed")
+func vipsGenHistFindIndexed(ctx context.Context, input *C.VipsImage, index *C.VipsImage, opts *HistFindIndexedOptions) (*C.VipsImage, error) {
+	op := startOp(ctx, "hist_find_indexed", input)
+	defer op.end()
 
 	var out_out *C.VipsImage
 
@@ -2026,7 +2143,7 @@ func vipsGenHistFindIndexed(input *C.VipsImage, index *C.VipsImage, opts *HistFi
 
 	ret := C.gen_vips_hist_find_indexed(input, index, &out_out, &cOpts)
 	if ret != 0 {
-		return nil, handleImageError(out_out)
+		return nil, op.fail(handleImageError(out_out))
 	}
 
 	return out_out, nil
@@ -2039,8 +2156,9 @@ type HistFindNdimOptions struct {
 
 // vipsGenHistFindNdim calls the vips hist_find_ndim operation.
 // find n-dimensional image histogram
-func vipsGenHistFindNdim(input *C.VipsImage, opts *HistFindNdimOptions) (*C.VipsImage, error) {
-	incOpCounter("hist_find_ndim")
+func vipsGenHistFindNdim(ctx context.Context, input *C.VipsImage, opts *HistFindNdimOptions) (*C.VipsImage, error) {
+	op := startOp(ctx, "hist_find_ndim", input)
+	defer op.end()
 
 	var out_out *C.VipsImage
 
@@ -2054,7 +2172,7 @@ func vipsGenHistFindNdim(input *C.VipsImage, opts *HistFindNdimOptions) (*C.Vips
 
 	ret := C.gen_vips_hist_find_ndim(input, &out_out, &cOpts)
 	if ret != 0 {
-		return nil, handleImageError(out_out)
+		return nil, op.fail(handleImageError(out_out))
 	}
 
 	return out_out, nil
@@ -2062,14 +2180,15 @@ func vipsGenHistFindNdim(input *C.VipsImage, opts *HistFindNdimOptions) (*C.Vips
 
 // vipsGenHistIsmonotonic calls the vips hist_ismonotonic operation.
 // test for monotonicity
-func vipsGenHistIsmonotonic(input *C.VipsImage) (bool, error) {
-	incOpCounter("hist_ismonotonic")
+func vipsGenHistIsmonotonic(ctx context.Context, input *C.VipsImage) (bool, error) {
+	op := startOp(ctx, "hist_ismonotonic", input)
+	defer op.end()
 
 	var out_monotonic C.int
 
 	ret := C.gen_vips_hist_ismonotonic(input, &out_monotonic)
 	if ret != 0 {
-		return false, handleVipsError()
+		return false, op.fail(handleVipsError())
 	}
 
 	return out_monotonic != 0, nil
@@ -2082,8 +2201,9 @@ type HistLocalOptions struct {
 
 // vipsGenHistLocal calls the vips hist_local operation.
 // local histogram equalisation
-func vipsGenHistLocal(input *C.VipsImage, width int, height int, opts *HistLocalOptions) (*C.VipsImage, error) {
-	incOpCounter("hist_local")
+func vipsGenHistLocal(ctx context.Context, input *C.VipsImage, width int, height int, opts *HistLocalOptions) (*C.VipsImage, error) {
+	op := startOp(ctx, "hist_local", input)
+	defer op.end()
 
 	var out_out *C.VipsImage
 
@@ -2097,7 +2217,7 @@ func vipsGenHistLocal(input *C.VipsImage, width int, height int, opts *HistLocal
 
 	ret := C.gen_vips_hist_local(input, C.int(width), C.int(height), &out_out, &cOpts)
 	if ret != 0 {
-		return nil, handleImageError(out_out)
+		return nil, op.fail(handleImageError(out_out))
 	}
 
 	return out_out, nil
@@ -2105,14 +2225,15 @@ func vipsGenHistLocal(input *C.VipsImage, width int, height int, opts *HistLocal
 
 // vipsGenHistMatch calls the vips hist_match operation.
 // match two histograms
-func vipsGenHistMatch(input *C.VipsImage, ref *C.VipsImage) (*C.VipsImage, error) {
-	incOpCounter("hist_match")
+func vipsGenHistMatch(ctx context.Context, input *C.VipsImage, ref *C.VipsImage) (*C.VipsImage, error) {
+	op := startOp(ctx, "hist_match", input)
+	defer op.end()
 
 	var out_out *C.VipsImage
 
 	ret := C.gen_vips_hist_match(input, ref, &out_out)
 	if ret != 0 {
-		return nil, handleImageError(out_out)
+		return nil, op.fail(handleImageError(out_out))
 	}
 
 	return out_out, nil
@@ -2120,14 +2241,15 @@ func vipsGenHistMatch(input *C.VipsImage, ref *C.VipsImage) (*C.VipsImage, error
 
 // vipsGenHistNorm calls the vips hist_norm operation.
 // normalise histogram
-func vipsGenHistNorm(input *C.VipsImage) (*C.VipsImage, error) {
-	incOpCounter("hist_norm")
+func vipsGenHistNorm(ctx context.Context, input *C.VipsImage) (*C.VipsImage, error) {
+	op := startOp(ctx, "hist_norm", input)
+	defer op.end()
 
 	var out_out *C.VipsImage
 
 	ret := C.gen_vips_hist_norm(input, &out_out)
 	if ret != 0 {
-		return nil, handleImageError(out_out)
+		return nil, op.fail(handleImageError(out_out))
 	}
 
 	return out_out, nil
@@ -2135,14 +2257,15 @@ func vipsGenHistNorm(input *C.VipsImage) (*C.VipsImage, error) {
 
 // vipsGenHistPlot calls the vips hist_plot operation.
 // plot histogram
-func vipsGenHistPlot(input *C.VipsImage) (*C.VipsImage, error) {
-	incOpCounter("hist_plot")
+func vipsGenHistPlot(ctx context.Context, input *C.VipsImage) (*C.VipsImage, error) {
+	op := startOp(ctx, "hist_plot", input)
+	defer op.end()
 
 	var out_out *C.VipsImage
 
 	ret := C.gen_vips_hist_plot(input, &out_out)
 	if ret != 0 {
-		return nil, handleImageError(out_out)
+		return nil, op.fail(handleImageError(out_out))
 	}
 
 	return out_out, nil
@@ -2157,8 +2280,9 @@ type HoughCircleOptions struct {
 
 // vipsGenHoughCircle calls the vips hough_circle operation.
 // find hough circle transform
-func vipsGenHoughCircle(input *C.VipsImage, opts *HoughCircleOptions) (*C.VipsImage, error) {
-	incOpCounter("hough_circle")
+func vipsGenHoughCircle(ctx context.Context, input *C.VipsImage, opts *HoughCircleOptions) (*C.VipsImage, error) {
+	op := startOp(ctx, "hough_circle", input)
+	defer op.end()
 
 	var out_out *C.VipsImage
 
@@ -2180,7 +2304,7 @@ func vipsGenHoughCircle(input *C.VipsImage, opts *HoughCircleOptions) (*C.VipsIm
 
 	ret := C.gen_vips_hough_circle(input, &out_out, &cOpts)
 	if ret != 0 {
-		return nil, handleImageError(out_out)
+		return nil, op.fail(handleImageError(out_out))
 	}
 
 	return out_out, nil
@@ -2194,8 +2318,9 @@ type HoughLineOptions struct {
 
 // vipsGenHoughLine calls the vips hough_line operation.
 // find hough line transform
-func vipsGenHoughLine(input *C.VipsImage, opts *HoughLineOptions) (*C.VipsImage, error) {
-	incOpCounter("hough_line")
+func vipsGenHoughLine(ctx context.Context, input *C.VipsImage, opts *HoughLineOptions) (*C.VipsImage, error) {
+	op := startOp(ctx, "hough_line", input)
+	defer op.end()
 
 	var out_out *C.VipsImage
 
@@ -2213,7 +2338,7 @@ func vipsGenHoughLine(input *C.VipsImage, opts *HoughLineOptions) (*C.VipsImage,
 
 	ret := C.gen_vips_hough_line(input, &out_out, &cOpts)
 	if ret != 0 {
-		return nil, handleImageError(out_out)
+		return nil, op.fail(handleImageError(out_out))
 	}
 
 	return out_out, nil
@@ -2230,8 +2355,9 @@ type IccExportOptions struct {
 
 // vipsGenIccExport calls the vips icc_export operation.
 // output to device with ICC profile
-func vipsGenIccExport(input *C.VipsImage, opts *IccExportOptions) (*C.VipsImage, error) {
-	incOpCounter("icc_export")
+func vipsGenIccExport(ctx context.Context, input *C.VipsImage, opts *IccExportOptions) (*C.VipsImage, error) {
+	op := startOp(ctx, "icc_export", input)
+	defer op.end()
 
 	var out_out *C.VipsImage
 
@@ -2263,7 +2389,7 @@ func vipsGenIccExport(input *C.VipsImage, opts *IccExportOptions) (*C.VipsImage,
 
 	ret := C.gen_vips_icc_export(input, &out_out, &cOpts)
 	if ret != 0 {
-		return nil, handleImageError(out_out)
+		return nil, op.fail(handleImageError(out_out))
 	}
 
 	return out_out, nil
@@ -2280,8 +2406,9 @@ type IccImportOptions struct {
 
 // vipsGenIccImport calls the vips icc_import operation.
 // import from device with ICC profile
-func vipsGenIccImport(input *C.VipsImage, opts *IccImportOptions) (*C.VipsImage, error) {
-	incOpCounter("icc_import")
+func vipsGenIccImport(ctx context.Context, input *C.VipsImage, opts *IccImportOptions) (*C.VipsImage, error) {
+	op := startOp(ctx, "icc_import", input)
+	defer op.end()
 
 	var out_out *C.VipsImage
 
@@ -2313,7 +2440,7 @@ func vipsGenIccImport(input *C.VipsImage, opts *IccImportOptions) (*C.VipsImage,
 
 	ret := C.gen_vips_icc_import(input, &out_out, &cOpts)
 	if ret != 0 {
-		return nil, handleImageError(out_out)
+		return nil, op.fail(handleImageError(out_out))
 	}
 
 	return out_out, nil
@@ -2328,8 +2455,9 @@ type IdentityOptions struct {
 
 // vipsGenIdentity calls the vips identity operation.
 // make a 1D image where pixel values are indexes
-func vipsGenIdentity(opts *IdentityOptions) (*C.VipsImage, error) {
-	incOpCounter("identity")
+func vipsGenIdentity(ctx context.Context, opts *IdentityOptions) (*C.VipsImage, error) {
+	op := startOp(ctx, "identity", nil)
+	defer op.end()
 
 	var out_out *C.VipsImage
 
@@ -2351,7 +2479,7 @@ func vipsGenIdentity(opts *IdentityOptions) (*C.VipsImage, error) {
 
 	ret := C.gen_vips_identity(&out_out, &cOpts)
 	if ret != 0 {
-		return nil, handleImageError(out_out)
+		return nil, op.fail(handleImageError(out_out))
 	}
 
 	return out_out, nil
@@ -2364,8 +2492,9 @@ type IfthenelseOptions struct {
 
 // vipsGenIfthenelse calls the vips ifthenelse operation.
 // ifthenelse an image
-func vipsGenIfthenelse(cond *C.VipsImage, in1 *C.VipsImage, in2 *C.VipsImage, opts *IfthenelseOptions) (*C.VipsImage, error) {
-	incOpCounter("ifthenelse")
+func vipsGenIfthenelse(ctx context.Context, cond *C.VipsImage, in1 *C.VipsImage, in2 *C.VipsImage, opts *IfthenelseOptions) (*C.VipsImage, error) {
+	op := startOp(ctx, "ifthenelse", cond)
+	defer op.end()
 
 	var out_out *C.VipsImage
 
@@ -2379,7 +2508,7 @@ func vipsGenIfthenelse(cond *C.VipsImage, in1 *C.VipsImage, in2 *C.VipsImage, op
 
 	ret := C.gen_vips_ifthenelse(cond, in1, in2, &out_out, &cOpts)
 	if ret != 0 {
-		return nil, handleImageError(out_out)
+		return nil, op.fail(handleImageError(out_out))
 	}
 
 	return out_out, nil
@@ -2393,8 +2522,9 @@ type InsertOptions struct {
 
 // vipsGenInsert calls the vips insert operation.
 // insert image @sub into @main at @x, @y
-func vipsGenInsert(main *C.VipsImage, sub *C.VipsImage, x int, y int, opts *InsertOptions) (*C.VipsImage, error) {
-	incOpCounter("insert")
+func vipsGenInsert(ctx context.Context, main *C.VipsImage, sub *C.VipsImage, x int, y int, opts *InsertOptions) (*C.VipsImage, error) {
+	op := startOp(ctx, "insert", main)
+	defer op.end()
 
 	var out_out *C.VipsImage
 
@@ -2416,7 +2546,7 @@ func vipsGenInsert(main *C.VipsImage, sub *C.VipsImage, x int, y int, opts *Inse
 
 	ret := C.gen_vips_insert(main, sub, C.int(x), C.int(y), &out_out, &cOpts)
 	if ret != 0 {
-		return nil, handleImageError(out_out)
+		return nil, op.fail(handleImageError(out_out))
 	}
 
 	return out_out, nil
@@ -2424,14 +2554,15 @@ func vipsGenInsert(main *C.VipsImage, sub *C.VipsImage, x int, y int, opts *Inse
 
 // vipsGenInvert calls the vips invert operation.
 // invert an image
-func vipsGenInvert(input *C.VipsImage) (*C.VipsImage, error) {
-	incOpCounter("invert")
+func vipsGenInvert(ctx context.Context, input *C.VipsImage) (*C.VipsImage, error) {
+	op := startOp(ctx, "invert", input)
+	defer op.end()
 
 	var out_out *C.VipsImage
 
 	ret := C.gen_vips_invert(input, &out_out)
 	if ret != 0 {
-		return nil, handleImageError(out_out)
+		return nil, op.fail(handleImageError(out_out))
 	}
 
 	return out_out, nil
@@ -2444,8 +2575,9 @@ type InvertlutOptions struct {
 
 // vipsGenInvertlut calls the vips invertlut operation.
 // build an inverted look-up table
-func vipsGenInvertlut(input *C.VipsImage, opts *InvertlutOptions) (*C.VipsImage, error) {
-	incOpCounter("invertlut")
+func vipsGenInvertlut(ctx context.Context, input *C.VipsImage, opts *InvertlutOptions) (*C.VipsImage, error) {
+	op := startOp(ctx, "invertlut", input)
+	defer op.end()
 
 	var out_out *C.VipsImage
 
@@ -2459,7 +2591,7 @@ func vipsGenInvertlut(input *C.VipsImage, opts *InvertlutOptions) (*C.VipsImage,
 
 	ret := C.gen_vips_invertlut(input, &out_out, &cOpts)
 	if ret != 0 {
-		return nil, handleImageError(out_out)
+		return nil, op.fail(handleImageError(out_out))
 	}
 
 	return out_out, nil
@@ -2472,8 +2604,9 @@ type InvfftOptions struct {
 
 // vipsGenInvfft calls the vips invfft operation.
 // inverse FFT
-func vipsGenInvfft(input *C.VipsImage, opts *InvfftOptions) (*C.VipsImage, error) {
-	incOpCounter("invfft")
+func vipsGenInvfft(ctx context.Context, input *C.VipsImage, opts *InvfftOptions) (*C.VipsImage, error) {
+	op := startOp(ctx, "invfft", input)
+	defer op.end()
 
 	var out_out *C.VipsImage
 
@@ -2487,7 +2620,7 @@ func vipsGenInvfft(input *C.VipsImage, opts *InvfftOptions) (*C.VipsImage, error
 
 	ret := C.gen_vips_invfft(input, &out_out, &cOpts)
 	if ret != 0 {
-		return nil, handleImageError(out_out)
+		return nil, op.fail(handleImageError(out_out))
 	}
 
 	return out_out, nil
@@ -2503,8 +2636,9 @@ type JoinOptions struct {
 
 // vipsGenJoin calls the vips join operation.
 // join a pair of images
-func vipsGenJoin(in1 *C.VipsImage, in2 *C.VipsImage, direction Direction, opts *JoinOptions) (*C.VipsImage, error) {
-	incOpCounter("join")
+func vipsGenJoin(ctx context.Context, in1 *C.VipsImage, in2 *C.VipsImage, direction Direction, opts *JoinOptions) (*C.VipsImage, error) {
+	op := startOp(ctx, "join", in1)
+	defer op.end()
 
 	var out_out *C.VipsImage
 
@@ -2534,7 +2668,7 @@ func vipsGenJoin(in1 *C.VipsImage, in2 *C.VipsImage, direction Direction, opts *
 
 	ret := C.gen_vips_join(in1, in2, C.VipsDirection(direction), &out_out, &cOpts)
 	if ret != 0 {
-		return nil, handleImageError(out_out)
+		return nil, op.fail(handleImageError(out_out))
 	}
 
 	return out_out, nil
@@ -2542,15 +2676,16 @@ func vipsGenJoin(in1 *C.VipsImage, in2 *C.VipsImage, direction Direction, opts *
 
 // vipsGenLabelregions calls the vips labelregions operation.
 // label regions in an image
-func vipsGenLabelregions(input *C.VipsImage) (*C.VipsImage, int, error) {
-	incOpCounter("labelregions")
+func vipsGenLabelregions(ctx context.Context, input *C.VipsImage) (*C.VipsImage, int, error) {
+	op := startOp(ctx, "labelregions", input)
+	defer op.end()
 
 	var out_mask *C.VipsImage
 	var out_segments C.int
 
 	ret := C.gen_vips_labelregions(input, &out_mask, &out_segments)
 	if ret != 0 {
-		return nil, 0, handleImageError(out_mask)
+		return nil, 0, op.fail(handleImageError(out_mask))
 	}
 
 	return out_mask, int(out_segments), nil
@@ -2563,8 +2698,9 @@ type LinearOptions struct {
 
 // vipsGenLinear calls the vips linear operation.
 // calculate (a * in + b)
-func vipsGenLinear(input *C.VipsImage, a []float64, b []float64, opts *LinearOptions) (*C.VipsImage, error) {
-	incOpCounter("linear")
+func vipsGenLinear(ctx context.Context, input *C.VipsImage, a []float64, b []float64, opts *LinearOptions) (*C.VipsImage, error) {
+	op := startOp(ctx, "linear", input)
+	defer op.end()
 
 	var out_out *C.VipsImage
 
@@ -2578,7 +2714,7 @@ func vipsGenLinear(input *C.VipsImage, a []float64, b []float64, opts *LinearOpt
 
 	ret := C.gen_vips_linear(input, (*C.double)(unsafe.Pointer(&a[0])), C.int(len(a)), (*C.double)(unsafe.Pointer(&b[0])), C.int(len(b)), &out_out, &cOpts)
 	if ret != 0 {
-		return nil, handleImageError(out_out)
+		return nil, op.fail(handleImageError(out_out))
 	}
 
 	return out_out, nil
@@ -2594,8 +2730,9 @@ type LinecacheOptions struct {
 
 // vipsGenLinecache calls the vips linecache operation.
 // cache an image as a set of lines
-func vipsGenLinecache(input *C.VipsImage, opts *LinecacheOptions) (*C.VipsImage, error) {
-	incOpCounter("linecache")
+func vipsGenLinecache(ctx context.Context, input *C.VipsImage, opts *LinecacheOptions) (*C.VipsImage, error) {
+	op := startOp(ctx, "linecache", input)
+	defer op.end()
 
 	var out_out *C.VipsImage
 
@@ -2621,7 +2758,7 @@ func vipsGenLinecache(input *C.VipsImage, opts *LinecacheOptions) (*C.VipsImage,
 
 	ret := C.gen_vips_linecache(input, &out_out, &cOpts)
 	if ret != 0 {
-		return nil, handleImageError(out_out)
+		return nil, op.fail(handleImageError(out_out))
 	}
 
 	return out_out, nil
@@ -2635,8 +2772,9 @@ type LogmatOptions struct {
 
 // vipsGenLogmat calls the vips logmat operation.
 // make a Laplacian of Gaussian image
-func vipsGenLogmat(sigma float64, minAmpl float64, opts *LogmatOptions) (*C.VipsImage, error) {
-	incOpCounter("logmat")
+func vipsGenLogmat(ctx context.Context, sigma float64, minAmpl float64, opts *LogmatOptions) (*C.VipsImage, error) {
+	op := startOp(ctx, "logmat", nil)
+	defer op.end()
 
 	var out_out *C.VipsImage
 
@@ -2654,7 +2792,7 @@ func vipsGenLogmat(sigma float64, minAmpl float64, opts *LogmatOptions) (*C.Vips
 
 	ret := C.gen_vips_logmat(C.double(sigma), C.double(minAmpl), &out_out, &cOpts)
 	if ret != 0 {
-		return nil, handleImageError(out_out)
+		return nil, op.fail(handleImageError(out_out))
 	}
 
 	return out_out, nil
@@ -2662,7 +2800,8 @@ func vipsGenLogmat(sigma float64, minAmpl float64, opts *LogmatOptions) (*C.Vips
 
 // MapimOptions are optional parameters for mapim.
 type MapimOptions struct {
-	Interpolate *C.VipsInterpolate
+	// Interpolate names an interpolator, such as "bicubic" or "nohalo".
+	Interpolate *string
 	Background []float64
 	Premultiplied *bool
 	Extend *ExtendStrategy
@@ -2670,8 +2809,9 @@ type MapimOptions struct {
 
 // vipsGenMapim calls the vips mapim operation.
 // resample with a map image
-func vipsGenMapim(input *C.VipsImage, index *C.VipsImage, opts *MapimOptions) (*C.VipsImage, error) {
-	incOpCounter("mapim")
+func vipsGenMapim(ctx context.Context, input *C.VipsImage, index *C.VipsImage, opts *MapimOptions) (*C.VipsImage, error) {
+	op := startOp(ctx, "mapim", input)
+	defer op.end()
 
 	var out_out *C.VipsImage
 
@@ -2681,7 +2821,12 @@ func vipsGenMapim(input *C.VipsImage, index *C.VipsImage, opts *MapimOptions) (*
 	if opts != nil {
 		if opts.Interpolate != nil {
 			cOpts.has_interpolate = 1
-			cOpts.interpolate = opts.Interpolate
+			interp_interpolate, err := NewInterpolate(*opts.Interpolate)
+			if err != nil {
+				return nil, op.fail(err)
+			}
+			defer C.g_object_unref(C.gpointer(unsafe.Pointer(interp_interpolate)))
+			cOpts.interpolate = interp_interpolate
 		}
 		if opts.Background != nil {
 			cOpts.has_background = 1
@@ -2701,7 +2846,7 @@ func vipsGenMapim(input *C.VipsImage, index *C.VipsImage, opts *MapimOptions) (*
 
 	ret := C.gen_vips_mapim(input, index, &out_out, &cOpts)
 	if ret != 0 {
-		return nil, handleImageError(out_out)
+		return nil, op.fail(handleImageError(out_out))
 	}
 
 	return out_out, nil
@@ -2714,8 +2859,9 @@ type MaplutOptions struct {
 
 // vipsGenMaplut calls the vips maplut operation.
 // map an image though a lut
-func vipsGenMaplut(input *C.VipsImage, lut *C.VipsImage, opts *MaplutOptions) (*C.VipsImage, error) {
-	incOpCounter("maplut")
+func vipsGenMaplut(ctx context.Context, input *C.VipsImage, lut *C.VipsImage, opts *MaplutOptions) (*C.VipsImage, error) {
+	op := startOp(ctx, "maplut", input)
+	defer op.end()
 
 	var out_out *C.VipsImage
 
@@ -2729,7 +2875,7 @@ func vipsGenMaplut(input *C.VipsImage, lut *C.VipsImage, opts *MaplutOptions) (*
 
 	ret := C.gen_vips_maplut(input, lut, &out_out, &cOpts)
 	if ret != 0 {
-		return nil, handleImageError(out_out)
+		return nil, op.fail(handleImageError(out_out))
 	}
 
 	return out_out, nil
@@ -2745,8 +2891,9 @@ type MaskButterworthOptions struct {
 
 // vipsGenMaskButterworth calls the vips mask_butterworth operation.
 // make a butterworth filter
-func vipsGenMaskButterworth(width int, height int, order float64, frequencyCutoff float64, amplitudeCutoff float64, opts *MaskButterworthOptions) (*C.VipsImage, error) {
-	incOpCounter("mask_butterworth")
+func vipsGenMaskButterworth(ctx context.Context, width int, height int, order float64, frequencyCutoff float64, amplitudeCutoff float64, opts *MaskButterworthOptions) (*C.VipsImage, error) {
+	op := startOp(ctx, "mask_butterworth", nil)
+	defer op.end()
 
 	var out_out *C.VipsImage
 
@@ -2772,7 +2919,7 @@ func vipsGenMaskButterworth(width int, height int, order float64, frequencyCutof
 
 	ret := C.gen_vips_mask_butterworth(C.int(width), C.int(height), C.double(order), C.double(frequencyCutoff), C.double(amplitudeCutoff), &out_out, &cOpts)
 	if ret != 0 {
-		return nil, handleImageError(out_out)
+		return nil, op.fail(handleImageError(out_out))
 	}
 
 	return out_out, nil
@@ -2788,8 +2935,9 @@ type MaskButterworthBandOptions struct {
 
 // vipsGenMaskButterworthBand calls the vips mask_butterworth_band operation.
 // make a butterworth_band filter
-func vipsGenMaskButterworthBand(width int, height int, order float64, frequencyCutoffX float64, frequencyCutoffY float64, radius float64, amplitudeCutoff float64, opts *MaskButterworthBandOptions) (*C.VipsImage, error) {
-	incOpCounter("mask_butterworth_band")
+func vipsGenMaskButterworthBand(ctx context.Context, width int, height int, order float64, frequencyCutoffX float64, frequencyCutoffY float64, radius float64, amplitudeCutoff float64, opts *MaskButterworthBandOptions) (*C.VipsImage, error) {
+	op := startOp(ctx, "mask_butterworth_band", nil)
+	defer op.end()
 
 	var out_out *C.VipsImage
 
@@ -2815,7 +2963,7 @@ func vipsGenMaskButterworthBand(width int, height int, order float64, frequencyC
 
 	ret := C.gen_vips_mask_butterworth_band(C.int(width), C.int(height), C.double(order), C.double(frequencyCutoffX), C.double(frequencyCutoffY), C.double(radius), C.double(amplitudeCutoff), &out_out, &cOpts)
 	if ret != 0 {
-		return nil, handleImageError(out_out)
+		return nil, op.fail(handleImageError(out_out))
 	}
 
 	return out_out, nil
@@ -2831,8 +2979,9 @@ type MaskButterworthRingOptions struct {
 
 // vipsGenMaskButterworthRing calls the vips mask_butterworth_ring operation.
 // make a butterworth ring filter
-func vipsGenMaskButterworthRing(width int, height int, order float64, frequencyCutoff float64, amplitudeCutoff float64, ringwidth float64, opts *MaskButterworthRingOptions) (*C.VipsImage, error) {
-	incOpCounter("mask_butterworth_ring")
+func vipsGenMaskButterworthRing(ctx context.Context, width int, height int, order float64, frequencyCutoff float64, amplitudeCutoff float64, ringwidth float64, opts *MaskButterworthRingOptions) (*C.VipsImage, error) {
+	op := startOp(ctx, "mask_butterworth_ring", nil)
+	defer op.end()
 
 	var out_out *C.VipsImage
 
@@ -2858,7 +3007,7 @@ func vipsGenMaskButterworthRing(width int, height int, order float64, frequencyC
 
 	ret := C.gen_vips_mask_butterworth_ring(C.int(width), C.int(height), C.double(order), C.double(frequencyCutoff), C.double(amplitudeCutoff), C.double(ringwidth), &out_out, &cOpts)
 	if ret != 0 {
-		return nil, handleImageError(out_out)
+		return nil, op.fail(handleImageError(out_out))
 	}
 
 	return out_out, nil
@@ -2874,8 +3023,9 @@ type MaskFractalOptions struct {
 
 // vipsGenMaskFractal calls the vips mask_fractal operation.
 // make fractal filter
-func vipsGenMaskFractal(width int, height int, fractalDimension float64, opts *MaskFractalOptions) (*C.VipsImage, error) {
-	incOpCounter("mask_fractal")
+func vipsGenMaskFractal(ctx context.Context, width int, height int, fractalDimension float64, opts *MaskFractalOptions) (*C.VipsImage, error) {
+	op := startOp(ctx, "mask_fractal", nil)
+	defer op.end()
 
 	var out_out *C.VipsImage
 
@@ -2901,7 +3051,7 @@ func vipsGenMaskFractal(width int, height int, fractalDimension float64, opts *M
 
 	ret := C.gen_vips_mask_fractal(C.int(width), C.int(height), C.double(fractalDimension), &out_out, &cOpts)
 	if ret != 0 {
-		return nil, handleImageError(out_out)
+		return nil, op.fail(handleImageError(out_out))
 	}
 
 	return out_out, nil
@@ -2917,8 +3067,9 @@ type MaskGaussianOptions struct {
 
 // vipsGenMaskGaussian calls the vips mask_gaussian operation.
 // make a gaussian filter
-func vipsGenMaskGaussian(width int, height int, frequencyCutoff float64, amplitudeCutoff float64, opts *MaskGaussianOptions) (*C.VipsImage, error) {
-	incOpCounter("mask_gaussian")
+func vipsGenMaskGaussian(ctx context.Context, width int, height int, frequencyCutoff float64, amplitudeCutoff float64, opts *MaskGaussianOptions) (*C.VipsImage, error) {
+	op := startOp(ctx, "mask_gaussian", nil)
+	defer op.end()
 
 	var out_out *C.VipsImage
 
@@ -2944,7 +3095,7 @@ func vipsGenMaskGaussian(width int, height int, frequencyCutoff float64, amplitu
 
 	ret := C.gen_vips_mask_gaussian(C.int(width), C.int(height), C.double(frequencyCutoff), C.double(amplitudeCutoff), &out_out, &cOpts)
 	if ret != 0 {
-		return nil, handleImageError(out_out)
+		return nil, op.fail(handleImageError(out_out))
 	}
 
 	return out_out, nil
@@ -2960,8 +3111,9 @@ type MaskGaussianBandOptions struct {
 
 // vipsGenMaskGaussianBand calls the vips mask_gaussian_band operation.
 // make a gaussian filter
-func vipsGenMaskGaussianBand(width int, height int, frequencyCutoffX float64, frequencyCutoffY float64, radius float64, amplitudeCutoff float64, opts *MaskGaussianBandOptions) (*C.VipsImage, error) {
-	incOpCounter("mask_gaussian_band")
+func vipsGenMaskGaussianBand(ctx context.Context, width int, height int, frequencyCutoffX float64, frequencyCutoffY float64, radius float64, amplitudeCutoff float64, opts *MaskGaussianBandOptions) (*C.VipsImage, error) {
+	op := startOp(ctx, "mask_gaussian_band", nil)
+	defer op.end()
 
 	var out_out *C.VipsImage
 
@@ -2987,7 +3139,7 @@ func vipsGenMaskGaussianBand(width int, height int, frequencyCutoffX float64, fr
 
 	ret := C.gen_vips_mask_gaussian_band(C.int(width), C.int(height), C.double(frequencyCutoffX), C.double(frequencyCutoffY), C.double(radius), C.double(amplitudeCutoff), &out_out, &cOpts)
 	if ret != 0 {
-		return nil, handleImageError(out_out)
+		return nil, op.fail(handleImageError(out_out))
 	}
 
 	return out_out, nil
@@ -3003,8 +3155,9 @@ type MaskGaussianRingOptions struct {
 
 // vipsGenMaskGaussianRing calls the vips mask_gaussian_ring operation.
 // make a gaussian ring filter
-func vipsGenMaskGaussianRing(width int, height int, frequencyCutoff float64, amplitudeCutoff float64, ringwidth float64, opts *MaskGaussianRingOptions) (*C.VipsImage, error) {
-	incOpCounter("mask_gaussian_ring")
+func vipsGenMaskGaussianRing(ctx context.Context, width int, height int, frequencyCutoff float64, amplitudeCutoff float64, ringwidth float64, opts *MaskGaussianRingOptions) (*C.VipsImage, error) {
+	op := startOp(ctx, "mask_gaussian_ring", nil)
+	defer op.end()
 
 	var out_out *C.VipsImage
 
@@ -3030,7 +3183,7 @@ func vipsGenMaskGaussianRing(width int, height int, frequencyCutoff float64, amp
 
 	ret := C.gen_vips_mask_gaussian_ring(C.int(width), C.int(height), C.double(frequencyCutoff), C.double(amplitudeCutoff), C.double(ringwidth), &out_out, &cOpts)
 	if ret != 0 {
-		return nil, handleImageError(out_out)
+		return nil, op.fail(handleImageError(out_out))
 	}
 
 	return out_out, nil
@@ -3046,8 +3199,9 @@ type MaskIdealOptions struct {
 
 // vipsGenMaskIdeal calls the vips mask_ideal operation.
 // make an ideal filter
-func vipsGenMaskIdeal(width int, height int, frequencyCutoff float64, opts *MaskIdealOptions) (*C.VipsImage, error) {
-	incOpCounter("mask_ideal")
+func vipsGenMaskIdeal(ctx context.Context, width int, height int, frequencyCutoff float64, opts *MaskIdealOptions) (*C.VipsImage, error) {
+	op := startOp(ctx, "mask_ideal", nil)
+	defer op.end()
 
 	var out_out *C.VipsImage
 
@@ -3073,7 +3227,7 @@ func vipsGenMaskIdeal(width int, height int, frequencyCutoff float64, opts *Mask
 
 	ret := C.gen_vips_mask_ideal(C.int(width), C.int(height), C.double(frequencyCutoff), &out_out, &cOpts)
 	if ret != 0 {
-		return nil, handleImageError(out_out)
+		return nil, op.fail(handleImageError(out_out))
 	}
 
 	return out_out, nil
@@ -3089,8 +3243,9 @@ type MaskIdealBandOptions struct {
 
 // vipsGenMaskIdealBand calls the vips mask_ideal_band operation.
 // make an ideal band filter
-func vipsGenMaskIdealBand(width int, height int, frequencyCutoffX float64, frequencyCutoffY float64, radius float64, opts *MaskIdealBandOptions) (*C.VipsImage, error) {
-	incOpCounter("mask_ideal_band")
+func vipsGenMaskIdealBand(ctx context.Context, width int, height int, frequencyCutoffX float64, frequencyCutoffY float64, radius float64, opts *MaskIdealBandOptions) (*C.VipsImage, error) {
+	op := startOp(ctx, "mask_ideal_band", nil)
+	defer op.end()
 
 	var out_out *C.VipsImage
 
@@ -3116,7 +3271,7 @@ func vipsGenMaskIdealBand(width int, height int, frequencyCutoffX float64, frequ
 
 	ret := C.gen_vips_mask_ideal_band(C.int(width), C.int(height), C.double(frequencyCutoffX), C.double(frequencyCutoffY), C.double(radius), &out_out, &cOpts)
 	if ret != 0 {
-		return nil, handleImageError(out_out)
+		return nil, op.fail(handleImageError(out_out))
 	}
 
 	return out_out, nil
@@ -3132,8 +3287,9 @@ type MaskIdealRingOptions struct {
 
 // vipsGenMaskIdealRing calls the vips mask_ideal_ring operation.
 // make an ideal ring filter
-func vipsGenMaskIdealRing(width int, height int, frequencyCutoff float64, ringwidth float64, opts *MaskIdealRingOptions) (*C.VipsImage, error) {
-	incOpCounter("mask_ideal_ring")
+func vipsGenMaskIdealRing(ctx context.Context, width int, height int, frequencyCutoff float64, ringwidth float64, opts *MaskIdealRingOptions) (*C.VipsImage, error) {
+	op := startOp(ctx, "mask_ideal_ring", nil)
+	defer op.end()
 
 	var out_out *C.VipsImage
 
@@ -3159,7 +3315,7 @@ func vipsGenMaskIdealRing(width int, height int, frequencyCutoff float64, ringwi
 
 	ret := C.gen_vips_mask_ideal_ring(C.int(width), C.int(height), C.double(frequencyCutoff), C.double(ringwidth), &out_out, &cOpts)
 	if ret != 0 {
-		return nil, handleImageError(out_out)
+		return nil, op.fail(handleImageError(out_out))
 	}
 
 	return out_out, nil
@@ -3170,13 +3326,15 @@ type MatchOptions struct {
 	Hwindow *int
 	Harea *int
 	Search *bool
-	Interpolate *C.VipsInterpolate
+	// Interpolate names an interpolator, such as "bicubic" or "nohalo".
+	Interpolate *string
 }
 
 // vipsGenMatch calls the vips match operation.
 // first-order match of two images
-func vipsGenMatch(ref *C.VipsImage, sec *C.VipsImage, xr1 int, yr1 int, xs1 int, ys1 int, xr2 int, yr2 int, xs2 int, ys2 int, opts *MatchOptions) (*C.VipsImage, error) {
-	incOpCounter("match")
+func vipsGenMatch(ctx context.Context, ref *C.VipsImage, sec *C.VipsImage, xr1 int, yr1 int, xs1 int, ys1 int, xr2 int, yr2 int, xs2 int, ys2 int, opts *MatchOptions) (*C.VipsImage, error) {
+	op := startOp(ctx, "match", ref)
+	defer op.end()
 
 	var out_out *C.VipsImage
 
@@ -3196,13 +3354,18 @@ func vipsGenMatch(ref *C.VipsImage, sec *C.VipsImage, xr1 int, yr1 int, xs1 int,
 		}
 		if opts.Interpolate != nil {
 			cOpts.has_interpolate = 1
-			cOpts.interpolate = opts.Interpolate
+			interp_interpolate, err := NewInterpolate(*opts.Interpolate)
+			if err != nil {
+				return nil, op.fail(err)
+			}
+			defer C.g_object_unref(C.gpointer(unsafe.Pointer(interp_interpolate)))
+			cOpts.interpolate = interp_interpolate
 		}
 	}
 
 	ret := C.gen_vips_match(ref, sec, C.int(xr1), C.int(yr1), C.int(xs1), C.int(ys1), C.int(xr2), C.int(yr2), C.int(xs2), C.int(ys2), &out_out, &cOpts)
 	if ret != 0 {
-		return nil, handleImageError(out_out)
+		return nil, op.fail(handleImageError(out_out))
 	}
 
 	return out_out, nil
@@ -3210,14 +3373,15 @@ func vipsGenMatch(ref *C.VipsImage, sec *C.VipsImage, xr1 int, yr1 int, xs1 int,
 
 // vipsGenMath calls the vips math operation.
 // apply a math operation to an image
-func vipsGenMath(input *C.VipsImage, math OperationMath) (*C.VipsImage, error) {
-	incOpCounter("math")
+func vipsGenMath(ctx context.Context, input *C.VipsImage, math OperationMath) (*C.VipsImage, error) {
+	op := startOp(ctx, "math", input)
+	defer op.end()
 
 	var out_out *C.VipsImage
 
 	ret := C.gen_vips_math(input, C.VipsOperationMath(math), &out_out)
 	if ret != 0 {
-		return nil, handleImageError(out_out)
+		return nil, op.fail(handleImageError(out_out))
 	}
 
 	return out_out, nil
@@ -3225,14 +3389,15 @@ func vipsGenMath(input *C.VipsImage, math OperationMath) (*C.VipsImage, error) {
 
 // vipsGenMath2 calls the vips math2 operation.
 // binary math operations
-func vipsGenMath2(left *C.VipsImage, right *C.VipsImage, math2 OperationMath2) (*C.VipsImage, error) {
-	incOpCounter("math2")
+func vipsGenMath2(ctx context.Context, left *C.VipsImage, right *C.VipsImage, math2 OperationMath2) (*C.VipsImage, error) {
+	op := startOp(ctx, "math2", left)
+	defer op.end()
 
 	var out_out *C.VipsImage
 
 	ret := C.gen_vips_math2(left, right, C.VipsOperationMath2(math2), &out_out)
 	if ret != 0 {
-		return nil, handleImageError(out_out)
+		return nil, op.fail(handleImageError(out_out))
 	}
 
 	return out_out, nil
@@ -3240,14 +3405,15 @@ func vipsGenMath2(left *C.VipsImage, right *C.VipsImage, math2 OperationMath2) (
 
 // vipsGenMath2Const calls the vips math2_const operation.
 // binary math operations with a constant
-func vipsGenMath2Const(input *C.VipsImage, math2 OperationMath2, c []float64) (*C.VipsImage, error) {
-	incOpCounter("math2_const")
+func vipsGenMath2Const(ctx context.Context, input *C.VipsImage, math2 OperationMath2, c []float64) (*C.VipsImage, error) {
+	op := startOp(ctx, "math2_const", input)
+	defer op.end()
 
 	var out_out *C.VipsImage
 
 	ret := C.gen_vips_math2_const(input, C.VipsOperationMath2(math2), (*C.double)(unsafe.Pointer(&c[0])), C.int(len(c)), &out_out)
 	if ret != 0 {
-		return nil, handleImageError(out_out)
+		return nil, op.fail(handleImageError(out_out))
 	}
 
 	return out_out, nil
@@ -3255,14 +3421,15 @@ func vipsGenMath2Const(input *C.VipsImage, math2 OperationMath2, c []float64) (*
 
 // vipsGenMatrixinvert calls the vips matrixinvert operation.
 // invert a matrix
-func vipsGenMatrixinvert(input *C.VipsImage) (*C.VipsImage, error) {
-	incOpCounter("matrixinvert")
+func vipsGenMatrixinvert(ctx context.Context, input *C.VipsImage) (*C.VipsImage, error) {
+	op := startOp(ctx, "matrixinvert", input)
+	defer op.end()
 
 	var out_out *C.VipsImage
 
 	ret := C.gen_vips_matrixinvert(input, &out_out)
 	if ret != 0 {
-		return nil, handleImageError(out_out)
+		return nil, op.fail(handleImageError(out_out))
 	}
 
 	return out_out, nil
@@ -3270,14 +3437,15 @@ func vipsGenMatrixinvert(input *C.VipsImage) (*C.VipsImage, error) {
 
 // vipsGenMatrixmultiply calls the vips matrixmultiply operation.
 // multiply two matrices
-func vipsGenMatrixmultiply(left *C.VipsImage, right *C.VipsImage) (*C.VipsImage, error) {
-	incOpCounter("matrixmultiply")
+func vipsGenMatrixmultiply(ctx context.Context, left *C.VipsImage, right *C.VipsImage) (*C.VipsImage, error) {
+	op := startOp(ctx, "matrixmultiply", left)
+	defer op.end()
 
 	var out_out *C.VipsImage
 
 	ret := C.gen_vips_matrixmultiply(left, right, &out_out)
 	if ret != 0 {
-		return nil, handleImageError(out_out)
+		return nil, op.fail(handleImageError(out_out))
 	}
 
 	return out_out, nil
@@ -3285,14 +3453,15 @@ func vipsGenMatrixmultiply(left *C.VipsImage, right *C.VipsImage) (*C.VipsImage,
 
 // vipsGenMaxpair calls the vips maxpair operation.
 // maximum of a pair of images
-func vipsGenMaxpair(left *C.VipsImage, right *C.VipsImage) (*C.VipsImage, error) {
-	incOpCounter("maxpair")
+func vipsGenMaxpair(ctx context.Context, left *C.VipsImage, right *C.VipsImage) (*C.VipsImage, error) {
+	op := startOp(ctx, "maxpair", left)
+	defer op.end()
 
 	var out_out *C.VipsImage
 
 	ret := C.gen_vips_maxpair(left, right, &out_out)
 	if ret != 0 {
-		return nil, handleImageError(out_out)
+		return nil, op.fail(handleImageError(out_out))
 	}
 
 	return out_out, nil
@@ -3305,8 +3474,9 @@ type MergeOptions struct {
 
 // vipsGenMerge calls the vips merge operation.
 // merge two images
-func vipsGenMerge(ref *C.VipsImage, sec *C.VipsImage, direction Direction, dx int, dy int, opts *MergeOptions) (*C.VipsImage, error) {
-	incOpCounter("merge")
+func vipsGenMerge(ctx context.Context, ref *C.VipsImage, sec *C.VipsImage, direction Direction, dx int, dy int, opts *MergeOptions) (*C.VipsImage, error) {
+	op := startOp(ctx, "merge", ref)
+	defer op.end()
 
 	var out_out *C.VipsImage
 
@@ -3320,7 +3490,7 @@ func vipsGenMerge(ref *C.VipsImage, sec *C.VipsImage, direction Direction, dx in
 
 	ret := C.gen_vips_merge(ref, sec, C.VipsDirection(direction), C.int(dx), C.int(dy), &out_out, &cOpts)
 	if ret != 0 {
-		return nil, handleImageError(out_out)
+		return nil, op.fail(handleImageError(out_out))
 	}
 
 	return out_out, nil
@@ -3328,14 +3498,15 @@ func vipsGenMerge(ref *C.VipsImage, sec *C.VipsImage, direction Direction, dx in
 
 // vipsGenMinpair calls the vips minpair operation.
 // minimum of a pair of images
-func vipsGenMinpair(left *C.VipsImage, right *C.VipsImage) (*C.VipsImage, error) {
-	incOpCounter("minpair")
+func vipsGenMinpair(ctx context.Context, left *C.VipsImage, right *C.VipsImage) (*C.VipsImage, error) {
+	op := startOp(ctx, "minpair", left)
+	defer op.end()
 
 	var out_out *C.VipsImage
 
 	ret := C.gen_vips_minpair(left, right, &out_out)
 	if ret != 0 {
-		return nil, handleImageError(out_out)
+		return nil, op.fail(handleImageError(out_out))
 	}
 
 	return out_out, nil
@@ -3343,14 +3514,15 @@ func vipsGenMinpair(left *C.VipsImage, right *C.VipsImage) (*C.VipsImage, error)
 
 // vipsGenMorph calls the vips morph operation.
 // morphology operation
-func vipsGenMorph(input *C.VipsImage, mask *C.VipsImage, morph OperationMorphology) (*C.VipsImage, error) {
-	incOpCounter("morph")
+func vipsGenMorph(ctx context.Context, input *C.VipsImage, mask *C.VipsImage, morph OperationMorphology) (*C.VipsImage, error) {
+	op := startOp(ctx, "morph", input)
+	defer op.end()
 
 	var out_out *C.VipsImage
 
 	ret := C.gen_vips_morph(input, mask, C.VipsOperationMorphology(morph), &out_out)
 	if ret != 0 {
-		return nil, handleImageError(out_out)
+		return nil, op.fail(handleImageError(out_out))
 	}
 
 	return out_out, nil
@@ -3366,8 +3538,9 @@ type MosaicOptions struct {
 
 // vipsGenMosaic calls the vips mosaic operation.
 // mosaic two images
-func vipsGenMosaic(ref *C.VipsImage, sec *C.VipsImage, direction Direction, xref int, yref int, xsec int, ysec int, opts *MosaicOptions) (*C.VipsImage, int, int, float64, float64, float64, float64, error) {
-	incOpCounter("mosaic")
+func vipsGenMosaic(ctx context.Context, ref *C.VipsImage, sec *C.VipsImage, direction Direction, xref int, yref int, xsec int, ysec int, opts *MosaicOptions) (*C.VipsImage, int, int, float64, float64, float64, float64, error) {
+	op := startOp(ctx, "mosaic", ref)
+	defer op.end()
 
 	var out_out *C.VipsImage
 	var out_dx0 C.int
@@ -3399,7 +3572,7 @@ func vipsGenMosaic(ref *C.VipsImage, sec *C.VipsImage, direction Direction, xref
 
 	ret := C.gen_vips_mosaic(ref, sec, C.VipsDirection(direction), C.int(xref), C.int(yref), C.int(xsec), C.int(ysec), &out_out, &out_dx0, &out_dy0, &out_scale1, &out_angle1, &out_dy1, &out_dx1, &cOpts)
 	if ret != 0 {
-		return nil, 0, 0, 0, 0, 0, 0, handleImageError(out_out)
+		return nil, 0, 0, 0, 0, 0, 0, op.fail(handleImageError(out_out))
 	}
 
 	return out_out, int(out_dx0), int(out_dy0), float64(out_scale1), float64(out_angle1), float64(out_dy1), float64(out_dx1), nil
@@ -3410,14 +3583,16 @@ type Mosaic1Options struct {
 	Hwindow *int
 	Harea *int
 	Search *bool
-	Interpolate *C.VipsInterpolate
+	// Interpolate names an interpolator, such as "bicubic" or "nohalo".
+	Interpolate *string
 	Mblend *int
 }
 
 // vipsGenMosaic1 calls the vips mosaic1 operation.
 // first-order mosaic of two images
-func vipsGenMosaic1(ref *C.VipsImage, sec *C.VipsImage, direction Direction, xr1 int, yr1 int, xs1 int, ys1 int, xr2 int, yr2 int, xs2 int, ys2 int, opts *Mosaic1Options) (*C.VipsImage, error) {
-	incOpCounter("mosaic1")
+func vipsGenMosaic1(ctx context.Context, ref *C.VipsImage, sec *C.VipsImage, direction Direction, xr1 int, yr1 int, xs1 int, ys1 int, xr2 int, yr2 int, xs2 int, ys2 int, opts *Mosaic1Options) (*C.VipsImage, error) {
+	op := startOp(ctx, "mosaic1", ref)
+	defer op.end()
 
 	var out_out *C.VipsImage
 
@@ -3437,7 +3612,12 @@ func vipsGenMosaic1(ref *C.VipsImage, sec *C.VipsImage, direction Direction, xr1
 		}
 		if opts.Interpolate != nil {
 			cOpts.has_interpolate = 1
-			cOpts.interpolate = opts.Interpolate
+			interp_interpolate, err := NewInterpolate(*opts.Interpolate)
+			if err != nil {
+				return nil, op.fail(err)
+			}
+			defer C.g_object_unref(C.gpointer(unsafe.Pointer(interp_interpolate)))
+			cOpts.interpolate = interp_interpolate
 		}
 		if opts.Mblend != nil {
 			cOpts.has_mblend = 1
@@ -3447,7 +3627,7 @@ func vipsGenMosaic1(ref *C.VipsImage, sec *C.VipsImage, direction Direction, xr1
 
 	ret := C.gen_vips_mosaic1(ref, sec, C.VipsDirection(direction), C.int(xr1), C.int(yr1), C.int(xs1), C.int(ys1), C.int(xr2), C.int(yr2), C.int(xs2), C.int(ys2), &out_out, &cOpts)
 	if ret != 0 {
-		return nil, handleImageError(out_out)
+		return nil, op.fail(handleImageError(out_out))
 	}
 
 	return out_out, nil
@@ -3460,8 +3640,9 @@ type MsbOptions struct {
 
 // vipsGenMsb calls the vips msb operation.
 // pick most-significant byte from an image
-func vipsGenMsb(input *C.VipsImage, opts *MsbOptions) (*C.VipsImage, error) {
-	incOpCounter("msb")
+func vipsGenMsb(ctx context.Context, input *C.VipsImage, opts *MsbOptions) (*C.VipsImage, error) {
+	op := startOp(ctx, "msb", input)
+	defer op.end()
 
 	var out_out *C.VipsImage
 
@@ -3475,7 +3656,7 @@ func vipsGenMsb(input *C.VipsImage, opts *MsbOptions) (*C.VipsImage, error) {
 
 	ret := C.gen_vips_msb(input, &out_out, &cOpts)
 	if ret != 0 {
-		return nil, handleImageError(out_out)
+		return nil, op.fail(handleImageError(out_out))
 	}
 
 	return out_out, nil
@@ -3483,14 +3664,15 @@ func vipsGenMsb(input *C.VipsImage, opts *MsbOptions) (*C.VipsImage, error) {
 
 // vipsGenMultiply calls the vips multiply operation.
 // multiply two images
-func vipsGenMultiply(left *C.VipsImage, right *C.VipsImage) (*C.VipsImage, error) {
-	incOpCounter("multiply")
+func vipsGenMultiply(ctx context.Context, left *C.VipsImage, right *C.VipsImage) (*C.VipsImage, error) {
+	op := startOp(ctx, "multiply", left)
+	defer op.end()
 
 	var out_out *C.VipsImage
 
 	ret := C.gen_vips_multiply(left, right, &out_out)
 	if ret != 0 {
-		return nil, handleImageError(out_out)
+		return nil, op.fail(handleImageError(out_out))
 	}
 
 	return out_out, nil
@@ -3498,14 +3680,15 @@ func vipsGenMultiply(left *C.VipsImage, right *C.VipsImage) (*C.VipsImage, error
 
 // vipsGenPercent calls the vips percent operation.
 // find threshold for percent of pixels
-func vipsGenPercent(input *C.VipsImage, percent float64) (int, error) {
-	incOpCounter("percent")
+func vipsGenPercent(ctx context.Context, input *C.VipsImage, percent float64) (int, error) {
+	op := startOp(ctx, "percent", input)
+	defer op.end()
 
 	var out_threshold C.int
 
 	ret := C.gen_vips_percent(input, C.double(percent), &out_threshold)
 	if ret != 0 {
-		return 0, handleVipsError()
+		return 0, op.fail(handleVipsError())
 	}
 
 	return int(out_threshold), nil
@@ -3520,8 +3703,9 @@ type PerlinOptions struct {
 
 // vipsGenPerlin calls the vips perlin operation.
 // make a perlin noise image
-func vipsGenPerlin(width int, height int, opts *PerlinOptions) (*C.VipsImage, error) {
-	incOpCounter("perlin")
+func vipsGenPerlin(ctx context.Context, width int, height int, opts *PerlinOptions) (*C.VipsImage, error) {
+	op := startOp(ctx, "perlin", nil)
+	defer op.end()
 
 	var out_out *C.VipsImage
 
@@ -3543,7 +3727,7 @@ func vipsGenPerlin(width int, height int, opts *PerlinOptions) (*C.VipsImage, er
 
 	ret := C.gen_vips_perlin(C.int(width), C.int(height), &out_out, &cOpts)
 	if ret != 0 {
-		return nil, handleImageError(out_out)
+		return nil, op.fail(handleImageError(out_out))
 	}
 
 	return out_out, nil
@@ -3551,14 +3735,15 @@ func vipsGenPerlin(width int, height int, opts *PerlinOptions) (*C.VipsImage, er
 
 // vipsGenPhasecor calls the vips phasecor operation.
 // calculate phase correlation
-func vipsGenPhasecor(input *C.VipsImage, in2 *C.VipsImage) (*C.VipsImage, error) {
-	incOpCounter("phasecor")
+func vipsGenPhasecor(ctx context.Context, input *C.VipsImage, in2 *C.VipsImage) (*C.VipsImage, error) {
+	op := startOp(ctx, "phasecor", input)
+	defer op.end()
 
 	var out_out *C.VipsImage
 
 	ret := C.gen_vips_phasecor(input, in2, &out_out)
 	if ret != 0 {
-		return nil, handleImageError(out_out)
+		return nil, op.fail(handleImageError(out_out))
 	}
 
 	return out_out, nil
@@ -3571,8 +3756,9 @@ type PremultiplyOptions struct {
 
 // vipsGenPremultiply calls the vips premultiply operation.
 // premultiply image alpha
-func vipsGenPremultiply(input *C.VipsImage, opts *PremultiplyOptions) (*C.VipsImage, error) {
-	incOpCounter("premultiply")
+func vipsGenPremultiply(ctx context.Context, input *C.VipsImage, opts *PremultiplyOptions) (*C.VipsImage, error) {
+	op := startOp(ctx, "premultiply", input)
+	defer op.end()
 
 	var out_out *C.VipsImage
 
@@ -3586,7 +3772,7 @@ func vipsGenPremultiply(input *C.VipsImage, opts *PremultiplyOptions) (*C.VipsIm
 
 	ret := C.gen_vips_premultiply(input, &out_out, &cOpts)
 	if ret != 0 {
-		return nil, handleImageError(out_out)
+		return nil, op.fail(handleImageError(out_out))
 	}
 
 	return out_out, nil
@@ -3594,14 +3780,15 @@ func vipsGenPremultiply(input *C.VipsImage, opts *PremultiplyOptions) (*C.VipsIm
 
 // vipsGenPrewitt calls the vips prewitt operation.
 // Prewitt edge detector
-func vipsGenPrewitt(input *C.VipsImage) (*C.VipsImage, error) {
-	incOpCounter("prewitt")
+func vipsGenPrewitt(ctx context.Context, input *C.VipsImage) (*C.VipsImage, error) {
+	op := startOp(ctx, "prewitt", input)
+	defer op.end()
 
 	var out_out *C.VipsImage
 
 	ret := C.gen_vips_prewitt(input, &out_out)
 	if ret != 0 {
-		return nil, handleImageError(out_out)
+		return nil, op.fail(handleImageError(out_out))
 	}
 
 	return out_out, nil
@@ -3609,15 +3796,16 @@ func vipsGenPrewitt(input *C.VipsImage) (*C.VipsImage, error) {
 
 // vipsGenProfile calls the vips profile operation.
 // find image profiles
-func vipsGenProfile(input *C.VipsImage) (*C.VipsImage, *C.VipsImage, error) {
-	incOpCounter("profile")
+func vipsGenProfile(ctx context.Context, input *C.VipsImage) (*C.VipsImage, *C.VipsImage, error) {
+	op := startOp(ctx, "profile", input)
+	defer op.end()
 
 	var out_columns *C.VipsImage
 	var out_rows *C.VipsImage
 
 	ret := C.gen_vips_profile(input, &out_columns, &out_rows)
 	if ret != 0 {
-		return nil, nil, handleImageError(out_columns)
+		return nil, nil, op.fail(handleImageError(out_columns))
 	}
 
 	return out_columns, out_rows, nil
@@ -3625,15 +3813,16 @@ func vipsGenProfile(input *C.VipsImage) (*C.VipsImage, *C.VipsImage, error) {
 
 // vipsGenProject calls the vips project operation.
 // find image projections
-func vipsGenProject(input *C.VipsImage) (*C.VipsImage, *C.VipsImage, error) {
-	incOpCounter("project")
+func vipsGenProject(ctx context.Context, input *C.VipsImage) (*C.VipsImage, *C.VipsImage, error) {
+	op := startOp(ctx, "project", input)
+	defer op.end()
 
 	var out_columns *C.VipsImage
 	var out_rows *C.VipsImage
 
 	ret := C.gen_vips_project(input, &out_columns, &out_rows)
 	if ret != 0 {
-		return nil, nil, handleImageError(out_columns)
+		return nil, nil, op.fail(handleImageError(out_columns))
 	}
 
 	return out_columns, out_rows, nil
@@ -3641,13 +3830,15 @@ func vipsGenProject(input *C.VipsImage) (*C.VipsImage, *C.VipsImage, error) {
 
 // QuadraticOptions are optional parameters for quadratic.
 type QuadraticOptions struct {
-	Interpolate *C.VipsInterpolate
+	// Interpolate names an interpolator, such as "bicubic" or "nohalo".
+	Interpolate *string
 }
 
 // vipsGenQuadratic calls the vips quadratic operation.
 // resample an image with a quadratic transform
-func vipsGenQuadratic(input *C.VipsImage, coeff *C.VipsImage, opts *QuadraticOptions) (*C.VipsImage, error) {
-	incOpCounter("quadratic")
+func vipsGenQuadratic(ctx context.Context, input *C.VipsImage, coeff *C.VipsImage, opts *QuadraticOptions) (*C.VipsImage, error) {
+	op := startOp(ctx, "quadratic", input)
+	defer op.end()
 
 	var out_out *C.VipsImage
 
@@ -3655,13 +3846,18 @@ func vipsGenQuadratic(input *C.VipsImage, coeff *C.VipsImage, opts *QuadraticOpt
 	if opts != nil {
 		if opts.Interpolate != nil {
 			cOpts.has_interpolate = 1
-			cOpts.interpolate = opts.Interpolate
+			interp_interpolate, err := NewInterpolate(*opts.Interpolate)
+			if err != nil {
+				return nil, op.fail(err)
+			}
+			defer C.g_object_unref(C.gpointer(unsafe.Pointer(interp_interpolate)))
+			cOpts.interpolate = interp_interpolate
 		}
 	}
 
 	ret := C.gen_vips_quadratic(input, coeff, &out_out, &cOpts)
 	if ret != 0 {
-		return nil, handleImageError(out_out)
+		return nil, op.fail(handleImageError(out_out))
 	}
 
 	return out_out, nil
@@ -3669,14 +3865,15 @@ func vipsGenQuadratic(input *C.VipsImage, coeff *C.VipsImage, opts *QuadraticOpt
 
 // vipsGenRad2float calls the vips rad2float operation.
 // unpack Radiance coding to float RGB
-func vipsGenRad2float(input *C.VipsImage) (*C.VipsImage, error) {
-	incOpCounter("rad2float")
+func vipsGenRad2float(ctx context.Context, input *C.VipsImage) (*C.VipsImage, error) {
+	op := startOp(ctx, "rad2float", input)
+	defer op.end()
 
 	var out_out *C.VipsImage
 
 	ret := C.gen_vips_rad2float(input, &out_out)
 	if ret != 0 {
-		return nil, handleImageError(out_out)
+		return nil, op.fail(handleImageError(out_out))
 	}
 
 	return out_out, nil
@@ -3684,14 +3881,15 @@ func vipsGenRad2float(input *C.VipsImage) (*C.VipsImage, error) {
 
 // vipsGenRank calls the vips rank operation.
 // rank filter
-func vipsGenRank(input *C.VipsImage, width int, height int, index int) (*C.VipsImage, error) {
-	incOpCounter("rank")
+func vipsGenRank(ctx context.Context, input *C.VipsImage, width int, height int, index int) (*C.VipsImage, error) {
+	op := startOp(ctx, "rank", input)
+	defer op.end()
 
 	var out_out *C.VipsImage
 
 	ret := C.gen_vips_rank(input, C.int(width), C.int(height), C.int(index), &out_out)
 	if ret != 0 {
-		return nil, handleImageError(out_out)
+		return nil, op.fail(handleImageError(out_out))
 	}
 
 	return out_out, nil
@@ -3699,14 +3897,15 @@ func vipsGenRank(input *C.VipsImage, width int, height int, index int) (*C.VipsI
 
 // vipsGenRecomb calls the vips recomb operation.
 // linear recombination with matrix
-func vipsGenRecomb(input *C.VipsImage, m *C.VipsImage) (*C.VipsImage, error) {
-	incOpCounter("recomb")
+func vipsGenRecomb(ctx context.Context, input *C.VipsImage, m *C.VipsImage) (*C.VipsImage, error) {
+	op := startOp(ctx, "recomb", input)
+	defer op.end()
 
 	var out_out *C.VipsImage
 
 	ret := C.gen_vips_recomb(input, m, &out_out)
 	if ret != 0 {
-		return nil, handleImageError(out_out)
+		return nil, op.fail(handleImageError(out_out))
 	}
 
 	return out_out, nil
@@ -3720,8 +3919,9 @@ type ReduceOptions struct {
 
 // vipsGenReduce calls the vips reduce operation.
 // reduce an image
-func vipsGenReduce(input *C.VipsImage, hshrink float64, vshrink float64, opts *ReduceOptions) (*C.VipsImage, error) {
-	incOpCounter("reduce")
+func vipsGenReduce(ctx context.Context, input *C.VipsImage, hshrink float64, vshrink float64, opts *ReduceOptions) (*C.VipsImage, error) {
+	op := startOp(ctx, "reduce", input)
+	defer op.end()
 
 	var out_out *C.VipsImage
 
@@ -3739,7 +3939,7 @@ func vipsGenReduce(input *C.VipsImage, hshrink float64, vshrink float64, opts *R
 
 	ret := C.gen_vips_reduce(input, C.double(hshrink), C.double(vshrink), &out_out, &cOpts)
 	if ret != 0 {
-		return nil, handleImageError(out_out)
+		return nil, op.fail(handleImageError(out_out))
 	}
 
 	return out_out, nil
@@ -3753,8 +3953,9 @@ type ReducehOptions struct {
 
 // vipsGenReduceh calls the vips reduceh operation.
 // shrink an image horizontally
-func vipsGenReduceh(input *C.VipsImage, hshrink float64, opts *ReducehOptions) (*C.VipsImage, error) {
-	incOpCounter("reduceh")
+func vipsGenReduceh(ctx context.Context, input *C.VipsImage, hshrink float64, opts *ReducehOptions) (*C.VipsImage, error) {
+	op := startOp(ctx, "reduceh", input)
+	defer op.end()
 
 	var out_out *C.VipsImage
 
@@ -3772,7 +3973,7 @@ func vipsGenReduceh(input *C.VipsImage, hshrink float64, opts *ReducehOptions) (
 
 	ret := C.gen_vips_reduceh(input, C.double(hshrink), &out_out, &cOpts)
 	if ret != 0 {
-		return nil, handleImageError(out_out)
+		return nil, op.fail(handleImageError(out_out))
 	}
 
 	return out_out, nil
@@ -3786,8 +3987,9 @@ type ReducevOptions struct {
 
 // vipsGenReducev calls the vips reducev operation.
 // shrink an image vertically
-func vipsGenReducev(input *C.VipsImage, vshrink float64, opts *ReducevOptions) (*C.VipsImage, error) {
-	incOpCounter("reducev")
+func vipsGenReducev(ctx context.Context, input *C.VipsImage, vshrink float64, opts *ReducevOptions) (*C.VipsImage, error) {
+	op := startOp(ctx, "reducev", input)
+	defer op.end()
 
 	var out_out *C.VipsImage
 
@@ -3805,7 +4007,7 @@ func vipsGenReducev(input *C.VipsImage, vshrink float64, opts *ReducevOptions) (
 
 	ret := C.gen_vips_reducev(input, C.double(vshrink), &out_out, &cOpts)
 	if ret != 0 {
-		return nil, handleImageError(out_out)
+		return nil, op.fail(handleImageError(out_out))
 	}
 
 	return out_out, nil
@@ -3813,14 +4015,15 @@ func vipsGenReducev(input *C.VipsImage, vshrink float64, opts *ReducevOptions) (
 
 // vipsGenRelational calls the vips relational operation.
 // relational operation on two images
-func vipsGenRelational(left *C.VipsImage, right *C.VipsImage, relational OperationRelational) (*C.VipsImage, error) {
-	incOpCounter("relational")
+func vipsGenRelational(ctx context.Context, left *C.VipsImage, right *C.VipsImage, relational OperationRelational) (*C.VipsImage, error) {
+	op := startOp(ctx, "relational", left)
+	defer op.end()
 
 	var out_out *C.VipsImage
 
 	ret := C.gen_vips_relational(left, right, C.VipsOperationRelational(relational), &out_out)
 	if ret != 0 {
-		return nil, handleImageError(out_out)
+		return nil, op.fail(handleImageError(out_out))
 	}
 
 	return out_out, nil
@@ -3828,14 +4031,15 @@ func vipsGenRelational(left *C.VipsImage, right *C.VipsImage, relational Operati
 
 // vipsGenRelationalConst calls the vips relational_const operation.
 // relational operations against a constant
-func vipsGenRelationalConst(input *C.VipsImage, relational OperationRelational, c []float64) (*C.VipsImage, error) {
-	incOpCounter("relational_const")
+func vipsGenRelationalConst(ctx context.Context, input *C.VipsImage, relational OperationRelational, c []float64) (*C.VipsImage, error) {
+	op := startOp(ctx, "relational_const", input)
+	defer op.end()
 
 	var out_out *C.VipsImage
 
 	ret := C.gen_vips_relational_const(input, C.VipsOperationRelational(relational), (*C.double)(unsafe.Pointer(&c[0])), C.int(len(c)), &out_out)
 	if ret != 0 {
-		return nil, handleImageError(out_out)
+		return nil, op.fail(handleImageError(out_out))
 	}
 
 	return out_out, nil
@@ -3843,14 +4047,15 @@ func vipsGenRelationalConst(input *C.VipsImage, relational OperationRelational,
 
 // vipsGenRemainder calls the vips remainder operation.
 // remainder after integer division of two images
-func vipsGenRemainder(left *C.VipsImage, right *C.VipsImage) (*C.VipsImage, error) {
-	incOpCounter("remainder")
+func vipsGenRemainder(ctx context.Context, left *C.VipsImage, right *C.VipsImage) (*C.VipsImage, error) {
+	op := startOp(ctx, "remainder", left)
+	defer op.end()
 
 	var out_out *C.VipsImage
 
 	ret := C.gen_vips_remainder(left, right, &out_out)
 	if ret != 0 {
-		return nil, handleImageError(out_out)
+		return nil, op.fail(handleImageError(out_out))
 	}
 
 	return out_out, nil
@@ -3858,14 +4063,15 @@ func vipsGenRemainder(left *C.VipsImage, right *C.VipsImage) (*C.VipsImage, erro
 
 // vipsGenRemainderConst calls the vips remainder_const operation.
 // remainder after integer division of an image and a constant
-func vipsGenRemainderConst(input *C.VipsImage, c []float64) (*C.VipsImage, error) {
-	incOpCounter("remainder_const")
+func vipsGenRemainderConst(ctx context.Context, input *C.VipsImage, c []float64) (*C.VipsImage, error) {
+	op := startOp(ctx, "remainder_const", input)
+	defer op.end()
 
 	var out_out *C.VipsImage
 
 	ret := C.gen_vips_remainder_const(input, (*C.double)(unsafe.Pointer(&c[0])), C.int(len(c)), &out_out)
 	if ret != 0 {
-		return nil, handleImageError(out_out)
+		return nil, op.fail(handleImageError(out_out))
 	}
 
 	return out_out, nil
@@ -3873,8 +4079,9 @@ func vipsGenRemainderConst(input *C.VipsImage, c []float64) (*C.VipsImage, error
 
 // vipsGenRemosaic calls the vips remosaic operation.
 // rebuild an mosaiced image
-func vipsGenRemosaic(input *C.VipsImage, oldStr string, newStr string) (*C.VipsImage, error) {
-	incOpCounter("remosaic")
+func vipsGenRemosaic(ctx context.Context, input *C.VipsImage, oldStr string, newStr string) (*C.VipsImage, error) {
+	op := startOp(ctx, "remosaic", input)
+	defer op.end()
 
 	cStr_oldStr := C.CString(oldStr)
 	defer C.free(unsafe.Pointer(cStr_oldStr))
@@ -3884,7 +4091,7 @@ func vipsGenRemosaic(input *C.VipsImage, oldStr string, newStr string) (*C.VipsI
 
 	ret := C.gen_vips_remosaic(input, cStr_oldStr, cStr_newStr, &out_out)
 	if ret != 0 {
-		return nil, handleImageError(out_out)
+		return nil, op.fail(handleImageError(out_out))
 	}
 
 	return out_out, nil
@@ -3892,14 +4099,15 @@ func vipsGenRemosaic(input *C.VipsImage, oldStr string, newStr string) (*C.VipsI
 
 // vipsGenReplicate calls the vips replicate operation.
 // replicate an image
-func vipsGenReplicate(input *C.VipsImage, across int, down int) (*C.VipsImage, error) {
-	incOpCounter("replicate")
+func vipsGenReplicate(ctx context.Context, input *C.VipsImage, across int, down int) (*C.VipsImage, error) {
+	op := startOp(ctx, "replicate", input)
+	defer op.end()
 
 	var out_out *C.VipsImage
 
 	ret := C.gen_vips_replicate(input, C.int(across), C.int(down), &out_out)
 	if ret != 0 {
-		return nil, handleImageError(out_out)
+		return nil, op.fail(handleImageError(out_out))
 	}
 
 	return out_out, nil
@@ -3907,14 +4115,15 @@ func vipsGenReplicate(input *C.VipsImage, across int, down int) (*C.VipsImage, e
 
 // vipsGenRot calls the vips rot operation.
 // rotate an image
-func vipsGenRot(input *C.VipsImage, angle Angle) (*C.VipsImage, error) {
-	incOpCounter("rot")
+func vipsGenRot(ctx context.Context, input *C.VipsImage, angle Angle) (*C.VipsImage, error) {
+	op := startOp(ctx, "rot", input)
+	defer op.end()
 
 	var out_out *C.VipsImage
 
 	ret := C.gen_vips_rot(input, C.VipsAngle(angle), &out_out)
 	if ret != 0 {
-		return nil, handleImageError(out_out)
+		return nil, op.fail(handleImageError(out_out))
 	}
 
 	return out_out, nil
@@ -3927,8 +4136,9 @@ type Rot45Options struct {
 
 // vipsGenRot45 calls the vips rot45 operation.
 // rotate an image
-func vipsGenRot45(input *C.VipsImage, opts *Rot45Options) (*C.VipsImage, error) {
-	incOpCounter("rot45")
+func vipsGenRot45(ctx context.Context, input *C.VipsImage, opts *Rot45Options) (*C.VipsImage, error) {
+	op := startOp(ctx, "rot45", input)
+	defer op.end()
 
 	var out_out *C.VipsImage
 
@@ -3942,7 +4152,7 @@ func vipsGenRot45(input *C.VipsImage, opts *Rot45Options) (*C.VipsImage, error)
 
 	ret := C.gen_vips_rot45(input, &out_out, &cOpts)
 	if ret != 0 {
-		return nil, handleImageError(out_out)
+		return nil, op.fail(handleImageError(out_out))
 	}
 
 	return out_out, nil
@@ -3950,7 +4160,8 @@ func vipsGenRot45(input *C.VipsImage, opts *Rot45Options) (*C.VipsImage, error)
 
 // RotateOptions are optional parameters for rotate.
 type RotateOptions struct {
-	Interpolate *C.VipsInterpolate
+	// Interpolate names an interpolator, such as "bicubic" or "nohalo".
+	Interpolate *string
 	Background []float64
 	Odx *float64
 	Ody *float64
@@ -3960,8 +4171,9 @@ type RotateOptions struct {
 
 // vipsGenRotate calls the vips rotate operation.
 // rotate an image by a number of degrees
-func vipsGenRotate(input *C.VipsImage, angle float64, opts *RotateOptions) (*C.VipsImage, error) {
-	incOpCounter("rotate")
+func vipsGenRotate(ctx context.Context, input *C.VipsImage, angle float64, opts *RotateOptions) (*C.VipsImage, error) {
+	op := startOp(ctx, "rotate", input)
+	defer op.end()
 
 	var out_out *C.VipsImage
 
@@ -3971,7 +4183,12 @@ func vipsGenRotate(input *C.VipsImage, angle float64, opts *RotateOptions) (*C.V
 	if opts != nil {
 		if opts.Interpolate != nil {
 			cOpts.has_interpolate = 1
-			cOpts.interpolate = opts.Interpolate
+			interp_interpolate, err := NewInterpolate(*opts.Interpolate)
+			if err != nil {
+				return nil, op.fail(err)
+			}
+			defer C.g_object_unref(C.gpointer(unsafe.Pointer(interp_interpolate)))
+			cOpts.interpolate = interp_interpolate
 		}
 		if opts.Background != nil {
 			cOpts.has_background = 1
@@ -3999,7 +4216,7 @@ func vipsGenRotate(input *C.VipsImage, angle float64, opts *RotateOptions) (*C.V
 
 	ret := C.gen_vips_rotate(input, C.double(angle), &out_out, &cOpts)
 	if ret != 0 {
-		return nil, handleImageError(out_out)
+		return nil, op.fail(handleImageError(out_out))
 	}
 
 	return out_out, nil
@@ -4007,14 +4224,15 @@ func vipsGenRotate(input *C.VipsImage, angle float64, opts *RotateOptions) (*C.V
 
 // vipsGenRound calls the vips round operation.
 // perform a round function on an image
-func vipsGenRound(input *C.VipsImage, round OperationRound) (*C.VipsImage, error) {
-	incOpCounter("round")
+func vipsGenRound(ctx context.Context, input *C.VipsImage, round OperationRound) (*C.VipsImage, error) {
+	op := startOp(ctx, "round", input)
+	defer op.end()
 
 	var out_out *C.VipsImage
 
 	ret := C.gen_vips_round(input, C.VipsOperationRound(round), &out_out)
 	if ret != 0 {
-		return nil, handleImageError(out_out)
+		return nil, op.fail(handleImageError(out_out))
 	}
 
 	return out_out, nil
@@ -4022,14 +4240,15 @@ func vipsGenRound(input *C.VipsImage, round OperationRound) (*C.VipsImage, error
 
 // vipsGenSRGB2HSV calls the vips sRGB2HSV operation.
 // transform sRGB to HSV
-func vipsGenSRGB2HSV(input *C.VipsImage) (*C.VipsImage, error) {
-	incOpCounter("sRGB2HSV")
+func vipsGenSRGB2HSV(ctx context.Context, input *C.VipsImage) (*C.VipsImage, error) {
+	op := startOp(ctx, "sRGB2HSV", input)
+	defer op.end()
 
 	var out_out *C.VipsImage
 
 	ret := C.gen_vips_sRGB2HSV(input, &out_out)
 	if ret != 0 {
-		return nil, handleImageError(out_out)
+		return nil, op.fail(handleImageError(out_out))
 	}
 
 	return out_out, nil
@@ -4037,14 +4256,15 @@ func vipsGenSRGB2HSV(input *C.VipsImage) (*C.VipsImage, error) {
 
 // vipsGenSRGB2scRGB calls the vips sRGB2scRGB operation.
 // convert an sRGB image to scRGB
-func vipsGenSRGB2scRGB(input *C.VipsImage) (*C.VipsImage, error) {
-	incOpCounter("sRGB2scRGB")
+func vipsGenSRGB2scRGB(ctx context.Context, input *C.VipsImage) (*C.VipsImage, error) {
+	op := startOp(ctx, "sRGB2scRGB", input)
+	defer op.end()
 
 	var out_out *C.VipsImage
 
 	ret := C.gen_vips_sRGB2scRGB(input, &out_out)
 	if ret != 0 {
-		return nil, handleImageError(out_out)
+		return nil, op.fail(handleImageError(out_out))
 	}
 
 	return out_out, nil
@@ -4057,8 +4277,9 @@ type ScRGB2BWOptions struct {
 
 // vipsGenScRGB2BW calls the vips scRGB2BW operation.
 // convert scRGB to BW
-func vipsGenScRGB2BW(input *C.VipsImage, opts *ScRGB2BWOptions) (*C.VipsImage, error) {
-	incOpCounter("scRGB2BW")
+func vipsGenScRGB2BW(ctx context.Context, input *C.VipsImage, opts *ScRGB2BWOptions) (*C.VipsImage, error) {
+	op := startOp(ctx, "scRGB2BW", input)
+	defer op.end()
 
 	var out_out *C.VipsImage
 
@@ -4072,7 +4293,7 @@ func vipsGenScRGB2BW(input *C.VipsImage, opts *ScRGB2BWOptions) (*C.VipsImage, e
 
 	ret := C.gen_vips_scRGB2BW(input, &out_out, &cOpts)
 	if ret != 0 {
-		return nil, handleImageError(out_out)
+		return nil, op.fail(handleImageError(out_out))
 	}
 
 	return out_out, nil
@@ -4080,14 +4301,15 @@ func vipsGenScRGB2BW(input *C.VipsImage, opts *ScRGB2BWOptions) (*C.VipsImage, e
 
 // vipsGenScRGB2XYZ calls the vips scRGB2XYZ operation.
 // transform scRGB to XYZ
-func vipsGenScRGB2XYZ(input *C.VipsImage) (*C.VipsImage, error) {
-	incOpCounter("scRGB2XYZ")
+func vipsGenScRGB2XYZ(ctx context.Context, input *C.VipsImage) (*C.VipsImage, error) {
+	op := startOp(ctx, "scRGB2XYZ", input)
+	defer op.end()
 
 	var out_out *C.VipsImage
 
 	ret := C.gen_vips_scRGB2XYZ(input, &out_out)
 	if ret != 0 {
-		return nil, handleImageError(out_out)
+		return nil, op.fail(handleImageError(out_out))
 	}
 
 	return out_out, nil
@@ -4100,8 +4322,9 @@ type ScRGB2sRGBOptions struct {
 
 // vipsGenScRGB2sRGB calls the vips scRGB2sRGB operation.
 // convert scRGB to sRGB
-func vipsGenScRGB2sRGB(input *C.VipsImage, opts *ScRGB2sRGBOptions) (*C.VipsImage, error) {
-	incOpCounter("scRGB2sRGB")
+func vipsGenScRGB2sRGB(ctx context.Context, input *C.VipsImage, opts *ScRGB2sRGBOptions) (*C.VipsImage, error) {
+	op := startOp(ctx, "scRGB2sRGB", input)
+	defer op.end()
 
 	var out_out *C.VipsImage
 
@@ -4115,7 +4338,7 @@ func vipsGenScRGB2sRGB(input *C.VipsImage, opts *ScRGB2sRGBOptions) (*C.VipsImag
 
 	ret := C.gen_vips_scRGB2sRGB(input, &out_out, &cOpts)
 	if ret != 0 {
-		return nil, handleImageError(out_out)
+		return nil, op.fail(handleImageError(out_out))
 	}
 
 	return out_out, nil
@@ -4129,8 +4352,9 @@ type ScaleOptions struct {
 
 // vipsGenScale calls the vips scale operation.
 // scale an image to uchar
-func vipsGenScale(input *C.VipsImage, opts *ScaleOptions) (*C.VipsImage, error) {
-	incOpCounter("scale")
+func vipsGenScale(ctx context.Context, input *C.VipsImage, opts *ScaleOptions) (*C.VipsImage, error) {
+	op := startOp(ctx, "scale", input)
+	defer op.end()
 
 	var out_out *C.VipsImage
 
@@ -4148,7 +4372,7 @@ func vipsGenScale(input *C.VipsImage, opts *ScaleOptions) (*C.VipsImage, error)
 
 	ret := C.gen_vips_scale(input, &out_out, &cOpts)
 	if ret != 0 {
-		return nil, handleImageError(out_out)
+		return nil, op.fail(handleImageError(out_out))
 	}
 
 	return out_out, nil
@@ -4156,14 +4380,72 @@ func vipsGenScale(input *C.VipsImage, opts *ScaleOptions) (*C.VipsImage, error)
 
 // vipsGenScharr calls the vips scharr operation.
 // Scharr edge detector
-func vipsGenScharr(input *C.VipsImage) (*C.VipsImage, error) {
-	incOpCounter("scharr")
+func vipsGenScharr(ctx context.Context, input *C.VipsImage) (*C.VipsImage, error) {
+	op := startOp(ctx, "scharr", input)
+	defer op.end()
 
 	var out_out *C.VipsImage
 
 	ret := C.gen_vips_scharr(input, &out_out)
 	if ret != 0 {
-		return nil, handleImageError(out_out)
+		return nil, op.fail(handleImageError(out_out))
+	}
+
+	return out_out, nil
+}
+
+// SdfOptions are optional parameters for sdf.
+type SdfOptions struct {
+	R *float64
+	A []float64
+	B []float64
+	Corners []float64
+}
+
+// vipsGenSdf calls the vips sdf operation.
+// create an SDF image
+// Requires libvips 8.16+.
+func vipsGenSdf(ctx context.Context, width int, height int, shape SdfShape, opts *SdfOptions) (*C.VipsImage, error) {
+	if err := requireLibvips("sdf", 8, 16, 0); err != nil {
+		return nil, err
+	}
+
+	op := startOp(ctx, "sdf", nil)
+	defer op.end()
+
+	var out_out *C.VipsImage
+
+	var cOpts C.GenSdfOpts
+	var pinner runtime.Pinner
+	defer pinner.Unpin()
+	if opts != nil {
+		if opts.R != nil {
+			cOpts.has_r = 1
+			cOpts.r = C.double(*opts.R)
+		}
+		if opts.A != nil {
+			cOpts.has_a = 1
+			pinner.Pin(&opts.A[0])
+			cOpts.a = (*C.double)(unsafe.Pointer(&opts.A[0]))
+			cOpts.a_n = C.int(len(opts.A))
+		}
+		if opts.B != nil {
+			cOpts.has_b = 1
+			pinner.Pin(&opts.B[0])
+			cOpts.b = (*C.double)(unsafe.Pointer(&opts.B[0]))
+			cOpts.b_n = C.int(len(opts.B))
+		}
+		if opts.Corners != nil {
+			cOpts.has_corners = 1
+			pinner.Pin(&opts.Corners[0])
+			cOpts.corners = (*C.double)(unsafe.Pointer(&opts.Corners[0]))
+			cOpts.corners_n = C.int(len(opts.Corners))
+		}
+	}
+
+	ret := C.gen_vips_sdf(C.int(width), C.int(height), C.int(shape), &out_out, &cOpts)
+	if ret != 0 {
+		return nil, op.fail(handleImageError(out_out))
 	}
 
 	return out_out, nil
@@ -4176,8 +4458,9 @@ type SequentialOptions struct {
 
 // vipsGenSequential calls the vips sequential operation.
 // check sequential access
-func vipsGenSequential(input *C.VipsImage, opts *SequentialOptions) (*C.VipsImage, error) {
-	incOpCounter("sequential")
+func vipsGenSequential(ctx context.Context, input *C.VipsImage, opts *SequentialOptions) (*C.VipsImage, error) {
+	op := startOp(ctx, "sequential", input)
+	defer op.end()
 
 	var out_out *C.VipsImage
 
@@ -4191,7 +4474,7 @@ func vipsGenSequential(input *C.VipsImage, opts *SequentialOptions) (*C.VipsImag
 
 	ret := C.gen_vips_sequential(input, &out_out, &cOpts)
 	if ret != 0 {
-		return nil, handleImageError(out_out)
+		return nil, op.fail(handleImageError(out_out))
 	}
 
 	return out_out, nil
@@ -4209,8 +4492,9 @@ type SharpenOptions struct {
 
 // vipsGenSharpen calls the vips sharpen operation.
 // unsharp masking for print
-func vipsGenSharpen(input *C.VipsImage, opts *SharpenOptions) (*C.VipsImage, error) {
-	incOpCounter("sharpen")
+func vipsGenSharpen(ctx context.Context, input *C.VipsImage, opts *SharpenOptions) (*C.VipsImage, error) {
+	op := startOp(ctx, "sharpen", input)
+	defer op.end()
 
 	var out_out *C.VipsImage
 
@@ -4244,7 +4528,7 @@ func vipsGenSharpen(input *C.VipsImage, opts *SharpenOptions) (*C.VipsImage, err
 
 	ret := C.gen_vips_sharpen(input, &out_out, &cOpts)
 	if ret != 0 {
-		return nil, handleImageError(out_out)
+		return nil, op.fail(handleImageError(out_out))
 	}
 
 	return out_out, nil
@@ -4257,8 +4541,9 @@ type ShrinkOptions struct {
 
 // vipsGenShrink calls the vips shrink operation.
 // shrink an image
-func vipsGenShrink(input *C.VipsImage, hshrink float64, vshrink float64, opts *ShrinkOptions) (*C.VipsImage, error) {
-	incOpCounter("shrink")
+func vipsGenShrink(ctx context.Context, input *C.VipsImage, hshrink float64, vshrink float64, opts *ShrinkOptions) (*C.VipsImage, error) {
+	op := startOp(ctx, "shrink", input)
+	defer op.end()
 
 	var out_out *C.VipsImage
 
@@ -4272,7 +4557,7 @@ func vipsGenShrink(input *C.VipsImage, hshrink float64, vshrink float64, opts *S
 
 	ret := C.gen_vips_shrink(input, C.double(hshrink), C.double(vshrink), &out_out, &cOpts)
 	if ret != 0 {
-		return nil, handleImageError(out_out)
+		return nil, op.fail(handleImageError(out_out))
 	}
 
 	return out_out, nil
@@ -4285,8 +4570,9 @@ type ShrinkhOptions struct {
 
 // vipsGenShrinkh calls the vips shrinkh operation.
 // shrink an image horizontally
-func vipsGenShrinkh(input *C.VipsImage, hshrink int, opts *ShrinkhOptions) (*C.VipsImage, error) {
-	incOpCounter("shrinkh")
+func vipsGenShrinkh(ctx context.Context, input *C.VipsImage, hshrink int, opts *ShrinkhOptions) (*C.VipsImage, error) {
+	op := startOp(ctx, "shrinkh", input)
+	defer op.end()
 
 	var out_out *C.VipsImage
 
@@ -4300,7 +4586,7 @@ func vipsGenShrinkh(input *C.VipsImage, hshrink int, opts *ShrinkhOptions) (*C.V
 
 	ret := C.gen_vips_shrinkh(input, C.int(hshrink), &out_out, &cOpts)
 	if ret != 0 {
-		return nil, handleImageError(out_out)
+		return nil, op.fail(handleImageError(out_out))
 	}
 
 	return out_out, nil
@@ -4313,8 +4599,9 @@ type ShrinkvOptions struct {
 
 // vipsGenShrinkv calls the vips shrinkv operation.
 // shrink an image vertically
-func vipsGenShrinkv(input *C.VipsImage, vshrink int, opts *ShrinkvOptions) (*C.VipsImage, error) {
-	incOpCounter("shrinkv")
+func vipsGenShrinkv(ctx context.Context, input *C.VipsImage, vshrink int, opts *ShrinkvOptions) (*C.VipsImage, error) {
+	op := startOp(ctx, "shrinkv", input)
+	defer op.end()
 
 	var out_out *C.VipsImage
 
@@ -4328,7 +4615,7 @@ func vipsGenShrinkv(input *C.VipsImage, vshrink int, opts *ShrinkvOptions) (*C.V
 
 	ret := C.gen_vips_shrinkv(input, C.int(vshrink), &out_out, &cOpts)
 	if ret != 0 {
-		return nil, handleImageError(out_out)
+		return nil, op.fail(handleImageError(out_out))
 	}
 
 	return out_out, nil
@@ -4336,14 +4623,15 @@ func vipsGenShrinkv(input *C.VipsImage, vshrink int, opts *ShrinkvOptions) (*C.V
 
 // vipsGenSign calls the vips sign operation.
 // unit vector of pixel
-func vipsGenSign(input *C.VipsImage) (*C.VipsImage, error) {
-	incOpCounter("sign")
+func vipsGenSign(ctx context.Context, input *C.VipsImage) (*C.VipsImage, error) {
+	op := startOp(ctx, "sign", input)
+	defer op.end()
 
 	var out_out *C.VipsImage
 
 	ret := C.gen_vips_sign(input, &out_out)
 	if ret != 0 {
-		return nil, handleImageError(out_out)
+		return nil, op.fail(handleImageError(out_out))
 	}
 
 	return out_out, nil
@@ -4353,7 +4641,8 @@ func vipsGenSign(input *C.VipsImage) (*C.VipsImage, error) {
 type SimilarityOptions struct {
 	Scale *float64
 	Angle *float64
-	Interpolate *C.VipsInterpolate
+	// Interpolate names an interpolator, such as "bicubic" or "nohalo".
+	Interpolate *string
 	Background []float64
 	Odx *float64
 	Ody *float64
@@ -4363,8 +4652,9 @@ type SimilarityOptions struct {
 
 // vipsGenSimilarity calls the vips similarity operation.
 // similarity transform of an image
-func vipsGenSimilarity(input *C.VipsImage, opts *SimilarityOptions) (*C.VipsImage, error) {
-	incOpCounter("similarity")
+func vipsGenSimilarity(ctx context.Context, input *C.VipsImage, opts *SimilarityOptions) (*C.VipsImage, error) {
+	op := startOp(ctx, "similarity", input)
+	defer op.end()
 
 	var out_out *C.VipsImage
 
@@ -4382,7 +4672,12 @@ func vipsGenSimilarity(input *C.VipsImage, opts *SimilarityOptions) (*C.VipsImag
 		}
 		if opts.Interpolate != nil {
 			cOpts.has_interpolate = 1
-			cOpts.interpolate = opts.Interpolate
+			interp_interpolate, err := NewInterpolate(*opts.Interpolate)
+			if err != nil {
+				return nil, op.fail(err)
+			}
+			defer C.g_object_unref(C.gpointer(unsafe.Pointer(interp_interpolate)))
+			cOpts.interpolate = interp_interpolate
 		}
 		if opts.Background != nil {
 			cOpts.has_background = 1
@@ -4410,7 +4705,7 @@ func vipsGenSimilarity(input *C.VipsImage, opts *SimilarityOptions) (*C.VipsImag
 
 	ret := C.gen_vips_similarity(input, &out_out, &cOpts)
 	if ret != 0 {
-		return nil, handleImageError(out_out)
+		return nil, op.fail(handleImageError(out_out))
 	}
 
 	return out_out, nil
@@ -4425,8 +4720,9 @@ type SinesOptions struct {
 
 // vipsGenSines calls the vips sines operation.
 // make a 2D sine wave
-func vipsGenSines(width int, height int, opts *SinesOptions) (*C.VipsImage, error) {
-	incOpCounter("sines")
+func vipsGenSines(ctx context.Context, width int, height int, opts *SinesOptions) (*C.VipsImage, error) {
+	op := startOp(ctx, "sines", nil)
+	defer op.end()
 
 	var out_out *C.VipsImage
 
@@ -4448,7 +4744,7 @@ func vipsGenSines(width int, height int, opts *SinesOptions) (*C.VipsImage, erro
 
 	ret := C.gen_vips_sines(C.int(width), C.int(height), &out_out, &cOpts)
 	if ret != 0 {
-		return nil, handleImageError(out_out)
+		return nil, op.fail(handleImageError(out_out))
 	}
 
 	return out_out, nil
@@ -4462,8 +4758,9 @@ type SmartcropOptions struct {
 
 // vipsGenSmartcrop calls the vips smartcrop operation.
 // extract an area from an image
-func vipsGenSmartcrop(input *C.VipsImage, width int, height int, opts *SmartcropOptions) (int, *C.VipsImage, int, error) {
-	incOpCounter("smartcrop")
+func vipsGenSmartcrop(ctx context.Context, input *C.VipsImage, width int, height int, opts *SmartcropOptions) (int, *C.VipsImage, int, error) {
+	op := startOp(ctx, "smartcrop", input)
+	defer op.end()
 
 	var out_attentionX C.int
 	var out_out *C.VipsImage
@@ -4483,7 +4780,7 @@ func vipsGenSmartcrop(input *C.VipsImage, width int, height int, opts *Smartcrop
 
 	ret := C.gen_vips_smartcrop(input, C.int(width), C.int(height), &out_attentionX, &out_out, &out_attentionY, &cOpts)
 	if ret != 0 {
-		return 0, nil, 0, handleImageError(out_out)
+		return 0, nil, 0, op.fail(handleImageError(out_out))
 	}
 
 	return int(out_attentionX), out_out, int(out_attentionY), nil
@@ -4491,14 +4788,15 @@ func vipsGenSmartcrop(input *C.VipsImage, width int, height int, opts *Smartcrop
 
 // vipsGenSobel calls the vips sobel operation.
 // Sobel edge detector
-func vipsGenSobel(input *C.VipsImage) (*C.VipsImage, error) {
-	incOpCounter("sobel")
+func vipsGenSobel(ctx context.Context, input *C.VipsImage) (*C.VipsImage, error) {
+	op := startOp(ctx, "sobel", input)
+	defer op.end()
 
 	var out_out *C.VipsImage
 
 	ret := C.gen_vips_sobel(input, &out_out)
 	if ret != 0 {
-		return nil, handleImageError(out_out)
+		return nil, op.fail(handleImageError(out_out))
 	}
 
 	return out_out, nil
@@ -4506,14 +4804,15 @@ func vipsGenSobel(input *C.VipsImage) (*C.VipsImage, error) {
 
 // vipsGenSpcor calls the vips spcor operation.
 // spatial correlation
-func vipsGenSpcor(input *C.VipsImage, ref *C.VipsImage) (*C.VipsImage, error) {
-	incOpCounter("spcor")
+func vipsGenSpcor(ctx context.Context, input *C.VipsImage, ref *C.VipsImage) (*C.VipsImage, error) {
+	op := startOp(ctx, "spcor", input)
+	defer op.end()
 
 	var out_out *C.VipsImage
 
 	ret := C.gen_vips_spcor(input, ref, &out_out)
 	if ret != 0 {
-		return nil, handleImageError(out_out)
+		return nil, op.fail(handleImageError(out_out))
 	}
 
 	return out_out, nil
@@ -4521,14 +4820,15 @@ func vipsGenSpcor(input *C.VipsImage, ref *C.VipsImage) (*C.VipsImage, error) {
 
 // vipsGenSpectrum calls the vips spectrum operation.
 // make displayable power spectrum
-func vipsGenSpectrum(input *C.VipsImage) (*C.VipsImage, error) {
-	incOpCounter("spectrum")
+func vipsGenSpectrum(ctx context.Context, input *C.VipsImage) (*C.VipsImage, error) {
+	op := startOp(ctx, "spectrum", input)
+	defer op.end()
 
 	var out_out *C.VipsImage
 
 	ret := C.gen_vips_spectrum(input, &out_out)
 	if ret != 0 {
-		return nil, handleImageError(out_out)
+		return nil, op.fail(handleImageError(out_out))
 	}
 
 	return out_out, nil
@@ -4536,14 +4836,15 @@ func vipsGenSpectrum(input *C.VipsImage) (*C.VipsImage, error) {
 
 // vipsGenStats calls the vips stats operation.
 // find many image stats
-func vipsGenStats(input *C.VipsImage) (*C.VipsImage, error) {
-	incOpCounter("stats")
+func vipsGenStats(ctx context.Context, input *C.VipsImage) (*C.VipsImage, error) {
+	op := startOp(ctx, "stats", input)
+	defer op.end()
 
 	var out_out *C.VipsImage
 
 	ret := C.gen_vips_stats(input, &out_out)
 	if ret != 0 {
-		return nil, handleImageError(out_out)
+		return nil, op.fail(handleImageError(out_out))
 	}
 
 	return out_out, nil
@@ -4559,8 +4860,9 @@ type StdifOptions struct {
 
 // vipsGenStdif calls the vips stdif operation.
 // statistical difference
-func vipsGenStdif(input *C.VipsImage, width int, height int, opts *StdifOptions) (*C.VipsImage, error) {
-	incOpCounter("stdif")
+func vipsGenStdif(ctx context.Context, input *C.VipsImage, width int, height int, opts *StdifOptions) (*C.VipsImage, error) {
+	op := startOp(ctx, "stdif", input)
+	defer op.end()
 
 	var out_out *C.VipsImage
 
@@ -4586,7 +4888,7 @@ func vipsGenStdif(input *C.VipsImage, width int, height int, opts *StdifOptions)
 
 	ret := C.gen_vips_stdif(input, C.int(width), C.int(height), &out_out, &cOpts)
 	if ret != 0 {
-		return nil, handleImageError(out_out)
+		return nil, op.fail(handleImageError(out_out))
 	}
 
 	return out_out, nil
@@ -4599,8 +4901,9 @@ type SubsampleOptions struct {
 
 // vipsGenSubsample calls the vips subsample operation.
 // subsample an image
-func vipsGenSubsample(input *C.VipsImage, xfac int, yfac int, opts *SubsampleOptions) (*C.VipsImage, error) {
-	incOpCounter("subsample")
+func vipsGenSubsample(ctx context.Context, input *C.VipsImage, xfac int, yfac int, opts *SubsampleOptions) (*C.VipsImage, error) {
+	op := startOp(ctx, "subsample", input)
+	defer op.end()
 
 	var out_out *C.VipsImage
 
@@ -4614,7 +4917,7 @@ func vipsGenSubsample(input *C.VipsImage, xfac int, yfac int, opts *SubsampleOpt
 
 	ret := C.gen_vips_subsample(input, C.int(xfac), C.int(yfac), &out_out, &cOpts)
 	if ret != 0 {
-		return nil, handleImageError(out_out)
+		return nil, op.fail(handleImageError(out_out))
 	}
 
 	return out_out, nil
@@ -4622,14 +4925,15 @@ func vipsGenSubsample(input *C.VipsImage, xfac int, yfac int, opts *SubsampleOpt
 
 // vipsGenSubtract calls the vips subtract operation.
 // subtract two images
-func vipsGenSubtract(left *C.VipsImage, right *C.VipsImage) (*C.VipsImage, error) {
-	incOpCounter("subtract")
+func vipsGenSubtract(ctx context.Context, left *C.VipsImage, right *C.VipsImage) (*C.VipsImage, error) {
+	op := startOp(ctx, "subtract", left)
+	defer op.end()
 
 	var out_out *C.VipsImage
 
 	ret := C.gen_vips_subtract(left, right, &out_out)
 	if ret != 0 {
-		return nil, handleImageError(out_out)
+		return nil, op.fail(handleImageError(out_out))
 	}
 
 	return out_out, nil
@@ -4637,14 +4941,15 @@ func vipsGenSubtract(left *C.VipsImage, right *C.VipsImage) (*C.VipsImage, error
 
 // vipsGenSum calls the vips sum operation.
 // sum an array of images
-func vipsGenSum(input []*C.VipsImage) (*C.VipsImage, error) {
-	incOpCounter("sum")
+func vipsGenSum(ctx context.Context, input []*C.VipsImage) (*C.VipsImage, error) {
+	op := startOp(ctx, "sum", nil)
+	defer op.end()
 
 	var out_out *C.VipsImage
 
 	ret := C.gen_vips_sum((**C.VipsImage)(unsafe.Pointer(&input[0])), C.int(len(input)), &out_out)
 	if ret != 0 {
-		return nil, handleImageError(out_out)
+		return nil, op.fail(handleImageError(out_out))
 	}
 
 	return out_out, nil
@@ -4662,8 +4967,9 @@ type TilecacheOptions struct {
 
 // vipsGenTilecache calls the vips tilecache operation.
 // cache an image as a set of tiles
-func vipsGenTilecache(input *C.VipsImage, opts *TilecacheOptions) (*C.VipsImage, error) {
-	incOpCounter("tilecache")
+func vipsGenTilecache(ctx context.Context, input *C.VipsImage, opts *TilecacheOptions) (*C.VipsImage, error) {
+	op := startOp(ctx, "tilecache", input)
+	defer op.end()
 
 	var out_out *C.VipsImage
 
@@ -4697,7 +5003,7 @@ func vipsGenTilecache(input *C.VipsImage, opts *TilecacheOptions) (*C.VipsImage,
 
 	ret := C.gen_vips_tilecache(input, &out_out, &cOpts)
 	if ret != 0 {
-		return nil, handleImageError(out_out)
+		return nil, op.fail(handleImageError(out_out))
 	}
 
 	return out_out, nil
@@ -4719,8 +5025,9 @@ type TonelutOptions struct {
 
 // vipsGenTonelut calls the vips tonelut operation.
 // build a look-up table
-func vipsGenTonelut(opts *TonelutOptions) (*C.VipsImage, error) {
-	incOpCounter("tonelut")
+func vipsGenTonelut(ctx context.Context, opts *TonelutOptions) (*C.VipsImage, error) {
+	op := startOp(ctx, "tonelut", nil)
+	defer op.end()
 
 	var out_out *C.VipsImage
 
@@ -4770,7 +5077,7 @@ func vipsGenTonelut(opts *TonelutOptions) (*C.VipsImage, error) {
 
 	ret := C.gen_vips_tonelut(&out_out, &cOpts)
 	if ret != 0 {
-		return nil, handleImageError(out_out)
+		return nil, op.fail(handleImageError(out_out))
 	}
 
 	return out_out, nil
@@ -4783,8 +5090,14 @@ type Transpose3dOptions struct {
 
 // vipsGenTranspose3d calls the vips transpose3d operation.
 // transpose3d an image
-func vipsGenTranspose3d(input *C.VipsImage, opts *Transpose3dOptions) (*C.VipsImage, error) {
-	incOpCounter("transpose3d")
+// Requires libvips 8.14+.
+func vipsGenTranspose3d(ctx context.Context, input *C.VipsImage, opts *Transpose3dOptions) (*C.VipsImage, error) {
+	if err := requireLibvips("transpose3d", 8, 14, 0); err != nil {
+		return nil, err
+	}
+
+	op := startOp(ctx, "transpose3d", input)
+	defer op.end()
 
 	var out_out *C.VipsImage
 
@@ -4798,7 +5111,7 @@ func vipsGenTranspose3d(input *C.VipsImage, opts *Transpose3dOptions) (*C.VipsIm
 
 	ret := C.gen_vips_transpose3d(input, &out_out, &cOpts)
 	if ret != 0 {
-		return nil, handleImageError(out_out)
+		return nil, op.fail(handleImageError(out_out))
 	}
 
 	return out_out, nil
@@ -4806,14 +5119,15 @@ func vipsGenTranspose3d(input *C.VipsImage, opts *Transpose3dOptions) (*C.VipsIm
 
 // vipsGenUhdr2scRGB calls the vips uhdr2scRGB operation.
 // transform uhdr to scRGB
-func vipsGenUhdr2scRGB(input *C.VipsImage) (*C.VipsImage, error) {
-	incOpCounter("uhdr2scRGB")
+func vipsGenUhdr2scRGB(ctx context.Context, input *C.VipsImage) (*C.VipsImage, error) {
+	op := startOp(ctx, "uhdr2scRGB", input)
+	defer op.end()
 
 	var out_out *C.VipsImage
 
 	ret := C.gen_vips_uhdr2scRGB(input, &out_out)
 	if ret != 0 {
-		return nil, handleImageError(out_out)
+		return nil, op.fail(handleImageError(out_out))
 	}
 
 	return out_out, nil
@@ -4827,8 +5141,9 @@ type UnpremultiplyOptions struct {
 
 // vipsGenUnpremultiply calls the vips unpremultiply operation.
 // unpremultiply image alpha
-func vipsGenUnpremultiply(input *C.VipsImage, opts *UnpremultiplyOptions) (*C.VipsImage, error) {
-	incOpCounter("unpremultiply")
+func vipsGenUnpremultiply(ctx context.Context, input *C.VipsImage, opts *UnpremultiplyOptions) (*C.VipsImage, error) {
+	op := startOp(ctx, "unpremultiply", input)
+	defer op.end()
 
 	var out_out *C.VipsImage
 
@@ -4846,7 +5161,7 @@ func vipsGenUnpremultiply(input *C.VipsImage, opts *UnpremultiplyOptions) (*C.Vi
 
 	ret := C.gen_vips_unpremultiply(input, &out_out, &cOpts)
 	if ret != 0 {
-		return nil, handleImageError(out_out)
+		return nil, op.fail(handleImageError(out_out))
 	}
 
 	return out_out, nil
@@ -4860,8 +5175,9 @@ type WorleyOptions struct {
 
 // vipsGenWorley calls the vips worley operation.
 // make a worley noise image
-func vipsGenWorley(width int, height int, opts *WorleyOptions) (*C.VipsImage, error) {
-	incOpCounter("worley")
+func vipsGenWorley(ctx context.Context, width int, height int, opts *WorleyOptions) (*C.VipsImage, error) {
+	op := startOp(ctx, "worley", nil)
+	defer op.end()
 
 	var out_out *C.VipsImage
 
@@ -4879,7 +5195,7 @@ func vipsGenWorley(width int, height int, opts *WorleyOptions) (*C.VipsImage, er
 
 	ret := C.gen_vips_worley(C.int(width), C.int(height), &out_out, &cOpts)
 	if ret != 0 {
-		return nil, handleImageError(out_out)
+		return nil, op.fail(handleImageError(out_out))
 	}
 
 	return out_out, nil
@@ -4893,8 +5209,9 @@ type WrapOptions struct {
 
 // vipsGenWrap calls the vips wrap operation.
 // wrap image origin
-func vipsGenWrap(input *C.VipsImage, opts *WrapOptions) (*C.VipsImage, error) {
-	incOpCounter("wrap")
+func vipsGenWrap(ctx context.Context, input *C.VipsImage, opts *WrapOptions) (*C.VipsImage, error) {
+	op := startOp(ctx, "wrap", input)
+	defer op.end()
 
 	var out_out *C.VipsImage
 
@@ -4912,7 +5229,7 @@ func vipsGenWrap(input *C.VipsImage, opts *WrapOptions) (*C.VipsImage, error) {
 
 	ret := C.gen_vips_wrap(input, &out_out, &cOpts)
 	if ret != 0 {
-		return nil, handleImageError(out_out)
+		return nil, op.fail(handleImageError(out_out))
 	}
 
 	return out_out, nil
@@ -4927,8 +5244,9 @@ type XyzOptions struct {
 
 // vipsGenXyz calls the vips xyz operation.
 // make an image where pixel values are coordinates
-func vipsGenXyz(width int, height int, opts *XyzOptions) (*C.VipsImage, error) {
-	incOpCounter("xyz")
+func vipsGenXyz(ctx context.Context, width int, height int, opts *XyzOptions) (*C.VipsImage, error) {
+	op := startOp(ctx, "xyz", nil)
+	defer op.end()
 
 	var out_out *C.VipsImage
 
@@ -4950,7 +5268,7 @@ func vipsGenXyz(width int, height int, opts *XyzOptions) (*C.VipsImage, error) {
 
 	ret := C.gen_vips_xyz(C.int(width), C.int(height), &out_out, &cOpts)
 	if ret != 0 {
-		return nil, handleImageError(out_out)
+		return nil, op.fail(handleImageError(out_out))
 	}
 
 	return out_out, nil
@@ -4963,8 +5281,9 @@ type ZoneOptions struct {
 
 // vipsGenZone calls the vips zone operation.
 // make a zone plate
-func vipsGenZone(width int, height int, opts *ZoneOptions) (*C.VipsImage, error) {
-	incOpCounter("zone")
+func vipsGenZone(ctx context.Context, width int, height int, opts *ZoneOptions) (*C.VipsImage, error) {
+	op := startOp(ctx, "zone", nil)
+	defer op.end()
 
 	var out_out *C.VipsImage
 
@@ -4978,7 +5297,7 @@ func vipsGenZone(width int, height int, opts *ZoneOptions) (*C.VipsImage, error)
 
 	ret := C.gen_vips_zone(C.int(width), C.int(height), &out_out, &cOpts)
 	if ret != 0 {
-		return nil, handleImageError(out_out)
+		return nil, op.fail(handleImageError(out_out))
 	}
 
 	return out_out, nil
@@ -4986,14 +5305,15 @@ func vipsGenZone(width int, height int, opts *ZoneOptions) (*C.VipsImage, error)
 
 // vipsGenZoom calls the vips zoom operation.
 // zoom an image
-func vipsGenZoom(input *C.VipsImage, xfac int, yfac int) (*C.VipsImage, error) {
-	incOpCounter("zoom")
+func vipsGenZoom(ctx context.Context, input *C.VipsImage, xfac int, yfac int) (*C.VipsImage, error) {
+	op := startOp(ctx, "zoom", input)
+	defer op.end()
 
 	var out_out *C.VipsImage
 
 	ret := C.gen_vips_zoom(input, C.int(xfac), C.int(yfac), &out_out)
 	if ret != 0 {
-		return nil, handleImageError(out_out)
+		return nil, op.fail(handleImageError(out_out))
 	}
 
 	return out_out, nil
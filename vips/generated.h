@@ -65,6 +65,8 @@ int gen_vips_abs(VipsImage * input, VipsImage ** out_out);
 
 int gen_vips_add(VipsImage * left, VipsImage * right, VipsImage ** out_out);
 
+int gen_vips_addalpha(VipsImage * input, VipsImage ** out_out);
+
 typedef struct {
     int has_interpolate;
     VipsInterpolate *interpolate;
@@ -960,6 +962,19 @@ int gen_vips_scale(VipsImage * input, VipsImage ** out_out, GenScaleOpts *opts);
 
 int gen_vips_scharr(VipsImage * input, VipsImage ** out_out);
 
+typedef struct {
+    int has_r;
+    double r;
+    int has_a;
+    double *a; int a_n;
+    int has_b;
+    double *b; int b_n;
+    int has_corners;
+    double *corners; int corners_n;
+} GenSdfOpts;
+
+int gen_vips_sdf(int width, int height, int shape, VipsImage ** out_out, GenSdfOpts *opts);
+
 typedef struct {
     int has_tileHeight;
     int tileHeight;
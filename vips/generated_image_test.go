@@ -0,0 +1,125 @@
+package vips
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestGeneratedImage_Mutating(t *testing.T) {
+	require.NoError(t, Startup(nil))
+
+	image, err := NewImageFromFile(resources + "png-24bit.png")
+	require.NoError(t, err)
+	defer image.Close()
+
+	require.NoError(t, image.Shrink(4, 4, nil))
+	assert.Equal(t, 480, image.Width())
+	assert.Equal(t, 270, image.Height())
+
+	require.NoError(t, image.Bandmean())
+	assert.Equal(t, 1, image.Bands())
+
+	_, _, err = image.ExportPng(NewPngExportParams())
+	require.NoError(t, err)
+}
+
+func TestGeneratedImage_WithOptions(t *testing.T) {
+	require.NoError(t, Startup(nil))
+
+	image, err := NewImageFromFile(resources + "jpg-24bit.jpg")
+	require.NoError(t, err)
+	defer image.Close()
+	width := image.Width()
+
+	ceil := true
+	require.NoError(t, image.Shrinkh(3, &ShrinkhOptions{Ceil: &ceil}))
+	assert.Equal(t, (width+2)/3, image.Width())
+}
+
+func TestGeneratedImage_InterpolateOption(t *testing.T) {
+	require.NoError(t, Startup(nil))
+
+	image, err := NewImageFromFile(resources + "jpg-24bit.jpg")
+	require.NoError(t, err)
+	defer image.Close()
+	width := image.Width()
+
+	bicubic := "bicubic"
+	require.NoError(t, image.Affine([]float64{2, 0, 0, 2}, &AffineOptions{Interpolate: &bicubic}))
+	assert.Equal(t, width*2, image.Width())
+
+	bogus := "bogus"
+	assert.Error(t, image.Affine([]float64{2, 0, 0, 2}, &AffineOptions{Interpolate: &bogus}))
+}
+
+func TestGeneratedImage_Values(t *testing.T) {
+	require.NoError(t, Startup(nil))
+
+	image, err := Black(10, 10)
+	require.NoError(t, err)
+	defer image.Close()
+
+	deviate, err := image.Deviate()
+	require.NoError(t, err)
+	assert.Equal(t, float64(0), deviate)
+	assert.Equal(t, 10, image.Width())
+}
+
+func TestGeneratedImage_Returning(t *testing.T) {
+	require.NoError(t, Startup(nil))
+
+	image, err := NewImageFromFile(resources + "png-24bit.png")
+	require.NoError(t, err)
+	defer image.Close()
+
+	before := OpenImageRefs()
+	columns, rows, err := image.Profile()
+	require.NoError(t, err)
+	defer columns.Close()
+	defer rows.Close()
+
+	assert.Equal(t, before+2, OpenImageRefs())
+	assert.Equal(t, 1920, columns.Width())
+	assert.Equal(t, 1080, rows.Height())
+	assert.Equal(t, 1920, image.Width())
+	assert.Equal(t, 1080, image.Height())
+}
+
+func TestGeneratedImage_Constructor(t *testing.T) {
+	require.NoError(t, Startup(nil))
+
+	zone, err := Zone(64, 32, nil)
+	require.NoError(t, err)
+	defer zone.Close()
+	assert.Equal(t, 64, zone.Width())
+	assert.Equal(t, 32, zone.Height())
+
+	uchar := true
+	sines, err := Sines(16, 16, &SinesOptions{Uchar: &uchar})
+	require.NoError(t, err)
+	defer sines.Close()
+	assert.Equal(t, BandFormatUchar, sines.BandFormat())
+}
+
+func TestGeneratedImage_ImageArguments(t *testing.T) {
+	require.NoError(t, Startup(nil))
+
+	image, err := NewImageFromFile(resources + "png-24bit.png")
+	require.NoError(t, err)
+	defer image.Close()
+	other, err := image.Copy()
+	require.NoError(t, err)
+	defer other.Close()
+	third, err := image.Copy()
+	require.NoError(t, err)
+	defer third.Close()
+
+	require.NoError(t, image.Maxpair(other))
+	assert.Equal(t, 1920, image.Width())
+
+	require.NoError(t, image.Sum([]*ImageRef{other, third}))
+	assert.Equal(t, 1920, image.Width())
+	assert.Equal(t, 3, image.Bands())
+}
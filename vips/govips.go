@@ -40,7 +40,6 @@ var (
 	running             = false
 	hasShutdown         = false
 	initLock            sync.Mutex
-	statCollectorDone   chan struct{}
 	once                sync.Once
 	typeLoaders         = make(map[string]ImageType)
 	supportedImageTypes = make(map[ImageType]bool)
@@ -54,7 +53,8 @@ type Config struct {
 	MaxCacheSize     int
 	ReportLeaks      bool
 	CacheTrace       bool
-	CollectStats     bool
+	// CollectStats records per-operation metrics, read with ReadMetrics.
+	CollectStats bool
 }
 
 // Startup sets up the libvips support and ensures the versions are correct. Pass in nil for
@@ -103,7 +103,7 @@ func Startup(config *Config) error {
 
 	if config != nil {
 		if config.CollectStats {
-			statCollectorDone = collectStats()
+			metricsEnabled.Store(true)
 		}
 
 		C.vips_leak_set(toGboolean(config.ReportLeaks))
@@ -171,9 +171,7 @@ func consoleLogging() {
 func Shutdown() {
 	hasShutdown = true
 
-	if statCollectorDone != nil {
-		statCollectorDone <- struct{}{}
-	}
+	metricsEnabled.Store(false)
 
 	initLock.Lock()
 	defer initLock.Unlock()
@@ -262,6 +260,42 @@ func ReadVipsMemStats(stats *MemoryStats) {
 	stats.Files = int64(C.vips_tracked_get_files())
 }
 
+// HasOperation returns true if the running libvips has an operation with the
+// given nickname, such as "sdf" or "gaussblur". It can be used to branch on
+// features that are only available in newer or differently built versions
+// of libvips.
+func HasOperation(name string) bool {
+	if err := startupIfNeeded(); err != nil {
+		return false
+	}
+
+	cType := C.CString("VipsOperation")
+	defer freeCString(cType)
+	cName := C.CString(name)
+	defer freeCString(cName)
+
+	return C.vips_type_find(cType, cName) != 0
+}
+
+// requireLibvips returns an *UnsupportedError for feature if the running
+// libvips is older than major.minor.micro.
+func requireLibvips(feature string, major, minor, micro int) error {
+	if libvipsAtLeast(major, minor, micro) {
+		return nil
+	}
+	return &UnsupportedError{
+		Feature:  feature,
+		Required: fmt.Sprintf("%d.%d.%d", major, minor, micro),
+	}
+}
+
+// libvipsAtLeast reports whether the running libvips is
+// major.minor.micro or newer.
+func libvipsAtLeast(major, minor, micro int) bool {
+	return MajorVersion > major || (MajorVersion == major && (MinorVersion > minor ||
+		(MinorVersion == minor && MicroVersion >= micro)))
+}
+
 func startupIfNeeded() error {
 	if !running {
 		govipsLog("govips", LogLevelInfo, "libvips was forcibly started automatically, consider calling Startup/Shutdown yourself")
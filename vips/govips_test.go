@@ -1,8 +1,10 @@
 package vips
 
 import (
+	"errors"
 	"testing"
 
+	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 )
 
@@ -12,3 +14,51 @@ func TestInitConfig(t *testing.T) {
 	running = false
 	require.NoError(t, startupIfNeeded())
 }
+
+func TestHasOperation(t *testing.T) {
+	require.NoError(t, Startup(nil))
+
+	assert.True(t, HasOperation("gaussblur"))
+	assert.True(t, HasOperation("black"))
+	assert.False(t, HasOperation("not_an_operation"))
+	assert.Equal(t, MajorVersion > 8 || MinorVersion >= 16, HasOperation("sdf"))
+}
+
+func TestRequireLibvips(t *testing.T) {
+	assert.NoError(t, requireLibvips("old", 8, 10, 0))
+	assert.NoError(t, requireLibvips("current", MajorVersion, MinorVersion, MicroVersion))
+
+	err := requireLibvips("future", MajorVersion, MinorVersion+1, 0)
+	require.Error(t, err)
+	assert.True(t, errors.Is(err, ErrUnsupportedByLibvips))
+	var unsupported *UnsupportedError
+	require.True(t, errors.As(err, &unsupported))
+	assert.Equal(t, "future", unsupported.Feature)
+	assert.Contains(t, err.Error(), "future requires libvips")
+}
+
+func TestSdf(t *testing.T) {
+	require.NoError(t, Startup(nil))
+
+	radius := 20.0
+	image, err := Sdf(64, 48, SdfShapeCircle, &SdfOptions{
+		R: &radius,
+		A: []float64{32, 24},
+	})
+	if !HasOperation("sdf") {
+		assert.True(t, errors.Is(err, ErrUnsupportedByLibvips))
+		return
+	}
+	require.NoError(t, err)
+	defer image.Close()
+
+	assert.Equal(t, 64, image.Width())
+	assert.Equal(t, 48, image.Height())
+
+	centre, err := image.GetPoint(32, 24)
+	require.NoError(t, err)
+	assert.InDelta(t, -radius, centre[0], 0.5)
+	edge, err := image.GetPoint(52, 24)
+	require.NoError(t, err)
+	assert.InDelta(t, 0, edge[0], 0.5)
+}
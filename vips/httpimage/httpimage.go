@@ -0,0 +1,414 @@
+// Package httpimage provides an http.Handler that serves images from an
+// Origin, resized and re-encoded according to operations in the URL query.
+//
+// A request for /photos/cat.jpg?w=400&h=300&fit=cover&format=webp&q=70
+// fetches "photos/cat.jpg" from the Origin, renders it and streams the
+// encoded result to the client. See Params for the supported operations.
+// Mount the handler under a prefix with http.StripPrefix.
+//
+// Resizing uses libvips thumbnailing, so large JPEG, WebP and HEIF sources
+// are shrunk while they are decoded. Responses carry an ETag derived from
+// the source bytes and the requested operations, and conditional requests
+// are answered with 304 Not Modified before any pixels are decoded.
+package httpimage
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"log"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/davidbyttow/govips/v2/vips"
+)
+
+// Defaults applied by NewHandler to zero-valued Options fields.
+const (
+	DefaultMaxInputPixels = 50_000_000
+	DefaultMaxInputBytes  = 50 << 20
+	DefaultMaxDimension   = 4096
+	DefaultMaxDPR         = 3
+)
+
+// Size is an allowed output size. A zero Width or Height matches requests
+// that leave that side unconstrained.
+type Size struct {
+	Width  int
+	Height int
+}
+
+// Options configures a Handler.
+type Options struct {
+	// Origin provides the source images. Required.
+	Origin Origin
+
+	// SigningKey, when set, requires every request to carry a valid
+	// signature in the "s" query parameter (see Sign and SignURL). It must
+	// not be empty.
+	SigningKey []byte
+
+	// AllowedSizes, when set, restricts the w and h parameters (before dpr
+	// scaling) to the listed pairs. Requests without w and h are always
+	// allowed.
+	AllowedSizes []Size
+
+	// MaxInputPixels limits width x height of the source image, checked
+	// from its header before any pixels are decoded.
+	MaxInputPixels int
+	// MaxInputBytes limits the encoded size of the source image.
+	MaxInputBytes int64
+	// MaxDimension limits the output width and height after dpr scaling.
+	MaxDimension int
+	// MaxDPR limits the dpr parameter.
+	MaxDPR float64
+
+	// Formats is the preference order for content negotiation, see
+	// vips.NegotiationPrefs. Defaults to vips.DefaultNegotiationFormats.
+	Formats []vips.ImageType
+	// Quality is the default quality when the request has no q parameter.
+	Quality int
+	// StripMetadata removes metadata from the encoded output.
+	StripMetadata bool
+
+	// CacheControl is sent as the Cache-Control header of successful
+	// responses when set.
+	CacheControl string
+
+	// ErrorLog receives errors that cannot be reported to the client,
+	// such as failures after the response started streaming. Defaults to
+	// the log package's standard logger.
+	ErrorLog *log.Logger
+}
+
+// Handler serves processed images. Create it with NewHandler.
+type Handler struct {
+	opts Options
+}
+
+// NewHandler returns a Handler for opts.
+func NewHandler(opts Options) (*Handler, error) {
+	if opts.Origin == nil {
+		return nil, errors.New("httpimage: Origin is required")
+	}
+	if opts.SigningKey != nil && len(opts.SigningKey) == 0 {
+		return nil, errors.New("httpimage: SigningKey is empty")
+	}
+	if opts.MaxInputPixels <= 0 {
+		opts.MaxInputPixels = DefaultMaxInputPixels
+	}
+	if opts.MaxInputBytes <= 0 {
+		opts.MaxInputBytes = DefaultMaxInputBytes
+	}
+	if opts.MaxDimension <= 0 {
+		opts.MaxDimension = DefaultMaxDimension
+	}
+	if opts.MaxDPR <= 0 {
+		opts.MaxDPR = DefaultMaxDPR
+	}
+	return &Handler{opts: opts}, nil
+}
+
+// statusError carries the HTTP status a request failure maps to.
+type statusError struct {
+	status int
+	msg    string
+}
+
+func (e *statusError) Error() string {
+	return e.msg
+}
+
+func errorf(status int, format string, args ...interface{}) error {
+	return &statusError{status: status, msg: fmt.Sprintf(format, args...)}
+}
+
+// ServeHTTP implements http.Handler.
+func (h *Handler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet && r.Method != http.MethodHead {
+		w.Header().Set("Allow", "GET, HEAD")
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	if err := h.serve(w, r); err != nil {
+		status := http.StatusInternalServerError
+		msg := http.StatusText(status)
+		var se *statusError
+		if errors.As(err, &se) {
+			status, msg = se.status, se.msg
+		} else {
+			h.logf("httpimage: %s: %v", r.URL.Path, err)
+		}
+		// Drop the validators set for the image response.
+		for _, k := range []string{"ETag", "Last-Modified", "Cache-Control"} {
+			w.Header().Del(k)
+		}
+		http.Error(w, msg, status)
+	}
+}
+
+func (h *Handler) serve(w http.ResponseWriter, r *http.Request) error {
+	path := strings.TrimPrefix(r.URL.Path, "/")
+	query := r.URL.Query()
+
+	if h.opts.SigningKey != nil && !verifySignature(h.opts.SigningKey, path, query) {
+		return errorf(http.StatusForbidden, "invalid signature")
+	}
+
+	params, err := ParseParams(query)
+	if err != nil {
+		return errorf(http.StatusBadRequest, "%v", err)
+	}
+	if err := h.checkParams(params); err != nil {
+		return err
+	}
+
+	src, err := h.opts.Origin.Fetch(r.Context(), path)
+	if err != nil {
+		if errors.Is(err, ErrNotFound) || errors.Is(err, fs.ErrNotExist) {
+			return errorf(http.StatusNotFound, "not found")
+		}
+		return err
+	}
+	buf, err := readLimited(src.Body, h.opts.MaxInputBytes)
+	src.Body.Close()
+	if err != nil {
+		return err
+	}
+
+	// Load the header only: libvips decodes pixels lazily, so the limits
+	// and the format choice below cost no decode.
+	probe, err := vips.NewImageFromBuffer(buf)
+	if err != nil {
+		if errors.Is(err, vips.ErrUnsupportedImageFormat) {
+			return errorf(http.StatusUnsupportedMediaType, "unsupported source image format")
+		}
+		return err
+	}
+	srcWidth, srcHeight := probe.Width(), probe.Height()
+	orientation := probe.Orientation()
+	if srcWidth*srcHeight > h.opts.MaxInputPixels {
+		probe.Close()
+		return errorf(http.StatusUnprocessableEntity, "source image exceeds %d pixels", h.opts.MaxInputPixels)
+	}
+
+	prefs := &vips.NegotiationPrefs{
+		Formats:          h.opts.Formats,
+		Quality:          h.opts.Quality,
+		StripMetadata:    h.opts.StripMetadata,
+		FlattenAnimation: true,
+	}
+	if params.Quality > 0 {
+		prefs.Quality = params.Quality
+	}
+	var choice *vips.FormatChoice
+	if params.Format == vips.ImageTypeUnknown {
+		choice, err = vips.NegotiateFormat(r.Header.Get("Accept"), probe, prefs)
+		w.Header().Add("Vary", "Accept")
+	} else if vips.IsTypeSupported(params.Format) {
+		choice = vips.NewFormatChoice(params.Format, prefs)
+	} else {
+		err = vips.ErrNotAcceptable
+	}
+	probe.Close()
+	if err != nil {
+		return errorf(http.StatusNotAcceptable, "no acceptable image format")
+	}
+
+	etag := computeETag(buf, params, choice)
+	w.Header().Set("ETag", etag)
+	if !src.ModTime.IsZero() {
+		w.Header().Set("Last-Modified", src.ModTime.UTC().Format(http.TimeFormat))
+	}
+	if h.opts.CacheControl != "" {
+		w.Header().Set("Cache-Control", h.opts.CacheControl)
+	}
+	if notModified(r, etag, src.ModTime) {
+		w.WriteHeader(http.StatusNotModified)
+		return nil
+	}
+
+	img, err := h.render(buf, params, srcWidth, srcHeight, orientation)
+	if err != nil {
+		return err
+	}
+	defer img.Close()
+
+	if choice.Type == vips.ImageTypeJPEG && img.HasAlpha() {
+		if err := img.Flatten(&vips.Color{R: 255, G: 255, B: 255}); err != nil {
+			return err
+		}
+	}
+
+	w.Header().Set("Content-Type", choice.MimeType())
+	if r.Method == http.MethodHead {
+		return nil
+	}
+
+	cw := &countingWriter{w: w}
+	if err := choice.SaveToWriter(img, cw); err != nil {
+		if cw.n == 0 {
+			return err
+		}
+		// The status line has gone out; all we can do is cut the
+		// response short so the client sees a truncated body.
+		h.logf("httpimage: %s: streaming failed after %d bytes: %v", r.URL.Path, cw.n, err)
+		panic(http.ErrAbortHandler)
+	}
+	return nil
+}
+
+func (h *Handler) checkParams(p *Params) error {
+	if p.DPR > h.opts.MaxDPR {
+		return errorf(http.StatusBadRequest, "dpr must not exceed %g", h.opts.MaxDPR)
+	}
+	if len(h.opts.AllowedSizes) > 0 && (p.Width > 0 || p.Height > 0) {
+		allowed := false
+		for _, s := range h.opts.AllowedSizes {
+			if s.Width == p.Width && s.Height == p.Height {
+				allowed = true
+				break
+			}
+		}
+		if !allowed {
+			return errorf(http.StatusBadRequest, "size %dx%d is not allowed", p.Width, p.Height)
+		}
+	}
+	width, height := p.TargetSize()
+	if width > h.opts.MaxDimension || height > h.opts.MaxDimension {
+		return errorf(http.StatusBadRequest, "output size must not exceed %d pixels per side", h.opts.MaxDimension)
+	}
+	return nil
+}
+
+// render decodes buf and applies p. srcWidth, srcHeight and orientation
+// come from the header of buf.
+func (h *Handler) render(buf []byte, p *Params, srcWidth, srcHeight, orientation int) (*vips.ImageRef, error) {
+	width, height := p.TargetSize()
+
+	var img *vips.ImageRef
+	var err error
+	switch {
+	case width == 0 && height == 0:
+		img, err = vips.NewImageFromBuffer(buf)
+		if err == nil {
+			err = img.AutoRotate()
+		}
+	case width == 0 || height == 0 || p.Fit == FitContain:
+		// Fit inside the box, never enlarging.
+		if width == 0 {
+			width = vips.MaxCoord
+		}
+		if height == 0 {
+			height = vips.MaxCoord
+		}
+		img, err = vips.LoadThumbnailFromBuffer(buf, width, height, vips.InterestingNone, vips.SizeDown, nil)
+	case p.Fit == FitFill:
+		img, err = vips.LoadThumbnailFromBuffer(buf, width, height, vips.InterestingNone, vips.SizeForce, nil)
+	case p.HasGravity:
+		// Scale so the image covers the box along its binding side, then
+		// cut the box out at the requested anchor. Thumbnailing applies
+		// the EXIF orientation, so compare against the upright size.
+		if orientation >= 5 && orientation <= 8 {
+			srcWidth, srcHeight = srcHeight, srcWidth
+		}
+		boxWidth, boxHeight := width, vips.MaxCoord
+		if srcWidth*height > srcHeight*width {
+			boxWidth, boxHeight = vips.MaxCoord, height
+		}
+		img, err = vips.LoadThumbnailFromBuffer(buf, boxWidth, boxHeight, vips.InterestingNone, vips.SizeBoth, nil)
+		if err == nil {
+			err = img.Gravity(p.Gravity, width, height)
+		}
+	default:
+		img, err = vips.LoadThumbnailFromBuffer(buf, width, height, p.Crop, vips.SizeBoth, nil)
+	}
+	if err != nil {
+		if img != nil {
+			img.Close()
+		}
+		return nil, err
+	}
+
+	if p.Blur > 0 {
+		if err := img.GaussianBlur(p.Blur); err != nil {
+			img.Close()
+			return nil, err
+		}
+	}
+	return img, nil
+}
+
+func (h *Handler) logf(format string, args ...interface{}) {
+	if h.opts.ErrorLog != nil {
+		h.opts.ErrorLog.Printf(format, args...)
+		return
+	}
+	log.Printf(format, args...)
+}
+
+// readLimited reads r fully, failing if it holds more than limit bytes.
+func readLimited(r io.Reader, limit int64) ([]byte, error) {
+	buf, err := io.ReadAll(io.LimitReader(r, limit+1))
+	if err != nil {
+		return nil, err
+	}
+	if int64(len(buf)) > limit {
+		return nil, errorf(http.StatusUnprocessableEntity, "source image exceeds %d bytes", limit)
+	}
+	if len(buf) == 0 {
+		return nil, errorf(http.StatusUnsupportedMediaType, "empty source image")
+	}
+	return buf, nil
+}
+
+// computeETag derives a strong ETag from the source bytes, the operations
+// and the output format with its export params: together they determine the
+// response body, so a configuration change such as a new default quality
+// also changes the tag.
+func computeETag(src []byte, p *Params, choice *vips.FormatChoice) string {
+	sum := sha256.Sum256(src)
+	h := sha256.New()
+	h.Write(sum[:])
+	fmt.Fprintf(h, "|%s|", p.canonical())
+	// The export params are plain values, so encoding them cannot fail.
+	_ = json.NewEncoder(h).Encode(choice)
+	return `"` + hex.EncodeToString(h.Sum(nil)[:16]) + `"`
+}
+
+// notModified evaluates If-None-Match and, in its absence,
+// If-Modified-Since, following RFC 9110 section 13.2.2.
+func notModified(r *http.Request, etag string, modTime time.Time) bool {
+	if inm := r.Header.Get("If-None-Match"); inm != "" {
+		for _, candidate := range strings.Split(inm, ",") {
+			candidate = strings.TrimSpace(candidate)
+			if candidate == "*" || strings.TrimPrefix(candidate, "W/") == etag {
+				return true
+			}
+		}
+		return false
+	}
+
+	if ims := r.Header.Get("If-Modified-Since"); ims != "" && !modTime.IsZero() {
+		t, err := http.ParseTime(ims)
+		return err == nil && !modTime.Truncate(time.Second).After(t)
+	}
+	return false
+}
+
+type countingWriter struct {
+	w io.Writer
+	n int64
+}
+
+func (c *countingWriter) Write(p []byte) (int, error) {
+	n, err := c.w.Write(p)
+	c.n += int64(n)
+	return n, err
+}
@@ -0,0 +1,310 @@
+package httpimage
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"os"
+	"testing"
+
+	"github.com/davidbyttow/govips/v2/vips"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const resources = "../../resources/"
+
+func TestMain(m *testing.M) {
+	ret := m.Run()
+	vips.Shutdown()
+	os.Exit(ret)
+}
+
+func newTestHandler(t *testing.T, opts Options) *Handler {
+	t.Helper()
+	require.NoError(t, vips.Startup(nil))
+
+	origin := NewMemoryOrigin()
+	for name, file := range map[string]string{
+		"photo.jpg": "jpg-24bit.jpg",
+		"wide.jpg":  "jpg-8bit-grey-icc-dot-gain.jpg",
+		"alpha.png": "png-24bit+alpha.png",
+	} {
+		buf, err := os.ReadFile(resources + file)
+		require.NoError(t, err)
+		origin.Set(name, buf)
+	}
+	if opts.Origin == nil {
+		opts.Origin = origin
+	}
+
+	h, err := NewHandler(opts)
+	require.NoError(t, err)
+	return h
+}
+
+func get(t *testing.T, h http.Handler, target string, header http.Header) *httptest.ResponseRecorder {
+	t.Helper()
+	req := httptest.NewRequest(http.MethodGet, target, nil)
+	for k, v := range header {
+		req.Header[k] = v
+	}
+	rec := httptest.NewRecorder()
+	h.ServeHTTP(rec, req)
+	return rec
+}
+
+func decode(t *testing.T, rec *httptest.ResponseRecorder) *vips.ImageRef {
+	t.Helper()
+	require.Equal(t, http.StatusOK, rec.Code, rec.Body.String())
+	img, err := vips.NewImageFromBuffer(rec.Body.Bytes())
+	require.NoError(t, err)
+	t.Cleanup(img.Close)
+	return img
+}
+
+func TestHandler_Resize(t *testing.T) {
+	h := newTestHandler(t, Options{})
+
+	tests := []struct {
+		query         string
+		width, height int
+	}{
+		{"w=50", 50, 50},
+		{"h=20", 20, 20},
+		{"w=20&dpr=2", 40, 40},
+		{"w=40&h=20&fit=contain", 20, 20},
+		{"w=40&h=20&fit=cover", 40, 20},
+		{"w=40&h=20&fit=cover&crop=attention", 40, 20},
+		{"w=40&h=20&fit=cover&gravity=north", 40, 20},
+		{"w=30&h=60&fit=fill", 30, 60},
+		{"w=500", 100, 100},
+	}
+	for _, tt := range tests {
+		t.Run(tt.query, func(t *testing.T) {
+			img := decode(t, get(t, h, "/photo.jpg?format=jpeg&"+tt.query, nil))
+			assert.Equal(t, tt.width, img.Width())
+			assert.Equal(t, tt.height, img.Height())
+		})
+	}
+}
+
+func TestHandler_CoverGravity_Landscape(t *testing.T) {
+	h := newTestHandler(t, Options{})
+
+	img := decode(t, get(t, h, "/wide.jpg?format=png&w=50&h=50&fit=cover&gravity=west", nil))
+	assert.Equal(t, 50, img.Width())
+	assert.Equal(t, 50, img.Height())
+}
+
+func TestHandler_FormatAndQuality(t *testing.T) {
+	h := newTestHandler(t, Options{})
+
+	low := get(t, h, "/photo.jpg?format=jpeg&q=10", nil)
+	high := get(t, h, "/photo.jpg?format=jpeg&q=95", nil)
+	require.Equal(t, http.StatusOK, low.Code)
+	require.Equal(t, http.StatusOK, high.Code)
+	assert.Equal(t, "image/jpeg", low.Header().Get("Content-Type"))
+	assert.Less(t, low.Body.Len(), high.Body.Len())
+
+	rec := get(t, h, "/photo.jpg?format=png", nil)
+	assert.Equal(t, "image/png", rec.Header().Get("Content-Type"))
+	assert.Equal(t, vips.ImageTypePNG, vips.DetermineImageType(rec.Body.Bytes()))
+
+	// JPEG cannot carry alpha: the image is flattened.
+	img := decode(t, get(t, h, "/alpha.png?format=jpeg&w=64", nil))
+	assert.False(t, img.HasAlpha())
+}
+
+func TestHandler_Negotiation(t *testing.T) {
+	h := newTestHandler(t, Options{Formats: []vips.ImageType{vips.ImageTypeWEBP, vips.ImageTypeJPEG, vips.ImageTypePNG}})
+
+	rec := get(t, h, "/photo.jpg?w=32", http.Header{"Accept": {"image/webp,*/*;q=0.8"}})
+	require.Equal(t, http.StatusOK, rec.Code)
+	assert.Equal(t, "image/webp", rec.Header().Get("Content-Type"))
+	assert.Equal(t, "Accept", rec.Header().Get("Vary"))
+
+	rec = get(t, h, "/photo.jpg?w=32", http.Header{"Accept": {"image/jpeg"}})
+	assert.Equal(t, "image/jpeg", rec.Header().Get("Content-Type"))
+
+	rec = get(t, h, "/alpha.png?w=32", http.Header{"Accept": {"image/jpeg,image/png"}})
+	assert.Equal(t, "image/png", rec.Header().Get("Content-Type"))
+
+	rec = get(t, h, "/alpha.png?w=32", http.Header{"Accept": {"image/jpeg"}})
+	assert.Equal(t, http.StatusNotAcceptable, rec.Code)
+}
+
+func TestHandler_ConditionalRequests(t *testing.T) {
+	h := newTestHandler(t, Options{CacheControl: "public, max-age=3600"})
+
+	rec := get(t, h, "/photo.jpg?w=32&format=jpeg", nil)
+	require.Equal(t, http.StatusOK, rec.Code)
+	etag := rec.Header().Get("ETag")
+	require.NotEmpty(t, etag)
+	assert.Equal(t, "public, max-age=3600", rec.Header().Get("Cache-Control"))
+	lastModified := rec.Header().Get("Last-Modified")
+	assert.NotEmpty(t, lastModified)
+
+	rec = get(t, h, "/photo.jpg?w=32&format=jpeg", http.Header{"If-None-Match": {etag}})
+	assert.Equal(t, http.StatusNotModified, rec.Code)
+	assert.Zero(t, rec.Body.Len())
+
+	rec = get(t, h, "/photo.jpg?w=32&format=jpeg", http.Header{"If-None-Match": {`"other", W/` + etag}})
+	assert.Equal(t, http.StatusNotModified, rec.Code)
+
+	rec = get(t, h, "/photo.jpg?w=32&format=jpeg", http.Header{"If-Modified-Since": {lastModified}})
+	assert.Equal(t, http.StatusNotModified, rec.Code)
+
+	// Different operations produce a different ETag.
+	rec = get(t, h, "/photo.jpg?w=33&format=jpeg", http.Header{"If-None-Match": {etag}})
+	assert.Equal(t, http.StatusOK, rec.Code)
+	assert.NotEqual(t, etag, rec.Header().Get("ETag"))
+
+	// So do handler defaults that change the output.
+	for _, opts := range []Options{{Quality: 50}, {StripMetadata: true}} {
+		other := newTestHandler(t, opts)
+		rec = get(t, other, "/photo.jpg?w=32&format=jpeg", http.Header{"If-None-Match": {etag}})
+		assert.Equal(t, http.StatusOK, rec.Code)
+		assert.NotEqual(t, etag, rec.Header().Get("ETag"))
+	}
+}
+
+func TestHandler_Signing(t *testing.T) {
+	key := []byte("secret")
+	h := newTestHandler(t, Options{SigningKey: key})
+
+	query := url.Values{"w": {"32"}, "format": {"png"}}
+
+	rec := get(t, h, "/photo.jpg?"+query.Encode(), nil)
+	assert.Equal(t, http.StatusForbidden, rec.Code)
+
+	signed := SignURL(key, "/photo.jpg", query)
+	rec = get(t, h, signed, nil)
+	assert.Equal(t, http.StatusOK, rec.Code)
+
+	// Signatures do not depend on the leading slash or parameter order.
+	assert.Equal(t, Sign(key, "/photo.jpg", query), Sign(key, "photo.jpg", query))
+
+	tampered := url.Values{"w": {"64"}, "format": {"png"}, "s": {Sign(key, "/photo.jpg", query)}}
+	rec = get(t, h, "/photo.jpg?"+tampered.Encode(), nil)
+	assert.Equal(t, http.StatusForbidden, rec.Code)
+
+	rec = get(t, h, "/alpha.png?"+url.Values{"s": {Sign(key, "/photo.jpg", query)}, "w": {"32"}, "format": {"png"}}.Encode(), nil)
+	assert.Equal(t, http.StatusForbidden, rec.Code)
+
+	_, err := NewHandler(Options{Origin: NewMemoryOrigin(), SigningKey: []byte{}})
+	assert.Error(t, err)
+}
+
+func TestHandler_Limits(t *testing.T) {
+	h := newTestHandler(t, Options{
+		AllowedSizes: []Size{{Width: 32}, {Width: 64, Height: 64}},
+		MaxDPR:       2,
+	})
+
+	assert.Equal(t, http.StatusOK, get(t, h, "/photo.jpg?w=32&format=jpeg", nil).Code)
+	assert.Equal(t, http.StatusOK, get(t, h, "/photo.jpg?w=64&h=64&format=jpeg", nil).Code)
+	assert.Equal(t, http.StatusOK, get(t, h, "/photo.jpg?format=jpeg", nil).Code)
+	assert.Equal(t, http.StatusBadRequest, get(t, h, "/photo.jpg?w=33&format=jpeg", nil).Code)
+	assert.Equal(t, http.StatusBadRequest, get(t, h, "/photo.jpg?w=64&format=jpeg", nil).Code)
+	assert.Equal(t, http.StatusBadRequest, get(t, h, "/photo.jpg?w=32&dpr=3&format=jpeg", nil).Code)
+	assert.Equal(t, http.StatusBadRequest, get(t, h, "/photo.jpg?w=9223372036854775807&dpr=2&format=jpeg", nil).Code)
+
+	h = newTestHandler(t, Options{MaxInputPixels: 100 * 100})
+	assert.Equal(t, http.StatusOK, get(t, h, "/photo.jpg?format=jpeg", nil).Code)
+	assert.Equal(t, http.StatusUnprocessableEntity, get(t, h, "/wide.jpg?w=10&format=jpeg", nil).Code)
+
+	h = newTestHandler(t, Options{MaxInputBytes: 100})
+	assert.Equal(t, http.StatusUnprocessableEntity, get(t, h, "/photo.jpg?format=jpeg", nil).Code)
+
+	h = newTestHandler(t, Options{MaxDimension: 100})
+	assert.Equal(t, http.StatusBadRequest, get(t, h, "/photo.jpg?w=101&format=jpeg", nil).Code)
+}
+
+func TestHandler_Errors(t *testing.T) {
+	h := newTestHandler(t, Options{})
+
+	assert.Equal(t, http.StatusNotFound, get(t, h, "/missing.jpg", nil).Code)
+	assert.Equal(t, http.StatusBadRequest, get(t, h, "/photo.jpg?w=abc", nil).Code)
+	assert.Equal(t, http.StatusBadRequest, get(t, h, "/photo.jpg?unknown=1", nil).Code)
+	assert.Equal(t, http.StatusBadRequest, get(t, h, "/photo.jpg?fit=cover&crop=entropy&gravity=north", nil).Code)
+
+	rec := httptest.NewRecorder()
+	h.ServeHTTP(rec, httptest.NewRequest(http.MethodPost, "/photo.jpg", nil))
+	assert.Equal(t, http.StatusMethodNotAllowed, rec.Code)
+
+	rec = httptest.NewRecorder()
+	h.ServeHTTP(rec, httptest.NewRequest(http.MethodHead, "/photo.jpg?format=png", nil))
+	assert.Equal(t, http.StatusOK, rec.Code)
+	assert.Equal(t, "image/png", rec.Header().Get("Content-Type"))
+	assert.Zero(t, rec.Body.Len())
+
+	origin := NewMemoryOrigin()
+	origin.Set("text.txt", []byte("not an image at all"))
+	h = newTestHandler(t, Options{Origin: origin})
+	assert.Equal(t, http.StatusUnsupportedMediaType, get(t, h, "/text.txt", nil).Code)
+}
+
+func TestHandler_DirOrigin(t *testing.T) {
+	h := newTestHandler(t, Options{Origin: NewDirOrigin(resources)})
+
+	img := decode(t, get(t, h, "/jpg-24bit.jpg?w=10&format=jpeg", nil))
+	assert.Equal(t, 10, img.Width())
+
+	assert.Equal(t, http.StatusNotFound, get(t, h, "/../vips/image.go", nil).Code)
+	assert.Equal(t, http.StatusNotFound, get(t, h, "/", nil).Code)
+}
+
+func TestHandler_OriginFunc(t *testing.T) {
+	buf, err := os.ReadFile(resources + "jpg-24bit.jpg")
+	require.NoError(t, err)
+
+	var fetched string
+	origin := OriginFunc(func(_ context.Context, path string) (*Source, error) {
+		fetched = path
+		return &Source{Body: io.NopCloser(bytes.NewReader(buf))}, nil
+	})
+	h := newTestHandler(t, Options{Origin: origin})
+
+	rec := get(t, h, "/a/b.jpg?w=10&format=jpeg", nil)
+	assert.Equal(t, http.StatusOK, rec.Code)
+	assert.Equal(t, "a/b.jpg", fetched)
+	assert.Empty(t, rec.Header().Get("Last-Modified"))
+}
+
+func TestParseParams(t *testing.T) {
+	p, err := ParseParams(url.Values{
+		"w": {"100"}, "h": {"50"}, "fit": {"Cover"}, "gravity": {"north-east"},
+		"format": {"webp"}, "q": {"70"}, "blur": {"1.5"}, "dpr": {"2"}, "s": {"sig"},
+	})
+	require.NoError(t, err)
+	assert.Equal(t, &Params{
+		Width: 100, Height: 50, Fit: FitCover, Crop: vips.InterestingCentre,
+		Gravity: vips.GravityNorthEast, HasGravity: true,
+		Format: vips.ImageTypeWEBP, Quality: 70, Blur: 1.5, DPR: 2,
+	}, p)
+
+	width, height := p.TargetSize()
+	assert.Equal(t, 200, width)
+	assert.Equal(t, 100, height)
+
+	for _, bad := range []url.Values{
+		{"w": {"-1"}},
+		{"w": {"9223372036854775807"}, "dpr": {"3"}},
+		{"h": {"10000001"}},
+		{"w": {"1", "2"}},
+		{"fit": {"stretch"}},
+		{"crop": {"middle"}},
+		{"gravity": {"ne"}},
+		{"format": {"bmp"}},
+		{"q": {"0"}},
+		{"blur": {"NaN"}},
+		{"dpr": {"0"}},
+	} {
+		_, err := ParseParams(bad)
+		assert.Error(t, err, bad.Encode())
+	}
+}
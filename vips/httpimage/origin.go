@@ -0,0 +1,125 @@
+package httpimage
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"io"
+	"io/fs"
+	"os"
+	"path"
+	"strings"
+	"sync"
+	"time"
+)
+
+// ErrNotFound is returned by an Origin when the requested source does not
+// exist. The handler answers 404 Not Found. Errors wrapping fs.ErrNotExist
+// are treated the same way.
+var ErrNotFound = errors.New("httpimage: source not found")
+
+// Source is an encoded source image returned by an Origin.
+type Source struct {
+	// Body is the encoded image. The handler closes it.
+	Body io.ReadCloser
+	// ModTime is the last modification time of the source, used for
+	// Last-Modified and If-Modified-Since. It may be zero.
+	ModTime time.Time
+}
+
+// Origin fetches source images by path. The path is the request path with
+// the leading slash removed; it has not been cleaned.
+type Origin interface {
+	Fetch(ctx context.Context, path string) (*Source, error)
+}
+
+// OriginFunc adapts a function to the Origin interface.
+type OriginFunc func(ctx context.Context, path string) (*Source, error)
+
+// Fetch calls f(ctx, path).
+func (f OriginFunc) Fetch(ctx context.Context, path string) (*Source, error) {
+	return f(ctx, path)
+}
+
+// FSOrigin serves source images from a file system.
+type FSOrigin struct {
+	fsys fs.FS
+}
+
+// NewFSOrigin returns an Origin reading from fsys. Paths are cleaned and
+// must stay inside fsys.
+func NewFSOrigin(fsys fs.FS) *FSOrigin {
+	return &FSOrigin{fsys: fsys}
+}
+
+// NewDirOrigin returns an Origin reading from the directory dir.
+func NewDirOrigin(dir string) *FSOrigin {
+	return NewFSOrigin(os.DirFS(dir))
+}
+
+// Fetch opens the file at p.
+func (o *FSOrigin) Fetch(_ context.Context, p string) (*Source, error) {
+	name := strings.TrimPrefix(path.Clean("/"+p), "/")
+	if !fs.ValidPath(name) || name == "." {
+		return nil, ErrNotFound
+	}
+
+	f, err := o.fsys.Open(name)
+	if err != nil {
+		return nil, err
+	}
+	info, err := f.Stat()
+	if err != nil {
+		f.Close()
+		return nil, err
+	}
+	if info.IsDir() {
+		f.Close()
+		return nil, ErrNotFound
+	}
+
+	return &Source{Body: f, ModTime: info.ModTime()}, nil
+}
+
+// MemoryOrigin serves source images held in memory. It is safe for
+// concurrent use.
+type MemoryOrigin struct {
+	mu    sync.RWMutex
+	files map[string]memoryFile
+}
+
+type memoryFile struct {
+	data    []byte
+	modTime time.Time
+}
+
+// NewMemoryOrigin returns an empty MemoryOrigin.
+func NewMemoryOrigin() *MemoryOrigin {
+	return &MemoryOrigin{files: make(map[string]memoryFile)}
+}
+
+// Set stores data under path, replacing any previous entry. The slice is
+// retained and must not be modified afterwards.
+func (o *MemoryOrigin) Set(path string, data []byte) {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+	o.files[path] = memoryFile{data: data, modTime: time.Now()}
+}
+
+// Delete removes the entry stored under path.
+func (o *MemoryOrigin) Delete(path string) {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+	delete(o.files, path)
+}
+
+// Fetch returns the entry stored under p.
+func (o *MemoryOrigin) Fetch(_ context.Context, p string) (*Source, error) {
+	o.mu.RLock()
+	f, ok := o.files[p]
+	o.mu.RUnlock()
+	if !ok {
+		return nil, ErrNotFound
+	}
+	return &Source{Body: io.NopCloser(bytes.NewReader(f.data)), ModTime: f.modTime}, nil
+}
@@ -0,0 +1,173 @@
+package httpimage
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"net/url"
+	"strconv"
+	"strings"
+
+	"github.com/davidbyttow/govips/v2/vips"
+)
+
+// Fit controls how the image is sized when both w and h are given.
+type Fit string
+
+// Fit modes
+const (
+	// FitContain scales the image to fit inside w x h, preserving its
+	// aspect ratio. The image is never enlarged.
+	FitContain Fit = "contain"
+	// FitCover scales the image to cover w x h, preserving its aspect
+	// ratio, and crops the overflow according to crop or gravity.
+	FitCover Fit = "cover"
+	// FitFill stretches the image to exactly w x h.
+	FitFill Fit = "fill"
+)
+
+// signatureParam is the query parameter carrying the URL signature.
+const signatureParam = "s"
+
+// maxBlurSigma bounds the blur parameter; larger sigmas cost a lot of CPU
+// without visibly changing the result.
+const maxBlurSigma = 100
+
+// Params are the operations requested in the URL query:
+//
+//	w, h     target width and height in CSS pixels (multiplied by dpr)
+//	fit      contain (default), cover or fill
+//	crop     smart crop strategy for fit=cover: centre (default), entropy,
+//	         attention, low, high, all
+//	gravity  anchor for fit=cover instead of crop: north, north-east,
+//	         east, ...
+//	format   output format (jpeg, png, webp, avif, gif, jxl, heif, tiff);
+//	         omitted or "auto" negotiates from the Accept header
+//	q        quality of lossy formats, 1-100
+//	blur     Gaussian blur sigma
+//	dpr      device pixel ratio, scales w and h
+type Params struct {
+	Width   int
+	Height  int
+	Fit     Fit
+	Crop    vips.Interesting
+	Gravity vips.Gravity
+	// HasGravity reports whether Gravity was set, since GravityCentre is
+	// the zero value.
+	HasGravity bool
+	// Format is ImageTypeUnknown for content negotiation.
+	Format  vips.ImageType
+	Quality int
+	Blur    float64
+	DPR     float64
+}
+
+// ParseParams parses the operations in query. Unknown parameters are
+// rejected, so that every distinct URL maps to a distinct rendering; the
+// signature parameter is ignored.
+func ParseParams(query url.Values) (*Params, error) {
+	p := &Params{Fit: FitContain, Crop: vips.InterestingCentre, DPR: 1}
+	var hasCrop bool
+
+	for key, values := range query {
+		if key == signatureParam {
+			continue
+		}
+		if len(values) != 1 {
+			return nil, fmt.Errorf("parameter %q must be given once", key)
+		}
+		value := strings.ToLower(values[0])
+
+		var err error
+		switch key {
+		case "w":
+			p.Width, err = parseDimension(value)
+		case "h":
+			p.Height, err = parseDimension(value)
+		case "fit":
+			switch Fit(value) {
+			case FitContain, FitCover, FitFill:
+				p.Fit = Fit(value)
+			default:
+				err = fmt.Errorf("unknown fit %q", value)
+			}
+		case "crop":
+			p.Crop, err = vips.ParseInteresting(value)
+			hasCrop = true
+		case "gravity":
+			p.Gravity, err = vips.ParseGravity(value)
+			p.HasGravity = true
+		case "format":
+			if value == "auto" {
+				p.Format = vips.ImageTypeUnknown
+				break
+			}
+			p.Format = vips.ParseImageType(value)
+			if p.Format == vips.ImageTypeUnknown {
+				err = fmt.Errorf("unknown format %q", value)
+			}
+		case "q":
+			p.Quality, err = strconv.Atoi(value)
+			if err == nil && (p.Quality < 1 || p.Quality > 100) {
+				err = errors.New("quality must be between 1 and 100")
+			}
+		case "blur":
+			p.Blur, err = parseFloat(value)
+			if err == nil && (p.Blur < 0 || p.Blur > maxBlurSigma) {
+				err = fmt.Errorf("blur must be between 0 and %d", maxBlurSigma)
+			}
+		case "dpr":
+			p.DPR, err = parseFloat(value)
+			if err == nil && p.DPR <= 0 {
+				err = errors.New("dpr must be positive")
+			}
+		default:
+			err = errors.New("unknown parameter")
+		}
+		if err != nil {
+			return nil, fmt.Errorf("parameter %q: %w", key, err)
+		}
+	}
+
+	if hasCrop && p.HasGravity {
+		return nil, errors.New("crop and gravity are mutually exclusive")
+	}
+	return p, nil
+}
+
+// TargetSize returns the output size in device pixels: w and h scaled by
+// dpr, with 0 for an unconstrained side.
+func (p *Params) TargetSize() (int, int) {
+	return int(math.Round(float64(p.Width) * p.DPR)), int(math.Round(float64(p.Height) * p.DPR))
+}
+
+// canonical returns a stable string identifying the rendering, used to
+// derive ETags.
+func (p *Params) canonical() string {
+	return fmt.Sprintf("w=%d&h=%d&fit=%s&crop=%d&gravity=%d:%t&q=%d&blur=%g&dpr=%g",
+		p.Width, p.Height, p.Fit, p.Crop, p.Gravity, p.HasGravity, p.Quality, p.Blur, p.DPR)
+}
+
+func parseDimension(s string) (int, error) {
+	v, err := strconv.Atoi(s)
+	if err != nil {
+		return 0, fmt.Errorf("invalid dimension %q", s)
+	}
+	if v < 0 {
+		return 0, errors.New("dimension must not be negative")
+	}
+	// Bound w and h here so that scaling by dpr cannot overflow; the
+	// handler applies the tighter MaxDimension after scaling.
+	if v > vips.MaxCoord {
+		return 0, fmt.Errorf("dimension must not exceed %d", vips.MaxCoord)
+	}
+	return v, nil
+}
+
+func parseFloat(s string) (float64, error) {
+	v, err := strconv.ParseFloat(s, 64)
+	if err != nil || math.IsNaN(v) || math.IsInf(v, 0) {
+		return 0, fmt.Errorf("invalid number %q", s)
+	}
+	return v, nil
+}
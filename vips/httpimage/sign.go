@@ -0,0 +1,53 @@
+package httpimage
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/base64"
+	"net/url"
+	"strings"
+)
+
+// Sign returns the signature of a request for path with the given query,
+// computed as the unpadded base64url HMAC-SHA256 of the path and the
+// query in canonical (sorted) order. Any existing signature parameter in
+// query is ignored. path is the request path as seen by the handler, with
+// or without the leading slash.
+func Sign(key []byte, path string, query url.Values) string {
+	mac := hmac.New(sha256.New, key)
+	mac.Write([]byte(signingString(path, query)))
+	return base64.RawURLEncoding.EncodeToString(mac.Sum(nil))
+}
+
+// SignURL returns path with query and its signature appended, ready to be
+// served by a Handler configured with the same key.
+func SignURL(key []byte, path string, query url.Values) string {
+	signed := url.Values{}
+	for k, v := range query {
+		if k != signatureParam {
+			signed[k] = v
+		}
+	}
+	signed.Set(signatureParam, Sign(key, path, query))
+	return path + "?" + signed.Encode()
+}
+
+// verifySignature reports whether query carries a valid signature for path.
+func verifySignature(key []byte, path string, query url.Values) bool {
+	got := query.Get(signatureParam)
+	if got == "" {
+		return false
+	}
+	want := Sign(key, path, query)
+	return hmac.Equal([]byte(got), []byte(want))
+}
+
+func signingString(path string, query url.Values) string {
+	unsigned := url.Values{}
+	for k, v := range query {
+		if k != signatureParam {
+			unsigned[k] = v
+		}
+	}
+	return "/" + strings.TrimPrefix(path, "/") + "?" + unsigned.Encode()
+}
@@ -4,6 +4,7 @@ package vips
 import "C"
 
 import (
+	"context"
 	"errors"
 	"fmt"
 	"image"
@@ -47,6 +48,10 @@ type ImageRef struct {
 	// pixels from the source on demand, so the C source and the Go
 	// reader stay alive until Close or materialize. See stream.go.
 	streamSource *streamSourceRef
+
+	// ctx is the context the operations on the image are traced under;
+	// see SetContext.
+	ctx context.Context
 }
 
 // ImageMetadata is a data structure holding the width, height, orientation and other metadata of the picture.
@@ -123,6 +128,20 @@ type ImportParams struct {
 	HeifThumbnail    BoolParameter
 	SvgUnlimited     BoolParameter
 	Access           IntParameter
+
+	// Size limits, checked against the image header before any pixel is
+	// decoded; see ImportLimits. Unset limits fall back to the package
+	// default (SetDefaultImportLimits) and a limit set to 0 is disabled.
+	MaxPixels       IntParameter
+	MaxWidth        IntParameter
+	MaxHeight       IntParameter
+	MaxPages        IntParameter
+	MaxDecodedBytes IntParameter
+
+	// Options sets any option of a libvips loader, such as
+	// JpegloadOptions or PdfloadOptions. They apply after the fields
+	// above, and only when the image is decoded by that loader.
+	Options LoadOptions
 }
 
 // NewImportParams creates default ImportParams
@@ -312,7 +331,8 @@ type WebpExportParams struct {
 	// the target. Leave at 0 (the default) to encode at Quality instead.
 	//
 	// Requires libvips 8.17.4+ (when target_size was added to webpsave);
-	// ExportWebp returns an error if TargetSize is set on an older libvips.
+	// ExportWebp returns ErrUnsupportedByLibvips if TargetSize is set on an
+	// older libvips.
 	TargetSize int
 }
 
@@ -361,7 +381,7 @@ func NewTiffExportParams() *TiffExportParams {
 // For vips below 8.12, magicksave is used as a fallback. The relevant
 // parameters are Quality and Bitdepth.
 //
-// StripMetadata has no effect on GIF images.
+// StripMetadata is only applied by native gifsave.
 type GifExportParams struct {
 	StripMetadata bool
 	// Quality is only used with vips < 8.12 (magicksave fallback).
@@ -383,10 +403,11 @@ func NewGifExportParams() *GifExportParams {
 
 // HeifExportParams are options when exporting a HEIF to file or buffer
 type HeifExportParams struct {
-	Quality  int
-	Bitdepth int
-	Effort   int
-	Lossless bool
+	StripMetadata bool
+	Quality       int
+	Bitdepth      int
+	Effort        int
+	Lossless      bool
 }
 
 // NewHeifExportParams creates default values for an export of a HEIF image.
@@ -442,11 +463,12 @@ func NewJp2kExportParams() *Jp2kExportParams {
 
 // JxlExportParams are options when exporting an JXL to file or buffer.
 type JxlExportParams struct {
-	Quality  int
-	Lossless bool
-	Tier     int
-	Distance float64
-	Effort   int
+	StripMetadata bool
+	Quality       int
+	Lossless      bool
+	Tier          int
+	Distance      float64
+	Effort        int
 }
 
 // NewJxlExportParams creates default values for an export of an JXL image.
@@ -493,15 +515,50 @@ func NewImageFromFile(file string) (*ImageRef, error) {
 	return LoadImageFromFile(file, nil)
 }
 
-// LoadImageFromFile loads an image from file and creates a new ImageRef
+// LoadImageFromFile loads an image from file and creates a new ImageRef.
+// libvips reads the file itself, as the pixels are needed, instead of the
+// whole file being read into memory first; params.Access selects random
+// or sequential access. The header is checked against the import limits
+// of params (see ImportLimits) before any pixel is decoded.
 func LoadImageFromFile(file string, params *ImportParams) (*ImageRef, error) {
-	buf, err := os.ReadFile(file)
+	return LoadImageFromFileContext(context.Background(), file, params)
+}
+
+// LoadImageFromFileContext is like LoadImageFromFile, tracing the load
+// and the operations on the image under ctx (see Tracer).
+func LoadImageFromFileContext(ctx context.Context, file string, params *ImportParams) (*ImageRef, error) {
+	if err := startupIfNeeded(); err != nil {
+		return nil, err
+	}
+
+	if params == nil {
+		params = NewImportParams()
+	}
+
+	vipsImage, currentFormat, originalFormat, err := vipsLoadFromFile(ctx, file, params)
 	if err != nil {
 		return nil, err
 	}
+	if originalFormat == ImageTypeUnknown {
+		// The format can't be told from the start of the file, such as an
+		// SVG with a long preamble: fall back to sniffing the whole file.
+		buf, err := os.ReadFile(file)
+		if err != nil {
+			return nil, err
+		}
+		govipsLog("govips", LogLevelDebug, fmt.Sprintf("creating imageRef from file %s", file))
+		return LoadImageFromBufferContext(ctx, buf, params)
+	}
+	if err := checkImportLimits(vipsImage, importLimits(params)); err != nil {
+		clearImage(vipsImage)
+		return nil, err
+	}
+
+	ref := newImageRef(vipsImage, currentFormat, originalFormat, nil)
+	ref.ctx = ctx
 
-	govipsLog("govips", LogLevelDebug, fmt.Sprintf("creating imageRef from file %s", file))
-	return LoadImageFromBuffer(buf, params)
+	govipsLog("govips", LogLevelDebug, fmt.Sprintf("created imageRef %p from file %s", ref, file))
+	return ref, nil
 }
 
 // NewImageFromBuffer loads an image buffer and creates a new Image
@@ -509,8 +566,17 @@ func NewImageFromBuffer(buf []byte) (*ImageRef, error) {
 	return LoadImageFromBuffer(buf, nil)
 }
 
-// LoadImageFromBuffer loads an image buffer and creates a new Image
+// LoadImageFromBuffer loads an image buffer and creates a new Image.
+// The header is checked against the import limits of params (see
+// ImportLimits) before any pixel is decoded, and ErrImageTooLarge is
+// returned for an image over a limit.
 func LoadImageFromBuffer(buf []byte, params *ImportParams) (*ImageRef, error) {
+	return LoadImageFromBufferContext(context.Background(), buf, params)
+}
+
+// LoadImageFromBufferContext is like LoadImageFromBuffer, tracing the
+// load and the operations on the image under ctx (see Tracer).
+func LoadImageFromBufferContext(ctx context.Context, buf []byte, params *ImportParams) (*ImageRef, error) {
 	if err := startupIfNeeded(); err != nil {
 		return nil, err
 	}
@@ -519,12 +585,17 @@ func LoadImageFromBuffer(buf []byte, params *ImportParams) (*ImageRef, error) {
 		params = NewImportParams()
 	}
 
-	vipsImage, currentFormat, originalFormat, err := vipsLoadFromBuffer(buf, params)
+	vipsImage, currentFormat, originalFormat, err := vipsLoadFromBuffer(ctx, buf, params)
 	if err != nil {
 		return nil, err
 	}
+	if err := checkImportLimits(vipsImage, importLimits(params)); err != nil {
+		clearImage(vipsImage)
+		return nil, err
+	}
 
 	ref := newImageRef(vipsImage, currentFormat, originalFormat, buf)
+	ref.ctx = ctx
 
 	govipsLog("govips", LogLevelDebug, fmt.Sprintf("created imageRef %p", ref))
 	return ref, nil
@@ -551,6 +622,10 @@ func LoadThumbnailFromFile(file string, width, height int, crop Interesting, siz
 		return nil, err
 	}
 
+	if err := checkFileLimits(context.Background(), file, params, importLimits(params)); err != nil {
+		return nil, err
+	}
+
 	vipsImage, format, err := vipsThumbnailFromFile(file, width, height, crop, size, params)
 	if err != nil {
 		return nil, err
@@ -573,6 +648,10 @@ func LoadThumbnailFromBuffer(buf []byte, width, height int, crop Interesting, si
 		return nil, err
 	}
 
+	if err := checkBufferLimits(context.Background(), buf, params, importLimits(params)); err != nil {
+		return nil, err
+	}
+
 	vipsImage, format, err := vipsThumbnailFromBuffer(buf, width, height, crop, size, params)
 	if err != nil {
 		return nil, err
@@ -599,53 +678,59 @@ func (r *ImageRef) Metadata() *ImageMetadata {
 // Copy creates a new copy of the given image.
 func (r *ImageRef) Copy() (*ImageRef, error) {
 	defer runtime.KeepAlive(r)
-	out, err := vipsGenCopy(r.image, nil)
+	out, err := vipsGenCopy(r.Context(), r.image, nil)
 	if err != nil {
 		return nil, err
 	}
 
-	return newImageRef(out, r.format, r.originalFormat, r.buf), nil
+	ref := newImageRef(out, r.format, r.originalFormat, r.buf)
+	ref.ctx = r.ctx
+	return ref, nil
 }
 
 // Copy creates a new copy of the given image with the new X and Y resolution (PPI).
 func (r *ImageRef) CopyChangingResolution(xres, yres float64) (*ImageRef, error) {
 	defer runtime.KeepAlive(r)
-	out, err := vipsGenCopy(r.image, &CopyOptions{Xres: &xres, Yres: &yres})
+	out, err := vipsGenCopy(r.Context(), r.image, &CopyOptions{Xres: &xres, Yres: &yres})
 	if err != nil {
 		return nil, err
 	}
 
-	return newImageRef(out, r.format, r.originalFormat, r.buf), nil
+	ref := newImageRef(out, r.format, r.originalFormat, r.buf)
+	ref.ctx = r.ctx
+	return ref, nil
 }
 
 // Copy creates a new copy of the given image with the interpretation.
 func (r *ImageRef) CopyChangingInterpretation(interpretation Interpretation) (*ImageRef, error) {
 	defer runtime.KeepAlive(r)
-	out, err := vipsGenCopy(r.image, &CopyOptions{Interpretation: &interpretation})
+	out, err := vipsGenCopy(r.Context(), r.image, &CopyOptions{Interpretation: &interpretation})
 	if err != nil {
 		return nil, err
 	}
 
-	return newImageRef(out, r.format, r.originalFormat, r.buf), nil
+	ref := newImageRef(out, r.format, r.originalFormat, r.buf)
+	ref.ctx = r.ctx
+	return ref, nil
 }
 
 // XYZ creates a two-band uint32 image where the elements in the first band have the value of their x coordinate
 // and elements in the second band have their y coordinate.
 func XYZ(width, height int) (*ImageRef, error) {
-	vipsImage, err := vipsGenXyz(width, height, nil)
+	vipsImage, err := vipsGenXyz(context.Background(), width, height, nil)
 	return newImageRef(vipsImage, ImageTypeUnknown, ImageTypeUnknown, nil), err
 }
 
 // Identity creates an identity lookup table, which will leave an image unchanged when applied with Maplut.
 // Each entry in the table has a value equal to its position.
 func Identity(ushort bool) (*ImageRef, error) {
-	img, err := vipsGenIdentity(&IdentityOptions{Ushort: &ushort})
+	img, err := vipsGenIdentity(context.Background(), &IdentityOptions{Ushort: &ushort})
 	return newImageRef(img, ImageTypeUnknown, ImageTypeUnknown, nil), err
 }
 
 // Black creates a new black image of the specified size
 func Black(width, height int) (*ImageRef, error) {
-	vipsImage, err := vipsGenBlack(width, height, nil)
+	vipsImage, err := vipsGenBlack(context.Background(), width, height, nil)
 	if err != nil {
 		return nil, err
 	}
@@ -656,7 +741,7 @@ func Black(width, height int) (*ImageRef, error) {
 // When uchar is true, pixel values are 0-255 uint8; when false, 0.0-1.0 float.
 // Useful for creating gradient overlays when combined with rotation, BandJoin, and Composite.
 func Grey(width, height int, uchar bool) (*ImageRef, error) {
-	img, err := vipsGenGrey(width, height, &GreyOptions{Uchar: &uchar})
+	img, err := vipsGenGrey(context.Background(), width, height, &GreyOptions{Uchar: &uchar})
 	if err != nil {
 		return nil, err
 	}
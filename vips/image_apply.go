@@ -0,0 +1,84 @@
+package vips
+
+import (
+	"errors"
+	"runtime"
+)
+
+// Apply returns a new image with op applied, leaving r unchanged. op is
+// called with a fresh ImageRef that shares the lazy pixel pipeline of r
+// but has its own metadata, so it may use any of the mutating ImageRef
+// methods. Fanning several variants out from one decoded image is cheap:
+// no pixels are copied, and each variant is computed when it is exported.
+//
+// The returned image must be closed like any other and counts in
+// OpenImageRefs. If op fails, the new image is closed and the error is
+// returned. A sequentially stream-loaded r is materialized first, since a
+// sequential pipeline can only be read once.
+func (r *ImageRef) Apply(op func(img *ImageRef) error) (*ImageRef, error) {
+	defer runtime.KeepAlive(r)
+	if r.image == nil {
+		return nil, errors.New("attempt to apply to a closed ImageRef")
+	}
+	if err := r.materialize(); err != nil {
+		return nil, err
+	}
+
+	out, err := r.Copy()
+	if err != nil {
+		return nil, err
+	}
+	if err := op(out); err != nil {
+		out.Close()
+		return nil, err
+	}
+	return out, nil
+}
+
+// Resized is like Resize, returning the resized image as a new ImageRef
+// and leaving r unchanged (see Apply).
+func (r *ImageRef) Resized(scale float64, kernel Kernel) (*ImageRef, error) {
+	return r.Apply(func(img *ImageRef) error {
+		return img.Resize(scale, kernel)
+	})
+}
+
+// Thumbnailed is like Thumbnail, returning the thumbnail as a new ImageRef
+// and leaving r unchanged (see Apply).
+func (r *ImageRef) Thumbnailed(width, height int, crop Interesting) (*ImageRef, error) {
+	return r.Apply(func(img *ImageRef) error {
+		return img.Thumbnail(width, height, crop)
+	})
+}
+
+// Extracted is like ExtractArea, returning the area as a new ImageRef and
+// leaving r unchanged (see Apply).
+func (r *ImageRef) Extracted(left, top, width, height int) (*ImageRef, error) {
+	return r.Apply(func(img *ImageRef) error {
+		return img.ExtractArea(left, top, width, height)
+	})
+}
+
+// Rotated is like Rotate, returning the rotated image as a new ImageRef
+// and leaving r unchanged (see Apply).
+func (r *ImageRef) Rotated(angle Angle) (*ImageRef, error) {
+	return r.Apply(func(img *ImageRef) error {
+		return img.Rotate(angle)
+	})
+}
+
+// Flipped is like Flip, returning the flipped image as a new ImageRef and
+// leaving r unchanged (see Apply).
+func (r *ImageRef) Flipped(direction Direction) (*ImageRef, error) {
+	return r.Apply(func(img *ImageRef) error {
+		return img.Flip(direction)
+	})
+}
+
+// AutoRotated is like AutoRotate, returning the upright image as a new
+// ImageRef and leaving r unchanged (see Apply).
+func (r *ImageRef) AutoRotated() (*ImageRef, error) {
+	return r.Apply(func(img *ImageRef) error {
+		return img.AutoRotate()
+	})
+}
@@ -0,0 +1,122 @@
+package vips
+
+import (
+	"errors"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestImageRef_Apply_LeavesReceiverUnchanged(t *testing.T) {
+	require.NoError(t, Startup(nil))
+
+	image, err := NewImageFromFile(resources + "png-24bit.png")
+	require.NoError(t, err)
+	defer image.Close()
+
+	before := OpenImageRefs()
+
+	small, err := image.Resized(0.25, KernelLinear)
+	require.NoError(t, err)
+	defer small.Close()
+	thumb, err := image.Thumbnailed(100, 100, InterestingCentre)
+	require.NoError(t, err)
+	defer thumb.Close()
+	rotated, err := image.Rotated(Angle90)
+	require.NoError(t, err)
+	defer rotated.Close()
+	area, err := image.Extracted(10, 20, 30, 40)
+	require.NoError(t, err)
+	defer area.Close()
+	flipped, err := image.Flipped(DirectionHorizontal)
+	require.NoError(t, err)
+	defer flipped.Close()
+
+	assert.Equal(t, before+5, OpenImageRefs())
+
+	assert.Equal(t, 1920, image.Width())
+	assert.Equal(t, 1080, image.Height())
+	assert.Equal(t, 480, small.Width())
+	assert.Equal(t, 100, thumb.Width())
+	assert.Equal(t, 100, thumb.Height())
+	assert.Equal(t, 1080, rotated.Width())
+	assert.Equal(t, 1920, rotated.Height())
+	assert.Equal(t, 30, area.Width())
+	assert.Equal(t, 40, area.Height())
+
+	for _, variant := range []*ImageRef{small, thumb, rotated, area, flipped} {
+		_, _, err := variant.ExportPng(NewPngExportParams())
+		require.NoError(t, err)
+	}
+	_, _, err = image.ExportPng(NewPngExportParams())
+	require.NoError(t, err)
+}
+
+func TestImageRef_Apply_OwnMetadata(t *testing.T) {
+	require.NoError(t, Startup(nil))
+
+	image, err := NewImageFromFile(resources + "jpg-24bit.jpg")
+	require.NoError(t, err)
+	defer image.Close()
+	orientation := image.Orientation()
+
+	variant, err := image.Apply(func(img *ImageRef) error {
+		img.SetString("govips-test", "variant")
+		return img.SetOrientation(6)
+	})
+	require.NoError(t, err)
+	defer variant.Close()
+
+	assert.Equal(t, 6, variant.Orientation())
+	assert.Equal(t, orientation, image.Orientation())
+	assert.Contains(t, variant.GetFields(), "govips-test")
+	assert.NotContains(t, image.GetFields(), "govips-test")
+}
+
+func TestImageRef_Apply_Error(t *testing.T) {
+	require.NoError(t, Startup(nil))
+
+	image, err := NewImageFromFile(resources + "jpg-24bit.jpg")
+	require.NoError(t, err)
+	defer image.Close()
+
+	before := OpenImageRefs()
+	errApply := errors.New("apply failed")
+	out, err := image.Apply(func(img *ImageRef) error {
+		return errApply
+	})
+	assert.Nil(t, out)
+	assert.True(t, errors.Is(err, errApply))
+	assert.Equal(t, before, OpenImageRefs())
+
+	image.Close()
+	_, err = image.Resized(0.5, KernelLinear)
+	assert.Error(t, err)
+}
+
+func TestImageRef_Apply_Concurrent(t *testing.T) {
+	require.NoError(t, Startup(nil))
+
+	image, err := NewImageFromFile(resources + "png-24bit.png")
+	require.NoError(t, err)
+	defer image.Close()
+
+	var wg sync.WaitGroup
+	for i := 1; i <= 8; i++ {
+		wg.Add(1)
+		go func(width int) {
+			defer wg.Done()
+			variant, err := image.Thumbnailed(width*10, width*10, InterestingNone)
+			if !assert.NoError(t, err) {
+				return
+			}
+			defer variant.Close()
+			_, _, err = variant.ExportJpeg(NewJpegExportParams())
+			assert.NoError(t, err)
+			assert.Equal(t, width*10, variant.Width())
+		}(i)
+	}
+	wg.Wait()
+}
@@ -11,7 +11,7 @@ import (
 // ToColorSpace changes the color space of the image to the interpretation supplied as the parameter.
 func (r *ImageRef) ToColorSpace(interpretation Interpretation) error {
 	defer runtime.KeepAlive(r)
-	out, err := vipsToColorSpace(r.image, interpretation)
+	out, err := vipsToColorSpace(r.Context(), r.image, interpretation)
 	if err != nil {
 		return err
 	}
@@ -26,7 +26,7 @@ func (r *ImageRef) Flatten(backgroundColor *Color) error {
 	if backgroundColor != nil {
 		opts.Background = []float64{float64(backgroundColor.R), float64(backgroundColor.G), float64(backgroundColor.B)}
 	}
-	out, err := vipsGenFlatten(r.image, opts)
+	out, err := vipsGenFlatten(r.Context(), r.image, opts)
 	if err != nil {
 		return err
 	}
@@ -113,7 +113,7 @@ func (r *ImageRef) ModulateHSV(brightness, saturation float64, hue int) error {
 // Invert inverts the image
 func (r *ImageRef) Invert() error {
 	defer runtime.KeepAlive(r)
-	out, err := vipsGenInvert(r.image)
+	out, err := vipsGenInvert(r.Context(), r.image)
 	if err != nil {
 		return err
 	}
@@ -125,7 +125,7 @@ func (r *ImageRef) Invert() error {
 // See https://www.libvips.org/API/current/libvips-conversion.html#vips-gamma
 func (r *ImageRef) Gamma(gamma float64) error {
 	defer runtime.KeepAlive(r)
-	out, err := vipsGenGamma(r.image, &GammaOptions{Exponent: &gamma})
+	out, err := vipsGenGamma(r.Context(), r.image, &GammaOptions{Exponent: &gamma})
 	if err != nil {
 		return err
 	}
@@ -141,7 +141,7 @@ func (r *ImageRef) Linear(a, b []float64) error {
 		return errors.New("a and b must be of same length")
 	}
 
-	out, err := vipsGenLinear(r.image, a, b, nil)
+	out, err := vipsGenLinear(r.Context(), r.image, a, b, nil)
 	if err != nil {
 		return err
 	}
@@ -153,7 +153,7 @@ func (r *ImageRef) Linear(a, b []float64) error {
 // See https://libvips.github.io/libvips/API/current/libvips-arithmetic.html#vips-linear1
 func (r *ImageRef) Linear1(a, b float64) error {
 	defer runtime.KeepAlive(r)
-	out, err := vipsGenLinear(r.image, []float64{a}, []float64{b}, nil)
+	out, err := vipsGenLinear(r.Context(), r.image, []float64{a}, []float64{b}, nil)
 	if err != nil {
 		return err
 	}
@@ -164,7 +164,7 @@ func (r *ImageRef) Linear1(a, b float64) error {
 // Cast converts the image to a target band format
 func (r *ImageRef) Cast(format BandFormat) error {
 	defer runtime.KeepAlive(r)
-	out, err := vipsGenCast(r.image, format, nil)
+	out, err := vipsGenCast(r.Context(), r.image, format, nil)
 	if err != nil {
 		return err
 	}
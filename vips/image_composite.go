@@ -8,7 +8,7 @@ import "runtime"
 // CompositeMulti composites the given overlay image on top of the associated image with provided blending mode.
 func (r *ImageRef) CompositeMulti(ins []*ImageComposite) error {
 	defer runtime.KeepAlive(r)
-	out, err := vipsComposite(toVipsCompositeStructs(r, ins))
+	out, err := vipsComposite(r.Context(), toVipsCompositeStructs(r, ins))
 	if err != nil {
 		return err
 	}
@@ -19,7 +19,7 @@ func (r *ImageRef) CompositeMulti(ins []*ImageComposite) error {
 // Composite composites the given overlay image on top of the associated image with provided blending mode.
 func (r *ImageRef) Composite(overlay *ImageRef, mode BlendMode, x, y int) error {
 	defer runtime.KeepAlive(r)
-	out, err := vipsGenComposite2(r.image, overlay.image, mode, &Composite2Options{X: &x, Y: &y})
+	out, err := vipsGenComposite2(r.Context(), r.image, overlay.image, mode, &Composite2Options{X: &x, Y: &y})
 	if err != nil {
 		return err
 	}
@@ -34,7 +34,7 @@ func (r *ImageRef) Insert(sub *ImageRef, x, y int, expand bool, background *Colo
 	if background != nil {
 		insertOpts.Background = []float64{float64(background.R), float64(background.G), float64(background.B), float64(background.A)}
 	}
-	out, err := vipsGenInsert(r.image, sub.image, x, y, insertOpts)
+	out, err := vipsGenInsert(r.Context(), r.image, sub.image, x, y, insertOpts)
 	if err != nil {
 		return err
 	}
@@ -45,7 +45,7 @@ func (r *ImageRef) Insert(sub *ImageRef, x, y int, expand bool, background *Colo
 // Join joins this image with another in the direction specified
 func (r *ImageRef) Join(in *ImageRef, dir Direction) error {
 	defer runtime.KeepAlive(r)
-	out, err := vipsJoin(r.image, in.image, dir)
+	out, err := vipsJoin(r.Context(), r.image, in.image, dir)
 	if err != nil {
 		return err
 	}
@@ -61,7 +61,7 @@ func (r *ImageRef) ArrayJoin(images []*ImageRef, across int) error {
 	for i := range inputs {
 		inputs[i] = allImages[i].image
 	}
-	out, err := vipsGenArrayjoin(inputs, &ArrayjoinOptions{Across: &across})
+	out, err := vipsGenArrayjoin(r.Context(), inputs, &ArrayjoinOptions{Across: &across})
 	if err != nil {
 		return err
 	}
@@ -0,0 +1,203 @@
+package vips
+
+// #include "image.h"
+import "C"
+
+import (
+	"bytes"
+	"encoding/binary"
+	"runtime"
+	"strings"
+	"unicode/utf16"
+)
+
+// FieldType is the kind of value held by a header field.
+type FieldType string
+
+// FieldType enum
+const (
+	FieldTypeInt         FieldType = "int"
+	FieldTypeDouble      FieldType = "double"
+	FieldTypeBool        FieldType = "bool"
+	FieldTypeString      FieldType = "string"
+	FieldTypeEnum        FieldType = "enum"
+	FieldTypeBlob        FieldType = "blob"
+	FieldTypeIntArray    FieldType = "int_array"
+	FieldTypeDoubleArray FieldType = "double_array"
+	FieldTypeImage       FieldType = "image"
+	FieldTypeOther       FieldType = "other"
+)
+
+// FieldImage describes an image stored in a header field, such as a
+// gain map.
+type FieldImage struct {
+	Width  int `json:"width"`
+	Height int `json:"height"`
+	Bands  int `json:"bands"`
+}
+
+// HeaderField is a header field with its value decoded according to its
+// GType. Value holds an int, float64, bool or string (the nickname for
+// enums and the printed form for other types), or a []int or []float64 for
+// arrays. Blobs only report their Size and images their dimensions.
+type HeaderField struct {
+	Name  string      `json:"name"`
+	Type  FieldType   `json:"type"`
+	GType string      `json:"gtype"`
+	Value interface{} `json:"value,omitempty"`
+	Size  int         `json:"size,omitempty"`
+	Image *FieldImage `json:"image,omitempty"`
+}
+
+// ImageDescription is the full header of an image. Enumerations are given
+// by their libvips nicknames, for example "uchar" and "srgb", and the
+// resolution in pixels per millimetre.
+type ImageDescription struct {
+	Format         string        `json:"format"`
+	Width          int           `json:"width"`
+	Height         int           `json:"height"`
+	Bands          int           `json:"bands"`
+	BandFormat     string        `json:"band_format"`
+	Coding         string        `json:"coding"`
+	Interpretation string        `json:"interpretation"`
+	Loader         string        `json:"loader,omitempty"`
+	XRes           float64       `json:"xres"`
+	YRes           float64       `json:"yres"`
+	XOffset        int           `json:"xoffset"`
+	YOffset        int           `json:"yoffset"`
+	Pages          int           `json:"pages"`
+	PageHeight     int           `json:"page_height"`
+	PageDelays     []int         `json:"page_delays,omitempty"`
+	Loop           *int          `json:"loop,omitempty"`
+	Orientation    int           `json:"orientation"`
+	HasAlpha       bool          `json:"has_alpha"`
+	ICCDescription string        `json:"icc_description,omitempty"`
+	Fields         []HeaderField `json:"fields"`
+}
+
+// Describe returns the full header of the image, including every metadata
+// field with its typed value. Pixels are not computed.
+func (r *ImageRef) Describe() *ImageDescription {
+	defer runtime.KeepAlive(r)
+
+	d := &ImageDescription{
+		Format:         r.format.Name(),
+		Width:          r.Width(),
+		Height:         r.Height(),
+		Bands:          r.Bands(),
+		BandFormat:     vipsEnumNick(C.vips_band_format_get_type(), int(r.image.BandFmt)),
+		Coding:         vipsEnumNick(C.vips_coding_get_type(), int(r.image.Coding)),
+		Interpretation: vipsEnumNick(C.vips_interpretation_get_type(), int(r.image.Type)),
+		XRes:           r.ResX(),
+		YRes:           r.ResY(),
+		XOffset:        r.OffsetX(),
+		YOffset:        r.OffsetY(),
+		Pages:          r.Pages(),
+		PageHeight:     r.PageHeight(),
+		Orientation:    r.Orientation(),
+		HasAlpha:       r.HasAlpha(),
+		Fields:         r.TypedFields(),
+	}
+	if loader, ok := vipsImageGetMetaLoader(r.image); ok {
+		d.Loader = loader
+	}
+	if profile, ok := vipsGetICCProfile(r.image); ok {
+		d.ICCDescription = iccProfileDescription(profile)
+	}
+
+	for _, field := range d.Fields {
+		switch field.Name {
+		case "delay":
+			if delays, ok := field.Value.([]int); ok {
+				d.PageDelays = delays
+			}
+		case "loop":
+			if loop, ok := field.Value.(int); ok {
+				d.Loop = &loop
+			}
+		}
+	}
+	return d
+}
+
+// iccProfileDescription returns the text of the profile description tag of
+// an ICC profile: the ASCII text of a v2 textDescriptionType, or the
+// English (else first) record of a v4 multiLocalizedUnicodeType. It returns
+// "" if the profile has no readable description.
+func iccProfileDescription(profile []byte) string {
+	const headerSize = 128
+	if len(profile) < headerSize+4 {
+		return ""
+	}
+	be := binary.BigEndian
+	count := int(be.Uint32(profile[headerSize:]))
+	for i := 0; i < count; i++ {
+		entry := headerSize + 4 + i*12
+		if entry+12 > len(profile) {
+			return ""
+		}
+		if string(profile[entry:entry+4]) != "desc" {
+			continue
+		}
+		offset := int(be.Uint32(profile[entry+4:]))
+		size := int(be.Uint32(profile[entry+8:]))
+		if offset < 0 || size < 12 || offset+size > len(profile) || offset+size < offset {
+			return ""
+		}
+		return iccTextDescription(profile[offset : offset+size])
+	}
+	return ""
+}
+
+// iccTextDescription decodes a desc tag, starting at its type signature.
+func iccTextDescription(tag []byte) string {
+	be := binary.BigEndian
+	switch string(tag[:4]) {
+	case "desc":
+		n := int(be.Uint32(tag[8:]))
+		if n < 0 || 12+n > len(tag) || 12+n < 12 {
+			return ""
+		}
+		text := tag[12 : 12+n]
+		if i := bytes.IndexByte(text, 0); i >= 0 {
+			text = text[:i]
+		}
+		return strings.TrimSpace(string(text))
+	case "mluc":
+		if len(tag) < 16 {
+			return ""
+		}
+		records := int(be.Uint32(tag[8:]))
+		recordSize := int(be.Uint32(tag[12:]))
+		if recordSize < 12 {
+			return ""
+		}
+		chosen := -1
+		for i := 0; i < records; i++ {
+			record := 16 + i*recordSize
+			if record < 0 || record+12 > len(tag) {
+				break
+			}
+			if chosen < 0 || string(tag[record:record+2]) == "en" {
+				chosen = record
+				if string(tag[record:record+2]) == "en" {
+					break
+				}
+			}
+		}
+		if chosen < 0 {
+			return ""
+		}
+		length := int(be.Uint32(tag[chosen+4:]))
+		offset := int(be.Uint32(tag[chosen+8:]))
+		if length < 0 || offset < 0 || offset+length > len(tag) || offset+length < offset {
+			return ""
+		}
+		units := make([]uint16, length/2)
+		for i := range units {
+			units[i] = be.Uint16(tag[offset+2*i:])
+		}
+		return strings.TrimSpace(strings.TrimRight(string(utf16.Decode(units)), "\x00"))
+	}
+	return ""
+}
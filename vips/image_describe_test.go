@@ -0,0 +1,109 @@
+package vips
+
+import (
+	"encoding/json"
+	"os"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestImageRef_Describe(t *testing.T) {
+	require.NoError(t, Startup(nil))
+
+	image, err := NewImageFromFile(resources + "jpg-24bit-icc-adobe-rgb.jpg")
+	require.NoError(t, err)
+	defer image.Close()
+
+	d := image.Describe()
+	assert.Equal(t, "jpeg", d.Format)
+	assert.Equal(t, image.Width(), d.Width)
+	assert.Equal(t, image.Height(), d.Height)
+	assert.Equal(t, 3, d.Bands)
+	assert.Equal(t, "uchar", d.BandFormat)
+	assert.Equal(t, "none", d.Coding)
+	assert.Equal(t, "srgb", d.Interpretation)
+	assert.Equal(t, "jpegload", d.Loader)
+	assert.Equal(t, "Adobe RGB (1998)", d.ICCDescription)
+	assert.Nil(t, d.PageDelays)
+
+	fields := map[string]HeaderField{}
+	for _, f := range d.Fields {
+		fields[f.Name] = f
+	}
+	assert.Len(t, fields, len(image.ImageFields()))
+
+	width := fields["width"]
+	assert.Equal(t, FieldTypeInt, width.Type)
+	assert.Equal(t, "gint", width.GType)
+	assert.Equal(t, image.Width(), width.Value)
+
+	assert.Equal(t, FieldTypeDouble, fields["xres"].Type)
+	assert.Equal(t, FieldTypeEnum, fields["interpretation"].Type)
+	assert.Equal(t, "srgb", fields["interpretation"].Value)
+	assert.Equal(t, FieldTypeString, fields["vips-loader"].Type)
+
+	icc := fields["icc-profile-data"]
+	assert.Equal(t, FieldTypeBlob, icc.Type)
+	assert.Equal(t, len(image.GetICCProfile()), icc.Size)
+	assert.Nil(t, icc.Value)
+}
+
+func TestImageRef_Describe__Animated(t *testing.T) {
+	require.NoError(t, Startup(nil))
+
+	params := NewImportParams()
+	params.NumPages.Set(-1)
+	image, err := LoadImageFromFile(resources+"gif-animated.gif", params)
+	require.NoError(t, err)
+	defer image.Close()
+
+	d := image.Describe()
+	assert.Equal(t, "gif", d.Format)
+	assert.Equal(t, 8, d.Pages)
+	assert.Equal(t, 128, d.PageHeight)
+	assert.Len(t, d.PageDelays, 8)
+	require.NotNil(t, d.Loop)
+	assert.Equal(t, image.Loop(), *d.Loop)
+
+	for _, f := range d.Fields {
+		if f.Name == "delay" {
+			assert.Equal(t, FieldTypeIntArray, f.Type)
+			assert.Equal(t, d.PageDelays, f.Value)
+		}
+	}
+}
+
+func TestImageRef_Describe__JSON(t *testing.T) {
+	require.NoError(t, Startup(nil))
+
+	image, err := NewImageFromFile(resources + "png-24bit.png")
+	require.NoError(t, err)
+	defer image.Close()
+
+	buf, err := json.Marshal(image.Describe())
+	require.NoError(t, err)
+
+	var out map[string]interface{}
+	require.NoError(t, json.Unmarshal(buf, &out))
+	assert.Equal(t, "png", out["format"])
+	assert.Equal(t, "uchar", out["band_format"])
+	assert.Equal(t, "pngload", out["loader"])
+	assert.NotContains(t, out, "icc_description")
+	assert.NotContains(t, out, "page_delays")
+	assert.NotEmpty(t, out["fields"])
+}
+
+func TestICCProfileDescription(t *testing.T) {
+	profile, err := os.ReadFile(resources + "adobe-rgb.icc")
+	require.NoError(t, err)
+	assert.Equal(t, "Adobe RGB (1998)", iccProfileDescription(profile))
+
+	profile, err = os.ReadFile(resources + "sRGB.icc")
+	require.NoError(t, err)
+	assert.Equal(t, "sRGB", iccProfileDescription(profile))
+
+	assert.Equal(t, "", iccProfileDescription(nil))
+	assert.Equal(t, "", iccProfileDescription(profile[:140]))
+}
@@ -0,0 +1,111 @@
+package vips
+
+import (
+	"errors"
+	"runtime"
+)
+
+// Drawing changes the pixels of the image in place. An image that shares
+// its pixels with other images, such as the source of a Copy or Apply, or
+// one that is not yet in memory, is first copied into memory, so drawing
+// never changes other images. Repeated draws on the same image draw on
+// that copy without copying again.
+//
+// Inks are given as ColorRGBA for images of any band count: greyscale
+// images take R as the grey level, and the last band of images with more
+// than four bands takes A. Values are scaled up for 16-bit images.
+
+// DrawRect draws an (optionally filled) rectangle with a single colour
+func (r *ImageRef) DrawRect(ink ColorRGBA, left int, top int, width int, height int, fill bool) error {
+	defer runtime.KeepAlive(r)
+	out, err := vipsDrawRect(r.Context(), r.image, ink, left, top, width, height, fill)
+	if err != nil {
+		return err
+	}
+	r.setImage(out)
+	return nil
+}
+
+// DrawLine draws a one pixel wide line from (x1, y1) to (x2, y2).
+func (r *ImageRef) DrawLine(ink ColorRGBA, x1, y1, x2, y2 int) error {
+	defer runtime.KeepAlive(r)
+	out, err := vipsDrawLine(r.Context(), r.image, ink, x1, y1, x2, y2)
+	if err != nil {
+		return err
+	}
+	r.setImage(out)
+	return nil
+}
+
+// DrawCircle draws a circle centred on (cx, cy), filled or as a one pixel
+// wide outline.
+func (r *ImageRef) DrawCircle(ink ColorRGBA, cx, cy, radius int, fill bool) error {
+	defer runtime.KeepAlive(r)
+	out, err := vipsDrawCircle(r.Context(), r.image, ink, cx, cy, radius, fill)
+	if err != nil {
+		return err
+	}
+	r.setImage(out)
+	return nil
+}
+
+// FloodFill fills the area around (x, y) with ink and returns the left,
+// top, width and height of the bounding box of the filled pixels. When
+// equal is true, the area is the connected pixels equal to the one at
+// (x, y); otherwise it is the area bounded by pixels equal to ink.
+func (r *ImageRef) FloodFill(ink ColorRGBA, x, y int, equal bool) (int, int, int, int, error) {
+	defer runtime.KeepAlive(r)
+	out, left, top, width, height, err := vipsDrawFlood(r.Context(), r.image, ink, x, y, equal)
+	if err != nil {
+		return -1, -1, -1, -1, err
+	}
+	r.setImage(out)
+	return left, top, width, height, nil
+}
+
+// DrawImage draws sub onto the image with its top left corner at (x, y).
+// sub is converted to the band format of the image. CombineModeSet
+// replaces the pixels; CombineModeAdd adds sub to them.
+func (r *ImageRef) DrawImage(sub *ImageRef, x, y int, mode CombineMode) error {
+	defer runtime.KeepAlive(r)
+	defer runtime.KeepAlive(sub)
+	if sub == nil || sub.image == nil {
+		return errors.New("attempt to draw a closed ImageRef")
+	}
+	out, err := vipsDrawImage(r.Context(), r.image, sub.image, x, y, mode)
+	if err != nil {
+		return err
+	}
+	r.setImage(out)
+	return nil
+}
+
+// DrawMask draws ink through mask, a one-band uchar image, with its top
+// left corner at (x, y): each mask value blends ink into the pixel under
+// it, 0 leaving the pixel unchanged and 255 painting it with ink. It can
+// be used to render antialiased text from Label or a text image.
+func (r *ImageRef) DrawMask(ink ColorRGBA, mask *ImageRef, x, y int) error {
+	defer runtime.KeepAlive(r)
+	defer runtime.KeepAlive(mask)
+	if mask == nil || mask.image == nil {
+		return errors.New("attempt to draw with a closed mask")
+	}
+	out, err := vipsDrawMask(r.Context(), r.image, ink, mask.image, x, y)
+	if err != nil {
+		return err
+	}
+	r.setImage(out)
+	return nil
+}
+
+// Smudge blurs the given area of the image slightly, for example to hide
+// small defects.
+func (r *ImageRef) Smudge(left, top, width, height int) error {
+	defer runtime.KeepAlive(r)
+	out, err := vipsDrawSmudge(r.Context(), r.image, left, top, width, height)
+	if err != nil {
+		return err
+	}
+	r.setImage(out)
+	return nil
+}
@@ -0,0 +1,175 @@
+package vips
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestImageRef_Draw_CopyOnWrite(t *testing.T) {
+	require.NoError(t, Startup(nil))
+
+	image, err := NewImageFromFile(resources + "png-24bit.png")
+	require.NoError(t, err)
+	defer image.Close()
+	before, err := image.GetPoint(10, 10)
+	require.NoError(t, err)
+
+	copied, err := image.Copy()
+	require.NoError(t, err)
+	defer copied.Close()
+
+	red := ColorRGBA{R: 255, A: 255}
+	require.NoError(t, copied.DrawRect(red, 0, 0, 20, 20, true))
+	require.NoError(t, copied.DrawLine(red, 0, 100, 100, 100))
+
+	drawn, err := copied.GetPoint(10, 10)
+	require.NoError(t, err)
+	assert.Equal(t, []float64{255, 0, 0}, drawn)
+
+	after, err := image.GetPoint(10, 10)
+	require.NoError(t, err)
+	assert.Equal(t, before, after)
+
+	variant, err := image.Apply(func(img *ImageRef) error {
+		return img.DrawCircle(red, 10, 10, 5, true)
+	})
+	require.NoError(t, err)
+	defer variant.Close()
+
+	after, err = image.GetPoint(10, 10)
+	require.NoError(t, err)
+	assert.Equal(t, before, after)
+}
+
+func TestImageRef_FloodFill(t *testing.T) {
+	require.NoError(t, Startup(nil))
+
+	image, err := Black(100, 100)
+	require.NoError(t, err)
+	defer image.Close()
+
+	white := ColorRGBA{R: 255, G: 255, B: 255, A: 255}
+	require.NoError(t, image.DrawRect(white, 20, 30, 40, 20, false))
+	require.NoError(t, image.DrawCircle(white, 80, 80, 10, false))
+
+	left, top, width, height, err := image.FloodFill(white, 40, 40, false)
+	require.NoError(t, err)
+	assert.Equal(t, 21, left)
+	assert.Equal(t, 31, top)
+	assert.Equal(t, 38, width)
+	assert.Equal(t, 18, height)
+
+	point, err := image.GetPoint(40, 40)
+	require.NoError(t, err)
+	assert.Equal(t, []float64{255}, point)
+	point, err = image.GetPoint(5, 5)
+	require.NoError(t, err)
+	assert.Equal(t, []float64{0}, point)
+}
+
+func TestImageRef_Draw_Inks(t *testing.T) {
+	require.NoError(t, Startup(nil))
+
+	ink := ColorRGBA{R: 10, G: 20, B: 30, A: 255}
+
+	grey, err := Black(10, 10)
+	require.NoError(t, err)
+	defer grey.Close()
+	require.NoError(t, grey.DrawRect(ink, 0, 0, 10, 10, true))
+	point, err := grey.GetPoint(5, 5)
+	require.NoError(t, err)
+	assert.Equal(t, []float64{10}, point)
+
+	rgba, err := NewImageFromFile(resources + "png-24bit+alpha.png")
+	require.NoError(t, err)
+	defer rgba.Close()
+	require.Equal(t, 4, rgba.Bands())
+	require.NoError(t, rgba.DrawRect(ink, 0, 0, 10, 10, true))
+	point, err = rgba.GetPoint(5, 5)
+	require.NoError(t, err)
+	assert.Equal(t, []float64{10, 20, 30, 255}, point)
+
+	deep, err := NewImageFromFile(resources + "png-24bit.png")
+	require.NoError(t, err)
+	defer deep.Close()
+	require.NoError(t, deep.ToColorSpace(InterpretationRGB16))
+	require.NoError(t, deep.DrawRect(ink, 0, 0, 10, 10, true))
+	point, err = deep.GetPoint(5, 5)
+	require.NoError(t, err)
+	assert.Equal(t, []float64{2570, 5140, 7710}, point)
+}
+
+func TestImageRef_DrawImage(t *testing.T) {
+	require.NoError(t, Startup(nil))
+
+	image, err := Black(100, 100)
+	require.NoError(t, err)
+	defer image.Close()
+
+	sub, err := Black(10, 10)
+	require.NoError(t, err)
+	defer sub.Close()
+	require.NoError(t, sub.DrawRect(ColorRGBA{R: 100}, 0, 0, 10, 10, true))
+
+	require.NoError(t, image.DrawImage(sub, 50, 50, CombineModeSet))
+	require.NoError(t, image.DrawImage(sub, 55, 55, CombineModeAdd))
+
+	point, err := image.GetPoint(52, 52)
+	require.NoError(t, err)
+	assert.Equal(t, []float64{100}, point)
+	point, err = image.GetPoint(57, 57)
+	require.NoError(t, err)
+	assert.Equal(t, []float64{200}, point)
+	point, err = image.GetPoint(5, 5)
+	require.NoError(t, err)
+	assert.Equal(t, []float64{0}, point)
+
+	sub.Close()
+	assert.Error(t, image.DrawImage(sub, 0, 0, CombineModeSet))
+}
+
+func TestImageRef_DrawMask(t *testing.T) {
+	require.NoError(t, Startup(nil))
+
+	image, err := NewImageFromFile(resources + "png-24bit.png")
+	require.NoError(t, err)
+	defer image.Close()
+
+	mask, err := Black(20, 20)
+	require.NoError(t, err)
+	defer mask.Close()
+	require.NoError(t, mask.Cast(BandFormatUchar))
+	require.NoError(t, mask.DrawRect(ColorRGBA{R: 255}, 0, 0, 10, 20, true))
+
+	before, err := image.GetPoint(15, 5)
+	require.NoError(t, err)
+
+	green := ColorRGBA{G: 255, A: 255}
+	require.NoError(t, image.DrawMask(green, mask, 0, 0))
+
+	point, err := image.GetPoint(5, 5)
+	require.NoError(t, err)
+	assert.Equal(t, []float64{0, 255, 0}, point)
+	point, err = image.GetPoint(15, 5)
+	require.NoError(t, err)
+	assert.Equal(t, before, point)
+}
+
+func TestImageRef_Smudge(t *testing.T) {
+	require.NoError(t, Startup(nil))
+
+	image, err := Black(20, 20)
+	require.NoError(t, err)
+	defer image.Close()
+	require.NoError(t, image.DrawRect(ColorRGBA{R: 255}, 10, 0, 10, 20, true))
+
+	require.NoError(t, image.Smudge(5, 5, 10, 10))
+
+	point, err := image.GetPoint(10, 10)
+	require.NoError(t, err)
+	assert.Greater(t, point[0], float64(0))
+	assert.Less(t, point[0], float64(255))
+	assert.Equal(t, 20, image.Width())
+}
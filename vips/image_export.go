@@ -40,18 +40,11 @@ func (r *ImageRef) Export(params *ExportParams) ([]byte, *ImageMetadata, error)
 	case ImageTypeHEIF:
 		return r.ExportHeif(heifParamsFromExport(params))
 	case ImageTypeAVIF:
-		return r.ExportAvif(&AvifExportParams{
-			StripMetadata: params.StripMetadata,
-			Quality:       params.Quality,
-			Lossless:      params.Lossless,
-			Speed:         params.Speed,
-		})
+		return r.ExportAvif(avifParamsFromExport(params))
+	case ImageTypeJP2K:
+		return r.ExportJp2k(jp2kParamsFromExport(params))
 	case ImageTypeJXL:
-		return r.ExportJxl(&JxlExportParams{
-			Quality:  params.Quality,
-			Lossless: params.Lossless,
-			Effort:   params.Effort,
-		})
+		return r.ExportJxl(jxlParamsFromExport(params))
 	default:
 		format = ImageTypeJPEG
 		return r.ExportJpeg(jpegParamsFromExport(params))
@@ -59,8 +52,8 @@ func (r *ImageRef) Export(params *ExportParams) ([]byte, *ImageMetadata, error)
 }
 
 // The *ParamsFromExport helpers map the generic (deprecated) ExportParams
-// onto format-specific params. They are shared between Export and the
-// streaming SaveToWriter so the two paths cannot drift. A nil params
+// onto format-specific params. They are shared between Export, the
+// streaming SaveToWriter and SaveToFile so the paths cannot drift. A nil params
 // yields the format's defaults.
 
 func jpegParamsFromExport(params *ExportParams) *JpegExportParams {
@@ -123,8 +116,43 @@ func heifParamsFromExport(params *ExportParams) *HeifExportParams {
 		return NewHeifExportParams()
 	}
 	return &HeifExportParams{
-		Quality:  params.Quality,
-		Lossless: params.Lossless,
+		StripMetadata: params.StripMetadata,
+		Quality:       params.Quality,
+		Lossless:      params.Lossless,
+	}
+}
+
+func avifParamsFromExport(params *ExportParams) *AvifExportParams {
+	if params == nil {
+		return NewAvifExportParams()
+	}
+	return &AvifExportParams{
+		StripMetadata: params.StripMetadata,
+		Quality:       params.Quality,
+		Lossless:      params.Lossless,
+		Speed:         params.Speed,
+	}
+}
+
+func jp2kParamsFromExport(params *ExportParams) *Jp2kExportParams {
+	p := NewJp2kExportParams()
+	if params != nil {
+		p.Quality = params.Quality
+		p.Lossless = params.Lossless
+		p.SubsampleMode = params.SubsampleMode
+	}
+	return p
+}
+
+func jxlParamsFromExport(params *ExportParams) *JxlExportParams {
+	if params == nil {
+		return NewJxlExportParams()
+	}
+	return &JxlExportParams{
+		StripMetadata: params.StripMetadata,
+		Quality:       params.Quality,
+		Lossless:      params.Lossless,
+		Effort:        params.Effort,
 	}
 }
 
@@ -170,7 +198,7 @@ func (r *ImageRef) ExportJpeg(params *JpegExportParams) ([]byte, *ImageMetadata,
 		params = NewJpegExportParams()
 	}
 
-	buf, err := vipsSaveJPEGToBuffer(r.image, *params)
+	buf, err := vipsSaveJPEGToBuffer(r.Context(), r.image, *params)
 	if err != nil {
 		return nil, nil, err
 	}
@@ -185,7 +213,7 @@ func (r *ImageRef) ExportPng(params *PngExportParams) ([]byte, *ImageMetadata, e
 		params = NewPngExportParams()
 	}
 
-	buf, err := vipsSavePNGToBuffer(r.image, *params)
+	buf, err := vipsSavePNGToBuffer(r.Context(), r.image, *params)
 	if err != nil {
 		return nil, nil, err
 	}
@@ -203,7 +231,7 @@ func (r *ImageRef) ExportWebp(params *WebpExportParams) ([]byte, *ImageMetadata,
 	paramsWithIccProfile := *params
 	paramsWithIccProfile.IccProfile = r.optimizedIccProfile
 
-	buf, err := vipsSaveWebPToBuffer(r.image, paramsWithIccProfile)
+	buf, err := vipsSaveWebPToBuffer(r.Context(), r.image, paramsWithIccProfile)
 	if err != nil {
 		return nil, nil, err
 	}
@@ -218,7 +246,7 @@ func (r *ImageRef) ExportHeif(params *HeifExportParams) ([]byte, *ImageMetadata,
 		params = NewHeifExportParams()
 	}
 
-	buf, err := vipsSaveHEIFToBuffer(r.image, *params)
+	buf, err := vipsSaveHEIFToBuffer(r.Context(), r.image, *params)
 	if err != nil {
 		return nil, nil, err
 	}
@@ -233,7 +261,7 @@ func (r *ImageRef) ExportTiff(params *TiffExportParams) ([]byte, *ImageMetadata,
 		params = NewTiffExportParams()
 	}
 
-	buf, err := vipsSaveTIFFToBuffer(r.image, *params)
+	buf, err := vipsSaveTIFFToBuffer(r.Context(), r.image, *params)
 	if err != nil {
 		return nil, nil, err
 	}
@@ -248,7 +276,7 @@ func (r *ImageRef) ExportGIF(params *GifExportParams) ([]byte, *ImageMetadata, e
 		params = NewGifExportParams()
 	}
 
-	buf, err := vipsSaveGIFToBuffer(r.image, *params)
+	buf, err := vipsSaveGIFToBuffer(r.Context(), r.image, *params)
 	if err != nil {
 		return nil, nil, err
 	}
@@ -263,7 +291,7 @@ func (r *ImageRef) ExportAvif(params *AvifExportParams) ([]byte, *ImageMetadata,
 		params = NewAvifExportParams()
 	}
 
-	buf, err := vipsSaveAVIFToBuffer(r.image, *params)
+	buf, err := vipsSaveAVIFToBuffer(r.Context(), r.image, *params)
 	if err != nil {
 		return nil, nil, err
 	}
@@ -278,7 +306,7 @@ func (r *ImageRef) ExportJp2k(params *Jp2kExportParams) ([]byte, *ImageMetadata,
 		params = NewJp2kExportParams()
 	}
 
-	buf, err := vipsSaveJP2KToBuffer(r.image, *params)
+	buf, err := vipsSaveJP2KToBuffer(r.Context(), r.image, *params)
 	if err != nil {
 		return nil, nil, err
 	}
@@ -293,7 +321,7 @@ func (r *ImageRef) ExportJxl(params *JxlExportParams) ([]byte, *ImageMetadata, e
 		params = NewJxlExportParams()
 	}
 
-	buf, err := vipsSaveJxlToBuffer(r.image, *params)
+	buf, err := vipsSaveJxlToBuffer(r.Context(), r.image, *params)
 	if err != nil {
 		return nil, nil, err
 	}
@@ -309,7 +337,7 @@ func (r *ImageRef) ExportMagick(params *MagickExportParams) ([]byte, *ImageMetad
 		params.Format = "JPG"
 	}
 
-	buf, err := vipsSaveMagickToBuffer(r.image, *params)
+	buf, err := vipsSaveMagickToBuffer(r.Context(), r.image, *params)
 	if err != nil {
 		return nil, nil, err
 	}
@@ -317,6 +345,25 @@ func (r *ImageRef) ExportMagick(params *MagickExportParams) ([]byte, *ImageMetad
 	return buf, r.newMetadata(ImageTypeMagick), nil
 }
 
+// ExportWithOptions exports the image to a buffer with the saver opts
+// belong to, e.g. &JpegsaveOptions{RestartInterval: &interval}. Unlike the
+// Export*Params types, the generated options cover every option of the
+// saver; nil fields keep the libvips defaults.
+func (r *ImageRef) ExportWithOptions(opts SaveOptions) ([]byte, *ImageMetadata, error) {
+	defer runtime.KeepAlive(r)
+	if opts == nil {
+		return nil, nil, errors.New("save options required")
+	}
+
+	format := saveOptionsImageType(opts)
+	buf, err := vipsSaveWithOptionsToBuffer(r.Context(), r.image, opts, format)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return buf, r.newMetadata(format), nil
+}
+
 // ToBytes writes the image to memory in VIPs format and returns the raw bytes, useful for storage.
 func (r *ImageRef) ToBytes() ([]byte, error) {
 	defer runtime.KeepAlive(r)
@@ -355,7 +402,7 @@ func (r *ImageRef) ToGoImage() (image.Image, error) {
 	defer runtime.KeepAlive(r)
 
 	// Work on a copy to avoid mutating the receiver
-	tmp, err := vipsGenCopy(r.image, nil)
+	tmp, err := vipsGenCopy(r.Context(), r.image, nil)
 	if err != nil {
 		return nil, err
 	}
@@ -364,7 +411,7 @@ func (r *ImageRef) ToGoImage() (image.Image, error) {
 	// Convert to sRGB if needed (keep B_W for grayscale)
 	interp := Interpretation(int(tmp.Type))
 	if interp != InterpretationSRGB && interp != InterpretationBW {
-		out, err := vipsToColorSpace(tmp, InterpretationSRGB)
+		out, err := vipsToColorSpace(r.Context(), tmp, InterpretationSRGB)
 		if err != nil {
 			return nil, err
 		}
@@ -374,7 +421,7 @@ func (r *ImageRef) ToGoImage() (image.Image, error) {
 
 	// Cast to uchar if needed
 	if BandFormat(int(tmp.BandFmt)) != BandFormatUchar {
-		out, err := vipsGenCast(tmp, BandFormatUchar, nil)
+		out, err := vipsGenCast(r.Context(), tmp, BandFormatUchar, nil)
 		if err != nil {
 			return nil, err
 		}
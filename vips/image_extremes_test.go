@@ -0,0 +1,84 @@
+package vips
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestImageRef_MaxMin(t *testing.T) {
+	require.NoError(t, Startup(nil))
+
+	image, err := Black(100, 100)
+	require.NoError(t, err)
+	defer image.Close()
+	require.NoError(t, image.DrawRect(ColorRGBA{R: 200}, 10, 20, 1, 1, true))
+	require.NoError(t, image.DrawRect(ColorRGBA{R: 100}, 30, 40, 1, 1, true))
+	require.NoError(t, image.DrawRect(ColorRGBA{R: 50}, 50, 60, 1, 1, true))
+
+	value, x, y, err := image.Max()
+	require.NoError(t, err)
+	assert.Equal(t, float64(200), value)
+	assert.Equal(t, 10, x)
+	assert.Equal(t, 20, y)
+
+	top, err := image.MaxN(3)
+	require.NoError(t, err)
+	assert.Equal(t, []Extremum{
+		{Value: 200, X: 10, Y: 20},
+		{Value: 100, X: 30, Y: 40},
+		{Value: 50, X: 50, Y: 60},
+	}, top)
+
+	require.NoError(t, image.Invert())
+	bottom, err := image.MinN(2)
+	require.NoError(t, err)
+	assert.Equal(t, []Extremum{
+		{Value: 55, X: 10, Y: 20},
+		{Value: 155, X: 30, Y: 40},
+	}, bottom)
+}
+
+func TestImageRef_BandMaxMin(t *testing.T) {
+	require.NoError(t, Startup(nil))
+
+	image, err := NewImageFromFile(resources + "png-24bit.png")
+	require.NoError(t, err)
+	defer image.Close()
+	require.NoError(t, image.DrawRect(ColorRGBA{R: 0, G: 255, B: 0, A: 255}, 0, 0, 1, 1, true))
+
+	maxima, err := image.BandMaxN(1, 1)
+	require.NoError(t, err)
+	require.Len(t, maxima, 1)
+	assert.Equal(t, float64(255), maxima[0].Value)
+
+	minima, err := image.BandMinN(0, 1)
+	require.NoError(t, err)
+	require.Len(t, minima, 1)
+	assert.Equal(t, float64(0), minima[0].Value)
+
+	_, err = image.BandMaxN(5, 1)
+	assert.Error(t, err)
+}
+
+func TestImageRef_MeasurePatches(t *testing.T) {
+	require.NoError(t, Startup(nil))
+
+	image, err := Black(40, 20)
+	require.NoError(t, err)
+	defer image.Close()
+	require.NoError(t, image.BandJoinConst([]float64{0, 0}))
+	require.NoError(t, image.DrawRect(ColorRGBA{R: 10, G: 20, B: 30}, 0, 0, 10, 10, true))
+	require.NoError(t, image.DrawRect(ColorRGBA{R: 40, G: 50, B: 60}, 30, 10, 10, 10, true))
+
+	patches, err := image.MeasurePatches(0, 0, 40, 20, 4, 2)
+	require.NoError(t, err)
+	require.Len(t, patches, 8)
+	assert.Equal(t, []float64{10, 20, 30}, patches[0])
+	assert.Equal(t, []float64{0, 0, 0}, patches[1])
+	assert.Equal(t, []float64{40, 50, 60}, patches[7])
+
+	_, err = image.MeasurePatches(0, 0, 40, 20, 0, 2)
+	assert.Error(t, err)
+}
@@ -0,0 +1,161 @@
+package vips
+
+import (
+	"fmt"
+	"runtime"
+)
+
+// MetaValue is the typed value of a header field, as returned by
+// ImageRef.Field. The As methods report false rather than returning a zero
+// value when the field holds another kind of value.
+type MetaValue struct {
+	// Type is the kind of value held.
+	Type FieldType
+	// GType is the name of the GType of the value, for example "gint" or
+	// "VipsBlob".
+	GType string
+
+	value interface{}
+}
+
+// Value returns the value as an int, float64, bool, string, []byte, []int,
+// []float64 or *FieldImage depending on Type.
+func (m MetaValue) Value() interface{} {
+	return m.value
+}
+
+// AsInt returns the value of an int or bool field.
+func (m MetaValue) AsInt() (int, bool) {
+	switch v := m.value.(type) {
+	case int:
+		return v, true
+	case bool:
+		if v {
+			return 1, true
+		}
+		return 0, true
+	}
+	return 0, false
+}
+
+// AsDouble returns the value of a double or int field.
+func (m MetaValue) AsDouble() (float64, bool) {
+	switch v := m.value.(type) {
+	case float64:
+		return v, true
+	case int:
+		return float64(v), true
+	}
+	return 0, false
+}
+
+// AsBool returns the value of a bool field.
+func (m MetaValue) AsBool() (bool, bool) {
+	v, ok := m.value.(bool)
+	return v, ok
+}
+
+// AsString returns the value of a string field, or the nickname of an enum
+// field.
+func (m MetaValue) AsString() (string, bool) {
+	if m.Type != FieldTypeString && m.Type != FieldTypeEnum {
+		return "", false
+	}
+	v, ok := m.value.(string)
+	return v, ok
+}
+
+// AsBlob returns a copy of the data of a blob field.
+func (m MetaValue) AsBlob() ([]byte, bool) {
+	v, ok := m.value.([]byte)
+	return v, ok
+}
+
+// AsIntArray returns the value of an int array field.
+func (m MetaValue) AsIntArray() ([]int, bool) {
+	v, ok := m.value.([]int)
+	return v, ok
+}
+
+// AsDoubleArray returns the value of a double array or int array field.
+func (m MetaValue) AsDoubleArray() ([]float64, bool) {
+	switch v := m.value.(type) {
+	case []float64:
+		return v, true
+	case []int:
+		out := make([]float64, len(v))
+		for i, n := range v {
+			out[i] = float64(n)
+		}
+		return out, true
+	}
+	return nil, false
+}
+
+// AsImage returns the dimensions of an image field.
+func (m MetaValue) AsImage() (*FieldImage, bool) {
+	v, ok := m.value.(*FieldImage)
+	return v, ok
+}
+
+// Field returns the typed value of the header field name, or false if the
+// image has no such field.
+func (r *ImageRef) Field(name string) (MetaValue, bool) {
+	defer runtime.KeepAlive(r)
+	field, ok := vipsImageGetField(r.image, name)
+	if !ok {
+		return MetaValue{}, false
+	}
+
+	m := MetaValue{Type: field.Type, GType: field.GType, value: field.Value}
+	switch field.Type {
+	case FieldTypeBlob:
+		m.value = vipsImageGetBlob(r.image, name)
+	case FieldTypeImage:
+		m.value = field.Image
+	}
+	return m, true
+}
+
+// SetField sets the header field name. The value may be an int, float64,
+// string, []byte, []int, []float64 or *ImageRef; an image is referenced,
+// not copied.
+func (r *ImageRef) SetField(name string, value interface{}) error {
+	defer runtime.KeepAlive(r)
+	switch v := value.(type) {
+	case int:
+		vipsImageSetInt(r.image, name, v)
+	case float64:
+		vipsImageSetDouble(r.image, name, v)
+	case string:
+		vipsImageSetString(r.image, name, v)
+	case []byte:
+		vipsImageSetBlob(r.image, name, v)
+	case []int:
+		vipsImageSetArrayInt(r.image, name, v)
+	case []float64:
+		vipsImageSetArrayDouble(r.image, name, v)
+	case *ImageRef:
+		if v == nil || v.image == nil {
+			return fmt.Errorf("field %s: image is closed", name)
+		}
+		vipsImageSetImage(r.image, name, v.image)
+		runtime.KeepAlive(v)
+	default:
+		return fmt.Errorf("field %s: unsupported value type %T", name, value)
+	}
+	return nil
+}
+
+// TypedFields returns every header field with its type and value. Blobs
+// report their size rather than their data.
+func (r *ImageRef) TypedFields() []HeaderField {
+	defer runtime.KeepAlive(r)
+	fields := []HeaderField{}
+	for _, name := range vipsImageGetFields(r.image) {
+		if field, ok := vipsImageGetField(r.image, name); ok {
+			fields = append(fields, field)
+		}
+	}
+	return fields
+}
@@ -0,0 +1,128 @@
+package vips
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestImageRef_Field(t *testing.T) {
+	require.NoError(t, Startup(nil))
+
+	image, err := NewImageFromFile(resources + "jpg-24bit-icc-adobe-rgb.jpg")
+	require.NoError(t, err)
+	defer image.Close()
+
+	width, ok := image.Field("width")
+	require.True(t, ok)
+	assert.Equal(t, FieldTypeInt, width.Type)
+	n, ok := width.AsInt()
+	assert.True(t, ok)
+	assert.Equal(t, image.Width(), n)
+	f, ok := width.AsDouble()
+	assert.True(t, ok)
+	assert.Equal(t, float64(image.Width()), f)
+	_, ok = width.AsString()
+	assert.False(t, ok)
+
+	interpretation, ok := image.Field("interpretation")
+	require.True(t, ok)
+	s, ok := interpretation.AsString()
+	assert.True(t, ok)
+	assert.Equal(t, "srgb", s)
+
+	icc, ok := image.Field("icc-profile-data")
+	require.True(t, ok)
+	blob, ok := icc.AsBlob()
+	assert.True(t, ok)
+	assert.Equal(t, image.GetICCProfile(), blob)
+	_, ok = icc.AsInt()
+	assert.False(t, ok)
+
+	_, ok = image.Field("no-such-field")
+	assert.False(t, ok)
+}
+
+func TestImageRef_SetField(t *testing.T) {
+	require.NoError(t, Startup(nil))
+
+	image, err := NewImageFromFile(resources + "png-24bit.png")
+	require.NoError(t, err)
+	defer image.Close()
+
+	require.NoError(t, image.SetField("test-ints", []int{1, 2, 3}))
+	require.NoError(t, image.SetField("test-doubles", []float64{0.5, 1.5}))
+	require.NoError(t, image.SetField("test-blob", []byte("hello")))
+	require.NoError(t, image.SetField("test-string", "hello"))
+
+	v, ok := image.Field("test-ints")
+	require.True(t, ok)
+	assert.Equal(t, FieldTypeIntArray, v.Type)
+	ints, ok := v.AsIntArray()
+	assert.True(t, ok)
+	assert.Equal(t, []int{1, 2, 3}, ints)
+	doubles, ok := v.AsDoubleArray()
+	assert.True(t, ok)
+	assert.Equal(t, []float64{1, 2, 3}, doubles)
+
+	v, ok = image.Field("test-doubles")
+	require.True(t, ok)
+	doubles, ok = v.AsDoubleArray()
+	assert.True(t, ok)
+	assert.Equal(t, []float64{0.5, 1.5}, doubles)
+	_, ok = v.AsIntArray()
+	assert.False(t, ok)
+
+	v, ok = image.Field("test-blob")
+	require.True(t, ok)
+	blob, _ := v.AsBlob()
+	assert.Equal(t, []byte("hello"), blob)
+
+	v, ok = image.Field("test-string")
+	require.True(t, ok)
+	s, _ := v.AsString()
+	assert.Equal(t, "hello", s)
+
+	assert.Error(t, image.SetField("test-bad", struct{}{}))
+}
+
+func TestImageRef_SetField__Image(t *testing.T) {
+	require.NoError(t, Startup(nil))
+
+	image, err := NewImageFromFile(resources + "png-24bit.png")
+	require.NoError(t, err)
+	defer image.Close()
+
+	gainMap, err := Black(16, 8)
+	require.NoError(t, err)
+	defer gainMap.Close()
+
+	require.NoError(t, image.SetField("test-image", gainMap))
+
+	v, ok := image.Field("test-image")
+	require.True(t, ok)
+	assert.Equal(t, FieldTypeImage, v.Type)
+	dims, ok := v.AsImage()
+	require.True(t, ok)
+	assert.Equal(t, FieldImage{Width: 16, Height: 8, Bands: 1}, *dims)
+}
+
+func TestImageRef_TypedFields(t *testing.T) {
+	require.NoError(t, Startup(nil))
+
+	image, err := NewImageFromFile(resources + "png-24bit.png")
+	require.NoError(t, err)
+	defer image.Close()
+
+	require.NoError(t, image.SetField("test-ints", []int{4, 5}))
+
+	types := map[string]FieldType{}
+	for _, f := range image.TypedFields() {
+		types[f.Name] = f.Type
+	}
+	assert.Equal(t, FieldTypeInt, types["height"])
+	assert.Equal(t, FieldTypeDouble, types["yres"])
+	assert.Equal(t, FieldTypeIntArray, types["test-ints"])
+	assert.Len(t, types, len(image.ImageFields()))
+}
@@ -19,7 +19,7 @@ func (r *ImageRef) GetICCProfile() []byte {
 // Typically, browsers and other software assume images without profile to be in the sRGB color space.
 func (r *ImageRef) RemoveICCProfile() error {
 	defer runtime.KeepAlive(r)
-	out, err := vipsGenCopy(r.image, nil)
+	out, err := vipsGenCopy(r.Context(), r.image, nil)
 	if err != nil {
 		return err
 	}
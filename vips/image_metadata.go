@@ -73,7 +73,7 @@ func (r *ImageRef) GetOrientation() int {
 // SetOrientation sets the orientation in the EXIF header of the associated image.
 func (r *ImageRef) SetOrientation(orientation int) error {
 	defer runtime.KeepAlive(r)
-	out, err := vipsGenCopy(r.image, nil)
+	out, err := vipsGenCopy(r.Context(), r.image, nil)
 	if err != nil {
 		return err
 	}
@@ -87,7 +87,7 @@ func (r *ImageRef) SetOrientation(orientation int) error {
 // RemoveOrientation removes the EXIF orientation information of the image.
 func (r *ImageRef) RemoveOrientation() error {
 	defer runtime.KeepAlive(r)
-	out, err := vipsGenCopy(r.image, nil)
+	out, err := vipsGenCopy(r.Context(), r.image, nil)
 	if err != nil {
 		return err
 	}
@@ -166,7 +166,7 @@ func (r *ImageRef) GetPages() int {
 // For animated images this corresponds to the number of frames
 func (r *ImageRef) SetPages(pages int) error {
 	defer runtime.KeepAlive(r)
-	out, err := vipsGenCopy(r.image, nil)
+	out, err := vipsGenCopy(r.Context(), r.image, nil)
 	if err != nil {
 		return err
 	}
@@ -194,7 +194,7 @@ func (r *ImageRef) GetPageHeight() int {
 // For animated images this is used when "unrolling" back to frames
 func (r *ImageRef) SetPageHeight(height int) error {
 	defer runtime.KeepAlive(r)
-	out, err := vipsGenCopy(r.image, nil)
+	out, err := vipsGenCopy(r.Context(), r.image, nil)
 	if err != nil {
 		return err
 	}
@@ -213,7 +213,7 @@ func (r *ImageRef) PageDelay() ([]int, error) {
 		// should not call if not multi page
 		return nil, nil
 	}
-	return vipsImageGetDelay(r.image, n)
+	return vipsImageGetDelay(r.Context(), r.image, n)
 }
 
 // SetPageDelay set the page delay array for animation
@@ -223,7 +223,7 @@ func (r *ImageRef) SetPageDelay(delay []int) error {
 	for _, d := range delay {
 		data = append(data, C.int(d))
 	}
-	return vipsImageSetDelay(r.image, data)
+	return vipsImageSetDelay(r.Context(), r.image, data)
 }
 
 // Loop returns the loop count for animated images.
@@ -244,7 +244,7 @@ func (r *ImageRef) SetLoop(loop int) error {
 // Background get the background of image.
 func (r *ImageRef) Background() ([]float64, error) {
 	defer runtime.KeepAlive(r)
-	out, err := vipsImageGetBackground(r.image)
+	out, err := vipsImageGetBackground(r.Context(), r.image)
 	if err != nil {
 		return nil, err
 	}
@@ -325,7 +325,7 @@ func (r *ImageRef) GetExif() map[string]string {
 // because govips needs it to correctly display the image.
 func (r *ImageRef) RemoveMetadata(keep ...string) error {
 	defer runtime.KeepAlive(r)
-	out, err := vipsGenCopy(r.image, nil)
+	out, err := vipsGenCopy(r.Context(), r.image, nil)
 	if err != nil {
 		return err
 	}
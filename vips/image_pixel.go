@@ -19,7 +19,7 @@ func (r *ImageRef) GaussianBlur(sigmas ...float64) error {
 	if len(sigmas) >= 2 {
 		minAmpl = sigmas[1]
 	}
-	out, err := vipsGenGaussblur(r.image, sigma, &GaussblurOptions{MinAmpl: &minAmpl})
+	out, err := vipsGenGaussblur(r.Context(), r.image, sigma, &GaussblurOptions{MinAmpl: &minAmpl})
 	if err != nil {
 		return err
 	}
@@ -33,7 +33,7 @@ func (r *ImageRef) GaussianBlur(sigmas ...float64) error {
 // m2: slope for jaggy areas
 func (r *ImageRef) Sharpen(sigma float64, x1 float64, m2 float64) error {
 	defer runtime.KeepAlive(r)
-	out, err := vipsGenSharpen(r.image, &SharpenOptions{Sigma: &sigma, X1: &x1, M2: &m2})
+	out, err := vipsGenSharpen(r.Context(), r.image, &SharpenOptions{Sigma: &sigma, X1: &x1, M2: &m2})
 	if err != nil {
 		return err
 	}
@@ -44,7 +44,7 @@ func (r *ImageRef) Sharpen(sigma float64, x1 float64, m2 float64) error {
 // Apply Sobel edge detector to the image.
 func (r *ImageRef) Sobel() error {
 	defer runtime.KeepAlive(r)
-	out, err := vipsGenSobel(r.image)
+	out, err := vipsGenSobel(r.Context(), r.image)
 	if err != nil {
 		return err
 	}
@@ -57,7 +57,7 @@ func (r *ImageRef) Sobel() error {
 // index is output. index numbers from 0.
 func (r *ImageRef) Rank(width int, height int, index int) error {
 	defer runtime.KeepAlive(r)
-	out, err := vipsGenRank(r.image, width, height, index)
+	out, err := vipsGenRank(r.Context(), r.image, width, height, index)
 	if err != nil {
 		return err
 	}
@@ -68,7 +68,7 @@ func (r *ImageRef) Rank(width int, height int, index int) error {
 // Mapim resamples an image using index to look up pixels
 func (r *ImageRef) Mapim(index *ImageRef) error {
 	defer runtime.KeepAlive(r)
-	out, err := vipsGenMapim(r.image, index.image, nil)
+	out, err := vipsGenMapim(r.Context(), r.image, index.image, nil)
 	if err != nil {
 		return err
 	}
@@ -79,7 +79,7 @@ func (r *ImageRef) Mapim(index *ImageRef) error {
 // Maplut maps an image through another image acting as a LUT (Look Up Table)
 func (r *ImageRef) Maplut(lut *ImageRef) error {
 	defer runtime.KeepAlive(r)
-	out, err := vipsGenMaplut(r.image, lut.image, nil)
+	out, err := vipsGenMaplut(r.Context(), r.image, lut.image, nil)
 	if err != nil {
 		return err
 	}
@@ -90,7 +90,7 @@ func (r *ImageRef) Maplut(lut *ImageRef) error {
 // ExtractBand extracts one or more bands out of the image (replacing the associated ImageRef)
 func (r *ImageRef) ExtractBand(band int, num int) error {
 	defer runtime.KeepAlive(r)
-	out, err := vipsGenExtractBand(r.image, band, &ExtractBandOptions{N: &num})
+	out, err := vipsGenExtractBand(r.Context(), r.image, band, &ExtractBandOptions{N: &num})
 	if err != nil {
 		return err
 	}
@@ -101,7 +101,7 @@ func (r *ImageRef) ExtractBand(band int, num int) error {
 // ExtractBandToImage extracts one or more bands out of the image to a new image
 func (r *ImageRef) ExtractBandToImage(band int, num int) (*ImageRef, error) {
 	defer runtime.KeepAlive(r)
-	out, err := vipsGenExtractBand(r.image, band, &ExtractBandOptions{N: &num})
+	out, err := vipsGenExtractBand(r.Context(), r.image, band, &ExtractBandOptions{N: &num})
 	if err != nil {
 		return nil, err
 	}
@@ -116,7 +116,7 @@ func (r *ImageRef) BandJoin(images ...*ImageRef) error {
 		vipsImages = append(vipsImages, vipsImage.image)
 	}
 
-	out, err := vipsGenBandjoin(vipsImages)
+	out, err := vipsGenBandjoin(r.Context(), vipsImages)
 	if err != nil {
 		return err
 	}
@@ -130,7 +130,7 @@ func (r *ImageRef) BandSplit() ([]*ImageRef, error) {
 	var out []*ImageRef
 	n := 1
 	for i := 0; i < r.Bands(); i++ {
-		img, err := vipsGenExtractBand(r.image, i, &ExtractBandOptions{N: &n})
+		img, err := vipsGenExtractBand(r.Context(), r.image, i, &ExtractBandOptions{N: &n})
 		if err != nil {
 			return out, err
 		}
@@ -145,7 +145,7 @@ func (r *ImageRef) BandJoinConst(constants []float64) error {
 	if len(constants) == 0 {
 		return errors.New("BandJoinConst: empty constants slice")
 	}
-	out, err := vipsGenBandjoinConst(r.image, constants)
+	out, err := vipsGenBandjoinConst(r.Context(), r.image, constants)
 	if err != nil {
 		return err
 	}
@@ -160,7 +160,7 @@ func (r *ImageRef) AddAlpha() error {
 		return nil
 	}
 
-	out, err := vipsAddAlpha(r.image)
+	out, err := vipsAddAlpha(r.Context(), r.image)
 	if err != nil {
 		return err
 	}
@@ -178,7 +178,7 @@ func (r *ImageRef) PremultiplyAlpha() error {
 
 	band := r.BandFormat()
 
-	out, err := vipsGenPremultiply(r.image, nil)
+	out, err := vipsGenPremultiply(r.Context(), r.image, nil)
 	if err != nil {
 		return err
 	}
@@ -197,13 +197,13 @@ func (r *ImageRef) UnpremultiplyAlpha() error {
 		return nil
 	}
 
-	unpremultiplied, err := vipsGenUnpremultiply(r.image, nil)
+	unpremultiplied, err := vipsGenUnpremultiply(r.Context(), r.image, nil)
 	if err != nil {
 		return err
 	}
 	defer clearImage(unpremultiplied)
 
-	out, err := vipsGenCast(unpremultiplied, r.preMultiplication.bandFormat, nil)
+	out, err := vipsGenCast(r.Context(), unpremultiplied, r.preMultiplication.bandFormat, nil)
 	if err != nil {
 		return err
 	}
@@ -216,7 +216,7 @@ func (r *ImageRef) UnpremultiplyAlpha() error {
 // Add calculates a sum of the image + addend and stores it back in the image
 func (r *ImageRef) Add(addend *ImageRef) error {
 	defer runtime.KeepAlive(r)
-	out, err := vipsGenAdd(r.image, addend.image)
+	out, err := vipsGenAdd(r.Context(), r.image, addend.image)
 	if err != nil {
 		return err
 	}
@@ -227,7 +227,7 @@ func (r *ImageRef) Add(addend *ImageRef) error {
 // Multiply calculates the product of the image * multiplier and stores it back in the image
 func (r *ImageRef) Multiply(multiplier *ImageRef) error {
 	defer runtime.KeepAlive(r)
-	out, err := vipsGenMultiply(r.image, multiplier.image)
+	out, err := vipsGenMultiply(r.Context(), r.image, multiplier.image)
 	if err != nil {
 		return err
 	}
@@ -238,7 +238,7 @@ func (r *ImageRef) Multiply(multiplier *ImageRef) error {
 // Divide calculates the product of the image / denominator and stores it back in the image
 func (r *ImageRef) Divide(denominator *ImageRef) error {
 	defer runtime.KeepAlive(r)
-	out, err := vipsGenDivide(r.image, denominator.image)
+	out, err := vipsGenDivide(r.Context(), r.image, denominator.image)
 	if err != nil {
 		return err
 	}
@@ -249,7 +249,7 @@ func (r *ImageRef) Divide(denominator *ImageRef) error {
 // Average finds the average value in an image
 func (r *ImageRef) Average() (float64, error) {
 	defer runtime.KeepAlive(r)
-	out, err := vipsGenAvg(r.image)
+	out, err := vipsGenAvg(r.Context(), r.image)
 	if err != nil {
 		return 0, err
 	}
@@ -260,14 +260,14 @@ func (r *ImageRef) Average() (float64, error) {
 // Returned values are left, top, width, height
 func (r *ImageRef) FindTrim(threshold float64, backgroundColor *Color) (int, int, int, int, error) {
 	defer runtime.KeepAlive(r)
-	return vipsFindTrim(r.image, threshold, backgroundColor)
+	return vipsFindTrim(r.Context(), r.image, threshold, backgroundColor)
 }
 
 // GetPoint reads a single pixel on an image.
 // The pixel values are returned in a slice with one element per band.
 func (r *ImageRef) GetPoint(x int, y int) ([]float64, error) {
 	defer runtime.KeepAlive(r)
-	return vipsGetPoint(r.image, r.Bands(), x, y)
+	return vipsGetPoint(r.Context(), r.image, r.Bands(), x, y)
 }
 
 // Stats find many image statistics in a single pass through the data. Image is changed into a one-band
@@ -281,7 +281,7 @@ func (r *ImageRef) GetPoint(x int, y int) ([]float64, error) {
 // If there is more than one maxima or minima, one of them will be chosen at random.
 func (r *ImageRef) Stats() error {
 	defer runtime.KeepAlive(r)
-	out, err := vipsGenStats(r.image)
+	out, err := vipsGenStats(r.Context(), r.image)
 	if err != nil {
 		return err
 	}
@@ -294,7 +294,7 @@ func (r *ImageRef) Stats() error {
 // char and uchar images are cast to uchar before histogramming, all other image types are cast to ushort.
 func (r *ImageRef) HistogramFind() error {
 	defer runtime.KeepAlive(r)
-	out, err := vipsGenHistFind(r.image, nil)
+	out, err := vipsGenHistFind(r.Context(), r.image, nil)
 	if err != nil {
 		return err
 	}
@@ -305,7 +305,7 @@ func (r *ImageRef) HistogramFind() error {
 // HistogramCumulative form cumulative histogram.
 func (r *ImageRef) HistogramCumulative() error {
 	defer runtime.KeepAlive(r)
-	out, err := vipsGenHistCum(r.image)
+	out, err := vipsGenHistCum(r.Context(), r.image)
 	if err != nil {
 		return err
 	}
@@ -318,7 +318,7 @@ func (r *ImageRef) HistogramCumulative() error {
 // image becomes 255. Normalise each band separately.
 func (r *ImageRef) HistogramNormalise() error {
 	defer runtime.KeepAlive(r)
-	out, err := vipsGenHistNorm(r.image)
+	out, err := vipsGenHistNorm(r.Context(), r.image)
 	if err != nil {
 		return err
 	}
@@ -331,23 +331,13 @@ func (r *ImageRef) HistogramNormalise() error {
 // where p is histogram-value / sum-of-histogram-values.
 func (r *ImageRef) HistogramEntropy() (float64, error) {
 	defer runtime.KeepAlive(r)
-	return vipsGenHistEntropy(r.image)
-}
-
-// DrawRect draws an (optionally filled) rectangle with a single colour
-func (r *ImageRef) DrawRect(ink ColorRGBA, left int, top int, width int, height int, fill bool) error {
-	defer runtime.KeepAlive(r)
-	err := vipsDrawRect(r.image, ink, left, top, width, height, fill)
-	if err != nil {
-		return err
-	}
-	return nil
+	return vipsGenHistEntropy(r.Context(), r.image)
 }
 
 // Subtract calculate subtract operation between two images.
 func (r *ImageRef) Subtract(in2 *ImageRef) error {
 	defer runtime.KeepAlive(r)
-	out, err := vipsGenSubtract(r.image, in2.image)
+	out, err := vipsGenSubtract(r.Context(), r.image, in2.image)
 	if err != nil {
 		return err
 	}
@@ -359,7 +349,7 @@ func (r *ImageRef) Subtract(in2 *ImageRef) error {
 // Abs calculate abs operation.
 func (r *ImageRef) Abs() error {
 	defer runtime.KeepAlive(r)
-	out, err := vipsGenAbs(r.image)
+	out, err := vipsGenAbs(r.Context(), r.image)
 	if err != nil {
 		return err
 	}
@@ -371,16 +361,77 @@ func (r *ImageRef) Abs() error {
 // Project calculate project operation.
 func (r *ImageRef) Project() (*ImageRef, *ImageRef, error) {
 	defer runtime.KeepAlive(r)
-	col, row, err := vipsGenProject(r.image)
+	col, row, err := vipsGenProject(r.Context(), r.image)
 	if err != nil {
 		return nil, nil, err
 	}
 
-	return newImageRef(col, r.format, r.originalFormat, nil), newImageRef(row, r.format, r.originalFormat, nil), nil
+	colRef := newImageRef(col, r.format, r.originalFormat, nil)
+	colRef.ctx = r.ctx
+	rowRef := newImageRef(row, r.format, r.originalFormat, nil)
+	rowRef.ctx = r.ctx
+	return colRef, rowRef, nil
 }
 
 // Min finds the minimum value in an image.
 func (r *ImageRef) Min() (float64, int, int, error) {
 	defer runtime.KeepAlive(r)
-	return vipsMin(r.image)
+	return vipsMin(r.Context(), r.image)
+}
+
+// Max finds the maximum value in an image and its x and y position.
+func (r *ImageRef) Max() (float64, int, int, error) {
+	defer runtime.KeepAlive(r)
+	extremes, err := vipsExtremes(r.Context(), r.image, -1, 1, true)
+	if err != nil {
+		return 0, 0, 0, err
+	}
+	if len(extremes) == 0 {
+		return 0, 0, 0, errors.New("no maximum found")
+	}
+	return extremes[0].Value, extremes[0].X, extremes[0].Y, nil
+}
+
+// Extremum is a value found by MaxN or MinN and its position in the image.
+type Extremum struct {
+	Value float64
+	X, Y  int
+}
+
+// MaxN finds the n largest values in an image, across all bands, and their
+// positions, largest first.
+func (r *ImageRef) MaxN(n int) ([]Extremum, error) {
+	defer runtime.KeepAlive(r)
+	return vipsExtremes(r.Context(), r.image, -1, n, true)
+}
+
+// MinN finds the n smallest values in an image, across all bands, and their
+// positions, smallest first.
+func (r *ImageRef) MinN(n int) ([]Extremum, error) {
+	defer runtime.KeepAlive(r)
+	return vipsExtremes(r.Context(), r.image, -1, n, false)
+}
+
+// BandMaxN is MaxN for a single band.
+func (r *ImageRef) BandMaxN(band int, n int) ([]Extremum, error) {
+	defer runtime.KeepAlive(r)
+	return vipsExtremes(r.Context(), r.image, band, n, true)
+}
+
+// BandMinN is MinN for a single band.
+func (r *ImageRef) BandMinN(band int, n int) ([]Extremum, error) {
+	defer runtime.KeepAlive(r)
+	return vipsExtremes(r.Context(), r.image, band, n, false)
+}
+
+// MeasurePatches measures a colour chart: the given area is divided into a
+// grid of hPatches by vPatches patches and the centre of each patch is
+// averaged. The result has one entry per patch, numbered left to right and
+// then top to bottom, each holding the average of every band.
+func (r *ImageRef) MeasurePatches(left, top, width, height, hPatches, vPatches int) ([][]float64, error) {
+	defer runtime.KeepAlive(r)
+	if hPatches < 1 || vPatches < 1 {
+		return nil, errors.New("number of patches must be at least 1")
+	}
+	return vipsMeasurePatches(r.Context(), r.image, left, top, width, height, hPatches, vPatches)
 }
@@ -0,0 +1,139 @@
+package vips
+
+// #include "operations.h"
+import "C"
+
+import (
+	"errors"
+	"fmt"
+	"runtime"
+)
+
+// IfThenElse uses the image as a condition and replaces it with then where
+// the condition is non-zero and with els where it is zero. With blend set
+// the condition is read as a 0-255 mask instead, and the branches are mixed
+// in proportion, which softens the edges of a segmentation mask.
+//
+// A branch is an *ImageRef or a constant, given as an int, a float64 or a
+// []float64 with one element per band. Constants take the size and format
+// of the other branch when it is an image, or else of the condition.
+func (r *ImageRef) IfThenElse(then, els any, blend bool) error {
+	defer runtime.KeepAlive(r)
+	images, release, err := selectBranches("IfThenElse", r, []any{then, els})
+	if err != nil {
+		return err
+	}
+	defer release()
+
+	out, err := vipsGenIfthenelse(r.Context(), r.image, images[0], images[1], &IfthenelseOptions{Blend: &blend})
+	if err != nil {
+		return err
+	}
+	r.setImage(out)
+	return nil
+}
+
+// Case uses the image as an index and replaces it with cases[i] where the
+// index is i. Pixels whose index is past the end take the last case. Cases
+// are images or constants, as in IfThenElse; constants take the size and
+// format of the first case that is an image, or else of the index.
+func (r *ImageRef) Case(cases []any) error {
+	defer runtime.KeepAlive(r)
+	if len(cases) == 0 {
+		return errors.New("Case: no cases")
+	}
+	images, release, err := selectBranches("Case", r, cases)
+	if err != nil {
+		return err
+	}
+	defer release()
+
+	out, err := vipsCase(r.Context(), r.image, images)
+	if err != nil {
+		return err
+	}
+	r.setImage(out)
+	return nil
+}
+
+// Switch returns an index image for use with Case. Each pixel holds the
+// position of the first condition that is non-zero there, or
+// len(conditions) where none is. Conditions are usually the result of
+// comparisons, such as a segmentation mask per class. The result is traced
+// under the context of the first condition.
+func Switch(conditions []*ImageRef) (*ImageRef, error) {
+	if len(conditions) == 0 {
+		return nil, errors.New("Switch: no conditions")
+	}
+	defer runtime.KeepAlive(conditions)
+
+	tests := make([]*C.VipsImage, len(conditions))
+	for i, c := range conditions {
+		if c == nil {
+			return nil, fmt.Errorf("Switch: condition %d is nil", i)
+		}
+		tests[i] = c.image
+	}
+
+	out, err := vipsSwitch(conditions[0].Context(), tests)
+	if err != nil {
+		return nil, err
+	}
+	ref := newImageRef(out, ImageTypeUnknown, ImageTypeUnknown, nil)
+	ref.ctx = conditions[0].ctx
+	return ref, nil
+}
+
+// selectBranches converts the branches of IfThenElse and Case to images.
+// Constants are matched to the first branch that is an image, or to match,
+// the way pyvips does it. release drops the images made for constants once
+// the operation holds its own references.
+func selectBranches(name string, match *ImageRef, branches []any) ([]*C.VipsImage, func(), error) {
+	like := match.image
+	for _, b := range branches {
+		if image, ok := b.(*ImageRef); ok && image != nil {
+			like = image.image
+			break
+		}
+	}
+
+	images := make([]*C.VipsImage, len(branches))
+	var owned []*C.VipsImage
+	release := func() {
+		for _, image := range owned {
+			clearImage(image)
+		}
+		runtime.KeepAlive(branches)
+	}
+
+	for i, b := range branches {
+		var constant []float64
+		switch v := b.(type) {
+		case *ImageRef:
+			if v == nil {
+				release()
+				return nil, nil, fmt.Errorf("%s: branch %d is nil", name, i)
+			}
+			images[i] = v.image
+			continue
+		case int:
+			constant = []float64{float64(v)}
+		case float64:
+			constant = []float64{v}
+		case []float64:
+			constant = v
+		default:
+			release()
+			return nil, nil, fmt.Errorf("%s: branch %d: cannot use %T as an image or constant", name, i, b)
+		}
+
+		image, err := vipsConstantImage(like, constant)
+		if err != nil {
+			release()
+			return nil, nil, fmt.Errorf("%s: branch %d: %w", name, i, err)
+		}
+		owned = append(owned, image)
+		images[i] = image
+	}
+	return images, release, nil
+}
@@ -0,0 +1,112 @@
+package vips
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// selectMask returns a 4x1 image that is value in the first width columns
+// and 0 elsewhere.
+func selectMask(t *testing.T, width int, value uint8) *ImageRef {
+	t.Helper()
+	mask, err := Black(4, 1)
+	require.NoError(t, err)
+	if width > 0 {
+		require.NoError(t, mask.DrawRect(ColorRGBA{R: value}, 0, 0, width, 1, true))
+	}
+	return mask
+}
+
+func assertRow(t *testing.T, image *ImageRef, expected ...float64) {
+	t.Helper()
+	for x, want := range expected {
+		got, err := image.GetPoint(x, 0)
+		require.NoError(t, err)
+		assert.InDelta(t, want, got[0], 1, "x=%d", x)
+	}
+}
+
+func TestIfThenElse_Constants(t *testing.T) {
+	require.NoError(t, Startup(nil))
+
+	cond := selectMask(t, 2, 255)
+	defer cond.Close()
+
+	require.NoError(t, cond.IfThenElse(10, 20.0, false))
+	assertRow(t, cond, 10, 10, 20, 20)
+}
+
+func TestIfThenElse_Images(t *testing.T) {
+	require.NoError(t, Startup(nil))
+
+	cond := selectMask(t, 1, 255)
+	defer cond.Close()
+	then, err := Black(4, 1)
+	require.NoError(t, err)
+	defer then.Close()
+	require.NoError(t, then.Linear([]float64{1}, []float64{100}))
+
+	require.NoError(t, cond.IfThenElse(then, []float64{5}, false))
+	assertRow(t, cond, 100, 5, 5, 5)
+}
+
+func TestIfThenElse_Blend(t *testing.T) {
+	require.NoError(t, Startup(nil))
+
+	cond := selectMask(t, 2, 128)
+	defer cond.Close()
+
+	require.NoError(t, cond.IfThenElse(200, 0, true))
+	assertRow(t, cond, 100, 100, 0, 0)
+}
+
+func TestSwitchCase(t *testing.T) {
+	require.NoError(t, Startup(nil))
+
+	first := selectMask(t, 1, 255)
+	defer first.Close()
+	second := selectMask(t, 2, 255)
+	defer second.Close()
+
+	index, err := Switch([]*ImageRef{first, second})
+	require.NoError(t, err)
+	defer index.Close()
+	assertRow(t, index, 0, 1, 2, 2)
+
+	fill, err := Black(4, 1)
+	require.NoError(t, err)
+	defer fill.Close()
+	require.NoError(t, fill.Linear([]float64{1}, []float64{30}))
+
+	selected, err := index.Copy()
+	require.NoError(t, err)
+	defer selected.Close()
+	require.NoError(t, selected.Case([]any{10, 20, fill}))
+	assertRow(t, selected, 10, 20, 30, 30)
+
+	// Indexes past the last case take the last case.
+	require.NoError(t, index.Case([]any{10, 20}))
+	assertRow(t, index, 10, 20, 20, 20)
+}
+
+func TestSelect_Errors(t *testing.T) {
+	require.NoError(t, Startup(nil))
+
+	cond := selectMask(t, 1, 255)
+	defer cond.Close()
+
+	err := cond.IfThenElse("red", 0, false)
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "cannot use string as an image or constant")
+
+	err = cond.IfThenElse(1, (*ImageRef)(nil), false)
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "branch 1 is nil")
+
+	assert.Error(t, cond.Case(nil))
+
+	_, err = Switch(nil)
+	assert.Error(t, err)
+}
@@ -38,7 +38,7 @@ func GetRotationAngleFromExif(orientation int) (Angle, bool) {
 // todo: use https://www.libvips.org/API/current/libvips-conversion.html#vips-autorot-remove-angle
 func (r *ImageRef) AutoRotate() error {
 	defer runtime.KeepAlive(r)
-	out, _, _, err := vipsGenAutorot(r.image)
+	out, _, _, err := vipsGenAutorot(r.Context(), r.image)
 	if err != nil {
 		return err
 	}
@@ -51,13 +51,13 @@ func (r *ImageRef) ExtractArea(left, top, width, height int) error {
 	defer runtime.KeepAlive(r)
 	if r.Height() > r.PageHeight() {
 		// use animated extract area if more than 1 pages loaded
-		out, err := vipsExtractAreaMultiPage(r.image, left, top, width, height)
+		out, err := vipsExtractAreaMultiPage(r.Context(), r.image, left, top, width, height)
 		if err != nil {
 			return err
 		}
 		r.setImage(out)
 	} else {
-		out, err := vipsExtractArea(r.image, left, top, width, height)
+		out, err := vipsExtractArea(r.Context(), r.image, left, top, width, height)
 		if err != nil {
 			return err
 		}
@@ -82,7 +82,7 @@ func (r *ImageRef) ResizeWithVScale(hScale, vScale float64, kernel Kernel) error
 	pages := r.Pages()
 	pageHeight := r.PageHeight()
 
-	out, err := vipsResizeWithVScale(r.image, hScale, vScale, kernel)
+	out, err := vipsResizeWithVScale(r.Context(), r.image, hScale, vScale, kernel)
 	if err != nil {
 		return err
 	}
@@ -102,11 +102,16 @@ func (r *ImageRef) ResizeWithVScale(hScale, vScale float64, kernel Kernel) error
 	return r.UnpremultiplyAlpha()
 }
 
+// MaxCoord is libvips' VIPS_MAX_COORD, the largest width or height it
+// handles. Pass it to Thumbnail for a side that should not constrain the
+// result.
+const MaxCoord = 10000000
+
 // Thumbnail resizes the image to the given width and height.
 // crop decides algorithm vips uses to shrink and crop to fill target,
 func (r *ImageRef) Thumbnail(width, height int, crop Interesting) error {
 	defer runtime.KeepAlive(r)
-	out, err := vipsThumbnail(r.image, width, height, crop, SizeBoth)
+	out, err := vipsThumbnail(r.Context(), r.image, width, height, crop, SizeBoth)
 	if err != nil {
 		return err
 	}
@@ -119,7 +124,7 @@ func (r *ImageRef) Thumbnail(width, height int, crop Interesting) error {
 // size controls upsize, downsize, both or force
 func (r *ImageRef) ThumbnailWithSize(width, height int, crop Interesting, size Size) error {
 	defer runtime.KeepAlive(r)
-	out, err := vipsThumbnail(r.image, width, height, crop, size)
+	out, err := vipsThumbnail(r.Context(), r.image, width, height, crop, size)
 	if err != nil {
 		return err
 	}
@@ -131,13 +136,13 @@ func (r *ImageRef) ThumbnailWithSize(width, height int, crop Interesting, size S
 func (r *ImageRef) Embed(left, top, width, height int, extend ExtendStrategy) error {
 	defer runtime.KeepAlive(r)
 	if r.Height() > r.PageHeight() {
-		out, err := vipsEmbedMultiPage(r.image, left, top, width, height, extend)
+		out, err := vipsEmbedMultiPage(r.Context(), r.image, left, top, width, height, extend)
 		if err != nil {
 			return err
 		}
 		r.setImage(out)
 	} else {
-		out, err := vipsEmbed(r.image, left, top, width, height, extend)
+		out, err := vipsEmbed(r.Context(), r.image, left, top, width, height, extend)
 		if err != nil {
 			return err
 		}
@@ -157,13 +162,13 @@ func (r *ImageRef) EmbedBackground(left, top, width, height int, backgroundColor
 		A: 255,
 	}
 	if r.Height() > r.PageHeight() {
-		out, err := vipsEmbedMultiPageBackground(r.image, left, top, width, height, c)
+		out, err := vipsEmbedMultiPageBackground(r.Context(), r.image, left, top, width, height, c)
 		if err != nil {
 			return err
 		}
 		r.setImage(out)
 	} else {
-		out, err := vipsEmbedBackground(r.image, left, top, width, height, c)
+		out, err := vipsEmbedBackground(r.Context(), r.image, left, top, width, height, c)
 		if err != nil {
 			return err
 		}
@@ -178,13 +183,13 @@ func (r *ImageRef) EmbedBackground(left, top, width, height int, backgroundColor
 func (r *ImageRef) EmbedBackgroundRGBA(left, top, width, height int, backgroundColor *ColorRGBA) error {
 	defer runtime.KeepAlive(r)
 	if r.Height() > r.PageHeight() {
-		out, err := vipsEmbedMultiPageBackground(r.image, left, top, width, height, backgroundColor)
+		out, err := vipsEmbedMultiPageBackground(r.Context(), r.image, left, top, width, height, backgroundColor)
 		if err != nil {
 			return err
 		}
 		r.setImage(out)
 	} else {
-		out, err := vipsEmbedBackground(r.image, left, top, width, height, backgroundColor)
+		out, err := vipsEmbedBackground(r.Context(), r.image, left, top, width, height, backgroundColor)
 		if err != nil {
 			return err
 		}
@@ -196,7 +201,7 @@ func (r *ImageRef) EmbedBackgroundRGBA(left, top, width, height int, backgroundC
 // Zoom zooms the image by repeating pixels (fast nearest-neighbour)
 func (r *ImageRef) Zoom(xFactor int, yFactor int) error {
 	defer runtime.KeepAlive(r)
-	out, err := vipsGenZoom(r.image, xFactor, yFactor)
+	out, err := vipsGenZoom(r.Context(), r.image, xFactor, yFactor)
 	if err != nil {
 		return err
 	}
@@ -206,7 +211,7 @@ func (r *ImageRef) Zoom(xFactor int, yFactor int) error {
 
 func (r *ImageRef) Gravity(gravity Gravity, width int, height int) error {
 	defer runtime.KeepAlive(r)
-	out, err := vipsGenGravity(r.image, gravity, width, height, nil)
+	out, err := vipsGenGravity(r.Context(), r.image, gravity, width, height, nil)
 	if err != nil {
 		return err
 	}
@@ -218,7 +223,7 @@ func (r *ImageRef) Gravity(gravity Gravity, width int, height int) error {
 // Flip flips the image either horizontally or vertically based on the parameter
 func (r *ImageRef) Flip(direction Direction) error {
 	defer runtime.KeepAlive(r)
-	out, err := vipsFlip(r.image, direction)
+	out, err := vipsFlip(r.Context(), r.image, direction)
 	if err != nil {
 		return err
 	}
@@ -265,7 +270,7 @@ func (r *ImageRef) Recomb(matrix [][]float64) error {
 	}
 
 	// Recombine the image using the matrix
-	out, err := vipsGenRecomb(r.image, matrixImage)
+	out, err := vipsGenRecomb(r.Context(), r.image, matrixImage)
 	runtime.KeepAlive(matrixValues)
 	if err != nil {
 		return err
@@ -299,7 +304,7 @@ func (r *ImageRef) Rotate(angle Angle) error {
 
 	}
 
-	out, err := vipsGenRot(r.image, angle)
+	out, err := vipsGenRot(r.Context(), r.image, angle)
 	if err != nil {
 		return err
 	}
@@ -320,7 +325,7 @@ func (r *ImageRef) Rotate(angle Angle) error {
 func (r *ImageRef) Similarity(scale float64, angle float64, backgroundColor *ColorRGBA,
 	idx float64, idy float64, odx float64, ody float64) error {
 	defer runtime.KeepAlive(r)
-	out, err := vipsSimilarity(r.image, scale, angle, backgroundColor, idx, idy, odx, ody)
+	out, err := vipsSimilarity(r.Context(), r.image, scale, angle, backgroundColor, idx, idy, odx, ody)
 	if err != nil {
 		return err
 	}
@@ -331,7 +336,7 @@ func (r *ImageRef) Similarity(scale float64, angle float64, backgroundColor *Col
 // Grid tiles the image pages into a matrix across*down
 func (r *ImageRef) Grid(tileHeight, across, down int) error {
 	defer runtime.KeepAlive(r)
-	out, err := vipsGenGrid(r.image, tileHeight, across, down)
+	out, err := vipsGenGrid(r.Context(), r.image, tileHeight, across, down)
 	if err != nil {
 		return err
 	}
@@ -342,7 +347,7 @@ func (r *ImageRef) Grid(tileHeight, across, down int) error {
 // SmartCrop will crop the image based on interesting factor
 func (r *ImageRef) SmartCrop(width int, height int, interesting Interesting) error {
 	defer runtime.KeepAlive(r)
-	out, err := vipsSmartCrop(r.image, width, height, interesting)
+	out, err := vipsSmartCrop(r.Context(), r.image, width, height, interesting)
 	if err != nil {
 		return err
 	}
@@ -353,7 +358,7 @@ func (r *ImageRef) SmartCrop(width int, height int, interesting Interesting) err
 // Crop will crop the image based on coordinate and box size
 func (r *ImageRef) Crop(left int, top int, width int, height int) error {
 	defer runtime.KeepAlive(r)
-	out, err := vipsCrop(r.image, left, top, width, height)
+	out, err := vipsCrop(r.Context(), r.image, left, top, width, height)
 	if err != nil {
 		return err
 	}
@@ -364,7 +369,7 @@ func (r *ImageRef) Crop(left int, top int, width int, height int) error {
 // Label overlays a label on top of the image
 func (r *ImageRef) Label(labelParams *LabelParams) error {
 	defer runtime.KeepAlive(r)
-	out, err := labelImage(r.image, labelParams)
+	out, err := labelImage(r.Context(), r.image, labelParams)
 	if err != nil {
 		return err
 	}
@@ -375,7 +380,7 @@ func (r *ImageRef) Label(labelParams *LabelParams) error {
 // Replicate repeats an image many times across and down
 func (r *ImageRef) Replicate(across int, down int) error {
 	defer runtime.KeepAlive(r)
-	out, err := vipsGenReplicate(r.image, across, down)
+	out, err := vipsGenReplicate(r.Context(), r.image, across, down)
 	if err != nil {
 		return err
 	}
@@ -7,8 +7,8 @@ import "unsafe"
 
 // NewInterpolate creates a VipsInterpolate from a name string.
 // Common names: "nearest", "bilinear", "bicubic", "nohalo", "vsqbs", "lbb".
-// The caller should call g_object_unref on the result when done, or pass it
-// to a generated operation (which does not take ownership).
+// The caller should call g_object_unref on the result when done. Generated
+// options such as AffineOptions take the name instead.
 func NewInterpolate(name string) (*C.VipsInterpolate, error) {
 	cName := C.CString(name)
 	defer C.free(unsafe.Pointer(cName))
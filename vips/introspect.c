@@ -0,0 +1,92 @@
+#include "introspect.h"
+
+#include <string.h>
+
+typedef struct {
+  GType *types;
+  int max;
+  int n;
+} TypeList;
+
+static void *collect_type(GType type, void *a) {
+  TypeList *list = (TypeList *)a;
+
+  if (G_TYPE_IS_ABSTRACT(type)) return NULL;
+
+  if (list->n < list->max) list->types[list->n] = type;
+  list->n++;
+
+  return NULL;
+}
+
+int introspect_types(const char *base, GType *types, int max) {
+  GType base_type = g_type_from_name(base);
+  if (!base_type) return 0;
+
+  TypeList list = {types, max, 0};
+  vips_type_map_all(base_type, collect_type, &list);
+
+  return list.n;
+}
+
+// The abstract classes operations are grouped under, as in the libvips
+// source tree and cmd/vipsgen.
+static const struct {
+  const char *type_name;
+  const char *category;
+} categories[] = {
+    {"VipsArithmetic", "arithmetic"},
+    {"VipsBinary", "arithmetic"},
+    {"VipsUnary", "arithmetic"},
+    {"VipsStatistic", "arithmetic"},
+    {"VipsColour", "colour"},
+    {"VipsColourCode", "colour"},
+    {"VipsColourDifference", "colour"},
+    {"VipsColourSpace", "colour"},
+    {"VipsColourTransform", "colour"},
+    {"VipsConversion", "conversion"},
+    {"VipsConvolution", "convolution"},
+    {"VipsCreate", "create"},
+    {"VipsDraw", "draw"},
+    {"VipsForeign", "foreign"},
+    {"VipsForeignLoad", "foreign"},
+    {"VipsForeignSave", "foreign"},
+    {"VipsFreqfilt", "freqfilt"},
+    {"VipsHistogram", "histogram"},
+    {"VipsMorphology", "morphology"},
+    {"VipsResample", "resample"},
+};
+
+static const char *find_category(GType type) {
+  for (GType walk = g_type_parent(type);
+       walk && walk != VIPS_TYPE_OPERATION && walk != VIPS_TYPE_OBJECT;
+       walk = g_type_parent(walk)) {
+    const char *name = g_type_name(walk);
+    for (size_t i = 0; i < G_N_ELEMENTS(categories); i++) {
+      if (strcmp(name, categories[i].type_name) == 0)
+        return categories[i].category;
+    }
+  }
+
+  return NULL;
+}
+
+int introspect_type(GType type, TypeInfo *info) {
+  memset(info, 0, sizeof(TypeInfo));
+
+  if (!g_type_is_a(type, VIPS_TYPE_OPERATION)) return -1;
+
+  // Classes of static types are never finalized, so the strings stay
+  // valid after the reference is dropped.
+  gpointer klass = g_type_class_ref(type);
+
+  info->nickname = VIPS_OBJECT_CLASS(klass)->nickname;
+  info->description = VIPS_OBJECT_CLASS(klass)->description;
+  info->category = find_category(type);
+  info->flags = VIPS_OPERATION_CLASS(klass)->flags;
+  if (g_type_is_a(type, VIPS_TYPE_FOREIGN))
+    info->suffs = (const char **)VIPS_FOREIGN_CLASS(klass)->suffs;
+
+  g_type_class_unref(klass);
+  return 0;
+}
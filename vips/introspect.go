@@ -0,0 +1,254 @@
+package vips
+
+// #include "introspect.h"
+// #include "call.h"
+import "C"
+
+import (
+	"mime"
+	"sort"
+	"strings"
+	"unsafe"
+)
+
+// OperationInfo describes an operation of the running libvips.
+type OperationInfo struct {
+	Name        string `json:"name"`
+	Description string `json:"description"`
+	// Category is the part of libvips the operation belongs to, such as
+	// "resample" or "foreign". It is empty for operations outside those
+	// groups, such as "thumbnail".
+	Category string `json:"category,omitempty"`
+	// Sequential operations read their input top to bottom, and so keep
+	// a sequential load streaming.
+	Sequential bool `json:"sequential"`
+	NoCache    bool `json:"nocache"`
+	Deprecated bool `json:"deprecated"`
+	// Arguments are in libvips priority order, the order vips takes
+	// them on the command line.
+	Arguments []ArgumentInfo `json:"arguments"`
+}
+
+// ArgumentInfo describes an argument of an operation. GType is the
+// libvips type name, for example "VipsImage", "gdouble" or "VipsKernel".
+type ArgumentInfo struct {
+	Name        string `json:"name"`
+	Description string `json:"description"`
+	GType       string `json:"gtype"`
+	Input       bool   `json:"input"`
+	Output      bool   `json:"output"`
+	Required    bool   `json:"required"`
+	Modify      bool   `json:"modify"`
+	Deprecated  bool   `json:"deprecated"`
+	Priority    int    `json:"priority"`
+}
+
+// FormatInfo describes a loader or saver of the running libvips. Each is
+// listed once, under the name of its file variant, such as "jpegload",
+// with the other variants it has.
+type FormatInfo struct {
+	// Name is the format, the operation without "load" or "save".
+	Name      string `json:"name"`
+	Operation string `json:"operation"`
+	Saver     bool   `json:"saver"`
+	// Suffixes are the filename suffixes libvips picks the operation
+	// for, such as ".jpg". MimeTypes are the matching MIME types where
+	// govips or the mime package know one.
+	Suffixes  []string `json:"suffixes"`
+	MimeTypes []string `json:"mime_types"`
+	// File is set when the operation takes a filename, Buffer when an
+	// "_buffer" variant exists, Source when a loader has a "_source"
+	// variant and Target when a saver has a "_target" variant.
+	File   bool `json:"file"`
+	Buffer bool `json:"buffer"`
+	Source bool `json:"source"`
+	Target bool `json:"target"`
+}
+
+// suffixMimeTypes covers the suffixes libvips uses that are not in
+// imageTypeExtensionMap.
+var suffixMimeTypes = map[string]string{
+	".jpg":  "image/jpeg",
+	".jpe":  "image/jpeg",
+	".jfif": "image/jpeg",
+	".tif":  "image/tiff",
+	".heif": "image/heif",
+	".j2k":  "image/jp2",
+	".jpx":  "image/jpx",
+	".j2c":  "image/jp2",
+	".jpc":  "image/jp2",
+}
+
+// Operations lists every operation of the running libvips, including
+// ones govips does not wrap, sorted by name. Any of them can be run with
+// Call.
+func Operations() ([]OperationInfo, error) {
+	if err := startupIfNeeded(); err != nil {
+		return nil, err
+	}
+
+	var ops []OperationInfo
+	for _, t := range introspectTypes("VipsOperation") {
+		var info C.TypeInfo
+		if C.introspect_type(t, &info) != 0 || info.nickname == nil {
+			continue
+		}
+		op := OperationInfo{
+			Name:        C.GoString(info.nickname),
+			Description: C.GoString(info.description),
+			Category:    C.GoString(info.category),
+			Sequential:  info.flags&C.VIPS_OPERATION_SEQUENTIAL != 0,
+			NoCache:     info.flags&C.VIPS_OPERATION_NOCACHE != 0,
+			Deprecated:  info.flags&C.VIPS_OPERATION_DEPRECATED != 0,
+		}
+		args, err := operationArguments(op.Name)
+		if err != nil {
+			return nil, err
+		}
+		op.Arguments = args
+		ops = append(ops, op)
+	}
+
+	sort.Slice(ops, func(i, j int) bool { return ops[i].Name < ops[j].Name })
+	return ops, nil
+}
+
+// operationArguments lists the arguments of an operation. The arguments
+// belong to instances, so it makes one without building it.
+func operationArguments(name string) ([]ArgumentInfo, error) {
+	cName := C.CString(name)
+	defer freeCString(cName)
+
+	operation := C.call_new_operation(cName)
+	if operation == nil {
+		return nil, handleVipsError()
+	}
+	defer C.call_free_operation(operation)
+
+	opArgs := callArgs(operation)
+	args := make([]ArgumentInfo, len(opArgs))
+	for i, a := range opArgs {
+		args[i] = ArgumentInfo{
+			Name:        a.name,
+			Description: a.description,
+			GType:       a.gtypeName(),
+			Input:       a.isInput(),
+			Output:      a.isOutput(),
+			Required:    a.isRequired(),
+			Modify:      a.isModify(),
+			Deprecated:  a.flags&C.VIPS_ARGUMENT_DEPRECATED != 0,
+			Priority:    a.priority,
+		}
+	}
+	return args, nil
+}
+
+// Formats lists every loader and saver of the running libvips, sorted by
+// operation name. Unlike IsTypeSupported it covers formats govips has no
+// ImageType for, such as FITS or OpenEXR.
+func Formats() ([]FormatInfo, error) {
+	if err := startupIfNeeded(); err != nil {
+		return nil, err
+	}
+
+	var formats []FormatInfo
+	formats = append(formats, foreignFormats("VipsForeignLoad", "load", false)...)
+	formats = append(formats, foreignFormats("VipsForeignSave", "save", true)...)
+
+	sort.Slice(formats, func(i, j int) bool { return formats[i].Operation < formats[j].Operation })
+	return formats, nil
+}
+
+// foreignFormats groups the subtypes of base by operation, folding the
+// "_buffer", "_source" and "_target" variants into their file variant.
+func foreignFormats(base, verb string, saver bool) []FormatInfo {
+	byOperation := map[string]*FormatInfo{}
+	for _, t := range introspectTypes(base) {
+		var info C.TypeInfo
+		if C.introspect_type(t, &info) != 0 || info.nickname == nil {
+			continue
+		}
+		operation, variant, _ := strings.Cut(C.GoString(info.nickname), "_")
+
+		format, ok := byOperation[operation]
+		if !ok {
+			format = &FormatInfo{
+				Name:      strings.TrimSuffix(operation, verb),
+				Operation: operation,
+				Saver:     saver,
+				Suffixes:  []string{},
+				MimeTypes: []string{},
+			}
+			byOperation[operation] = format
+		}
+
+		switch variant {
+		case "":
+			format.File = true
+		case "buffer":
+			format.Buffer = true
+		case "source":
+			format.Source = !saver
+		case "target":
+			format.Target = saver
+		}
+
+		for _, suffix := range cStrings(info.suffs) {
+			format.addSuffix(suffix)
+		}
+	}
+
+	formats := make([]FormatInfo, 0, len(byOperation))
+	for _, format := range byOperation {
+		formats = append(formats, *format)
+	}
+	return formats
+}
+
+func (f *FormatInfo) addSuffix(suffix string) {
+	suffix = strings.ToLower(suffix)
+	if !contains(f.Suffixes, suffix) {
+		f.Suffixes = append(f.Suffixes, suffix)
+	}
+	if m := suffixMimeType(suffix); m != "" && !contains(f.MimeTypes, m) {
+		f.MimeTypes = append(f.MimeTypes, m)
+	}
+}
+
+// suffixMimeType returns the MIME type of a filename suffix, or "" if
+// neither govips nor the mime package knows it.
+func suffixMimeType(suffix string) string {
+	if m, ok := suffixMimeTypes[suffix]; ok {
+		return m
+	}
+	for imageType, ext := range imageTypeExtensionMap {
+		if ext == suffix && imageType.MimeType() != "" {
+			return imageType.MimeType()
+		}
+	}
+	if mediaType, _, err := mime.ParseMediaType(mime.TypeByExtension(suffix)); err == nil {
+		return mediaType
+	}
+	return ""
+}
+
+func introspectTypes(base string) []C.GType {
+	cBase := C.CString(base)
+	defer freeCString(cBase)
+
+	types := make([]C.GType, C.INTROSPECT_MAX_TYPES)
+	n := int(C.introspect_types(cBase, &types[0], C.INTROSPECT_MAX_TYPES))
+	if n > len(types) {
+		n = len(types)
+	}
+	return types[:n]
+}
+
+// cStrings copies a NULL-terminated C string array.
+func cStrings(array **C.char) []string {
+	var result []string
+	for p := array; p != nil && *p != nil; p = (**C.char)(unsafe.Add(unsafe.Pointer(p), unsafe.Sizeof(*p))) {
+		result = append(result, C.GoString(*p))
+	}
+	return result
+}
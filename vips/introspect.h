@@ -0,0 +1,22 @@
+// https://www.libvips.org/API/current/VipsOperation.html
+
+#include <stdlib.h>
+#include <vips/vips.h>
+
+#define INTROSPECT_MAX_TYPES 1024
+
+// TypeInfo describes an operation class. All strings are owned by the
+// class.
+typedef struct {
+  const char *nickname;
+  const char *description;
+  const char *category;
+  VipsOperationFlags flags;
+  // suffs is NULL-terminated, and NULL for anything but loaders and savers.
+  const char **suffs;
+} TypeInfo;
+
+// introspect_types fills types with up to max concrete subtypes of base
+// and returns how many there are.
+int introspect_types(const char *base, GType *types, int max);
+int introspect_type(GType type, TypeInfo *info);
@@ -0,0 +1,97 @@
+package vips
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestOperations(t *testing.T) {
+	require.NoError(t, Startup(nil))
+
+	ops, err := Operations()
+	require.NoError(t, err)
+	require.NotEmpty(t, ops)
+
+	byName := map[string]OperationInfo{}
+	for _, op := range ops {
+		byName[op.Name] = op
+	}
+	for _, op := range ops {
+		assert.True(t, HasOperation(op.Name), op.Name)
+	}
+
+	invert, ok := byName["invert"]
+	require.True(t, ok)
+	assert.Equal(t, "arithmetic", invert.Category)
+	assert.NotEmpty(t, invert.Description)
+
+	gaussblur, ok := byName["gaussblur"]
+	require.True(t, ok)
+	assert.Equal(t, "convolution", gaussblur.Category)
+	args := map[string]ArgumentInfo{}
+	for _, a := range gaussblur.Arguments {
+		args[a.Name] = a
+	}
+	assert.Equal(t, "VipsImage", args["in"].GType)
+	assert.True(t, args["in"].Input)
+	assert.True(t, args["in"].Required)
+	assert.True(t, args["out"].Output)
+	assert.Equal(t, "gdouble", args["sigma"].GType)
+	assert.True(t, args["sigma"].Required)
+	assert.Equal(t, "VipsPrecision", args["precision"].GType)
+	assert.False(t, args["precision"].Required)
+
+	// Operations govips does not wrap are listed too.
+	_, ok = byName["system"]
+	assert.True(t, ok)
+}
+
+func TestFormats(t *testing.T) {
+	require.NoError(t, Startup(nil))
+
+	formats, err := Formats()
+	require.NoError(t, err)
+
+	byOperation := map[string]FormatInfo{}
+	for _, f := range formats {
+		byOperation[f.Operation] = f
+	}
+
+	jpegload, ok := byOperation["jpegload"]
+	require.True(t, ok)
+	assert.Equal(t, "jpeg", jpegload.Name)
+	assert.False(t, jpegload.Saver)
+	assert.Contains(t, jpegload.Suffixes, ".jpg")
+	assert.Equal(t, []string{"image/jpeg"}, jpegload.MimeTypes)
+	assert.True(t, jpegload.File)
+	assert.True(t, jpegload.Buffer)
+	assert.True(t, jpegload.Source)
+	assert.False(t, jpegload.Target)
+
+	pngsave, ok := byOperation["pngsave"]
+	require.True(t, ok)
+	assert.True(t, pngsave.Saver)
+	assert.Equal(t, []string{".png"}, pngsave.Suffixes)
+	assert.True(t, pngsave.Buffer)
+	assert.True(t, pngsave.Target)
+	assert.False(t, pngsave.Source)
+
+	// Variants are folded into their file operation.
+	_, ok = byOperation["jpegload_buffer"]
+	assert.False(t, ok)
+
+	_, err = json.Marshal(formats)
+	assert.NoError(t, err)
+}
+
+func TestSuffixMimeType(t *testing.T) {
+	assert.Equal(t, "image/jpeg", suffixMimeType(".jpg"))
+	assert.Equal(t, "image/jpeg", suffixMimeType(".jpeg"))
+	assert.Equal(t, "image/tiff", suffixMimeType(".tif"))
+	assert.Equal(t, "image/webp", suffixMimeType(".webp"))
+	assert.Equal(t, "image/avif", suffixMimeType(".avif"))
+	assert.Equal(t, "", suffixMimeType(".nosuchsuffix"))
+}
@@ -60,3 +60,29 @@ func fromCArrayDouble(out *C.double, n int) []float64 {
 
 	return data
 }
+
+// toCArrayDouble copies v into C memory, which the caller frees with C.free.
+func toCArrayDouble(v []float64) *C.double {
+	out := (*C.double)(C.malloc(C.size_t(len(v)) * C.size_t(unsafe.Sizeof(C.double(0)))))
+	dst := unsafe.Slice(out, len(v))
+	for i, d := range v {
+		dst[i] = C.double(d)
+	}
+	return out
+}
+
+// toCArrayInt copies v into C memory, which the caller frees with C.free.
+func toCArrayInt(v []int) *C.int {
+	out := (*C.int)(C.malloc(C.size_t(len(v)) * C.size_t(unsafe.Sizeof(C.int(0)))))
+	dst := unsafe.Slice(out, len(v))
+	for i, n := range v {
+		dst[i] = C.int(n)
+	}
+	return out
+}
+
+// ptrTo returns a pointer to a copy of v, for the optional fields of the
+// generated options structs.
+func ptrTo[T any](v T) *T {
+	return &v
+}
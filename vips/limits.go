@@ -0,0 +1,170 @@
+package vips
+
+// #include "foreign.h"
+import "C"
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"sync"
+)
+
+// ErrImageTooLarge is returned by the loaders when an image exceeds its
+// import limits.
+var ErrImageTooLarge = errors.New("image exceeds import limits")
+
+// ImportLimits bounds the images the loaders accept, as protection against
+// decompression bombs. The limits are checked from the image header, before
+// any pixel is decoded, so for SVG and PDF they apply to the rasterised
+// size at the requested density. A zero field means no limit.
+//
+// Most loaders only read the header up front; the ImageMagick loader
+// decodes the whole image while opening it, so the limits cannot protect
+// it from the cost of decoding.
+type ImportLimits struct {
+	// MaxPixels is the maximum number of pixels of all loaded pages
+	// together.
+	MaxPixels int
+	// MaxWidth is the maximum width.
+	MaxWidth int
+	// MaxHeight is the maximum height of a page.
+	MaxHeight int
+	// MaxPages is the maximum number of loaded pages or frames.
+	MaxPages int
+	// MaxDecodedBytes is the maximum size of the decoded pixels of all
+	// loaded pages.
+	MaxDecodedBytes int
+}
+
+var (
+	defaultImportLimits     ImportLimits
+	defaultImportLimitsLock sync.RWMutex
+)
+
+// SetDefaultImportLimits sets the limits used by every load whose
+// ImportParams leave them unset. There are no limits by default.
+func SetDefaultImportLimits(limits ImportLimits) {
+	defaultImportLimitsLock.Lock()
+	defer defaultImportLimitsLock.Unlock()
+	defaultImportLimits = limits
+}
+
+// DefaultImportLimits returns the limits set by SetDefaultImportLimits.
+func DefaultImportLimits() ImportLimits {
+	defaultImportLimitsLock.RLock()
+	defer defaultImportLimitsLock.RUnlock()
+	return defaultImportLimits
+}
+
+// importLimits resolves the limits of params against the package default.
+// params may be nil.
+func importLimits(params *ImportParams) ImportLimits {
+	limits := DefaultImportLimits()
+	if params == nil {
+		return limits
+	}
+	override := func(p IntParameter, limit *int) {
+		if p.IsSet() {
+			*limit = p.Get()
+		}
+	}
+	override(params.MaxPixels, &limits.MaxPixels)
+	override(params.MaxWidth, &limits.MaxWidth)
+	override(params.MaxHeight, &limits.MaxHeight)
+	override(params.MaxPages, &limits.MaxPages)
+	override(params.MaxDecodedBytes, &limits.MaxDecodedBytes)
+	return limits
+}
+
+func (l ImportLimits) isZero() bool {
+	return l == ImportLimits{}
+}
+
+// checkImportLimits checks the header of in, which must not have been
+// decoded yet, against limits.
+func checkImportLimits(in *C.VipsImage, limits ImportLimits) error {
+	if limits.isZero() {
+		return nil
+	}
+	sampleSize := int(C.vips_format_sizeof(in.BandFmt))
+	return limits.check(int(in.Xsize), int(in.Ysize), vipsGetPageHeight(in), int(in.Bands), sampleSize)
+}
+
+// check checks an image of the given size against the limits; sampleSize
+// is the size of one band of one pixel in bytes.
+func (l ImportLimits) check(width, height, pageHeight, bands, sampleSize int) error {
+	if pageHeight <= 0 || pageHeight > height || height%pageHeight != 0 {
+		pageHeight = height
+	}
+	pages := 1
+	if pageHeight > 0 {
+		pages = height / pageHeight
+	}
+
+	if l.MaxWidth > 0 && width > l.MaxWidth {
+		return fmt.Errorf("%w: width %d exceeds %d", ErrImageTooLarge, width, l.MaxWidth)
+	}
+	if l.MaxHeight > 0 && pageHeight > l.MaxHeight {
+		return fmt.Errorf("%w: height %d exceeds %d", ErrImageTooLarge, pageHeight, l.MaxHeight)
+	}
+	if l.MaxPages > 0 && pages > l.MaxPages {
+		return fmt.Errorf("%w: %d pages exceeds %d", ErrImageTooLarge, pages, l.MaxPages)
+	}
+	// width and height are below VIPS_MAX_COORD, so pixels cannot
+	// overflow; the decoded size is compared by division instead.
+	pixels := width * height
+	if l.MaxPixels > 0 && pixels > l.MaxPixels {
+		return fmt.Errorf("%w: %d pixels exceeds %d", ErrImageTooLarge, pixels, l.MaxPixels)
+	}
+	if perPixel := bands * sampleSize; l.MaxDecodedBytes > 0 && perPixel > 0 && pixels > l.MaxDecodedBytes/perPixel {
+		return fmt.Errorf("%w: %d decoded bytes exceeds %d", ErrImageTooLarge, uint64(pixels)*uint64(perPixel), l.MaxDecodedBytes)
+	}
+	return nil
+}
+
+// checkBufferLimits reads the header of buf and checks it against limits,
+// for loads such as thumbnails that do not go through vipsLoadFromBuffer.
+func checkBufferLimits(ctx context.Context, buf []byte, params *ImportParams, limits ImportLimits) error {
+	if limits.isZero() {
+		return nil
+	}
+	if params == nil {
+		params = NewImportParams()
+	}
+	in, _, _, err := vipsLoadFromBuffer(ctx, buf, params)
+	if err != nil {
+		return err
+	}
+	defer clearImage(in)
+	return checkImportLimits(in, limits)
+}
+
+// checkFileLimits reads the header of the file at filename and checks it
+// against limits.
+func checkFileLimits(ctx context.Context, filename string, params *ImportParams, limits ImportLimits) error {
+	if limits.isZero() {
+		return nil
+	}
+	filenameOption := filename
+	if params != nil {
+		filenameOption += "[" + params.OptionString() + "]"
+	}
+	cFileName := C.CString(filenameOption)
+	defer freeCString(cFileName)
+
+	var in *C.VipsImage
+	if C.load_header_from_file(cFileName, &in) != 0 {
+		// Mirror vipsThumbnailFromFile, which falls back to the buffer
+		// loaders for files libvips cannot open by name.
+		err := handleVipsError()
+		buf, readErr := os.ReadFile(filename)
+		if readErr != nil {
+			return err
+		}
+		return checkBufferLimits(ctx, buf, params, limits)
+	}
+	defer clearImage(in)
+	return checkImportLimits(in, limits)
+}
@@ -0,0 +1,149 @@
+package vips
+
+import (
+	"bytes"
+	"errors"
+	"os"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestImportLimits_check(t *testing.T) {
+	tests := []struct {
+		name   string
+		limits ImportLimits
+		ok     bool
+	}{
+		{"none", ImportLimits{}, true},
+		{"width", ImportLimits{MaxWidth: 99}, false},
+		{"width ok", ImportLimits{MaxWidth: 100}, true},
+		{"page height", ImportLimits{MaxHeight: 49}, false},
+		{"page height ok", ImportLimits{MaxHeight: 50}, true},
+		{"pages", ImportLimits{MaxPages: 3}, false},
+		{"pages ok", ImportLimits{MaxPages: 4}, true},
+		{"pixels", ImportLimits{MaxPixels: 19999}, false},
+		{"pixels ok", ImportLimits{MaxPixels: 20000}, true},
+		{"decoded bytes", ImportLimits{MaxDecodedBytes: 20000*3*2 - 1}, false},
+		{"decoded bytes ok", ImportLimits{MaxDecodedBytes: 20000 * 3 * 2}, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// 4 pages of 100x50, 3 bands of 16 bits.
+			err := tt.limits.check(100, 200, 50, 3, 2)
+			if tt.ok {
+				assert.NoError(t, err)
+			} else {
+				assert.True(t, errors.Is(err, ErrImageTooLarge), "got %v", err)
+			}
+		})
+	}
+}
+
+func TestLoadImageFromBuffer_Limits(t *testing.T) {
+	require.NoError(t, Startup(nil))
+
+	buf, err := os.ReadFile(resources + "png-24bit.png")
+	require.NoError(t, err)
+
+	params := NewImportParams()
+	params.MaxWidth.Set(1919)
+	_, err = LoadImageFromBuffer(buf, params)
+	assert.True(t, errors.Is(err, ErrImageTooLarge))
+
+	params = NewImportParams()
+	params.MaxPixels.Set(1920 * 1080)
+	params.MaxDecodedBytes.Set(1920 * 1080 * 3)
+	image, err := LoadImageFromBuffer(buf, params)
+	require.NoError(t, err)
+	image.Close()
+
+	params.MaxDecodedBytes.Set(1920*1080*3 - 1)
+	_, err = LoadImageFromBuffer(buf, params)
+	assert.True(t, errors.Is(err, ErrImageTooLarge))
+}
+
+func TestLoadImageFromBuffer_Limits_Pages(t *testing.T) {
+	require.NoError(t, Startup(nil))
+
+	buf, err := os.ReadFile(resources + "gif-animated.gif")
+	require.NoError(t, err)
+
+	params := NewImportParams()
+	params.NumPages.Set(-1)
+	params.MaxPages.Set(4)
+	_, err = LoadImageFromBuffer(buf, params)
+	assert.True(t, errors.Is(err, ErrImageTooLarge))
+
+	// Only the pages that are loaded count.
+	params.NumPages.Set(2)
+	image, err := LoadImageFromBuffer(buf, params)
+	require.NoError(t, err)
+	image.Close()
+}
+
+func TestLoadImageFromBuffer_Limits_Density(t *testing.T) {
+	require.NoError(t, Startup(nil))
+
+	buf, err := os.ReadFile(resources + "svg.svg")
+	require.NoError(t, err)
+
+	params := NewImportParams()
+	params.MaxWidth.Set(1000)
+	image, err := LoadImageFromBuffer(buf, params)
+	require.NoError(t, err)
+	image.Close()
+
+	params.Density.Set(720)
+	_, err = LoadImageFromBuffer(buf, params)
+	assert.True(t, errors.Is(err, ErrImageTooLarge))
+}
+
+func TestLoadImageFromReader_Limits(t *testing.T) {
+	require.NoError(t, Startup(nil))
+
+	buf, err := os.ReadFile(resources + "png-24bit.png")
+	require.NoError(t, err)
+
+	params := NewImportParams()
+	params.MaxHeight.Set(1000)
+	_, err = LoadImageFromReader(bytes.NewReader(buf), params)
+	assert.True(t, errors.Is(err, ErrImageTooLarge))
+
+	params.Access.Set(AccessSequential)
+	_, err = LoadImageFromReader(bytes.NewReader(buf), params)
+	assert.True(t, errors.Is(err, ErrImageTooLarge))
+}
+
+func TestLoadThumbnail_Limits(t *testing.T) {
+	require.NoError(t, Startup(nil))
+
+	params := NewImportParams()
+	params.MaxPixels.Set(1000 * 1000)
+	_, err := LoadThumbnailFromFile(resources+"png-24bit.png", 100, 100, InterestingNone, SizeBoth, params)
+	assert.True(t, errors.Is(err, ErrImageTooLarge))
+
+	buf, err := os.ReadFile(resources + "png-24bit.png")
+	require.NoError(t, err)
+	_, err = LoadThumbnailFromBuffer(buf, 100, 100, InterestingNone, SizeBoth, params)
+	assert.True(t, errors.Is(err, ErrImageTooLarge))
+}
+
+func TestSetDefaultImportLimits(t *testing.T) {
+	require.NoError(t, Startup(nil))
+	defer SetDefaultImportLimits(ImportLimits{})
+
+	SetDefaultImportLimits(ImportLimits{MaxWidth: 1000})
+	assert.Equal(t, ImportLimits{MaxWidth: 1000}, DefaultImportLimits())
+
+	_, err := NewImageFromFile(resources + "png-24bit.png")
+	assert.True(t, errors.Is(err, ErrImageTooLarge))
+
+	// A limit set to 0 in the params disables the default.
+	params := NewImportParams()
+	params.MaxWidth.Set(0)
+	image, err := LoadImageFromFile(resources+"png-24bit.png", params)
+	require.NoError(t, err)
+	image.Close()
+}
@@ -0,0 +1,325 @@
+package vips
+
+import (
+	"errors"
+	"io"
+	"strconv"
+	"strings"
+)
+
+// ErrNotAcceptable is returned by NegotiateFormat when none of the candidate
+// formats is both acceptable to the client and able to represent the image.
+// HTTP servers would typically answer 406 Not Acceptable.
+var ErrNotAcceptable = errors.New("no acceptable image format")
+
+// NegotiationPrefs configures NegotiateFormat.
+type NegotiationPrefs struct {
+	// Formats lists the candidate output formats in order of preference;
+	// the order breaks ties between formats the client accepts equally.
+	// Defaults to DefaultNegotiationFormats.
+	Formats []ImageType
+
+	// Quality overrides the default quality of lossy formats when > 0.
+	Quality int
+
+	// StripMetadata removes metadata such as EXIF, XMP and ICC profiles
+	// from the output, whichever format is chosen.
+	StripMetadata bool
+
+	// FlattenAnimation allows formats that cannot store animation to be
+	// chosen for multi-page images, in which case only the first page
+	// would be encoded. By default animated images are restricted to
+	// formats that keep every frame.
+	FlattenAnimation bool
+}
+
+// DefaultNegotiationFormats is the preference order used when
+// NegotiationPrefs.Formats is empty: modern formats first, then the
+// universally supported ones. JPEG and PNG are both listed so that opaque
+// images fall back to JPEG and images with alpha to PNG.
+var DefaultNegotiationFormats = []ImageType{
+	ImageTypeAVIF,
+	ImageTypeWEBP,
+	ImageTypeJPEG,
+	ImageTypePNG,
+	ImageTypeGIF,
+}
+
+// formatCapabilities records what each negotiable format can represent.
+// Universal formats are decoded by every client, so a wildcard media range
+// such as "*/*" or "image/*" is enough to choose them; the others must be
+// named in the Accept header.
+var formatCapabilities = map[ImageType]struct {
+	alpha     bool
+	animation bool
+	universal bool
+}{
+	ImageTypeAVIF: {alpha: true},
+	ImageTypeWEBP: {alpha: true, animation: true},
+	ImageTypeJPEG: {universal: true},
+	ImageTypePNG:  {alpha: true, universal: true},
+	ImageTypeGIF:  {alpha: true, animation: true, universal: true},
+	ImageTypeJXL:  {alpha: true},
+	ImageTypeHEIF: {alpha: true},
+	ImageTypeTIFF: {alpha: true},
+}
+
+// FormatChoice is the result of NegotiateFormat: the chosen ImageType and
+// the export params for it. Exactly one of the params fields is set, the one
+// matching Type.
+type FormatChoice struct {
+	Type ImageType
+
+	Jpeg *JpegExportParams
+	Png  *PngExportParams
+	Webp *WebpExportParams
+	Avif *AvifExportParams
+	Gif  *GifExportParams
+	Jxl  *JxlExportParams
+	Heif *HeifExportParams
+	Tiff *TiffExportParams
+}
+
+// MimeType returns the Content-Type for the chosen format.
+func (c *FormatChoice) MimeType() string {
+	return c.Type.MimeType()
+}
+
+// Export encodes img in the chosen format with the chosen params.
+func (c *FormatChoice) Export(img *ImageRef) ([]byte, *ImageMetadata, error) {
+	switch c.Type {
+	case ImageTypeJPEG:
+		return img.ExportJpeg(c.Jpeg)
+	case ImageTypePNG:
+		return img.ExportPng(c.Png)
+	case ImageTypeWEBP:
+		return img.ExportWebp(c.Webp)
+	case ImageTypeAVIF:
+		return img.ExportAvif(c.Avif)
+	case ImageTypeGIF:
+		return img.ExportGIF(c.Gif)
+	case ImageTypeJXL:
+		return img.ExportJxl(c.Jxl)
+	case ImageTypeHEIF:
+		return img.ExportHeif(c.Heif)
+	case ImageTypeTIFF:
+		return img.ExportTiff(c.Tiff)
+	default:
+		return nil, nil, ErrUnsupportedImageFormat
+	}
+}
+
+// SaveToWriter writes img to w in the chosen format. Formats supported by
+// the streaming save (see ImageRef.SaveToWriter) are streamed; the others
+// are encoded in memory and written in a single chunk.
+func (c *FormatChoice) SaveToWriter(img *ImageRef, w io.Writer) error {
+	switch c.Type {
+	case ImageTypeJPEG:
+		return img.SaveToWriterJpeg(w, c.Jpeg)
+	case ImageTypePNG:
+		return img.SaveToWriterPng(w, c.Png)
+	case ImageTypeWEBP:
+		return img.SaveToWriterWebp(w, c.Webp)
+	case ImageTypeGIF:
+		return img.SaveToWriterGif(w, c.Gif)
+	case ImageTypeHEIF:
+		return img.SaveToWriterHeif(w, c.Heif)
+	case ImageTypeTIFF:
+		return img.SaveToWriterTiff(w, c.Tiff)
+	}
+
+	buf, _, err := c.Export(img)
+	if err != nil {
+		return err
+	}
+	_, err = w.Write(buf)
+	return err
+}
+
+// NegotiateFormat picks the best output format for img given the value of
+// an HTTP Accept header. Candidates (prefs.Formats, or
+// DefaultNegotiationFormats) are discarded when libvips cannot save them
+// (IsTypeSupported), when they cannot store the image's alpha channel
+// (HasAlpha), or when they cannot store its animation (Pages, when more than
+// one page was loaded). Among the remaining formats, the one with the highest
+// Accept q-value wins, with ties broken by preference order. An empty Accept
+// header accepts everything.
+//
+// Clients such as curl and many HTTP libraries send only wildcards, which
+// say nothing about what they can decode. AVIF, WebP, JXL, HEIF and TIFF are
+// therefore only chosen when the header names them, as in "image/avif";
+// wildcards alone select JPEG, PNG or GIF.
+//
+// Responses whose format depends on Accept should carry "Vary: Accept".
+func NegotiateFormat(acceptHeader string, img *ImageRef, prefs *NegotiationPrefs) (*FormatChoice, error) {
+	if prefs == nil {
+		prefs = &NegotiationPrefs{}
+	}
+	formats := prefs.Formats
+	if len(formats) == 0 {
+		formats = DefaultNegotiationFormats
+	}
+
+	hasAlpha := img.HasAlpha()
+	// Pages reports the page count of the file; only treat the image as
+	// animated if more than one page was actually loaded.
+	animated := img.Pages() > 1 && img.PageHeight() < img.Height() && !prefs.FlattenAnimation
+	accept := parseAccept(acceptHeader)
+
+	best := ImageTypeUnknown
+	bestQ := 0.0
+	for _, format := range formats {
+		caps, ok := formatCapabilities[format]
+		if !ok || !IsTypeSupported(format) {
+			continue
+		}
+		if (hasAlpha && !caps.alpha) || (animated && !caps.animation) {
+			continue
+		}
+		if !caps.universal && !accept.names(format.MimeType()) {
+			continue
+		}
+		if q := accept.quality(format.MimeType()); q > bestQ {
+			best, bestQ = format, q
+		}
+	}
+
+	if best == ImageTypeUnknown {
+		return nil, ErrNotAcceptable
+	}
+	return NewFormatChoice(best, prefs), nil
+}
+
+// NewFormatChoice returns the FormatChoice NegotiateFormat would produce for
+// format, without any negotiation. Use it when the client asked for a
+// specific format. prefs may be nil.
+func NewFormatChoice(format ImageType, prefs *NegotiationPrefs) *FormatChoice {
+	if prefs == nil {
+		prefs = &NegotiationPrefs{}
+	}
+	c := &FormatChoice{Type: format}
+	switch format {
+	case ImageTypeJPEG:
+		c.Jpeg = NewJpegExportParams()
+		c.Jpeg.StripMetadata = prefs.StripMetadata
+		if prefs.Quality > 0 {
+			c.Jpeg.Quality = prefs.Quality
+		}
+	case ImageTypePNG:
+		c.Png = NewPngExportParams()
+		c.Png.StripMetadata = prefs.StripMetadata
+	case ImageTypeWEBP:
+		c.Webp = NewWebpExportParams()
+		c.Webp.StripMetadata = prefs.StripMetadata
+		if prefs.Quality > 0 {
+			c.Webp.Quality = prefs.Quality
+		}
+	case ImageTypeAVIF:
+		c.Avif = NewAvifExportParams()
+		c.Avif.StripMetadata = prefs.StripMetadata
+		if prefs.Quality > 0 {
+			c.Avif.Quality = prefs.Quality
+		}
+	case ImageTypeGIF:
+		c.Gif = NewGifExportParams()
+		c.Gif.StripMetadata = prefs.StripMetadata
+	case ImageTypeJXL:
+		c.Jxl = NewJxlExportParams()
+		c.Jxl.StripMetadata = prefs.StripMetadata
+		if prefs.Quality > 0 {
+			c.Jxl.Quality = prefs.Quality
+		}
+	case ImageTypeHEIF:
+		c.Heif = NewHeifExportParams()
+		c.Heif.StripMetadata = prefs.StripMetadata
+		if prefs.Quality > 0 {
+			c.Heif.Quality = prefs.Quality
+		}
+	case ImageTypeTIFF:
+		c.Tiff = NewTiffExportParams()
+		c.Tiff.StripMetadata = prefs.StripMetadata
+	}
+	return c
+}
+
+// acceptRange is one media range of an Accept header with its q-value.
+type acceptRange struct {
+	typ, subtype string
+	q            float64
+}
+
+type acceptRanges []acceptRange
+
+// parseAccept parses an Accept header per RFC 9110. Malformed ranges are
+// skipped; an empty header is treated as "*/*".
+func parseAccept(header string) acceptRanges {
+	if strings.TrimSpace(header) == "" {
+		return acceptRanges{{typ: "*", subtype: "*", q: 1}}
+	}
+
+	var ranges acceptRanges
+	for _, part := range strings.Split(header, ",") {
+		fields := strings.Split(part, ";")
+		mediaRange := strings.ToLower(strings.TrimSpace(fields[0]))
+		typ, subtype, ok := strings.Cut(mediaRange, "/")
+		if !ok || typ == "" || subtype == "" {
+			continue
+		}
+
+		q := 1.0
+		for _, param := range fields[1:] {
+			key, value, ok := strings.Cut(strings.TrimSpace(param), "=")
+			if !ok || strings.ToLower(strings.TrimSpace(key)) != "q" {
+				continue
+			}
+			if v, err := strconv.ParseFloat(strings.TrimSpace(value), 64); err == nil && v >= 0 && v <= 1 {
+				q = v
+			}
+		}
+		ranges = append(ranges, acceptRange{typ: typ, subtype: subtype, q: q})
+	}
+	return ranges
+}
+
+// names reports whether a range names mimeType itself rather than
+// matching it by a wildcard.
+func (a acceptRanges) names(mimeType string) bool {
+	typ, subtype, ok := strings.Cut(mimeType, "/")
+	if !ok {
+		return false
+	}
+	for _, r := range a {
+		if r.typ == typ && r.subtype == subtype {
+			return true
+		}
+	}
+	return false
+}
+
+// quality returns the q-value the ranges assign to mimeType, using the most
+// specific matching range: "type/subtype" over "type/*" over "*/*".
+func (a acceptRanges) quality(mimeType string) float64 {
+	typ, subtype, ok := strings.Cut(mimeType, "/")
+	if !ok {
+		return 0
+	}
+
+	q, specificity := 0.0, -1
+	for _, r := range a {
+		var s int
+		switch {
+		case r.typ == typ && r.subtype == subtype:
+			s = 2
+		case r.typ == typ && r.subtype == "*":
+			s = 1
+		case r.typ == "*" && r.subtype == "*":
+			s = 0
+		default:
+			continue
+		}
+		if s > specificity {
+			q, specificity = r.q, s
+		}
+	}
+	return q
+}
@@ -0,0 +1,159 @@
+package vips
+
+import (
+	"bytes"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseAccept(t *testing.T) {
+	accept := parseAccept("image/avif,image/webp;q=0.9, image/*;q=0.5, */*;q=0.1, text/html;q=bogus")
+
+	assert.Equal(t, 1.0, accept.quality("image/avif"))
+	assert.Equal(t, 0.9, accept.quality("image/webp"))
+	assert.Equal(t, 0.5, accept.quality("image/png"))
+	assert.Equal(t, 0.1, accept.quality("application/pdf"))
+	assert.Equal(t, 1.0, accept.quality("text/html"), "invalid q-values default to 1")
+
+	assert.Equal(t, 1.0, parseAccept("").quality("image/jpeg"))
+	assert.Equal(t, 0.0, parseAccept("image/webp").quality("image/jpeg"))
+	assert.Equal(t, 0.0, parseAccept("image/webp;q=0, */*").quality("image/webp"))
+
+	assert.True(t, accept.names("image/webp"))
+	assert.False(t, accept.names("image/png"), "image/* does not name a type")
+	assert.False(t, parseAccept("").names("image/jpeg"))
+}
+
+func TestNegotiateFormat_Wildcards(t *testing.T) {
+	require.NoError(t, Startup(nil))
+
+	opaque, err := NewImageFromFile(resources + "jpg-24bit.jpg")
+	require.NoError(t, err)
+	defer opaque.Close()
+	alpha, err := NewImageFromFile(resources + "png-24bit+alpha.png")
+	require.NoError(t, err)
+	defer alpha.Close()
+
+	// Wildcards fall back to the formats every client decodes, even though
+	// AVIF and WebP come first in DefaultNegotiationFormats.
+	for _, accept := range []string{"*/*", "image/*", "", "image/*;q=0.8, */*;q=0.5"} {
+		choice, err := NegotiateFormat(accept, opaque, nil)
+		require.NoError(t, err, accept)
+		assert.Equal(t, ImageTypeJPEG, choice.Type, "Accept: %q", accept)
+
+		choice, err = NegotiateFormat(accept, alpha, nil)
+		require.NoError(t, err, accept)
+		assert.Equal(t, ImageTypePNG, choice.Type, "Accept: %q", accept)
+	}
+
+	if IsTypeSupported(ImageTypeAVIF) {
+		choice, err := NegotiateFormat("image/avif,*/*", opaque, nil)
+		require.NoError(t, err)
+		assert.Equal(t, ImageTypeAVIF, choice.Type)
+	}
+
+	// A list of modern formats only is not acceptable to a wildcard client.
+	_, err = NegotiateFormat("*/*", opaque, &NegotiationPrefs{Formats: []ImageType{ImageTypeWEBP}})
+	assert.True(t, errors.Is(err, ErrNotAcceptable))
+}
+
+func TestNegotiateFormat_Opaque(t *testing.T) {
+	require.NoError(t, Startup(nil))
+
+	img, err := NewImageFromFile(resources + "jpg-24bit.jpg")
+	require.NoError(t, err)
+	defer img.Close()
+
+	choice, err := NegotiateFormat("image/jpeg,image/png", img, nil)
+	require.NoError(t, err)
+	assert.Equal(t, ImageTypeJPEG, choice.Type)
+	assert.Equal(t, "image/jpeg", choice.MimeType())
+	require.NotNil(t, choice.Jpeg)
+	assert.Nil(t, choice.Png)
+
+	choice, err = NegotiateFormat("", img, &NegotiationPrefs{Formats: []ImageType{ImageTypePNG, ImageTypeJPEG}})
+	require.NoError(t, err)
+	assert.Equal(t, ImageTypePNG, choice.Type)
+
+	if IsTypeSupported(ImageTypeWEBP) {
+		choice, err = NegotiateFormat("image/webp,*/*;q=0.8", img, &NegotiationPrefs{Quality: 60})
+		require.NoError(t, err)
+		assert.Equal(t, ImageTypeWEBP, choice.Type)
+		assert.Equal(t, 60, choice.Webp.Quality)
+
+		buf, _, err := choice.Export(img)
+		require.NoError(t, err)
+		assert.Equal(t, ImageTypeWEBP, DetermineImageType(buf))
+
+		var out bytes.Buffer
+		require.NoError(t, choice.SaveToWriter(img, &out))
+		assert.Equal(t, buf, out.Bytes())
+	}
+}
+
+func TestNegotiateFormat_Alpha(t *testing.T) {
+	require.NoError(t, Startup(nil))
+
+	img, err := NewImageFromFile(resources + "png-24bit+alpha.png")
+	require.NoError(t, err)
+	defer img.Close()
+
+	choice, err := NegotiateFormat("image/jpeg,image/png;q=0.5", img, nil)
+	require.NoError(t, err)
+	assert.Equal(t, ImageTypePNG, choice.Type, "JPEG cannot store alpha")
+
+	_, err = NegotiateFormat("image/jpeg", img, nil)
+	assert.True(t, errors.Is(err, ErrNotAcceptable))
+}
+
+func TestNegotiateFormat_Animated(t *testing.T) {
+	require.NoError(t, Startup(nil))
+
+	params := NewImportParams()
+	params.NumPages.Set(-1)
+	img, err := LoadImageFromFile(resources+"gif-animated.gif", params)
+	require.NoError(t, err)
+	defer img.Close()
+	require.Greater(t, img.Pages(), 1)
+
+	choice, err := NegotiateFormat("image/avif,image/png,image/gif", img, nil)
+	require.NoError(t, err)
+	assert.Equal(t, ImageTypeGIF, choice.Type, "AVIF and PNG would drop the animation")
+
+	choice, err = NegotiateFormat("image/png", img, &NegotiationPrefs{FlattenAnimation: true})
+	require.NoError(t, err)
+	assert.Equal(t, ImageTypePNG, choice.Type)
+}
+
+func TestFormatChoice_StripMetadata(t *testing.T) {
+	require.NoError(t, Startup(nil))
+
+	for _, format := range []ImageType{ImageTypeJPEG, ImageTypeJXL, ImageTypeHEIF} {
+		t.Run(ImageTypes[format], func(t *testing.T) {
+			if saver, ok := targetSavers[format]; ok && (!HasOperation(saver) || !IsTypeSupported(format)) {
+				t.Skipf("%s is not supported by this libvips", saver)
+			}
+
+			img, err := NewImageFromFile(resources + "with_exif_orientation_top_left.jpg")
+			require.NoError(t, err)
+			defer img.Close()
+			require.True(t, img.HasExif())
+
+			choice := NewFormatChoice(format, &NegotiationPrefs{StripMetadata: true})
+			exported, _, err := choice.Export(img)
+			require.NoError(t, err)
+			var streamed bytes.Buffer
+			require.NoError(t, choice.SaveToWriter(img, &streamed))
+
+			for name, buf := range map[string][]byte{"Export": exported, "SaveToWriter": streamed.Bytes()} {
+				out, err := NewImageFromBuffer(buf)
+				require.NoError(t, err, name)
+				assert.False(t, out.HasExif(), "%s kept EXIF", name)
+				out.Close()
+			}
+		})
+	}
+}
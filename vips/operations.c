@@ -3,6 +3,7 @@
 #include "lang.h"
 #include "operations.h"
 
+#include <string.h>
 #include <unistd.h>
 
 static int is_16bit(VipsInterpretation interpretation);
@@ -35,6 +36,78 @@ int minOp(VipsImage *in, double *out, int *x, int *y, int size) {
   return vips_min(in, out, "x", x, "y", y, "size", size, NULL);
 }
 
+// extremes finds the size largest (or smallest) values in band, or in all
+// bands when band is negative. The results are copied into g_malloc'd
+// arrays of length n owned by the caller.
+int extremes(VipsImage *in, int band, int size, int find_max, double **values,
+             int **xs, int **ys, int *n) {
+  VipsImage *base = vips_image_new();
+  VipsImage **t = (VipsImage **)vips_object_local_array(VIPS_OBJECT(base), 1);
+  VipsArrayDouble *out_array = NULL;
+  VipsArrayInt *x_array = NULL;
+  VipsArrayInt *y_array = NULL;
+  double out;
+  int code;
+
+  if (band >= 0) {
+    if (vips_extract_band(in, &t[0], band, NULL)) {
+      g_object_unref(base);
+      return 1;
+    }
+    in = t[0];
+  }
+
+  if (find_max) {
+    code = vips_max(in, &out, "size", size, "out_array", &out_array,
+                    "x_array", &x_array, "y_array", &y_array, NULL);
+  } else {
+    code = vips_min(in, &out, "size", size, "out_array", &out_array,
+                    "x_array", &x_array, "y_array", &y_array, NULL);
+  }
+  g_object_unref(base);
+  if (code) {
+    return 1;
+  }
+
+  int nv, nx, ny;
+  double *v = vips_array_double_get(out_array, &nv);
+  int *x = vips_array_int_get(x_array, &nx);
+  int *y = vips_array_int_get(y_array, &ny);
+  *n = VIPS_MIN(nv, VIPS_MIN(nx, ny));
+
+  *values = g_new(double, VIPS_MAX(*n, 1));
+  *xs = g_new(int, VIPS_MAX(*n, 1));
+  *ys = g_new(int, VIPS_MAX(*n, 1));
+  memcpy(*values, v, *n * sizeof(double));
+  memcpy(*xs, x, *n * sizeof(int));
+  memcpy(*ys, y, *n * sizeof(int));
+
+  vips_area_unref(VIPS_AREA(out_array));
+  vips_area_unref(VIPS_AREA(x_array));
+  vips_area_unref(VIPS_AREA(y_array));
+  return 0;
+}
+
+// measure_patches averages an h by v grid of patches in the given area,
+// writing the resulting double matrix, one row per patch and one column
+// per band, to a g_malloc'd buffer.
+int measure_patches(VipsImage *in, double **out, size_t *size, int left, int top,
+                    int width, int height, int h, int v) {
+  VipsImage *matrix;
+
+  if (vips_measure(in, &matrix, h, v, "left", left, "top", top, "width", width,
+                   "height", height, NULL)) {
+    return 1;
+  }
+
+  *out = vips_image_write_to_memory(matrix, size);
+  g_object_unref(matrix);
+  if (!*out) {
+    return 1;
+  }
+  return 0;
+}
+
 // Color
 
 int is_colorspace_supported(VipsImage *in) {
@@ -268,6 +341,18 @@ int join(VipsImage *in1, VipsImage *in2, VipsImage **out, int direction) {
   return vips_join(in1, in2, out, direction, NULL);
 }
 
+int switch_image(VipsImage **tests, VipsImage **out, int n) {
+  return vips_switch(tests, out, n, NULL);
+}
+
+int case_image(VipsImage *index, VipsImage **cases, VipsImage **out, int n) {
+  return vips_case(index, cases, out, n, NULL);
+}
+
+VipsImage *constant_image(VipsImage *match, double *c, int n) {
+  return vips_image_new_from_image(match, c, n);
+}
+
 int add_alpha(VipsImage *in, VipsImage **out) {
   return vips_addalpha(in, out, NULL);
 }
@@ -282,19 +367,113 @@ int text(VipsImage **out, TextOptions *o) {
 
 // Draw
 
-int draw_rect(VipsImage *in, double r, double g, double b, double a, int left,
+// draw_prepare points *out at the image to draw on: in itself when it is a
+// memory image that nothing else references, otherwise a private copy of
+// its pixels in memory. Draw operations modify their image in place, so
+// drawing on an image shared with other images, or with the operation
+// cache, would change their pixels too.
+static int draw_prepare(VipsImage *in, VipsImage **out) {
+  if (in->dtype == VIPS_IMAGE_SETBUF && G_OBJECT(in)->ref_count == 1) {
+    *out = in;
+    return 0;
+  }
+
+  *out = vips_image_new_memory();
+  if (vips_image_write(in, *out)) {
+    g_object_unref(*out);
+    *out = NULL;
+    return 1;
+  }
+  return 0;
+}
+
+// draw_failed releases the copy made by draw_prepare.
+static int draw_failed(VipsImage *in, VipsImage **out) {
+  if (*out != in) {
+    g_object_unref(*out);
+  }
+  *out = NULL;
+  return 1;
+}
+
+int draw_rect(VipsImage *in, VipsImage **out, double *ink, int n, int left,
               int top, int width, int height, int fill) {
-  if (is_16bit(in->Type)) {
-    r = 65535 * r / 255;
-    g = 65535 * g / 255;
-    b = 65535 * b / 255;
-    a = 65535 * a / 255;
+  if (draw_prepare(in, out)) {
+    return 1;
+  }
+  if (vips_draw_rect(*out, ink, n, left, top, width, height, "fill", fill,
+                     NULL)) {
+    return draw_failed(in, out);
+  }
+  return 0;
+}
+
+int draw_line(VipsImage *in, VipsImage **out, double *ink, int n, int x1,
+              int y1, int x2, int y2) {
+  if (draw_prepare(in, out)) {
+    return 1;
+  }
+  if (vips_draw_line(*out, ink, n, x1, y1, x2, y2, NULL)) {
+    return draw_failed(in, out);
+  }
+  return 0;
+}
+
+int draw_circle(VipsImage *in, VipsImage **out, double *ink, int n, int cx,
+                int cy, int radius, int fill) {
+  if (draw_prepare(in, out)) {
+    return 1;
+  }
+  if (vips_draw_circle(*out, ink, n, cx, cy, radius, "fill", fill, NULL)) {
+    return draw_failed(in, out);
+  }
+  return 0;
+}
+
+int draw_flood(VipsImage *in, VipsImage **out, double *ink, int n, int x,
+               int y, int equal, int *left, int *top, int *width,
+               int *height) {
+  if (draw_prepare(in, out)) {
+    return 1;
+  }
+  if (vips_draw_flood(*out, ink, n, x, y, "equal", equal, "left", left, "top",
+                      top, "width", width, "height", height, NULL)) {
+    return draw_failed(in, out);
+  }
+  return 0;
+}
+
+int draw_image(VipsImage *in, VipsImage **out, VipsImage *sub, int x, int y,
+               int mode) {
+  if (draw_prepare(in, out)) {
+    return 1;
+  }
+  if (vips_draw_image(*out, sub, x, y, "mode", mode, NULL)) {
+    return draw_failed(in, out);
+  }
+  return 0;
+}
+
+int draw_mask(VipsImage *in, VipsImage **out, double *ink, int n,
+              VipsImage *mask, int x, int y) {
+  if (draw_prepare(in, out)) {
+    return 1;
   }
+  if (vips_draw_mask(*out, ink, n, mask, x, y, NULL)) {
+    return draw_failed(in, out);
+  }
+  return 0;
+}
 
-  double ink[4];
-  int n = background_for_bands(in, ink, r, g, b, a);
-  return vips_draw_rect(in, ink, n, left, top, width, height, "fill", fill,
-                        NULL);
+int draw_smudge(VipsImage *in, VipsImage **out, int left, int top, int width,
+                int height) {
+  if (draw_prepare(in, out)) {
+    return 1;
+  }
+  if (vips_draw_smudge(*out, left, top, width, height, NULL)) {
+    return draw_failed(in, out);
+  }
+  return 0;
 }
 
 // Header
@@ -434,6 +613,93 @@ unsigned long image_get_blob(VipsImage *in, const char *name, const void **data,
   return 0;
 }
 
+void image_set_array_int(VipsImage *in, const char *name, const int *array,
+                         int n) {
+  vips_image_set_array_int(in, name, array, n);
+}
+
+void image_set_array_double(VipsImage *in, const char *name,
+                            const double *array, int n) {
+  vips_image_set_array_double(in, name, array, n);
+}
+
+void image_set_image(VipsImage *in, const char *name, VipsImage *image) {
+  vips_image_set_image(in, name, image);
+}
+
+int image_get_field(VipsImage *in, const char *name, FieldValue *out) {
+  GValue value = {0};
+  GType type;
+
+  memset(out, 0, sizeof(*out));
+  if (vips_image_get(in, name, &value)) {
+    return -1;
+  }
+
+  type = G_VALUE_TYPE(&value);
+  out->type_name = g_type_name(type);
+
+  if (type == G_TYPE_INT) {
+    out->kind = FIELD_INT;
+    out->i = g_value_get_int(&value);
+  } else if (type == G_TYPE_UINT) {
+    out->kind = FIELD_INT;
+    out->i = g_value_get_uint(&value);
+  } else if (type == G_TYPE_INT64) {
+    out->kind = FIELD_INT;
+    out->i = g_value_get_int64(&value);
+  } else if (type == G_TYPE_DOUBLE) {
+    out->kind = FIELD_DOUBLE;
+    out->d = g_value_get_double(&value);
+  } else if (type == G_TYPE_BOOLEAN) {
+    out->kind = FIELD_BOOL;
+    out->i = g_value_get_boolean(&value);
+  } else if (type == G_TYPE_STRING) {
+    out->kind = FIELD_STRING;
+    out->s = g_value_dup_string(&value);
+  } else if (type == VIPS_TYPE_REF_STRING) {
+    out->kind = FIELD_STRING;
+    out->s = g_strdup(vips_value_get_ref_string(&value, NULL));
+  } else if (G_TYPE_IS_ENUM(type)) {
+    out->kind = FIELD_ENUM;
+    out->i = g_value_get_enum(&value);
+    out->s = g_strdup(vips_enum_nick(type, (int)out->i));
+  } else if (type == VIPS_TYPE_BLOB) {
+    out->kind = FIELD_BLOB;
+    vips_value_get_blob(&value, &out->size);
+  } else if (type == VIPS_TYPE_ARRAY_INT) {
+    int *array = vips_value_get_array_int(&value, &out->n);
+    out->kind = FIELD_ARRAY_INT;
+    out->ints = g_malloc(out->n * sizeof(int));
+    memcpy(out->ints, array, out->n * sizeof(int));
+  } else if (type == VIPS_TYPE_ARRAY_DOUBLE) {
+    double *array = vips_value_get_array_double(&value, &out->n);
+    out->kind = FIELD_ARRAY_DOUBLE;
+    out->doubles = g_malloc(out->n * sizeof(double));
+    memcpy(out->doubles, array, out->n * sizeof(double));
+  } else if (type == VIPS_TYPE_IMAGE) {
+    VipsImage *image = VIPS_IMAGE(g_value_get_object(&value));
+    out->kind = FIELD_IMAGE;
+    if (image) {
+      out->width = image->Xsize;
+      out->height = image->Ysize;
+      out->bands = image->Bands;
+    }
+  } else {
+    out->kind = FIELD_OTHER;
+    out->s = g_strdup_value_contents(&value);
+  }
+
+  g_value_unset(&value);
+  return 0;
+}
+
+void field_value_free(FieldValue *v) {
+  g_free(v->s);
+  g_free(v->ints);
+  g_free(v->doubles);
+}
+
 // Label
 
 int label(VipsImage *in, VipsImage **out, LabelOptions *o) {
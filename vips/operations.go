@@ -4,9 +4,11 @@ package vips
 // #include "operations.h"
 import "C"
 import (
+	"context"
 	"errors"
 	"os"
 	"runtime"
+	"sort"
 	"strings"
 	"unsafe"
 )
@@ -14,25 +16,27 @@ import (
 // Arithmetic
 
 // https://libvips.github.io/libvips/API/current/libvips-arithmetic.html#vips-find-trim
-func vipsFindTrim(in *C.VipsImage, threshold float64, backgroundColor *Color) (int, int, int, int, error) {
-	incOpCounter("findTrim")
+func vipsFindTrim(ctx context.Context, in *C.VipsImage, threshold float64, backgroundColor *Color) (int, int, int, int, error) {
+	op := startOp(ctx, "findTrim", in)
+	defer op.end()
 	var left, top, width, height C.int
 
 	if err := C.find_trim(in, &left, &top, &width, &height, C.double(threshold), C.double(backgroundColor.R),
 		C.double(backgroundColor.G), C.double(backgroundColor.B)); err != 0 {
-		return -1, -1, -1, -1, handleVipsError()
+		return -1, -1, -1, -1, op.fail(handleVipsError())
 	}
 
 	return int(left), int(top), int(width), int(height), nil
 }
 
 // https://libvips.github.io/libvips/API/current/libvips-arithmetic.html#vips-getpoint
-func vipsGetPoint(in *C.VipsImage, n int, x int, y int) ([]float64, error) {
-	incOpCounter("getpoint")
+func vipsGetPoint(ctx context.Context, in *C.VipsImage, n int, x int, y int) ([]float64, error) {
+	op := startOp(ctx, "getpoint", in)
+	defer op.end()
 	var out *C.double
 
 	if err := C.getpoint(in, &out, C.int(n), C.int(x), C.int(y)); err != 0 {
-		return nil, handleVipsError()
+		return nil, op.fail(handleVipsError())
 	}
 
 	// Copy from C memory into a Go slice, then free the C allocation.
@@ -43,18 +47,81 @@ func vipsGetPoint(in *C.VipsImage, n int, x int, y int) ([]float64, error) {
 }
 
 // https://www.libvips.org/API/current/libvips-arithmetic.html#vips-min
-func vipsMin(in *C.VipsImage) (float64, int, int, error) {
-	incOpCounter("min")
+func vipsMin(ctx context.Context, in *C.VipsImage) (float64, int, int, error) {
+	op := startOp(ctx, "min", in)
+	defer op.end()
 	var out C.double
 	var x, y C.int
 
 	if err := C.minOp(in, &out, &x, &y, C.int(1)); err != 0 {
-		return 0, 0, 0, handleVipsError()
+		return 0, 0, 0, op.fail(handleVipsError())
 	}
 
 	return float64(out), int(x), int(y), nil
 }
 
+// https://www.libvips.org/API/current/libvips-arithmetic.html#vips-max
+func vipsExtremes(ctx context.Context, in *C.VipsImage, band int, size int, max bool) ([]Extremum, error) {
+	name := "min"
+	if max {
+		name = "max"
+	}
+	op := startOp(ctx, name, in)
+	defer op.end()
+	var values *C.double
+	var xs, ys *C.int
+	var n C.int
+
+	if err := C.extremes(in, C.int(band), C.int(size), C.int(boolToInt(max)), &values, &xs, &ys, &n); err != 0 {
+		return nil, op.fail(handleVipsError())
+	}
+	defer gFreePointer(unsafe.Pointer(values))
+	defer gFreePointer(unsafe.Pointer(xs))
+	defer gFreePointer(unsafe.Pointer(ys))
+
+	count := int(n)
+	cValues := unsafe.Slice((*float64)(unsafe.Pointer(values)), count)
+	cXs := unsafe.Slice((*C.int)(unsafe.Pointer(xs)), count)
+	cYs := unsafe.Slice((*C.int)(unsafe.Pointer(ys)), count)
+	result := make([]Extremum, count)
+	for i := range result {
+		result[i] = Extremum{Value: cValues[i], X: int(cXs[i]), Y: int(cYs[i])}
+	}
+
+	// libvips doesn't guarantee any order, so put the most extreme first.
+	sort.SliceStable(result, func(i, j int) bool {
+		if max {
+			return result[i].Value > result[j].Value
+		}
+		return result[i].Value < result[j].Value
+	})
+	return result, nil
+}
+
+// https://www.libvips.org/API/current/libvips-arithmetic.html#vips-measure
+func vipsMeasurePatches(ctx context.Context, in *C.VipsImage, left, top, width, height, hPatches, vPatches int) ([][]float64, error) {
+	op := startOp(ctx, "measure", in)
+	defer op.end()
+	var out *C.double
+	var size C.size_t
+
+	if err := C.measure_patches(in, &out, &size, C.int(left), C.int(top), C.int(width), C.int(height),
+		C.int(hPatches), C.int(vPatches)); err != 0 {
+		return nil, op.fail(handleVipsError())
+	}
+	defer gFreePointer(unsafe.Pointer(out))
+
+	patches := hPatches * vPatches
+	values := unsafe.Slice((*float64)(unsafe.Pointer(out)), int(size)/8)
+	bands := len(values) / patches
+	result := make([][]float64, patches)
+	for i := range result {
+		result[i] = make([]float64, bands)
+		copy(result[i], values[i*bands:(i+1)*bands])
+	}
+	return result, nil
+}
+
 // Color
 
 // Color represents an RGB
@@ -111,14 +178,15 @@ func vipsIsColorSpaceSupported(in *C.VipsImage) bool {
 }
 
 // https://libvips.github.io/libvips/API/current/libvips-colour.html#vips-colourspace
-func vipsToColorSpace(in *C.VipsImage, interpretation Interpretation) (*C.VipsImage, error) {
-	incOpCounter("to_colorspace")
+func vipsToColorSpace(ctx context.Context, in *C.VipsImage, interpretation Interpretation) (*C.VipsImage, error) {
+	op := startOp(ctx, "to_colorspace", in)
+	defer op.end()
 	var out *C.VipsImage
 
 	inter := C.VipsInterpretation(interpretation)
 
 	if err := C.to_colorspace(in, &out, inter); err != 0 {
-		return nil, handleImageError(out)
+		return nil, op.fail(handleImageError(out))
 	}
 
 	return out, nil
@@ -309,96 +377,101 @@ const (
 )
 
 // https://libvips.github.io/libvips/API/current/libvips-conversion.html#vips-embed
-func vipsEmbed(in *C.VipsImage, left, top, width, height int, extend ExtendStrategy) (*C.VipsImage, error) {
-	incOpCounter("embed")
+func vipsEmbed(ctx context.Context, in *C.VipsImage, left, top, width, height int, extend ExtendStrategy) (*C.VipsImage, error) {
+	op := startOp(ctx, "embed", in)
+	defer op.end()
 	var out *C.VipsImage
 
 	if err := C.embed_image(in, &out, C.int(left), C.int(top), C.int(width), C.int(height), C.int(extend)); err != 0 {
-		return nil, handleImageError(out)
+		return nil, op.fail(handleImageError(out))
 	}
 
 	return out, nil
 }
 
 // https://libvips.github.io/libvips/API/current/libvips-conversion.html#vips-embed
-func vipsEmbedBackground(in *C.VipsImage, left, top, width, height int, backgroundColor *ColorRGBA) (*C.VipsImage, error) {
-	incOpCounter("embed")
+func vipsEmbedBackground(ctx context.Context, in *C.VipsImage, left, top, width, height int, backgroundColor *ColorRGBA) (*C.VipsImage, error) {
+	op := startOp(ctx, "embed", in)
+	defer op.end()
 	var out *C.VipsImage
 
 	if err := C.embed_image_background(in, &out, C.int(left), C.int(top), C.int(width),
 		C.int(height), C.double(backgroundColor.R),
 		C.double(backgroundColor.G), C.double(backgroundColor.B), C.double(backgroundColor.A)); err != 0 {
-		return nil, handleImageError(out)
+		return nil, op.fail(handleImageError(out))
 	}
 
 	return out, nil
 }
 
-func vipsEmbedMultiPage(in *C.VipsImage, left, top, width, height int, extend ExtendStrategy) (*C.VipsImage, error) {
-	incOpCounter("embedMultiPage")
+func vipsEmbedMultiPage(ctx context.Context, in *C.VipsImage, left, top, width, height int, extend ExtendStrategy) (*C.VipsImage, error) {
+	op := startOp(ctx, "embedMultiPage", in)
+	defer op.end()
 	var out *C.VipsImage
 
 	if err := C.embed_multi_page_image(in, &out, C.int(left), C.int(top), C.int(width), C.int(height), C.int(extend)); err != 0 {
-		return nil, handleImageError(out)
+		return nil, op.fail(handleImageError(out))
 	}
 
 	return out, nil
 }
 
-func vipsEmbedMultiPageBackground(in *C.VipsImage, left, top, width, height int, backgroundColor *ColorRGBA) (*C.VipsImage, error) {
-	incOpCounter("embedMultiPageBackground")
+func vipsEmbedMultiPageBackground(ctx context.Context, in *C.VipsImage, left, top, width, height int, backgroundColor *ColorRGBA) (*C.VipsImage, error) {
+	op := startOp(ctx, "embedMultiPageBackground", in)
+	defer op.end()
 	var out *C.VipsImage
 
 	if err := C.embed_multi_page_image_background(in, &out, C.int(left), C.int(top), C.int(width),
 		C.int(height), C.double(backgroundColor.R),
 		C.double(backgroundColor.G), C.double(backgroundColor.B), C.double(backgroundColor.A)); err != 0 {
-		return nil, handleImageError(out)
+		return nil, op.fail(handleImageError(out))
 	}
 
 	return out, nil
 }
 
 // https://libvips.github.io/libvips/API/current/libvips-conversion.html#vips-flip
-func vipsFlip(in *C.VipsImage, direction Direction) (*C.VipsImage, error) {
-	return vipsGenFlip(in, direction)
+func vipsFlip(ctx context.Context, in *C.VipsImage, direction Direction) (*C.VipsImage, error) {
+	return vipsGenFlip(ctx, in, direction)
 }
 
 // https://libvips.github.io/libvips/API/current/libvips-conversion.html#vips-extract-area
-func vipsExtractArea(in *C.VipsImage, left, top, width, height int) (*C.VipsImage, error) {
-	return vipsGenExtractArea(in, left, top, width, height)
+func vipsExtractArea(ctx context.Context, in *C.VipsImage, left, top, width, height int) (*C.VipsImage, error) {
+	return vipsGenExtractArea(ctx, in, left, top, width, height)
 }
 
-func vipsExtractAreaMultiPage(in *C.VipsImage, left, top, width, height int) (*C.VipsImage, error) {
-	incOpCounter("extractAreaMultiPage")
+func vipsExtractAreaMultiPage(ctx context.Context, in *C.VipsImage, left, top, width, height int) (*C.VipsImage, error) {
+	op := startOp(ctx, "extractAreaMultiPage", in)
+	defer op.end()
 
 	pageHeight := vipsGetPageHeight(in)
 	nPages := int(in.Ysize) / pageHeight
 
 	pages := make([]*C.VipsImage, nPages)
 	for i := 0; i < nPages; i++ {
-		page, err := vipsGenExtractArea(in, left, pageHeight*i+top, width, height)
+		page, err := vipsGenExtractArea(ctx, in, left, pageHeight*i+top, width, height)
 		if err != nil {
 			for j := 0; j < i; j++ {
 				clearImage(pages[j])
 			}
-			return nil, err
+			return nil, op.fail(err)
 		}
 		pages[i] = page
 	}
 
 	across := 1
-	joined, err := vipsGenArrayjoin(pages, &ArrayjoinOptions{Across: &across})
+	joined, err := vipsGenArrayjoin(ctx, pages, &ArrayjoinOptions{Across: &across})
 	for _, p := range pages {
 		clearImage(p)
 	}
 	if err != nil {
-		return nil, err
+		return nil, op.fail(err)
 	}
 
-	out, err := vipsGenCopy(joined, nil)
+	out, err := vipsGenCopy(ctx, joined, nil)
 	clearImage(joined)
 	if err != nil {
-		return nil, err
+		return nil, op.fail(err)
 	}
 
 	vipsSetPageHeight(out, height)
@@ -406,56 +479,60 @@ func vipsExtractAreaMultiPage(in *C.VipsImage, left, top, width, height int) (*C
 }
 
 // http://libvips.github.io/libvips/API/current/libvips-resample.html#vips-similarity
-func vipsSimilarity(in *C.VipsImage, scale float64, angle float64, color *ColorRGBA,
+func vipsSimilarity(ctx context.Context, in *C.VipsImage, scale float64, angle float64, color *ColorRGBA,
 	idx float64, idy float64, odx float64, ody float64) (*C.VipsImage, error) {
-	incOpCounter("similarity")
+	op := startOp(ctx, "similarity", in)
+	defer op.end()
 	var out *C.VipsImage
 
 	if err := C.similarity(in, &out, C.double(scale), C.double(angle),
 		C.double(color.R), C.double(color.G), C.double(color.B), C.double(color.A),
 		C.double(idx), C.double(idy), C.double(odx), C.double(ody)); err != 0 {
-		return nil, handleImageError(out)
+		return nil, op.fail(handleImageError(out))
 	}
 
 	return out, nil
 }
 
 // http://libvips.github.io/libvips/API/current/libvips-conversion.html#vips-smartcrop
-func vipsSmartCrop(in *C.VipsImage, width int, height int, interesting Interesting) (*C.VipsImage, error) {
-	_, out, _, err := vipsGenSmartcrop(in, width, height, &SmartcropOptions{Interesting: &interesting})
+func vipsSmartCrop(ctx context.Context, in *C.VipsImage, width int, height int, interesting Interesting) (*C.VipsImage, error) {
+	_, out, _, err := vipsGenSmartcrop(ctx, in, width, height, &SmartcropOptions{Interesting: &interesting})
 	return out, err
 }
 
 // http://libvips.github.io/libvips/API/current/libvips-conversion.html#vips-crop
-func vipsCrop(in *C.VipsImage, left int, top int, width int, height int) (*C.VipsImage, error) {
-	incOpCounter("crop")
+func vipsCrop(ctx context.Context, in *C.VipsImage, left int, top int, width int, height int) (*C.VipsImage, error) {
+	op := startOp(ctx, "crop", in)
+	defer op.end()
 	var out *C.VipsImage
 
 	if err := C.crop(in, &out, C.int(left), C.int(top), C.int(width), C.int(height)); err != 0 {
-		return nil, handleImageError(out)
+		return nil, op.fail(handleImageError(out))
 	}
 
 	return out, nil
 }
 
 // https://libvips.github.io/libvips/API/current/libvips-conversion.html#vips-composite
-func vipsComposite(ins []*C.VipsImage, modes []C.int, xs, ys []C.int) (*C.VipsImage, error) {
+func vipsComposite(ctx context.Context, ins []*C.VipsImage, modes []C.int, xs, ys []C.int) (*C.VipsImage, error) {
 	if len(ins) == 0 || len(modes) == 0 || len(xs) == 0 || len(ys) == 0 {
 		return nil, errors.New("vipsComposite: empty input slice")
 	}
-	incOpCounter("composite_multi")
+	op := startOp(ctx, "composite_multi", nil)
+	defer op.end()
 	var out *C.VipsImage
 
 	if err := C.composite_image(&ins[0], &out, C.int(len(ins)), &modes[0], &xs[0], &ys[0]); err != 0 {
-		return nil, handleImageError(out)
+		return nil, op.fail(handleImageError(out))
 	}
 
 	return out, nil
 }
 
 // https://libvips.github.io/libvips/API/current/libvips-conversion.html#vips-join
-func vipsJoin(input1 *C.VipsImage, input2 *C.VipsImage, dir Direction) (*C.VipsImage, error) {
-	incOpCounter("join")
+func vipsJoin(ctx context.Context, input1 *C.VipsImage, input2 *C.VipsImage, dir Direction) (*C.VipsImage, error) {
+	op := startOp(ctx, "join", input1)
+	defer op.end()
 	var out *C.VipsImage
 
 	// NOTE: do NOT unref input1/input2 here. They are borrowed from the
@@ -464,17 +541,61 @@ func vipsJoin(input1 *C.VipsImage, input2 *C.VipsImage, dir Direction) (*C.VipsI
 	// lifetime of the output. Unref'ing here drops refs we never owned,
 	// which double-frees the inputs once their ImageRefs are collected.
 	if err := C.join(input1, input2, &out, C.int(dir)); err != 0 {
+		return nil, op.fail(handleVipsError())
+	}
+	return out, nil
+}
+
+// https://www.libvips.org/API/current/libvips-conversion.html#vips-switch
+func vipsSwitch(ctx context.Context, tests []*C.VipsImage) (*C.VipsImage, error) {
+	if len(tests) == 0 {
+		return nil, errors.New("vipsSwitch: empty input slice")
+	}
+	op := startOp(ctx, "switch", tests[0])
+	defer op.end()
+	var out *C.VipsImage
+
+	if err := C.switch_image(&tests[0], &out, C.int(len(tests))); err != 0 {
+		return nil, op.fail(handleImageError(out))
+	}
+	return out, nil
+}
+
+// https://www.libvips.org/API/current/libvips-conversion.html#vips-case
+func vipsCase(ctx context.Context, index *C.VipsImage, cases []*C.VipsImage) (*C.VipsImage, error) {
+	if len(cases) == 0 {
+		return nil, errors.New("vipsCase: empty input slice")
+	}
+	op := startOp(ctx, "case", index)
+	defer op.end()
+	var out *C.VipsImage
+
+	if err := C.case_image(index, &cases[0], &out, C.int(len(cases))); err != 0 {
+		return nil, op.fail(handleImageError(out))
+	}
+	return out, nil
+}
+
+// vipsConstantImage makes an image the size and format of match with
+// every pixel set to c, one band per element.
+func vipsConstantImage(match *C.VipsImage, c []float64) (*C.VipsImage, error) {
+	if len(c) == 0 {
+		return nil, errors.New("vipsConstantImage: empty constant")
+	}
+	out := C.constant_image(match, (*C.double)(&c[0]), C.int(len(c)))
+	if out == nil {
 		return nil, handleVipsError()
 	}
 	return out, nil
 }
 
-func vipsAddAlpha(in *C.VipsImage) (*C.VipsImage, error) {
-	incOpCounter("addalpha")
+func vipsAddAlpha(ctx context.Context, in *C.VipsImage) (*C.VipsImage, error) {
+	op := startOp(ctx, "addalpha", in)
+	defer op.end()
 	var out *C.VipsImage
 
 	if err := C.add_alpha(in, &out); err != 0 {
-		return nil, handleImageError(out)
+		return nil, op.fail(handleImageError(out))
 	}
 
 	return out, nil
@@ -557,21 +678,144 @@ func vipsText(params *TextParams) (*C.VipsImage, error) {
 
 // Draw
 
+// drawInk converts color to an ink for the bands of in: greyscale images
+// take R as the grey level, {R} or {R, A}, and images with more than four
+// bands take {R, G, B, 0, ..., A}. 16-bit images are scaled from 8 bits.
+func drawInk(in *C.VipsImage, color ColorRGBA) []float64 {
+	bands := int(in.Bands)
+	var ink []float64
+	switch bands {
+	case 1:
+		ink = []float64{float64(color.R)}
+	case 2:
+		ink = []float64{float64(color.R), float64(color.A)}
+	case 3:
+		ink = []float64{float64(color.R), float64(color.G), float64(color.B)}
+	default:
+		ink = make([]float64, bands)
+		ink[0], ink[1], ink[2] = float64(color.R), float64(color.G), float64(color.B)
+		ink[bands-1] = float64(color.A)
+	}
+
+	interpretation := Interpretation(in.Type)
+	if interpretation == InterpretationRGB16 || interpretation == InterpretationGrey16 {
+		for i := range ink {
+			ink[i] = 65535 * ink[i] / 255
+		}
+	}
+	return ink
+}
+
+// The draw operations return the image drawn on, which is in itself when
+// it could be drawn on in place, or else a private copy: see draw_prepare.
+
 // https://libvips.github.io/libvips/API/current/libvips-draw.html#vips-draw-rect
-func vipsDrawRect(in *C.VipsImage, color ColorRGBA, left int, top int, width int, height int, fill bool) error {
-	incOpCounter("draw_rect")
+func vipsDrawRect(ctx context.Context, in *C.VipsImage, color ColorRGBA, left int, top int, width int, height int, fill bool) (*C.VipsImage, error) {
+	op := startOp(ctx, "draw_rect", in)
+	defer op.end()
+
+	ink := drawInk(in, color)
+	var out *C.VipsImage
 
-	fillBit := 0
-	if fill {
-		fillBit = 1
+	if err := C.draw_rect(in, &out, (*C.double)(&ink[0]), C.int(len(ink)),
+		C.int(left), C.int(top), C.int(width), C.int(height), C.int(boolToInt(fill))); err != 0 {
+		return nil, op.fail(handleVipsError())
 	}
 
-	if err := C.draw_rect(in, C.double(color.R), C.double(color.G), C.double(color.B), C.double(color.A),
-		C.int(left), C.int(top), C.int(width), C.int(height), C.int(fillBit)); err != 0 {
-		return handleImageError(in)
+	return out, nil
+}
+
+// https://libvips.github.io/libvips/API/current/libvips-draw.html#vips-draw-line
+func vipsDrawLine(ctx context.Context, in *C.VipsImage, color ColorRGBA, x1, y1, x2, y2 int) (*C.VipsImage, error) {
+	op := startOp(ctx, "draw_line", in)
+	defer op.end()
+
+	ink := drawInk(in, color)
+	var out *C.VipsImage
+
+	if err := C.draw_line(in, &out, (*C.double)(&ink[0]), C.int(len(ink)),
+		C.int(x1), C.int(y1), C.int(x2), C.int(y2)); err != 0 {
+		return nil, op.fail(handleVipsError())
 	}
 
-	return nil
+	return out, nil
+}
+
+// https://libvips.github.io/libvips/API/current/libvips-draw.html#vips-draw-circle
+func vipsDrawCircle(ctx context.Context, in *C.VipsImage, color ColorRGBA, cx, cy, radius int, fill bool) (*C.VipsImage, error) {
+	op := startOp(ctx, "draw_circle", in)
+	defer op.end()
+
+	ink := drawInk(in, color)
+	var out *C.VipsImage
+
+	if err := C.draw_circle(in, &out, (*C.double)(&ink[0]), C.int(len(ink)),
+		C.int(cx), C.int(cy), C.int(radius), C.int(boolToInt(fill))); err != 0 {
+		return nil, op.fail(handleVipsError())
+	}
+
+	return out, nil
+}
+
+// https://libvips.github.io/libvips/API/current/libvips-draw.html#vips-draw-flood
+func vipsDrawFlood(ctx context.Context, in *C.VipsImage, color ColorRGBA, x, y int, equal bool) (*C.VipsImage, int, int, int, int, error) {
+	op := startOp(ctx, "draw_flood", in)
+	defer op.end()
+
+	ink := drawInk(in, color)
+	var out *C.VipsImage
+	var left, top, width, height C.int
+
+	if err := C.draw_flood(in, &out, (*C.double)(&ink[0]), C.int(len(ink)),
+		C.int(x), C.int(y), C.int(boolToInt(equal)), &left, &top, &width, &height); err != 0 {
+		return nil, -1, -1, -1, -1, op.fail(handleVipsError())
+	}
+
+	return out, int(left), int(top), int(width), int(height), nil
+}
+
+// https://libvips.github.io/libvips/API/current/libvips-draw.html#vips-draw-image
+func vipsDrawImage(ctx context.Context, in *C.VipsImage, sub *C.VipsImage, x, y int, mode CombineMode) (*C.VipsImage, error) {
+	op := startOp(ctx, "draw_image", in)
+	defer op.end()
+
+	var out *C.VipsImage
+
+	if err := C.draw_image(in, &out, sub, C.int(x), C.int(y), C.int(mode)); err != 0 {
+		return nil, op.fail(handleVipsError())
+	}
+
+	return out, nil
+}
+
+// https://libvips.github.io/libvips/API/current/libvips-draw.html#vips-draw-mask
+func vipsDrawMask(ctx context.Context, in *C.VipsImage, color ColorRGBA, mask *C.VipsImage, x, y int) (*C.VipsImage, error) {
+	op := startOp(ctx, "draw_mask", in)
+	defer op.end()
+
+	ink := drawInk(in, color)
+	var out *C.VipsImage
+
+	if err := C.draw_mask(in, &out, (*C.double)(&ink[0]), C.int(len(ink)),
+		mask, C.int(x), C.int(y)); err != 0 {
+		return nil, op.fail(handleVipsError())
+	}
+
+	return out, nil
+}
+
+// https://libvips.github.io/libvips/API/current/libvips-draw.html#vips-draw-smudge
+func vipsDrawSmudge(ctx context.Context, in *C.VipsImage, left, top, width, height int) (*C.VipsImage, error) {
+	op := startOp(ctx, "draw_smudge", in)
+	defer op.end()
+
+	var out *C.VipsImage
+
+	if err := C.draw_smudge(in, &out, C.int(left), C.int(top), C.int(width), C.int(height)); err != 0 {
+		return nil, op.fail(handleVipsError())
+	}
+
+	return out, nil
 }
 
 // Header
@@ -700,18 +944,20 @@ func vipsImageGetMetaLoader(in *C.VipsImage) (string, bool) {
 	return C.GoString(out), code == 0
 }
 
-func vipsImageGetDelay(in *C.VipsImage, n int) ([]int, error) {
-	incOpCounter("imageGetDelay")
+func vipsImageGetDelay(ctx context.Context, in *C.VipsImage, n int) ([]int, error) {
+	op := startOp(ctx, "imageGetDelay", in)
+	defer op.end()
 	var out *C.int
 
 	if err := C.get_image_delay(in, &out); err != 0 {
-		return nil, handleVipsError()
+		return nil, op.fail(handleVipsError())
 	}
 	return fromCArrayInt(out, n), nil
 }
 
-func vipsImageSetDelay(in *C.VipsImage, data []C.int) error {
-	incOpCounter("imageSetDelay")
+func vipsImageSetDelay(ctx context.Context, in *C.VipsImage, data []C.int) error {
+	op := startOp(ctx, "imageSetDelay", in)
+	defer op.end()
 	if n := len(data); n > 0 {
 		C.set_image_delay(in, &data[0], C.int(n))
 	}
@@ -726,13 +972,14 @@ func vipsImageSetLoop(in *C.VipsImage, loop int) {
 	C.set_image_loop(in, C.int(loop))
 }
 
-func vipsImageGetBackground(in *C.VipsImage) ([]float64, error) {
-	incOpCounter("imageGetBackground")
+func vipsImageGetBackground(ctx context.Context, in *C.VipsImage) ([]float64, error) {
+	op := startOp(ctx, "imageGetBackground", in)
+	defer op.end()
 	var out *C.double
 	var n C.int
 
 	if err := C.get_background(in, &out, &n); err != 0 {
-		return nil, handleVipsError()
+		return nil, op.fail(handleVipsError())
 	}
 	return fromCArrayDouble(out, int(n)), nil
 }
@@ -780,7 +1027,10 @@ func vipsDetermineImageTypeFromMetaLoader(in *C.VipsImage) ImageType {
 }
 
 func vipsImageSetBlob(in *C.VipsImage, name string, data []byte) {
-	cData := unsafe.Pointer(&data)
+	var cData unsafe.Pointer
+	if len(data) > 0 {
+		cData = unsafe.Pointer(&data[0])
+	}
 	cDataLength := C.size_t(len(data))
 
 	cField := C.CString(name)
@@ -875,6 +1125,86 @@ func vipsImageGetAsString(in *C.VipsImage, name string) string {
 	return ""
 }
 
+func vipsImageSetArrayInt(in *C.VipsImage, name string, values []int) {
+	cField := C.CString(name)
+	defer freeCString(cField)
+
+	data := make([]C.int, len(values))
+	for i, v := range values {
+		data[i] = C.int(v)
+	}
+	var ptr *C.int
+	if len(data) > 0 {
+		ptr = &data[0]
+	}
+	C.image_set_array_int(in, cField, ptr, C.int(len(data)))
+}
+
+func vipsImageSetArrayDouble(in *C.VipsImage, name string, values []float64) {
+	cField := C.CString(name)
+	defer freeCString(cField)
+
+	data := make([]C.double, len(values))
+	for i, v := range values {
+		data[i] = C.double(v)
+	}
+	var ptr *C.double
+	if len(data) > 0 {
+		ptr = &data[0]
+	}
+	C.image_set_array_double(in, cField, ptr, C.int(len(data)))
+}
+
+func vipsImageSetImage(in *C.VipsImage, name string, image *C.VipsImage) {
+	cField := C.CString(name)
+	defer freeCString(cField)
+
+	C.image_set_image(in, cField, image)
+}
+
+// vipsImageGetField reads the field name of in as a typed HeaderField.
+func vipsImageGetField(in *C.VipsImage, name string) (HeaderField, bool) {
+	cField := C.CString(name)
+	defer freeCString(cField)
+
+	var v C.FieldValue
+	if int(C.image_get_field(in, cField, &v)) != 0 {
+		return HeaderField{}, false
+	}
+	defer C.field_value_free(&v)
+
+	field := HeaderField{Name: name, GType: C.GoString(v.type_name)}
+	switch v.kind {
+	case C.FIELD_INT:
+		field.Type, field.Value = FieldTypeInt, int(v.i)
+	case C.FIELD_DOUBLE:
+		field.Type, field.Value = FieldTypeDouble, float64(v.d)
+	case C.FIELD_BOOL:
+		field.Type, field.Value = FieldTypeBool, v.i != 0
+	case C.FIELD_STRING:
+		field.Type, field.Value = FieldTypeString, C.GoString(v.s)
+	case C.FIELD_ENUM:
+		field.Type, field.Value = FieldTypeEnum, C.GoString(v.s)
+	case C.FIELD_BLOB:
+		field.Type, field.Size = FieldTypeBlob, int(v.size)
+	case C.FIELD_ARRAY_INT:
+		field.Type, field.Value = FieldTypeIntArray, append([]int{}, fromCArrayInt(v.ints, int(v.n))...)
+	case C.FIELD_ARRAY_DOUBLE:
+		field.Type, field.Value = FieldTypeDoubleArray, append([]float64{}, fromCArrayDouble(v.doubles, int(v.n))...)
+	case C.FIELD_IMAGE:
+		field.Type = FieldTypeImage
+		field.Image = &FieldImage{Width: int(v.width), Height: int(v.height), Bands: int(v.bands)}
+	default:
+		field.Type, field.Value = FieldTypeOther, C.GoString(v.s)
+	}
+	return field, true
+}
+
+// vipsEnumNick returns the libvips nickname of value in the enum gtype.
+func vipsEnumNick(gtype C.GType, value int) string {
+	return C.GoString(C.vips_enum_nick(gtype, C.int(value)))
+}
+
 // Label
 
 // Align represents VIPS_ALIGN
@@ -917,8 +1247,9 @@ type vipsLabelOptions struct {
 	Color     [3]C.double
 }
 
-func labelImage(in *C.VipsImage, params *LabelParams) (*C.VipsImage, error) {
-	incOpCounter("label")
+func labelImage(ctx context.Context, in *C.VipsImage, params *LabelParams) (*C.VipsImage, error) {
+	op := startOp(ctx, "label", in)
+	defer op.end()
 	var out *C.VipsImage
 
 	text := C.CString(params.Text)
@@ -950,7 +1281,7 @@ func labelImage(in *C.VipsImage, params *LabelParams) (*C.VipsImage, error) {
 	// todo: release inline pointer?
 	err := C.label(in, &out, (*C.LabelOptions)(unsafe.Pointer(&opts)))
 	if err != 0 {
-		return nil, handleImageError(out)
+		return nil, op.fail(handleImageError(out))
 	}
 
 	return out, nil
@@ -984,8 +1315,9 @@ const (
 )
 
 // https://libvips.github.io/libvips/API/current/libvips-resample.html#vips-resize
-func vipsResizeWithVScale(in *C.VipsImage, hscale, vscale float64, kernel Kernel) (*C.VipsImage, error) {
-	incOpCounter("resize")
+func vipsResizeWithVScale(ctx context.Context, in *C.VipsImage, hscale, vscale float64, kernel Kernel) (*C.VipsImage, error) {
+	op := startOp(ctx, "resize", in)
+	defer op.end()
 	var out *C.VipsImage
 
 	// libvips recommends Lanczos3 as the default kernel
@@ -994,18 +1326,19 @@ func vipsResizeWithVScale(in *C.VipsImage, hscale, vscale float64, kernel Kernel
 	}
 
 	if err := C.resize_image(in, &out, C.double(hscale), C.double(vscale), C.int(kernel)); err != 0 {
-		return nil, handleImageError(out)
+		return nil, op.fail(handleImageError(out))
 	}
 
 	return out, nil
 }
 
-func vipsThumbnail(in *C.VipsImage, width, height int, crop Interesting, size Size) (*C.VipsImage, error) {
-	incOpCounter("thumbnail")
+func vipsThumbnail(ctx context.Context, in *C.VipsImage, width, height int, crop Interesting, size Size) (*C.VipsImage, error) {
+	op := startOp(ctx, "thumbnail", in)
+	defer op.end()
 	var out *C.VipsImage
 
 	if err := C.thumbnail_image(in, &out, C.int(width), C.int(height), C.int(crop), C.int(size)); err != 0 {
-		return nil, handleImageError(out)
+		return nil, op.fail(handleImageError(out))
 	}
 
 	return out, nil
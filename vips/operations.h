@@ -14,6 +14,10 @@ int find_trim(VipsImage *in, int *left, int *top, int *width, int *height,
               double threshold, double r, double g, double b);
 int getpoint(VipsImage *in, double **vector, int n, int x, int y);
 int minOp(VipsImage *in, double *out, int *x, int *y, int size);
+int extremes(VipsImage *in, int band, int size, int find_max, double **values,
+             int **xs, int **ys, int *n);
+int measure_patches(VipsImage *in, double **out, size_t *size, int left, int top,
+                    int width, int height, int h, int v);
 
 // Color
 // https://libvips.github.io/libvips/API/current/libvips-colour.html
@@ -44,6 +48,9 @@ int similarity(VipsImage *in, VipsImage **out, double scale, double angle,
 int composite_image(VipsImage **in, VipsImage **out, int n, int *mode, int *x,
                     int *y);
 int join(VipsImage *in1, VipsImage *in2, VipsImage **out, int direction);
+int switch_image(VipsImage **tests, VipsImage **out, int n);
+int case_image(VipsImage *index, VipsImage **cases, VipsImage **out, int n);
+VipsImage *constant_image(VipsImage *match, double *c, int n);
 int add_alpha(VipsImage *in, VipsImage **out);
 
 // Create
@@ -67,8 +74,21 @@ int text(VipsImage **out, TextOptions *o);
 // Draw
 // https://libvips.github.io/libvips/API/current/libvips-draw.html
 
-int draw_rect(VipsImage *in, double r, double g, double b, double a, int left,
+int draw_rect(VipsImage *in, VipsImage **out, double *ink, int n, int left,
               int top, int width, int height, int fill);
+int draw_line(VipsImage *in, VipsImage **out, double *ink, int n, int x1,
+              int y1, int x2, int y2);
+int draw_circle(VipsImage *in, VipsImage **out, double *ink, int n, int cx,
+                int cy, int radius, int fill);
+int draw_flood(VipsImage *in, VipsImage **out, double *ink, int n, int x,
+               int y, int equal, int *left, int *top, int *width,
+               int *height);
+int draw_image(VipsImage *in, VipsImage **out, VipsImage *sub, int x, int y,
+               int mode);
+int draw_mask(VipsImage *in, VipsImage **out, double *ink, int n,
+              VipsImage *mask, int x, int y);
+int draw_smudge(VipsImage *in, VipsImage **out, int left, int top, int width,
+                int height);
 
 // Header
 // https://libvips.github.io/libvips/API/current/libvips-header.html
@@ -113,6 +133,46 @@ unsigned long image_get_double(VipsImage *in, const char *name, double *out);
 void image_set_int(VipsImage *in, const char *name, int i);
 unsigned long image_get_int(VipsImage *in, const char *name, int *out);
 
+void image_set_array_int(VipsImage *in, const char *name, const int *array,
+                         int n);
+void image_set_array_double(VipsImage *in, const char *name,
+                            const double *array, int n);
+void image_set_image(VipsImage *in, const char *name, VipsImage *image);
+
+// FieldKind classifies the GType of a header field value.
+typedef enum {
+  FIELD_OTHER = 0,
+  FIELD_INT,
+  FIELD_DOUBLE,
+  FIELD_BOOL,
+  FIELD_STRING,
+  FIELD_ENUM,
+  FIELD_BLOB,
+  FIELD_ARRAY_INT,
+  FIELD_ARRAY_DOUBLE,
+  FIELD_IMAGE
+} FieldKind;
+
+// FieldValue is a copy of a header field value. Strings and arrays are
+// owned by the struct and released with field_value_free.
+typedef struct {
+  FieldKind kind;
+  const char *type_name;
+  gint64 i;
+  double d;
+  char *s;
+  size_t size;
+  int *ints;
+  double *doubles;
+  int n;
+  int width;
+  int height;
+  int bands;
+} FieldValue;
+
+int image_get_field(VipsImage *in, const char *name, FieldValue *out);
+void field_value_free(FieldValue *v);
+
 // Label
 
 typedef struct {
@@ -0,0 +1,775 @@
+package vips
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// ErrInvalidPipeline is returned when a pipeline definition fails schema
+// validation. The returned error wraps it and names the offending step.
+var ErrInvalidPipeline = errors.New("invalid pipeline")
+
+// Pipeline is an ordered list of processing steps, typically configured as
+// data rather than code. A pipeline is decoded from JSON or YAML of the form
+//
+//	{"steps": [
+//	  {"op": "thumbnail", "width": 400, "height": 300, "crop": "attention"},
+//	  {"op": "sharpen", "sigma": 0.8},
+//	  {"op": "export", "format": "webp", "quality": 75}
+//	]}
+//
+// where each step's op selects one of the step types below and the other
+// keys are its fields in snake_case. Enum values are libvips nicknames, as
+// accepted by ParseKernel, ParseInteresting and the other ParseX functions
+// ("lanczos3", "attention", "horizontal", "dest-over", ...), plus "auto" for
+// the kernel; angles are given in degrees. Unknown ops, unknown keys, missing required fields and
+// out-of-range values are rejected with an error wrapping
+// ErrInvalidPipeline.
+//
+// An export step, if present, must be the last step.
+type Pipeline struct {
+	Steps []Step
+
+	// LoadOverlay resolves the Overlay of composite steps to an image. The
+	// pipeline closes the returned image after use. It is required by
+	// pipelines with composite steps: recipes are configuration, so the
+	// caller decides which images an overlay name may refer to.
+	LoadOverlay func(name string) (*ImageRef, error)
+}
+
+// Step is a single operation of a Pipeline. It is implemented by
+// ResizeStep, ThumbnailStep, CropStep, SmartCropStep, RotateStep,
+// AutoRotateStep, FlipStep, SharpenStep, ModulateStep, CompositeStep and
+// ExportStep.
+type Step interface {
+	// Op returns the name of the step in the JSON and YAML form.
+	Op() string
+
+	validate() error
+	apply(img *ImageRef, p *Pipeline) error
+	// randomAccess returns why the step cannot run on a sequentially
+	// streamed image with the given EXIF orientation, or "" if it can.
+	randomAccess(orientation int) string
+}
+
+// ResizeStep scales the image by Scale, or by Scale horizontally and VScale
+// vertically when VScale is set. See ImageRef.Resize.
+type ResizeStep struct {
+	Scale  float64
+	VScale float64
+	Kernel Kernel
+}
+
+// ThumbnailStep resizes the image to fit Width x Height with
+// ImageRef.ThumbnailWithSize, cropping the overflow according to Crop.
+// Either dimension may be zero to leave that side unconstrained. Like
+// ImageRef.Thumbnail, it applies the EXIF orientation.
+type ThumbnailStep struct {
+	Width  int
+	Height int
+	Crop   Interesting
+	Size   Size
+}
+
+// CropStep extracts the given area. See ImageRef.ExtractArea.
+type CropStep struct {
+	Left   int
+	Top    int
+	Width  int
+	Height int
+}
+
+// SmartCropStep crops the image to Width x Height, keeping the most
+// interesting area. See ImageRef.SmartCrop.
+type SmartCropStep struct {
+	Width       int
+	Height      int
+	Interesting Interesting
+}
+
+// RotateStep rotates the image by a multiple of 90 degrees.
+type RotateStep struct {
+	Angle Angle
+}
+
+// AutoRotateStep applies the EXIF orientation. See ImageRef.AutoRotate.
+type AutoRotateStep struct{}
+
+// FlipStep mirrors the image.
+type FlipStep struct {
+	Direction Direction
+}
+
+// SharpenStep sharpens the image. See ImageRef.Sharpen.
+type SharpenStep struct {
+	Sigma float64
+	X1    float64
+	M2    float64
+}
+
+// ModulateStep adjusts brightness, saturation and hue. See
+// ImageRef.Modulate.
+type ModulateStep struct {
+	Brightness float64
+	Saturation float64
+	Hue        float64
+}
+
+// CompositeStep blends an overlay image on top of the image at X, Y.
+// Overlay is resolved with Pipeline.LoadOverlay.
+type CompositeStep struct {
+	Overlay string
+	Mode    BlendMode
+	X       int
+	Y       int
+}
+
+// ExportStep encodes the image. Format defaults to the format the image was
+// loaded from. Quality overrides the format's default quality when > 0.
+type ExportStep struct {
+	Format        ImageType
+	Quality       int
+	StripMetadata bool
+}
+
+// Op implements Step.
+func (ResizeStep) Op() string { return "resize" }
+
+// Op implements Step.
+func (ThumbnailStep) Op() string { return "thumbnail" }
+
+// Op implements Step.
+func (CropStep) Op() string { return "crop" }
+
+// Op implements Step.
+func (SmartCropStep) Op() string { return "smartcrop" }
+
+// Op implements Step.
+func (RotateStep) Op() string { return "rotate" }
+
+// Op implements Step.
+func (AutoRotateStep) Op() string { return "autorotate" }
+
+// Op implements Step.
+func (FlipStep) Op() string { return "flip" }
+
+// Op implements Step.
+func (SharpenStep) Op() string { return "sharpen" }
+
+// Op implements Step.
+func (ModulateStep) Op() string { return "modulate" }
+
+// Op implements Step.
+func (CompositeStep) Op() string { return "composite" }
+
+// Op implements Step.
+func (ExportStep) Op() string { return "export" }
+
+func (s ResizeStep) validate() error {
+	if s.Scale <= 0 {
+		return errors.New("scale must be positive")
+	}
+	if s.VScale < 0 {
+		return errors.New("vscale must not be negative")
+	}
+	return nil
+}
+
+func (s ThumbnailStep) validate() error {
+	if s.Width < 0 || s.Height < 0 {
+		return errors.New("width and height must not be negative")
+	}
+	if s.Width == 0 && s.Height == 0 {
+		return errors.New("width or height is required")
+	}
+	return nil
+}
+
+func (s CropStep) validate() error {
+	if s.Left < 0 || s.Top < 0 {
+		return errors.New("left and top must not be negative")
+	}
+	if s.Width <= 0 || s.Height <= 0 {
+		return errors.New("width and height must be positive")
+	}
+	return nil
+}
+
+func (s SmartCropStep) validate() error {
+	if s.Width <= 0 || s.Height <= 0 {
+		return errors.New("width and height must be positive")
+	}
+	return nil
+}
+
+func (RotateStep) validate() error     { return nil }
+func (AutoRotateStep) validate() error { return nil }
+func (FlipStep) validate() error       { return nil }
+
+func (s SharpenStep) validate() error {
+	if s.Sigma <= 0 {
+		return errors.New("sigma must be positive")
+	}
+	return nil
+}
+
+func (s ModulateStep) validate() error {
+	if s.Brightness < 0 || s.Saturation < 0 {
+		return errors.New("brightness and saturation must not be negative")
+	}
+	return nil
+}
+
+func (s CompositeStep) validate() error {
+	if s.Overlay == "" {
+		return errors.New("overlay is required")
+	}
+	return nil
+}
+
+func (s ExportStep) validate() error {
+	if s.Quality < 0 || s.Quality > 100 {
+		return errors.New("quality must be between 0 and 100")
+	}
+	return nil
+}
+
+func (s ResizeStep) apply(img *ImageRef, _ *Pipeline) error {
+	if s.VScale > 0 {
+		return img.ResizeWithVScale(s.Scale, s.VScale, s.Kernel)
+	}
+	return img.Resize(s.Scale, s.Kernel)
+}
+
+func (s ThumbnailStep) apply(img *ImageRef, _ *Pipeline) error {
+	width, height := s.Width, s.Height
+	if width == 0 {
+		width = MaxCoord
+	}
+	if height == 0 {
+		height = MaxCoord
+	}
+	return img.ThumbnailWithSize(width, height, s.Crop, s.Size)
+}
+
+func (s CropStep) apply(img *ImageRef, _ *Pipeline) error {
+	return img.ExtractArea(s.Left, s.Top, s.Width, s.Height)
+}
+
+func (s SmartCropStep) apply(img *ImageRef, _ *Pipeline) error {
+	return img.SmartCrop(s.Width, s.Height, s.Interesting)
+}
+
+func (s RotateStep) apply(img *ImageRef, _ *Pipeline) error {
+	if s.Angle == Angle0 {
+		return nil
+	}
+	return img.Rotate(s.Angle)
+}
+
+func (AutoRotateStep) apply(img *ImageRef, _ *Pipeline) error {
+	return img.AutoRotate()
+}
+
+func (s FlipStep) apply(img *ImageRef, _ *Pipeline) error {
+	return img.Flip(s.Direction)
+}
+
+func (s SharpenStep) apply(img *ImageRef, _ *Pipeline) error {
+	return img.Sharpen(s.Sigma, s.X1, s.M2)
+}
+
+func (s ModulateStep) apply(img *ImageRef, _ *Pipeline) error {
+	return img.Modulate(s.Brightness, s.Saturation, s.Hue)
+}
+
+func (s CompositeStep) apply(img *ImageRef, p *Pipeline) error {
+	overlay, err := p.LoadOverlay(s.Overlay)
+	if err != nil {
+		return fmt.Errorf("load overlay %q: %w", s.Overlay, err)
+	}
+	// The composited image keeps its own reference to the overlay pixels.
+	defer overlay.Close()
+	return img.Composite(overlay, s.Mode, s.X, s.Y)
+}
+
+// apply is a no-op: the encode happens in Run and RunStream.
+func (ExportStep) apply(*ImageRef, *Pipeline) error { return nil }
+
+func (ResizeStep) randomAccess(int) string      { return "" }
+func (CropStep) randomAccess(int) string        { return "" }
+func (SharpenStep) randomAccess(int) string     { return "" }
+func (ModulateStep) randomAccess(int) string    { return "" }
+func (CompositeStep) randomAccess(int) string   { return "" }
+func (ExportStep) randomAccess(int) string      { return "" }
+func (s SmartCropStep) randomAccess(int) string { return interestingRandomAccess(s.Interesting) }
+
+func (s ThumbnailStep) randomAccess(orientation int) string {
+	if orientationNeedsRandomAccess(orientation) {
+		return fmt.Sprintf("applies EXIF orientation %d", orientation)
+	}
+	return interestingRandomAccess(s.Crop)
+}
+
+func (AutoRotateStep) randomAccess(orientation int) string {
+	if orientationNeedsRandomAccess(orientation) {
+		return fmt.Sprintf("applies EXIF orientation %d", orientation)
+	}
+	return ""
+}
+
+func (s RotateStep) randomAccess(int) string {
+	if s.Angle != Angle0 {
+		return "rotation reverses or transposes line order"
+	}
+	return ""
+}
+
+func (s FlipStep) randomAccess(int) string {
+	if s.Direction == DirectionVertical {
+		return "vertical flip reverses line order"
+	}
+	return ""
+}
+
+// interestingRandomAccess reports whether cropping with interesting needs to
+// analyse the whole image before the first line can be produced.
+func interestingRandomAccess(interesting Interesting) string {
+	switch interesting {
+	case InterestingNone, InterestingCentre:
+		return ""
+	}
+	return "smart crop analyses the whole image"
+}
+
+// StreamBreak describes a pipeline step that needs random access to its
+// input and so cannot run on a sequentially streamed image.
+type StreamBreak struct {
+	// Index is the position of the step in Pipeline.Steps.
+	Index  int
+	Op     string
+	Reason string
+}
+
+// StreamBreaks reports the steps that cannot run on a sequentially
+// streamed input with the given EXIF orientation (see ImageRef.Orientation).
+// RunStream materializes the image before the first of them; an empty
+// result means the whole pipeline streams in one pass.
+//
+// The orientation matters to the steps that apply it, autorotate and
+// thumbnail, with the same rule TranscodeStream uses: orientations 1 and 2
+// stream, 3-8 need random access. After either step the image is upright.
+func (p *Pipeline) StreamBreaks(orientation int) []StreamBreak {
+	var breaks []StreamBreak
+	for i, step := range p.Steps {
+		if reason := step.randomAccess(orientation); reason != "" {
+			breaks = append(breaks, StreamBreak{Index: i, Op: step.Op(), Reason: reason})
+		}
+		switch step.(type) {
+		case AutoRotateStep, ThumbnailStep:
+			orientation = 1
+		}
+	}
+	return breaks
+}
+
+// Validate checks every step and the position of the export step, and
+// that LoadOverlay is set if a composite step needs it.
+func (p *Pipeline) Validate() error {
+	if err := p.validateSteps(); err != nil {
+		return err
+	}
+	for i, step := range p.Steps {
+		if _, ok := step.(CompositeStep); ok && p.LoadOverlay == nil {
+			return fmt.Errorf("%w: step %d (%s): Pipeline.LoadOverlay is required to load overlays", ErrInvalidPipeline, i, step.Op())
+		}
+	}
+	return nil
+}
+
+// validateSteps is Validate without the LoadOverlay check, for decoding:
+// the loader is set on the parsed pipeline afterwards.
+func (p *Pipeline) validateSteps() error {
+	for i, step := range p.Steps {
+		if step == nil {
+			return fmt.Errorf("%w: step %d is nil", ErrInvalidPipeline, i)
+		}
+		if err := step.validate(); err != nil {
+			return fmt.Errorf("%w: step %d (%s): %v", ErrInvalidPipeline, i, step.Op(), err)
+		}
+		if _, ok := step.(ExportStep); ok && i != len(p.Steps)-1 {
+			return fmt.Errorf("%w: step %d (%s): export must be the last step", ErrInvalidPipeline, i, step.Op())
+		}
+	}
+	return nil
+}
+
+// exportStep returns the trailing export step, if any.
+func (p *Pipeline) exportStep() (ExportStep, bool) {
+	if len(p.Steps) == 0 {
+		return ExportStep{}, false
+	}
+	s, ok := p.Steps[len(p.Steps)-1].(ExportStep)
+	return s, ok
+}
+
+// Run applies the pipeline to img in place. If the pipeline ends with an
+// export step, the encoded image is returned; otherwise the returned buffer
+// is nil and img holds the result. img may have been loaded sequentially
+// with LoadImageFromReader: it is materialized as RunStream would.
+func (p *Pipeline) Run(img *ImageRef) ([]byte, *ImageMetadata, error) {
+	if err := p.Validate(); err != nil {
+		return nil, nil, err
+	}
+	if err := p.applySteps(img); err != nil {
+		return nil, nil, err
+	}
+
+	export, ok := p.exportStep()
+	if !ok {
+		return nil, nil, nil
+	}
+	return export.formatChoice(img).Export(img)
+}
+
+// RunStream decodes an image from r, applies the pipeline and encodes the
+// result to w, in the export step's format or, without one, the input
+// format. Like TranscodeStream, the image is loaded sequentially and pixels
+// flow from r to w in one pass unless a step needs random access (see
+// StreamBreaks), in which case the image is materialized just before that
+// step.
+func (p *Pipeline) RunStream(r io.Reader, w io.Writer) error {
+	if err := p.Validate(); err != nil {
+		return err
+	}
+	if r == nil {
+		return errors.New("pipeline: reader is nil")
+	}
+	if w == nil {
+		return errors.New("pipeline: writer is nil")
+	}
+
+	params := NewImportParams()
+	params.Access.Set(AccessSequential)
+	img, err := LoadImageFromReader(r, params)
+	if err != nil {
+		return fmt.Errorf("pipeline: %w", err)
+	}
+	defer img.Close()
+
+	if err := p.applySteps(img); err != nil {
+		return err
+	}
+
+	export, ok := p.exportStep()
+	if !ok {
+		export = ExportStep{Format: img.Format()}
+	}
+	if err := export.formatChoice(img).SaveToWriter(img, w); err != nil {
+		return fmt.Errorf("pipeline: %w", err)
+	}
+	return nil
+}
+
+// applySteps runs every step on img. A sequentially stream-loaded image is
+// materialized before the first step that needs random access; for other
+// images materialize is a no-op.
+func (p *Pipeline) applySteps(img *ImageRef) error {
+	materializeAt := -1
+	if breaks := p.StreamBreaks(img.Orientation()); len(breaks) > 0 {
+		materializeAt = breaks[0].Index
+	}
+	for i, step := range p.Steps {
+		if i == materializeAt {
+			if err := img.materialize(); err != nil {
+				return fmt.Errorf("pipeline: %w", err)
+			}
+		}
+		if err := step.apply(img, p); err != nil {
+			return fmt.Errorf("pipeline: step %d (%s): %w", i, step.Op(), err)
+		}
+	}
+	return nil
+}
+
+func (s ExportStep) formatChoice(img *ImageRef) *FormatChoice {
+	format := s.Format
+	if format == ImageTypeUnknown {
+		format = img.Format()
+	}
+	return NewFormatChoice(format, &NegotiationPrefs{Quality: s.Quality, StripMetadata: s.StripMetadata})
+}
+
+// ParsePipeline decodes and validates a JSON pipeline definition. Set
+// LoadOverlay on the result before running one with composite steps.
+func ParsePipeline(data []byte) (*Pipeline, error) {
+	p := &Pipeline{}
+	if err := json.Unmarshal(data, p); err != nil {
+		return nil, err
+	}
+	return p, nil
+}
+
+// ParsePipelineYAML decodes and validates a YAML pipeline definition. The
+// schema is the same as for JSON.
+func ParsePipelineYAML(data []byte) (*Pipeline, error) {
+	p := &Pipeline{}
+	if err := yaml.Unmarshal(data, p); err != nil {
+		return nil, err
+	}
+	return p, nil
+}
+
+// UnmarshalJSON implements json.Unmarshaler, decoding and validating the
+// schema described on Pipeline.
+func (p *Pipeline) UnmarshalJSON(data []byte) error {
+	var doc struct {
+		Steps []json.RawMessage `json:"steps"`
+	}
+	if err := decodeStrict(data, &doc); err != nil {
+		return fmt.Errorf("%w: %v", ErrInvalidPipeline, err)
+	}
+
+	steps := make([]Step, 0, len(doc.Steps))
+	for i, raw := range doc.Steps {
+		step, err := decodeStep(raw)
+		if err != nil {
+			return fmt.Errorf("%w: step %d: %v", ErrInvalidPipeline, i, err)
+		}
+		steps = append(steps, step)
+	}
+
+	decoded := Pipeline{Steps: steps, LoadOverlay: p.LoadOverlay}
+	if err := decoded.validateSteps(); err != nil {
+		return err
+	}
+	*p = decoded
+	return nil
+}
+
+// UnmarshalYAML implements yaml.Unmarshaler. The document is converted to
+// JSON so that both forms share one schema.
+func (p *Pipeline) UnmarshalYAML(value *yaml.Node) error {
+	var doc interface{}
+	if err := value.Decode(&doc); err != nil {
+		return err
+	}
+	data, err := json.Marshal(doc)
+	if err != nil {
+		return fmt.Errorf("%w: %v", ErrInvalidPipeline, err)
+	}
+	return p.UnmarshalJSON(data)
+}
+
+// stepOp is embedded in every wire step so that strict decoding accepts the
+// op key.
+type stepOp struct {
+	Op string `json:"op"`
+}
+
+func decodeStep(raw json.RawMessage) (Step, error) {
+	var op stepOp
+	if err := json.Unmarshal(raw, &op); err != nil {
+		return nil, err
+	}
+
+	var err error
+	switch op.Op {
+	case "":
+		return nil, errors.New("op is required")
+	case "resize":
+		var w struct {
+			stepOp
+			Scale  float64 `json:"scale"`
+			VScale float64 `json:"vscale"`
+			Kernel string  `json:"kernel"`
+		}
+		s := ResizeStep{Kernel: KernelAuto}
+		if err = decodeStrict(raw, &w); err == nil {
+			s.Scale, s.VScale = w.Scale, w.VScale
+			err = parseName("kernel", w.Kernel, parsePipelineKernel, &s.Kernel)
+		}
+		return s, err
+	case "thumbnail":
+		var w struct {
+			stepOp
+			Width  int    `json:"width"`
+			Height int    `json:"height"`
+			Crop   string `json:"crop"`
+			Size   string `json:"size"`
+		}
+		s := ThumbnailStep{Crop: InterestingNone, Size: SizeBoth}
+		if err = decodeStrict(raw, &w); err == nil {
+			s.Width, s.Height = w.Width, w.Height
+			err = parseName("crop", w.Crop, ParseInteresting, &s.Crop)
+		}
+		if err == nil {
+			err = parseName("size", w.Size, ParseSize, &s.Size)
+		}
+		return s, err
+	case "crop":
+		var w struct {
+			stepOp
+			Left   int `json:"left"`
+			Top    int `json:"top"`
+			Width  int `json:"width"`
+			Height int `json:"height"`
+		}
+		err = decodeStrict(raw, &w)
+		return CropStep{Left: w.Left, Top: w.Top, Width: w.Width, Height: w.Height}, err
+	case "smartcrop":
+		var w struct {
+			stepOp
+			Width       int    `json:"width"`
+			Height      int    `json:"height"`
+			Interesting string `json:"interesting"`
+		}
+		s := SmartCropStep{Interesting: InterestingAttention}
+		if err = decodeStrict(raw, &w); err == nil {
+			s.Width, s.Height = w.Width, w.Height
+			err = parseName("interesting", w.Interesting, ParseInteresting, &s.Interesting)
+		}
+		return s, err
+	case "rotate":
+		var w struct {
+			stepOp
+			Angle *int `json:"angle"`
+		}
+		var s RotateStep
+		if err = decodeStrict(raw, &w); err == nil {
+			err = parseAngle(w.Angle, &s.Angle)
+		}
+		return s, err
+	case "autorotate":
+		var w struct{ stepOp }
+		return AutoRotateStep{}, decodeStrict(raw, &w)
+	case "flip":
+		var w struct {
+			stepOp
+			Direction string `json:"direction"`
+		}
+		var s FlipStep
+		if err = decodeStrict(raw, &w); err == nil {
+			if w.Direction == "" {
+				err = errors.New("direction is required")
+			} else {
+				err = parseName("direction", w.Direction, ParseDirection, &s.Direction)
+			}
+		}
+		return s, err
+	case "sharpen":
+		// Defaults match libvips' vips_sharpen.
+		w := struct {
+			stepOp
+			Sigma float64 `json:"sigma"`
+			X1    float64 `json:"x1"`
+			M2    float64 `json:"m2"`
+		}{Sigma: 0.5, X1: 2, M2: 3}
+		err = decodeStrict(raw, &w)
+		return SharpenStep{Sigma: w.Sigma, X1: w.X1, M2: w.M2}, err
+	case "modulate":
+		w := struct {
+			stepOp
+			Brightness float64 `json:"brightness"`
+			Saturation float64 `json:"saturation"`
+			Hue        float64 `json:"hue"`
+		}{Brightness: 1, Saturation: 1}
+		err = decodeStrict(raw, &w)
+		return ModulateStep{Brightness: w.Brightness, Saturation: w.Saturation, Hue: w.Hue}, err
+	case "composite":
+		var w struct {
+			stepOp
+			Overlay string `json:"overlay"`
+			Mode    string `json:"mode"`
+			X       int    `json:"x"`
+			Y       int    `json:"y"`
+		}
+		s := CompositeStep{Mode: BlendModeOver}
+		if err = decodeStrict(raw, &w); err == nil {
+			s.Overlay, s.X, s.Y = w.Overlay, w.X, w.Y
+			err = parseName("mode", w.Mode, ParseBlendMode, &s.Mode)
+		}
+		return s, err
+	case "export":
+		var w struct {
+			stepOp
+			Format        string `json:"format"`
+			Quality       int    `json:"quality"`
+			StripMetadata bool   `json:"strip_metadata"`
+		}
+		var s ExportStep
+		if err = decodeStrict(raw, &w); err == nil {
+			s.Quality, s.StripMetadata = w.Quality, w.StripMetadata
+			if w.Format != "" {
+				if s.Format = ParseImageType(w.Format); s.Format == ImageTypeUnknown {
+					err = fmt.Errorf("unknown format %q", w.Format)
+				}
+			}
+		}
+		return s, err
+	default:
+		return nil, fmt.Errorf("unknown op %q", op.Op)
+	}
+}
+
+// decodeStrict decodes a single JSON value into v, rejecting unknown keys
+// and trailing data.
+func decodeStrict(data []byte, v interface{}) error {
+	dec := json.NewDecoder(bytes.NewReader(data))
+	dec.DisallowUnknownFields()
+	if err := dec.Decode(v); err != nil {
+		return err
+	}
+	if dec.More() {
+		return errors.New("unexpected data after JSON value")
+	}
+	return nil
+}
+
+// parseName sets dst to the enum value parse finds for name, a libvips
+// nickname. An empty name leaves dst at its default.
+func parseName[T any](field, name string, parse func(string) (T, error), dst *T) error {
+	if name == "" {
+		return nil
+	}
+	v, err := parse(name)
+	if err != nil {
+		return fmt.Errorf("unknown %s %q", field, name)
+	}
+	*dst = v
+	return nil
+}
+
+// parsePipelineKernel is ParseKernel plus "auto" for KernelAuto, which has
+// no libvips nickname.
+func parsePipelineKernel(name string) (Kernel, error) {
+	if strings.EqualFold(name, "auto") {
+		return KernelAuto, nil
+	}
+	return ParseKernel(name)
+}
+
+func parseAngle(degrees *int, dst *Angle) error {
+	if degrees == nil {
+		return errors.New("angle is required")
+	}
+	switch (*degrees%360 + 360) % 360 {
+	case 0:
+		*dst = Angle0
+	case 90:
+		*dst = Angle90
+	case 180:
+		*dst = Angle180
+	case 270:
+		*dst = Angle270
+	default:
+		return fmt.Errorf("angle %d is not a multiple of 90", *degrees)
+	}
+	return nil
+}
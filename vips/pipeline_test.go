@@ -0,0 +1,249 @@
+package vips
+
+import (
+	"bytes"
+	"errors"
+	"io"
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParsePipeline(t *testing.T) {
+	p, err := ParsePipeline([]byte(`{"steps": [
+		{"op": "autorotate"},
+		{"op": "resize", "scale": 0.5, "kernel": "Lanczos3"},
+		{"op": "thumbnail", "width": 100, "crop": "attention"},
+		{"op": "crop", "left": 1, "top": 2, "width": 3, "height": 4},
+		{"op": "smartcrop", "width": 10, "height": 10},
+		{"op": "rotate", "angle": -90},
+		{"op": "flip", "direction": "vertical"},
+		{"op": "sharpen"},
+		{"op": "modulate", "hue": 30},
+		{"op": "composite", "overlay": "logo.png", "mode": "multiply", "x": 3},
+		{"op": "export", "format": "webp", "quality": 80, "strip_metadata": true}
+	]}`))
+	require.NoError(t, err)
+	assert.Equal(t, []Step{
+		AutoRotateStep{},
+		ResizeStep{Scale: 0.5, Kernel: KernelLanczos3},
+		ThumbnailStep{Width: 100, Crop: InterestingAttention, Size: SizeBoth},
+		CropStep{Left: 1, Top: 2, Width: 3, Height: 4},
+		SmartCropStep{Width: 10, Height: 10, Interesting: InterestingAttention},
+		RotateStep{Angle: Angle270},
+		FlipStep{Direction: DirectionVertical},
+		SharpenStep{Sigma: 0.5, X1: 2, M2: 3},
+		ModulateStep{Brightness: 1, Saturation: 1, Hue: 30},
+		CompositeStep{Overlay: "logo.png", Mode: BlendModeMultiply, X: 3},
+		ExportStep{Format: ImageTypeWEBP, Quality: 80, StripMetadata: true},
+	}, p.Steps)
+}
+
+func TestParsePipelineYAML(t *testing.T) {
+	p, err := ParsePipelineYAML([]byte(`
+steps:
+  - op: thumbnail
+    width: 64
+    height: 64
+    crop: centre
+  - op: export
+    format: jpg
+    quality: 70
+`))
+	require.NoError(t, err)
+	assert.Equal(t, []Step{
+		ThumbnailStep{Width: 64, Height: 64, Crop: InterestingCentre, Size: SizeBoth},
+		ExportStep{Format: ImageTypeJPEG, Quality: 70},
+	}, p.Steps)
+
+	_, err = ParsePipelineYAML([]byte("steps:\n  - op: crop\n    width: 0\n"))
+	assert.True(t, errors.Is(err, ErrInvalidPipeline))
+}
+
+func TestParsePipeline_Invalid(t *testing.T) {
+	tests := []string{
+		`{"steps": [{"op": "resize"}]}`,
+		`{"steps": [{"op": "resize", "scale": 1, "bogus": 1}]}`,
+		`{"steps": [{"op": "resize", "scale": 1, "kernel": "bilinear"}]}`,
+		`{"steps": [{"op": "nope"}]}`,
+		`{"steps": [{"scale": 1}]}`,
+		`{"steps": [{"op": "thumbnail"}]}`,
+		`{"steps": [{"op": "rotate"}]}`,
+		`{"steps": [{"op": "rotate", "angle": 45}]}`,
+		`{"steps": [{"op": "flip"}]}`,
+		`{"steps": [{"op": "composite"}]}`,
+		`{"steps": [{"op": "export", "format": "bmp"}]}`,
+		`{"steps": [{"op": "export", "quality": 101}]}`,
+		`{"steps": [{"op": "export"}, {"op": "autorotate"}]}`,
+		`{"stages": []}`,
+	}
+	for _, tt := range tests {
+		_, err := ParsePipeline([]byte(tt))
+		assert.True(t, errors.Is(err, ErrInvalidPipeline), "%s: %v", tt, err)
+	}
+}
+
+func TestPipeline_StreamBreaks(t *testing.T) {
+	p := &Pipeline{Steps: []Step{
+		ResizeStep{Scale: 0.5},
+		ThumbnailStep{Width: 100},
+		FlipStep{Direction: DirectionHorizontal},
+		SharpenStep{Sigma: 1},
+		ExportStep{Format: ImageTypeJPEG},
+	}}
+	assert.Empty(t, p.StreamBreaks(1))
+	assert.Empty(t, p.StreamBreaks(2))
+
+	breaks := p.StreamBreaks(6)
+	require.Len(t, breaks, 1)
+	assert.Equal(t, 1, breaks[0].Index)
+	assert.Equal(t, "thumbnail", breaks[0].Op)
+
+	// Once autorotated, later steps see an upright image.
+	p.Steps = append([]Step{AutoRotateStep{}}, p.Steps...)
+	breaks = p.StreamBreaks(8)
+	require.Len(t, breaks, 1)
+	assert.Equal(t, 0, breaks[0].Index)
+
+	p = &Pipeline{Steps: []Step{
+		SmartCropStep{Width: 10, Height: 10, Interesting: InterestingEntropy},
+		RotateStep{Angle: Angle90},
+		FlipStep{Direction: DirectionVertical},
+		SmartCropStep{Width: 10, Height: 10, Interesting: InterestingCentre},
+	}}
+	breaks = p.StreamBreaks(1)
+	require.Len(t, breaks, 3)
+	assert.Equal(t, []int{0, 1, 2}, []int{breaks[0].Index, breaks[1].Index, breaks[2].Index})
+}
+
+func TestPipeline_Run(t *testing.T) {
+	Startup(nil)
+
+	img, err := NewImageFromFile(resources + "jpg-24bit.jpg")
+	require.NoError(t, err)
+	defer img.Close()
+
+	p, err := ParsePipeline([]byte(`{"steps": [
+		{"op": "thumbnail", "width": 60, "height": 40, "crop": "centre"},
+		{"op": "rotate", "angle": 90},
+		{"op": "sharpen", "sigma": 1},
+		{"op": "modulate", "brightness": 1.1},
+		{"op": "export", "format": "png"}
+	]}`))
+	require.NoError(t, err)
+
+	buf, _, err := p.Run(img)
+	require.NoError(t, err)
+	assert.Equal(t, ImageTypePNG, DetermineImageType(buf))
+
+	out, err := NewImageFromBuffer(buf)
+	require.NoError(t, err)
+	defer out.Close()
+	assert.Equal(t, 40, out.Width())
+	assert.Equal(t, 60, out.Height())
+}
+
+func TestPipeline_Run_NoExport(t *testing.T) {
+	Startup(nil)
+
+	img, err := NewImageFromFile(resources + "jpg-24bit.jpg")
+	require.NoError(t, err)
+	defer img.Close()
+
+	p := &Pipeline{Steps: []Step{CropStep{Left: 10, Top: 10, Width: 20, Height: 30}}}
+	buf, _, err := p.Run(img)
+	require.NoError(t, err)
+	assert.Nil(t, buf)
+	assert.Equal(t, 20, img.Width())
+	assert.Equal(t, 30, img.Height())
+}
+
+func TestPipeline_Run_Composite(t *testing.T) {
+	Startup(nil)
+
+	img, err := NewImageFromFile(resources + "jpg-24bit.jpg")
+	require.NoError(t, err)
+	defer img.Close()
+
+	var loaded string
+	p := &Pipeline{
+		Steps: []Step{CompositeStep{Overlay: "logo", Mode: BlendModeOver, X: 10, Y: 10}},
+		LoadOverlay: func(name string) (*ImageRef, error) {
+			loaded = name
+			return NewImageFromFile(resources + "with_alpha.png")
+		},
+	}
+	_, _, err = p.Run(img)
+	require.NoError(t, err)
+	assert.Equal(t, "logo", loaded)
+	assert.Equal(t, 100, img.Width())
+
+	p.LoadOverlay = func(string) (*ImageRef, error) { return nil, ErrUnsupportedImageFormat }
+	_, _, err = p.Run(img)
+	assert.True(t, errors.Is(err, ErrUnsupportedImageFormat))
+}
+
+func TestPipeline_CompositeRequiresLoadOverlay(t *testing.T) {
+	Startup(nil)
+
+	// Recipes parse without a loader, but never read overlays from disk.
+	p, err := ParsePipeline([]byte(`{"steps": [{"op": "composite", "overlay": "` + resources + `with_alpha.png"}]}`))
+	require.NoError(t, err)
+
+	img, err := NewImageFromFile(resources + "jpg-24bit.jpg")
+	require.NoError(t, err)
+	defer img.Close()
+
+	_, _, err = p.Run(img)
+	assert.True(t, errors.Is(err, ErrInvalidPipeline))
+	assert.Contains(t, err.Error(), "LoadOverlay")
+	assert.True(t, errors.Is(p.RunStream(strings.NewReader(""), io.Discard), ErrInvalidPipeline))
+}
+
+func TestPipeline_RunStream(t *testing.T) {
+	Startup(nil)
+
+	src, err := os.ReadFile(resources + "jpg-orientation-6.jpg")
+	require.NoError(t, err)
+
+	p, err := ParsePipeline([]byte(`{"steps": [
+		{"op": "autorotate"},
+		{"op": "resize", "scale": 0.5},
+		{"op": "export", "format": "jpeg", "quality": 75}
+	]}`))
+	require.NoError(t, err)
+	require.Len(t, p.StreamBreaks(6), 1)
+
+	var streamed bytes.Buffer
+	require.NoError(t, p.RunStream(bytes.NewReader(src), &streamed))
+
+	img, err := NewImageFromBuffer(src)
+	require.NoError(t, err)
+	defer img.Close()
+	buffered, _, err := p.Run(img)
+	require.NoError(t, err)
+
+	assert.Equal(t, buffered, streamed.Bytes())
+}
+
+func TestPipeline_RunStream_KeepsFormat(t *testing.T) {
+	Startup(nil)
+
+	src, err := os.ReadFile(resources + "png-24bit+alpha.png")
+	require.NoError(t, err)
+
+	p := &Pipeline{Steps: []Step{ResizeStep{Scale: 0.25}, FlipStep{Direction: DirectionHorizontal}}}
+	assert.Empty(t, p.StreamBreaks(1))
+
+	var out bytes.Buffer
+	require.NoError(t, p.RunStream(bytes.NewReader(src), &out))
+	assert.Equal(t, ImageTypePNG, DetermineImageType(out.Bytes()))
+
+	img, err := NewImageFromBuffer(out.Bytes())
+	require.NoError(t, err)
+	defer img.Close()
+	assert.Equal(t, 128, img.Width())
+}
@@ -0,0 +1,223 @@
+package vips
+
+// #include "image.h"
+import "C"
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"runtime"
+	"unsafe"
+)
+
+// Placeholders are computed from a small thumbnail rather than the full
+// image: both hashes only keep a handful of low-frequency DCT terms, so a
+// larger input costs encode time without changing the result.
+const (
+	blurHashThumbnailSize  = 64
+	thumbHashThumbnailSize = thumbHashMaxSize
+
+	// blurHashDecodeSize is the longest side BlurHash placeholders are
+	// rendered at before being upscaled by libvips to the requested size.
+	blurHashDecodeSize = 32
+)
+
+// BlurHash returns the BlurHash (https://blurha.sh) of the image using the
+// given number of horizontal and vertical components, each between 1 and 9.
+// The hash is computed from a small sRGB thumbnail of the image. BlurHash has
+// no notion of transparency, so images with an alpha channel are flattened
+// onto white first. The receiver is not modified.
+func (r *ImageRef) BlurHash(xComponents, yComponents int) (string, error) {
+	defer runtime.KeepAlive(r)
+	thumb, err := vipsThumbnail(r.Context(), r.image, blurHashThumbnailSize, blurHashThumbnailSize, InterestingNone, SizeDown)
+	if err != nil {
+		return "", err
+	}
+	pixels, width, height, err := vipsPlaceholderPixels(r.Context(), thumb, false)
+	if err != nil {
+		return "", err
+	}
+	return blurHashEncode(pixels, width, height, xComponents, yComponents)
+}
+
+// BlurHashFromBuffer returns the BlurHash of an encoded image. Unlike
+// ImageRef.BlurHash, the image is decoded with shrink-on-load, so only a
+// fraction of the pixels of large JPEG, WebP or HEIF inputs are ever decoded.
+func BlurHashFromBuffer(buf []byte, xComponents, yComponents int) (string, error) {
+	if err := startupIfNeeded(); err != nil {
+		return "", err
+	}
+	if len(buf) == 0 {
+		return "", errors.New("empty buffer")
+	}
+	thumb, _, err := vipsThumbnailFromBuffer(buf, blurHashThumbnailSize, blurHashThumbnailSize, InterestingNone, SizeDown, nil)
+	if err != nil {
+		return "", err
+	}
+	pixels, width, height, err := vipsPlaceholderPixels(context.Background(), thumb, false)
+	if err != nil {
+		return "", err
+	}
+	return blurHashEncode(pixels, width, height, xComponents, yComponents)
+}
+
+// ThumbHash returns the ThumbHash (https://evanw.github.io/thumbhash/) of the
+// image. The hash is computed from a thumbnail of at most 100x100 pixels in
+// sRGB and keeps the alpha channel and aspect ratio of the image. The receiver
+// is not modified.
+func (r *ImageRef) ThumbHash() ([]byte, error) {
+	defer runtime.KeepAlive(r)
+	thumb, err := vipsThumbnail(r.Context(), r.image, thumbHashThumbnailSize, thumbHashThumbnailSize, InterestingNone, SizeDown)
+	if err != nil {
+		return nil, err
+	}
+	pixels, width, height, err := vipsPlaceholderPixels(r.Context(), thumb, true)
+	if err != nil {
+		return nil, err
+	}
+	return thumbHashEncode(pixels, width, height)
+}
+
+// ThumbHashFromBuffer returns the ThumbHash of an encoded image, decoding it
+// with shrink-on-load.
+func ThumbHashFromBuffer(buf []byte) ([]byte, error) {
+	if err := startupIfNeeded(); err != nil {
+		return nil, err
+	}
+	if len(buf) == 0 {
+		return nil, errors.New("empty buffer")
+	}
+	thumb, _, err := vipsThumbnailFromBuffer(buf, thumbHashThumbnailSize, thumbHashThumbnailSize, InterestingNone, SizeDown, nil)
+	if err != nil {
+		return nil, err
+	}
+	pixels, width, height, err := vipsPlaceholderPixels(context.Background(), thumb, true)
+	if err != nil {
+		return nil, err
+	}
+	return thumbHashEncode(pixels, width, height)
+}
+
+// NewImageFromBlurHash renders a BlurHash into a new sRGB image of the given
+// size. punch adjusts the contrast of the placeholder; pass 1 for the
+// reference rendering. The hash is decoded at a small size and upscaled by
+// libvips, which is indistinguishable for such a smooth image and keeps
+// decoding cheap for large placeholders.
+func NewImageFromBlurHash(hash string, width, height int, punch float64) (*ImageRef, error) {
+	if width <= 0 || height <= 0 {
+		return nil, fmt.Errorf("invalid dimensions: width=%d height=%d", width, height)
+	}
+
+	decodeWidth, decodeHeight := width, height
+	if decodeWidth > blurHashDecodeSize || decodeHeight > blurHashDecodeSize {
+		if width >= height {
+			decodeWidth = blurHashDecodeSize
+			decodeHeight = max(1, height*blurHashDecodeSize/width)
+		} else {
+			decodeHeight = blurHashDecodeSize
+			decodeWidth = max(1, width*blurHashDecodeSize/height)
+		}
+	}
+
+	pixels, err := blurHashDecode(hash, decodeWidth, decodeHeight, punch)
+	if err != nil {
+		return nil, err
+	}
+
+	ref, err := NewImageFromMemory(pixels, decodeWidth, decodeHeight, 3, BandFormatUchar, InterpretationSRGB)
+	if err != nil {
+		return nil, err
+	}
+	if decodeWidth == width && decodeHeight == height {
+		return ref, nil
+	}
+
+	hScale := float64(width) / float64(decodeWidth)
+	vScale := float64(height) / float64(decodeHeight)
+	if err := ref.ResizeWithVScale(hScale, vScale, KernelLinear); err != nil {
+		ref.Close()
+		return nil, err
+	}
+	return ref, nil
+}
+
+// NewImageFromThumbHash renders a ThumbHash into a new four-band sRGB image.
+// The image is at most 32 pixels on its longest side, at the aspect ratio
+// stored in the hash; resize it to the display size as needed.
+func NewImageFromThumbHash(hash []byte) (*ImageRef, error) {
+	pixels, width, height, err := thumbHashDecode(hash)
+	if err != nil {
+		return nil, err
+	}
+	return NewImageFromMemory(pixels, width, height, 4, BandFormatUchar, InterpretationSRGB)
+}
+
+// vipsPlaceholderPixels converts in to packed 8-bit sRGB pixels, either RGBA
+// (withAlpha) or RGB flattened onto white. It takes ownership of in.
+func vipsPlaceholderPixels(ctx context.Context, in *C.VipsImage, withAlpha bool) ([]byte, int, int, error) {
+	tmp := in
+	defer func() { clearImage(tmp) }()
+
+	if Interpretation(int(tmp.Type)) != InterpretationSRGB {
+		out, err := vipsToColorSpace(ctx, tmp, InterpretationSRGB)
+		if err != nil {
+			return nil, 0, 0, err
+		}
+		clearImage(tmp)
+		tmp = out
+	}
+
+	// Flatten before casting so 16-bit alpha is composited at full precision.
+	if !withAlpha && vipsHasAlpha(tmp) {
+		out, err := vipsGenFlatten(ctx, tmp, &FlattenOptions{Background: []float64{255, 255, 255}})
+		if err != nil {
+			return nil, 0, 0, err
+		}
+		clearImage(tmp)
+		tmp = out
+	}
+
+	if BandFormat(int(tmp.BandFmt)) != BandFormatUchar {
+		out, err := vipsGenCast(ctx, tmp, BandFormatUchar, nil)
+		if err != nil {
+			return nil, 0, 0, err
+		}
+		clearImage(tmp)
+		tmp = out
+	}
+
+	width := int(tmp.Xsize)
+	height := int(tmp.Ysize)
+	bands := int(tmp.Bands)
+	if bands != 3 && bands != 4 {
+		return nil, 0, 0, fmt.Errorf("unsupported number of bands: %d", bands)
+	}
+
+	var cSize C.size_t
+	cData := C.vips_image_write_to_memory(tmp, &cSize)
+	if cData == nil {
+		return nil, 0, 0, errors.New("failed to write image to memory")
+	}
+	defer C.free(cData)
+	data := C.GoBytes(unsafe.Pointer(cData), C.int(cSize))
+
+	switch {
+	case withAlpha && bands == 3:
+		rgba := make([]byte, width*height*4)
+		for i, j := 0, 0; i+2 < len(data); i, j = i+3, j+4 {
+			rgba[j], rgba[j+1], rgba[j+2], rgba[j+3] = data[i], data[i+1], data[i+2], 255
+		}
+		return rgba, width, height, nil
+	case !withAlpha && bands == 4:
+		// Only reachable when the alpha band is not detected as such;
+		// drop it rather than mixing it into the colour.
+		rgb := make([]byte, width*height*3)
+		for i, j := 0, 0; i+3 < len(data); i, j = i+4, j+3 {
+			rgb[j], rgb[j+1], rgb[j+2] = data[i], data[i+1], data[i+2]
+		}
+		return rgb, width, height, nil
+	default:
+		return data, width, height, nil
+	}
+}
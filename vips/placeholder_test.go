@@ -0,0 +1,182 @@
+package vips
+
+import (
+	"errors"
+	"os"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func solidPixels(width, height int, px ...byte) []byte {
+	buf := make([]byte, 0, width*height*len(px))
+	for i := 0; i < width*height; i++ {
+		buf = append(buf, px...)
+	}
+	return buf
+}
+
+func TestBlurHash_RoundTripSolidColor(t *testing.T) {
+	hash, err := blurHashEncode(solidPixels(16, 8, 200, 100, 50), 16, 8, 4, 3)
+	require.NoError(t, err)
+	assert.Len(t, hash, 4+2*4*3)
+
+	// A single component is just the average colour, which survives the
+	// round trip exactly.
+	hash, err = blurHashEncode(solidPixels(16, 8, 200, 100, 50), 16, 8, 1, 1)
+	require.NoError(t, err)
+	assert.Len(t, hash, 6)
+
+	pixels, err := blurHashDecode(hash, 4, 2, 1)
+	require.NoError(t, err)
+	for i := 0; i < len(pixels); i += 3 {
+		assert.InDelta(t, 200, int(pixels[i]), 1)
+		assert.InDelta(t, 100, int(pixels[i+1]), 1)
+		assert.InDelta(t, 50, int(pixels[i+2]), 1)
+	}
+}
+
+func TestBlurHash_InvalidInput(t *testing.T) {
+	_, err := blurHashEncode(solidPixels(4, 4, 0, 0, 0), 4, 4, 0, 3)
+	assert.Error(t, err)
+	_, err = blurHashEncode(solidPixels(4, 4, 0, 0, 0), 4, 4, 10, 3)
+	assert.Error(t, err)
+
+	_, err = blurHashDecode("LEHV6n", 4, 4, 1)
+	assert.True(t, errors.Is(err, ErrInvalidBlurHash))
+	_, err = blurHashDecode("L\"HV6nWB2yk8pyo0adR*.7kCMdnj", 4, 4, 1)
+	assert.True(t, errors.Is(err, ErrInvalidBlurHash))
+}
+
+func TestThumbHash_RoundTripSolidColor(t *testing.T) {
+	hash, err := thumbHashEncode(solidPixels(40, 20, 30, 120, 220, 255), 40, 20)
+	require.NoError(t, err)
+
+	pixels, width, height, err := thumbHashDecode(hash)
+	require.NoError(t, err)
+	assert.Equal(t, 32, width)
+	assert.InDelta(t, 16, height, 3)
+	for i := 0; i < len(pixels); i += 4 {
+		assert.InDelta(t, 30, int(pixels[i]), 6)
+		assert.InDelta(t, 120, int(pixels[i+1]), 6)
+		assert.InDelta(t, 220, int(pixels[i+2]), 6)
+		assert.Equal(t, 255, int(pixels[i+3]))
+	}
+}
+
+func TestThumbHash_Alpha(t *testing.T) {
+	pixels := solidPixels(20, 20, 255, 0, 0, 255)
+	// Make the right half fully transparent.
+	for y := 0; y < 20; y++ {
+		for x := 10; x < 20; x++ {
+			pixels[(y*20+x)*4+3] = 0
+		}
+	}
+	hash, err := thumbHashEncode(pixels, 20, 20)
+	require.NoError(t, err)
+	assert.NotZero(t, hash[2]&0x80, "alpha flag should be set")
+
+	decoded, width, height, err := thumbHashDecode(hash)
+	require.NoError(t, err)
+	left := decoded[(height/2*width+2)*4+3]
+	right := decoded[(height/2*width+width-3)*4+3]
+	assert.Greater(t, int(left), 200)
+	assert.Less(t, int(right), 55)
+}
+
+func TestThumbHash_TooLarge(t *testing.T) {
+	_, err := thumbHashEncode(solidPixels(101, 10, 0, 0, 0, 255), 101, 10)
+	assert.Error(t, err)
+	_, _, _, err = thumbHashDecode([]byte{1, 2})
+	assert.True(t, errors.Is(err, ErrInvalidThumbHash))
+}
+
+func TestImageRef_BlurHash(t *testing.T) {
+	Startup(nil)
+
+	img, err := NewImageFromFile(resources + "jpg-24bit.jpg")
+	require.NoError(t, err)
+	defer img.Close()
+	width, height := img.Width(), img.Height()
+
+	hash, err := img.BlurHash(4, 3)
+	require.NoError(t, err)
+	assert.Len(t, hash, 28)
+
+	// The receiver is left untouched.
+	assert.Equal(t, width, img.Width())
+	assert.Equal(t, height, img.Height())
+
+	buf, err := os.ReadFile(resources + "jpg-24bit.jpg")
+	require.NoError(t, err)
+	fromBuffer, err := BlurHashFromBuffer(buf, 4, 3)
+	require.NoError(t, err)
+	assert.Equal(t, len(hash), len(fromBuffer))
+	assert.Equal(t, hash[0], fromBuffer[0])
+
+	placeholder, err := NewImageFromBlurHash(hash, 320, 240, 1)
+	require.NoError(t, err)
+	defer placeholder.Close()
+	assert.Equal(t, 320, placeholder.Width())
+	assert.Equal(t, 240, placeholder.Height())
+	assert.Equal(t, 3, placeholder.Bands())
+	assert.Equal(t, InterpretationSRGB, placeholder.Interpretation())
+}
+
+func TestImageRef_BlurHash_Alpha(t *testing.T) {
+	Startup(nil)
+
+	img, err := NewImageFromFile(resources + "png-8bit+alpha.png")
+	require.NoError(t, err)
+	defer img.Close()
+	require.True(t, img.HasAlpha())
+
+	hash, err := img.BlurHash(3, 3)
+	require.NoError(t, err)
+	assert.Len(t, hash, 22)
+}
+
+func TestImageRef_ThumbHash(t *testing.T) {
+	Startup(nil)
+
+	img, err := NewImageFromFile(resources + "png-8bit+alpha.png")
+	require.NoError(t, err)
+	defer img.Close()
+
+	hash, err := img.ThumbHash()
+	require.NoError(t, err)
+	assert.NotZero(t, hash[2]&0x80, "alpha flag should be set")
+
+	buf, err := os.ReadFile(resources + "png-8bit+alpha.png")
+	require.NoError(t, err)
+	fromBuffer, err := ThumbHashFromBuffer(buf)
+	require.NoError(t, err)
+	assert.Equal(t, len(hash), len(fromBuffer))
+
+	placeholder, err := NewImageFromThumbHash(hash)
+	require.NoError(t, err)
+	defer placeholder.Close()
+	assert.Equal(t, 4, placeholder.Bands())
+	assert.LessOrEqual(t, placeholder.Width(), 32)
+	assert.LessOrEqual(t, placeholder.Height(), 32)
+}
+
+func TestImageRef_ThumbHash_Grayscale(t *testing.T) {
+	Startup(nil)
+
+	img, err := NewImageFromFile(resources + "jpg-8bit-grey-icc-dot-gain.jpg")
+	require.NoError(t, err)
+	defer img.Close()
+
+	hash, err := img.ThumbHash()
+	require.NoError(t, err)
+	assert.Zero(t, hash[2]&0x80, "opaque image should not set the alpha flag")
+
+	placeholder, err := NewImageFromThumbHash(hash)
+	require.NoError(t, err)
+	defer placeholder.Close()
+	// 715x483 is landscape, so the placeholder is too.
+	assert.Equal(t, 32, placeholder.Width())
+	assert.Less(t, placeholder.Height(), 32)
+}
@@ -0,0 +1,110 @@
+package vips
+
+// #include "stream.h"
+import "C"
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"io"
+)
+
+// ProbeMetadata is the header information returned by Probe: the
+// ImageMetadata of a loaded image plus details useful for routing an image
+// before deciding how to process it.
+type ProbeMetadata struct {
+	ImageMetadata
+	// OriginalFormat is the format of the input, which differs from Format
+	// for inputs converted on load, such as BMP.
+	OriginalFormat ImageType
+	// PageHeight is the height of one page or frame.
+	PageHeight int
+	// Bands is the number of bands.
+	Bands int
+	// BandFormat is the format of a band as decoded by libvips.
+	BandFormat BandFormat
+	// BitsPerSample is the bit depth of one band in the input, for example
+	// 1 for a bilevel PNG, 8 for most JPEGs and 16 for a 16-bit PNG.
+	BitsPerSample int
+	HasAlpha      bool
+	HasICCProfile bool
+	// Animated reports whether the image has more than one frame meant to
+	// be played as an animation, as in an animated GIF or WebP.
+	Animated bool
+}
+
+// Probe reads the header of the image in r and returns its metadata
+// without decoding any pixels. It reads r through a streaming source, so
+// only the bytes the loader needs for the header are consumed from a
+// non-seekable reader; the rest of r is left unread. Pages is the number
+// of pages or frames in the whole input.
+func Probe(r io.Reader) (*ProbeMetadata, error) {
+	return ProbeContext(context.Background(), r)
+}
+
+// ProbeContext is like Probe, tracing the probe under ctx (see Tracer).
+func ProbeContext(ctx context.Context, r io.Reader) (*ProbeMetadata, error) {
+	if r == nil {
+		return nil, errors.New("reader is nil")
+	}
+	if err := startupIfNeeded(); err != nil {
+		return nil, err
+	}
+
+	op := startOp(ctx, "probe_source", nil)
+	defer op.end()
+
+	params := NewImportParams()
+	params.Access.Set(AccessSequential)
+	in, src, format, originalFormat, err := loadFromSource(r, params)
+	if err != nil {
+		return nil, op.fail(err)
+	}
+	defer src.release()
+	defer clearImage(in)
+	op.loaded(in, format, src.entry.bytesRead())
+
+	return probeImage(in, format, originalFormat), nil
+}
+
+// ProbeBuffer is like Probe for an image in memory.
+func ProbeBuffer(buf []byte) (*ProbeMetadata, error) {
+	return Probe(bytes.NewReader(buf))
+}
+
+func probeImage(in *C.VipsImage, format, originalFormat ImageType) *ProbeMetadata {
+	pages := vipsGetImageNPages(in)
+	if format == ImageTypeJP2K {
+		// n-pages counts pyramid layers in JP2K; see ImageRef.Pages.
+		pages = 1
+	}
+
+	bandFormat := BandFormat(int(in.BandFmt))
+	bits := vipsImageGetInt(in, "bits-per-sample")
+	if bits <= 0 {
+		bits = 8 * int(C.vips_format_sizeof(in.BandFmt))
+	}
+
+	_, hasDelay := vipsImageGetField(in, "delay")
+	animated := pages > 1 && (format == ImageTypeGIF || format == ImageTypeWEBP || hasDelay)
+
+	return &ProbeMetadata{
+		ImageMetadata: ImageMetadata{
+			Format:      format,
+			Width:       int(in.Xsize),
+			Height:      int(in.Ysize),
+			Colorspace:  Interpretation(int(in.Type)),
+			Orientation: vipsGetMetaOrientation(in),
+			Pages:       pages,
+		},
+		OriginalFormat: originalFormat,
+		PageHeight:     vipsGetPageHeight(in),
+		Bands:          int(in.Bands),
+		BandFormat:     bandFormat,
+		BitsPerSample:  bits,
+		HasAlpha:       vipsHasAlpha(in),
+		HasICCProfile:  vipsHasICCProfile(in),
+		Animated:       animated,
+	}
+}
@@ -0,0 +1,99 @@
+package vips
+
+import (
+	"bytes"
+	"os"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestProbe(t *testing.T) {
+	require.NoError(t, Startup(nil))
+
+	tests := []struct {
+		file     string
+		format   ImageType
+		pages    int
+		bands    int
+		bits     int
+		animated bool
+	}{
+		{"jpg-24bit.jpg", ImageTypeJPEG, 1, 3, 8, false},
+		{"jpg-24bit-icc-adobe-rgb.jpg", ImageTypeJPEG, 1, 3, 8, false},
+		{"jpg-orientation-6.jpg", ImageTypeJPEG, 1, 3, 8, false},
+		{"png-alpha-64bit.png", ImageTypePNG, 1, 4, 16, false},
+		{"gif-animated.gif", ImageTypeGIF, 8, 4, 8, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.file, func(t *testing.T) {
+			buf, err := os.ReadFile(resources + tt.file)
+			require.NoError(t, err)
+
+			m, err := ProbeBuffer(buf)
+			require.NoError(t, err)
+
+			image, err := NewImageFromBuffer(buf)
+			require.NoError(t, err)
+			defer image.Close()
+
+			assert.Equal(t, tt.format, m.Format)
+			assert.Equal(t, tt.pages, m.Pages)
+			assert.Equal(t, tt.bands, m.Bands)
+			assert.Equal(t, tt.bits, m.BitsPerSample)
+			assert.Equal(t, tt.animated, m.Animated)
+
+			assert.Equal(t, image.Width(), m.Width)
+			assert.Equal(t, image.Height(), m.Height)
+			assert.Equal(t, image.PageHeight(), m.PageHeight)
+			assert.Equal(t, image.Interpretation(), m.Colorspace)
+			assert.Equal(t, image.Orientation(), m.Orientation)
+			assert.Equal(t, image.HasAlpha(), m.HasAlpha)
+			assert.Equal(t, image.HasICCProfile(), m.HasICCProfile)
+		})
+	}
+
+	m, err := ProbeBuffer(mustReadFile(t, resources+"jpg-orientation-6.jpg"))
+	require.NoError(t, err)
+	assert.Equal(t, 6, m.Orientation)
+
+	m, err = ProbeBuffer(mustReadFile(t, resources+"jpg-24bit-icc-adobe-rgb.jpg"))
+	require.NoError(t, err)
+	assert.True(t, m.HasICCProfile)
+}
+
+func mustReadFile(t *testing.T, path string) []byte {
+	t.Helper()
+	buf, err := os.ReadFile(path)
+	require.NoError(t, err)
+	return buf
+}
+
+func TestProbe_ReadsOnlyHeader(t *testing.T) {
+	require.NoError(t, Startup(nil))
+
+	buf, err := os.ReadFile(resources + "png-alpha-64bit.png")
+	require.NoError(t, err)
+
+	before := OpenImageRefs()
+	r := &countingReader{r: bytes.NewReader(buf)}
+	m, err := Probe(r)
+	require.NoError(t, err)
+	assert.Equal(t, ImageTypePNG, m.Format)
+	assert.Less(t, r.n.Load(), int64(len(buf)))
+
+	sources, _ := streamRegistrySizes()
+	assert.Equal(t, 0, sources)
+	assertNoNewImageRefs(t, before)
+}
+
+func TestProbe_Invalid(t *testing.T) {
+	require.NoError(t, Startup(nil))
+
+	_, err := ProbeBuffer([]byte("not an image"))
+	assert.Error(t, err)
+
+	_, err = Probe(nil)
+	assert.Error(t, err)
+}
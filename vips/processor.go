@@ -0,0 +1,294 @@
+package vips
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"runtime"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+var (
+	// ErrProcessorBusy is returned by Processor.Submit when the queue is full.
+	ErrProcessorBusy = errors.New("processor queue is full")
+	// ErrProcessorClosed is returned by Processor.Submit after Close.
+	ErrProcessorClosed = errors.New("processor is closed")
+)
+
+const defaultMemoryPollInterval = 10 * time.Millisecond
+
+// ProcessorConfig configures a Processor.
+type ProcessorConfig struct {
+	// MaxConcurrent is the number of jobs that run at once. It defaults to
+	// runtime.NumCPU().
+	MaxConcurrent int
+	// MaxTrackedMemory holds back new jobs while the memory tracked by
+	// libvips (MemoryStats.Mem) is at or above this many bytes. A job is
+	// always admitted when no other job is admitted or running, so that the
+	// processor cannot stall on memory it does not own, such as the
+	// operation cache.
+	// Zero means no limit.
+	MaxTrackedMemory int64
+	// QueueDepth is the number of jobs that may wait for admission; Submit
+	// returns ErrProcessorBusy when it is reached. Zero means no limit and a
+	// negative value rejects every job that cannot start at once.
+	QueueDepth int
+	// MemoryPollInterval is how often a job held back by MaxTrackedMemory
+	// checks the tracked memory again. It defaults to 10ms.
+	MemoryPollInterval time.Duration
+}
+
+// ProcessorStats is a snapshot of the counters of a Processor, as returned
+// by ReadStats.
+type ProcessorStats struct {
+	// Queued is the number of jobs waiting for admission.
+	Queued int64
+	// Running is the number of jobs running.
+	Running int64
+	// Completed is the number of jobs that returned, including with an
+	// error.
+	Completed int64
+	// Failed is the number of completed jobs that returned an error or
+	// panicked.
+	Failed int64
+	// Rejected is the number of jobs refused with ErrProcessorBusy.
+	Rejected int64
+	// Canceled is the number of jobs whose context ended while they were
+	// queued.
+	Canceled int64
+	// MemoryWaits is the number of jobs that were held back by
+	// MaxTrackedMemory.
+	MemoryWaits int64
+	// QueueTime is the total time jobs spent queued before they started.
+	QueueTime time.Duration
+}
+
+// Job is a unit of work run by a Processor. It receives the context passed
+// to Submit.
+type Job func(ctx context.Context) error
+
+type processorTask struct {
+	ctx  context.Context
+	job  Job
+	done chan error
+}
+
+// Processor runs image jobs on a bounded pool of workers, admitting them
+// by concurrency and by libvips tracked memory. Workers are pinned to OS
+// threads and call ShutdownThread before they exit, so that libvips
+// per-thread state is released. A Processor must be closed with Close.
+type Processor struct {
+	config ProcessorConfig
+	slots  chan struct{}
+	tasks  chan processorTask
+	quit   chan struct{}
+
+	mu       sync.Mutex
+	closed   bool
+	inflight sync.WaitGroup
+	workers  sync.WaitGroup
+	once     sync.Once
+
+	queued      atomic.Int64
+	running     atomic.Int64
+	completed   atomic.Int64
+	failed      atomic.Int64
+	rejected    atomic.Int64
+	canceled    atomic.Int64
+	memoryWaits atomic.Int64
+	queueTime   atomic.Int64
+
+	// admitted counts jobs from admission until they finish, including
+	// those a worker has not picked up yet.
+	admitted atomic.Int64
+
+	trackedMemory func() int64
+}
+
+// NewProcessor starts a Processor with the given config.
+func NewProcessor(config ProcessorConfig) *Processor {
+	if config.MaxConcurrent <= 0 {
+		config.MaxConcurrent = runtime.NumCPU()
+	}
+	if config.MemoryPollInterval <= 0 {
+		config.MemoryPollInterval = defaultMemoryPollInterval
+	}
+
+	p := &Processor{
+		config:        config,
+		slots:         make(chan struct{}, config.MaxConcurrent),
+		tasks:         make(chan processorTask),
+		quit:          make(chan struct{}),
+		trackedMemory: readTrackedMemory,
+	}
+	p.workers.Add(config.MaxConcurrent)
+	for i := 0; i < config.MaxConcurrent; i++ {
+		go p.work()
+	}
+	return p
+}
+
+func readTrackedMemory() int64 {
+	var stats MemoryStats
+	ReadVipsMemStats(&stats)
+	return stats.Mem
+}
+
+// Submit runs job on the processor and returns its error. It blocks until
+// the job is admitted and has finished, or returns ErrProcessorBusy at
+// once when the queue is full. If ctx ends while the job is queued, Submit
+// returns ctx.Err() and the job does not run; once started, the job runs
+// to completion and should watch ctx itself.
+func (p *Processor) Submit(ctx context.Context, job Job) error {
+	p.mu.Lock()
+	if p.closed {
+		p.mu.Unlock()
+		return ErrProcessorClosed
+	}
+	p.inflight.Add(1)
+	p.mu.Unlock()
+	defer p.inflight.Done()
+
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+
+	start := time.Now()
+	if err := p.admit(ctx); err != nil {
+		return err
+	}
+	p.queueTime.Add(int64(time.Since(start)))
+
+	task := processorTask{ctx: ctx, job: job, done: make(chan error, 1)}
+	p.tasks <- task
+	return <-task.done
+}
+
+// admit waits for a slot and for tracked memory to fall under the limit.
+// On success the caller holds a slot, which the worker releases.
+func (p *Processor) admit(ctx context.Context) error {
+	select {
+	case p.slots <- struct{}{}:
+	default:
+		if p.config.QueueDepth < 0 {
+			p.rejected.Add(1)
+			return ErrProcessorBusy
+		}
+		if n := p.queued.Add(1); p.config.QueueDepth > 0 && n > int64(p.config.QueueDepth) {
+			p.queued.Add(-1)
+			p.rejected.Add(1)
+			return ErrProcessorBusy
+		}
+		select {
+		case p.slots <- struct{}{}:
+			p.queued.Add(-1)
+		case <-ctx.Done():
+			p.queued.Add(-1)
+			p.canceled.Add(1)
+			return ctx.Err()
+		case <-p.quit:
+			p.queued.Add(-1)
+			return ErrProcessorClosed
+		}
+	}
+
+	if p.reserveMemory() {
+		return nil
+	}
+
+	p.memoryWaits.Add(1)
+	p.queued.Add(1)
+	defer p.queued.Add(-1)
+	ticker := time.NewTicker(p.config.MemoryPollInterval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ticker.C:
+			if p.reserveMemory() {
+				return nil
+			}
+		case <-ctx.Done():
+			<-p.slots
+			p.canceled.Add(1)
+			return ctx.Err()
+		case <-p.quit:
+			<-p.slots
+			return ErrProcessorClosed
+		}
+	}
+}
+
+// reserveMemory admits a job that holds a slot if tracked memory is under
+// the limit, or if no other job is admitted. The check and the count are
+// one step, so a burst of Submit calls cannot all see an idle processor.
+func (p *Processor) reserveMemory() bool {
+	switch {
+	case p.config.MaxTrackedMemory <= 0:
+	case p.admitted.CompareAndSwap(0, 1):
+		return true
+	case p.trackedMemory() >= p.config.MaxTrackedMemory:
+		return false
+	}
+	p.admitted.Add(1)
+	return true
+}
+
+func (p *Processor) work() {
+	runtime.LockOSThread()
+	// The goroutine exits while locked, so the runtime retires its thread
+	// after libvips has released its per-thread state.
+	defer p.workers.Done()
+	defer ShutdownThread()
+
+	for task := range p.tasks {
+		p.running.Add(1)
+		err := runJob(task.ctx, task.job)
+		p.running.Add(-1)
+		p.admitted.Add(-1)
+		<-p.slots
+
+		p.completed.Add(1)
+		if err != nil {
+			p.failed.Add(1)
+		}
+		task.done <- err
+	}
+}
+
+func runJob(ctx context.Context, job Job) (err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			err = fmt.Errorf("processor job panicked: %v", r)
+		}
+	}()
+	return job(ctx)
+}
+
+// ReadStats returns the counters of the processor.
+func (p *Processor) ReadStats(stats *ProcessorStats) {
+	stats.Queued = p.queued.Load()
+	stats.Running = p.running.Load()
+	stats.Completed = p.completed.Load()
+	stats.Failed = p.failed.Load()
+	stats.Rejected = p.rejected.Load()
+	stats.Canceled = p.canceled.Load()
+	stats.MemoryWaits = p.memoryWaits.Load()
+	stats.QueueTime = time.Duration(p.queueTime.Load())
+}
+
+// Close stops accepting jobs, fails queued jobs with ErrProcessorClosed,
+// waits for running jobs and stops the workers.
+func (p *Processor) Close() {
+	p.once.Do(func() {
+		p.mu.Lock()
+		p.closed = true
+		p.mu.Unlock()
+
+		close(p.quit)
+		p.inflight.Wait()
+		close(p.tasks)
+		p.workers.Wait()
+	})
+}
@@ -0,0 +1,253 @@
+package vips
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestProcessor_Submit(t *testing.T) {
+	require.NoError(t, Startup(nil))
+
+	p := NewProcessor(ProcessorConfig{MaxConcurrent: 2})
+	defer p.Close()
+
+	var current, peak atomic.Int64
+	var wg sync.WaitGroup
+	for i := 0; i < 8; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			err := p.Submit(context.Background(), func(ctx context.Context) error {
+				n := current.Add(1)
+				defer current.Add(-1)
+				for {
+					m := peak.Load()
+					if n <= m || peak.CompareAndSwap(m, n) {
+						break
+					}
+				}
+
+				image, err := NewImageFromFile(resources + "png-24bit.png")
+				if err != nil {
+					return err
+				}
+				defer image.Close()
+				if err := image.Resize(0.25, KernelLanczos3); err != nil {
+					return err
+				}
+				_, _, err = image.ExportPng(NewPngExportParams())
+				return err
+			})
+			assert.NoError(t, err)
+		}()
+	}
+	wg.Wait()
+
+	assert.LessOrEqual(t, peak.Load(), int64(2))
+
+	var stats ProcessorStats
+	p.ReadStats(&stats)
+	assert.Equal(t, int64(8), stats.Completed)
+	assert.Equal(t, int64(0), stats.Failed)
+	assert.Equal(t, int64(0), stats.Queued)
+	assert.Equal(t, int64(0), stats.Running)
+}
+
+func TestProcessor_QueueFull(t *testing.T) {
+	p := NewProcessor(ProcessorConfig{MaxConcurrent: 1, QueueDepth: 1})
+	defer p.Close()
+
+	release, started := make(chan struct{}), make(chan struct{})
+	go p.Submit(context.Background(), func(ctx context.Context) error {
+		close(started)
+		<-release
+		return nil
+	})
+	<-started
+
+	ctx, cancel := context.WithCancel(context.Background())
+	queued := make(chan error)
+	go func() {
+		queued <- p.Submit(ctx, func(ctx context.Context) error {
+			t.Error("canceled job ran")
+			return nil
+		})
+	}()
+	require.Eventually(t, func() bool {
+		var stats ProcessorStats
+		p.ReadStats(&stats)
+		return stats.Queued == 1
+	}, time.Second, time.Millisecond)
+
+	err := p.Submit(context.Background(), func(ctx context.Context) error { return nil })
+	assert.True(t, errors.Is(err, ErrProcessorBusy))
+
+	cancel()
+	assert.True(t, errors.Is(<-queued, context.Canceled))
+	close(release)
+
+	var stats ProcessorStats
+	p.ReadStats(&stats)
+	assert.Equal(t, int64(1), stats.Rejected)
+	assert.Equal(t, int64(1), stats.Canceled)
+}
+
+func TestProcessor_NoQueue(t *testing.T) {
+	p := NewProcessor(ProcessorConfig{MaxConcurrent: 1, QueueDepth: -1})
+	defer p.Close()
+
+	release, started := make(chan struct{}), make(chan struct{})
+	go p.Submit(context.Background(), func(ctx context.Context) error {
+		close(started)
+		<-release
+		return nil
+	})
+	<-started
+
+	err := p.Submit(context.Background(), func(ctx context.Context) error { return nil })
+	assert.True(t, errors.Is(err, ErrProcessorBusy))
+	close(release)
+}
+
+func TestProcessor_MaxTrackedMemory(t *testing.T) {
+	p := NewProcessor(ProcessorConfig{
+		MaxConcurrent:      4,
+		MaxTrackedMemory:   100,
+		MemoryPollInterval: time.Millisecond,
+	})
+	defer p.Close()
+
+	var memory atomic.Int64
+	p.trackedMemory = memory.Load
+
+	release, started := make(chan struct{}), make(chan struct{})
+	go p.Submit(context.Background(), func(ctx context.Context) error {
+		memory.Store(200)
+		close(started)
+		<-release
+		memory.Store(0)
+		return nil
+	})
+	<-started
+
+	ran := make(chan struct{})
+	go p.Submit(context.Background(), func(ctx context.Context) error {
+		close(ran)
+		return nil
+	})
+
+	select {
+	case <-ran:
+		t.Fatal("job admitted over the memory limit")
+	case <-time.After(50 * time.Millisecond):
+	}
+
+	var stats ProcessorStats
+	p.ReadStats(&stats)
+	assert.Equal(t, int64(1), stats.MemoryWaits)
+	assert.Equal(t, int64(1), stats.Queued)
+
+	close(release)
+	<-ran
+}
+
+func TestProcessor_MaxTrackedMemoryBurst(t *testing.T) {
+	const burst = 8
+	p := NewProcessor(ProcessorConfig{
+		MaxConcurrent:      burst,
+		MaxTrackedMemory:   100,
+		MemoryPollInterval: time.Millisecond,
+	})
+	defer p.Close()
+
+	var memory atomic.Int64
+	memory.Store(200)
+	p.trackedMemory = memory.Load
+
+	// Admit a burst without handing the jobs to workers, as when several
+	// Submit calls win slots before any of them starts running.
+	ctx, cancel := context.WithCancel(context.Background())
+	admitted := make(chan struct{}, burst)
+	var wg sync.WaitGroup
+	wg.Add(burst)
+	for i := 0; i < burst; i++ {
+		go func() {
+			defer wg.Done()
+			if p.admit(ctx) == nil {
+				admitted <- struct{}{}
+			}
+		}()
+	}
+
+	time.Sleep(50 * time.Millisecond)
+	assert.Len(t, admitted, 1, "only one job may get in over the memory limit")
+	var stats ProcessorStats
+	p.ReadStats(&stats)
+	assert.Equal(t, int64(burst-1), stats.MemoryWaits)
+
+	cancel()
+	wg.Wait()
+	assert.Len(t, admitted, 1)
+
+	// Give back the admitted job's slot, as its worker would.
+	p.admitted.Add(-1)
+	<-p.slots
+}
+
+func TestProcessor_Panic(t *testing.T) {
+	p := NewProcessor(ProcessorConfig{MaxConcurrent: 1})
+	defer p.Close()
+
+	err := p.Submit(context.Background(), func(ctx context.Context) error { panic("boom") })
+	assert.Error(t, err)
+
+	// The worker survives the panic.
+	assert.NoError(t, p.Submit(context.Background(), func(ctx context.Context) error { return nil }))
+
+	var stats ProcessorStats
+	p.ReadStats(&stats)
+	assert.Equal(t, int64(1), stats.Failed)
+	assert.Equal(t, int64(2), stats.Completed)
+}
+
+func TestProcessor_Close(t *testing.T) {
+	p := NewProcessor(ProcessorConfig{MaxConcurrent: 1})
+
+	release, started := make(chan struct{}), make(chan struct{})
+	go p.Submit(context.Background(), func(ctx context.Context) error {
+		close(started)
+		<-release
+		return nil
+	})
+	<-started
+
+	queued := make(chan error)
+	go func() {
+		queued <- p.Submit(context.Background(), func(ctx context.Context) error { return nil })
+	}()
+	require.Eventually(t, func() bool {
+		var stats ProcessorStats
+		p.ReadStats(&stats)
+		return stats.Queued == 1
+	}, time.Second, time.Millisecond)
+
+	closed := make(chan struct{})
+	go func() {
+		p.Close()
+		close(closed)
+	}()
+	assert.True(t, errors.Is(<-queued, ErrProcessorClosed))
+
+	close(release)
+	<-closed
+
+	err := p.Submit(context.Background(), func(ctx context.Context) error { return nil })
+	assert.True(t, errors.Is(err, ErrProcessorClosed))
+}
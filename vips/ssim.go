@@ -0,0 +1,56 @@
+package vips
+
+// ssimWindow and ssimStride define the sliding window the local SSIM is
+// computed over, following the 8x8 block variant commonly used for image
+// codec tuning.
+const (
+	ssimWindow = 8
+	ssimStride = 4
+)
+
+// ssim returns the mean SSIM of two 8-bit single band images of the same
+// size, laid out row by row without padding.
+func ssim(a, b []byte, width, height int) float64 {
+	const (
+		c1 = (0.01 * 255) * (0.01 * 255)
+		c2 = (0.03 * 255) * (0.03 * 255)
+	)
+
+	winW := min(ssimWindow, width)
+	winH := min(ssimWindow, height)
+
+	var total float64
+	var count int
+	for y := 0; y+winH <= height; y += ssimStride {
+		for x := 0; x+winW <= width; x += ssimStride {
+			var sumA, sumB, sumAA, sumBB, sumAB float64
+			for j := y; j < y+winH; j++ {
+				row := j * width
+				for i := x; i < x+winW; i++ {
+					va := float64(a[row+i])
+					vb := float64(b[row+i])
+					sumA += va
+					sumB += vb
+					sumAA += va * va
+					sumBB += vb * vb
+					sumAB += va * vb
+				}
+			}
+			n := float64(winW * winH)
+			meanA := sumA / n
+			meanB := sumB / n
+			varA := sumAA/n - meanA*meanA
+			varB := sumBB/n - meanB*meanB
+			cov := sumAB/n - meanA*meanB
+
+			total += ((2*meanA*meanB + c1) * (2*cov + c2)) /
+				((meanA*meanA + meanB*meanB + c1) * (varA + varB + c2))
+			count++
+		}
+	}
+
+	if count == 0 {
+		return 1
+	}
+	return total / float64(count)
+}
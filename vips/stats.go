@@ -1,56 +1,305 @@
 package vips
 
-import "sync"
+// #include <vips/vips.h>
+import "C"
+
+import (
+	"context"
+	"expvar"
+	"sort"
+	"sync"
+	"sync/atomic"
+	"time"
+)
 
 // RuntimeStats is a data structure to house a map of govips operation counts
 type RuntimeStats struct {
 	OperationCounts map[string]int64
 }
 
+// ReadRuntimeStats returns operation counts for govips
+func ReadRuntimeStats(stats *RuntimeStats) {
+	stats.OperationCounts = make(map[string]int64)
+	for name, m := range *operationMetrics.Load() {
+		stats.OperationCounts[name] = m.calls.Load()
+	}
+}
+
+// durationBuckets are the upper bounds of the operation time histograms.
+var durationBuckets = [...]time.Duration{
+	100 * time.Microsecond,
+	250 * time.Microsecond,
+	500 * time.Microsecond,
+	time.Millisecond,
+	2500 * time.Microsecond,
+	5 * time.Millisecond,
+	10 * time.Millisecond,
+	25 * time.Millisecond,
+	50 * time.Millisecond,
+	100 * time.Millisecond,
+	250 * time.Millisecond,
+	500 * time.Millisecond,
+	time.Second,
+	2500 * time.Millisecond,
+	5 * time.Second,
+	10 * time.Second,
+}
+
+// opMetrics holds the counters of one operation. The histogram buckets
+// are not cumulative; ReadMetrics accumulates them.
+type opMetrics struct {
+	calls    atomic.Int64
+	errors   atomic.Int64
+	nanos    atomic.Int64
+	bytesIn  atomic.Int64
+	bytesOut atomic.Int64
+	buckets  [len(durationBuckets)]atomic.Int64
+}
+
+func (m *opMetrics) record(d time.Duration, bytesIn, bytesOut int64, failed bool) {
+	m.calls.Add(1)
+	m.nanos.Add(int64(d))
+	if failed {
+		m.errors.Add(1)
+	}
+	if bytesIn > 0 {
+		m.bytesIn.Add(bytesIn)
+	}
+	if bytesOut > 0 {
+		m.bytesOut.Add(bytesOut)
+	}
+	for i, bound := range durationBuckets {
+		if d <= bound {
+			m.buckets[i].Add(1)
+			break
+		}
+	}
+}
+
 var (
-	operationCounter chan string
-	runtimeStats     *RuntimeStats
-	statLock         sync.RWMutex
+	metricsEnabled atomic.Bool
+
+	// operationMetrics is copied on write, so that recording an operation
+	// only needs an atomic load and atomic adds. metricsLock serializes
+	// writers.
+	operationMetrics atomic.Pointer[map[string]*opMetrics]
+	metricsLock      sync.Mutex
+
+	metricsObserver atomic.Pointer[observerHolder]
 )
 
-func incOpCounter(op string) {
-	if operationCounter != nil {
-		operationCounter <- op
-	}
-}
-
-func collectStats() chan struct{} {
-	operationCounter = make(chan string, 100)
-	done := make(chan struct{})
-	exit := false
-	go func() {
-		for !exit {
-			select {
-			case op := <-operationCounter:
-				statLock.Lock()
-				runtimeStats.OperationCounts[op] = runtimeStats.OperationCounts[op] + 1
-				statLock.Unlock()
-			case <-done:
-				exit = true
-				break
-			}
+type observerHolder struct {
+	observer MetricsObserver
+}
+
+func init() {
+	operationMetrics.Store(&map[string]*opMetrics{})
+}
+
+func metricsFor(name string) *opMetrics {
+	if m, ok := (*operationMetrics.Load())[name]; ok {
+		return m
+	}
+
+	metricsLock.Lock()
+	defer metricsLock.Unlock()
+	current := *operationMetrics.Load()
+	if m, ok := current[name]; ok {
+		return m
+	}
+	next := make(map[string]*opMetrics, len(current)+1)
+	for k, v := range current {
+		next[k] = v
+	}
+	m := &opMetrics{}
+	next[name] = m
+	operationMetrics.Store(&next)
+	return m
+}
+
+// MetricsObserver receives every libvips operation run by govips, for
+// bridging to a metrics system such as Prometheus or OpenMetrics. It is
+// called synchronously on the goroutine that ran the operation, so it must
+// be fast and safe for concurrent use. bytesIn and bytesOut are the
+// encoded sizes for loads and saves and zero otherwise.
+type MetricsObserver interface {
+	ObserveOperation(name string, duration time.Duration, bytesIn, bytesOut int64, err error)
+}
+
+// SetMetricsObserver sets the observer of operations, or removes it when
+// o is nil. The observer is called whether or not Config.CollectStats is
+// set.
+func SetMetricsObserver(o MetricsObserver) {
+	if o == nil {
+		metricsObserver.Store(nil)
+		return
+	}
+	metricsObserver.Store(&observerHolder{observer: o})
+}
+
+// HistogramBucket is a bucket of a cumulative histogram: Count operations
+// took at most UpperBound.
+type HistogramBucket struct {
+	UpperBound time.Duration `json:"le_ns"`
+	Count      int64         `json:"count"`
+}
+
+// OperationMetrics are the metrics of one operation. Most libvips
+// operations are lazy: they only build the pipeline, and the pixels are
+// computed by the save (or other sink) at the end, where the time shows.
+type OperationMetrics struct {
+	Name      string        `json:"name"`
+	Calls     int64         `json:"calls"`
+	Errors    int64         `json:"errors"`
+	TotalTime time.Duration `json:"total_time_ns"`
+	// BytesIn and BytesOut are the encoded bytes read by loads and
+	// written by saves.
+	BytesIn  int64 `json:"bytes_in"`
+	BytesOut int64 `json:"bytes_out"`
+	// Buckets is the cumulative histogram of wall times. Calls is the
+	// count of the implicit +Inf bucket.
+	Buckets []HistogramBucket `json:"buckets"`
+}
+
+// Metrics is a snapshot of the govips metrics, as returned by
+// ReadMetrics.
+type Metrics struct {
+	// Operations are sorted by name.
+	Operations []OperationMetrics `json:"operations"`
+	// TrackedMemory and TrackedMemoryHighWater are the current and peak
+	// memory allocated by libvips, as in MemoryStats.
+	TrackedMemory          int64 `json:"tracked_memory"`
+	TrackedMemoryHighWater int64 `json:"tracked_memory_high_water"`
+}
+
+// ReadMetrics returns the per-operation metrics collected since startup
+// or the last ResetMetrics. Operations are only recorded when
+// Config.CollectStats is set.
+func ReadMetrics(m *Metrics) {
+	ops := *operationMetrics.Load()
+	m.Operations = make([]OperationMetrics, 0, len(ops))
+	for name, om := range ops {
+		out := OperationMetrics{
+			Name:      name,
+			Calls:     om.calls.Load(),
+			Errors:    om.errors.Load(),
+			TotalTime: time.Duration(om.nanos.Load()),
+			BytesIn:   om.bytesIn.Load(),
+			BytesOut:  om.bytesOut.Load(),
+			Buckets:   make([]HistogramBucket, len(durationBuckets)),
+		}
+		var cumulative int64
+		for i, bound := range durationBuckets {
+			cumulative += om.buckets[i].Load()
+			out.Buckets[i] = HistogramBucket{UpperBound: bound, Count: cumulative}
 		}
-	}()
-	return done
+		m.Operations = append(m.Operations, out)
+	}
+	sort.Slice(m.Operations, func(i, j int) bool {
+		return m.Operations[i].Name < m.Operations[j].Name
+	})
+
+	var mem MemoryStats
+	ReadVipsMemStats(&mem)
+	m.TrackedMemory = mem.Mem
+	m.TrackedMemoryHighWater = mem.MemHigh
 }
 
-// ReadRuntimeStats returns operation counts for govips
-func ReadRuntimeStats(stats *RuntimeStats) {
-	statLock.RLock()
-	defer statLock.RUnlock()
-	stats.OperationCounts = make(map[string]int64)
-	for k, v := range runtimeStats.OperationCounts {
-		stats.OperationCounts[k] = v
+// ResetMetrics clears the per-operation metrics.
+func ResetMetrics() {
+	metricsLock.Lock()
+	defer metricsLock.Unlock()
+	operationMetrics.Store(&map[string]*opMetrics{})
+}
+
+// PublishExpvar publishes the metrics under name in the expvar registry,
+// served at /debug/vars by the expvar package. Like expvar.Publish, it
+// panics if name is already in use.
+func PublishExpvar(name string) {
+	expvar.Publish(name, expvar.Func(func() interface{} {
+		var m Metrics
+		ReadMetrics(&m)
+		return m
+	}))
+}
+
+// opSpan times and traces one operation. It is a value type so that
+// recording allocates nothing:
+//
+//	op := startOp(ctx, "resize", in)
+//	defer op.end()
+//	...
+//	if ret != 0 {
+//		return nil, op.fail(handleImageError(out))
+//	}
+type opSpan struct {
+	name   string
+	start  time.Time
+	attrs  OperationAttributes
+	err    error
+	finish func(OperationAttributes, error)
+}
+
+// startOp starts the operation name on the image in, which may be nil
+// for operations without an input image. The span is inert when metrics,
+// the observer and the tracer are all disabled.
+func startOp(ctx context.Context, name string, in *C.VipsImage) opSpan {
+	tracer := globalTracer.Load()
+	if tracer == nil && !metricsEnabled.Load() && metricsObserver.Load() == nil {
+		return opSpan{}
+	}
+	s := opSpan{name: name, start: time.Now()}
+	if in != nil {
+		s.attrs.Width = int(in.Xsize)
+		s.attrs.Height = int(in.Ysize)
+		s.attrs.Bands = int(in.Bands)
+	}
+	if tracer != nil {
+		s.finish = tracer.tracer.StartOperation(ctx, name, s.attrs)
 	}
+	return s
 }
 
-func init() {
-	runtimeStats = &RuntimeStats{
-		OperationCounts: make(map[string]int64),
+// fail records err as the outcome of the operation and returns it.
+func (s *opSpan) fail(err error) error {
+	s.err = err
+	return err
+}
+
+// loaded records the format, dimensions and encoded size of a load.
+func (s *opSpan) loaded(out *C.VipsImage, format ImageType, bytesIn int64) {
+	if s.name == "" {
+		return
+	}
+	s.attrs.Format = format
+	s.attrs.BytesIn = bytesIn
+	if out != nil {
+		s.attrs.Width = int(out.Xsize)
+		s.attrs.Height = int(out.Ysize)
+		s.attrs.Bands = int(out.Bands)
+	}
+}
+
+// saved records the outcome of a buffer save and passes it through.
+func (s *opSpan) saved(buf []byte, err error) ([]byte, error) {
+	s.attrs.BytesOut = int64(len(buf))
+	s.err = err
+	return buf, err
+}
+
+// end records the operation.
+func (s *opSpan) end() {
+	if s.name == "" {
+		return
+	}
+	d := time.Since(s.start)
+	if metricsEnabled.Load() {
+		metricsFor(s.name).record(d, s.attrs.BytesIn, s.attrs.BytesOut, s.err != nil)
+	}
+	if h := metricsObserver.Load(); h != nil {
+		h.observer.ObserveOperation(s.name, d, s.attrs.BytesIn, s.attrs.BytesOut, s.err)
+	}
+	if s.finish != nil {
+		s.finish(s.attrs, s.err)
 	}
 }
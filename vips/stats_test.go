@@ -0,0 +1,229 @@
+package vips
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"expvar"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func enableMetrics(t *testing.T) {
+	t.Helper()
+	was := metricsEnabled.Load()
+	metricsEnabled.Store(true)
+	ResetMetrics()
+	t.Cleanup(func() {
+		metricsEnabled.Store(was)
+		ResetMetrics()
+	})
+}
+
+func findOperation(m *Metrics, name string) (OperationMetrics, bool) {
+	for _, op := range m.Operations {
+		if op.Name == name {
+			return op, true
+		}
+	}
+	return OperationMetrics{}, false
+}
+
+func TestMetrics_LoadAndSave(t *testing.T) {
+	require.NoError(t, Startup(nil))
+	enableMetrics(t)
+
+	buf := mustReadFile(t, resources+"jpg-24bit.jpg")
+	image, err := NewImageFromBuffer(buf)
+	require.NoError(t, err)
+	defer image.Close()
+
+	require.NoError(t, image.Resize(0.5, KernelLanczos3))
+	out, _, err := image.ExportJpeg(NewJpegExportParams())
+	require.NoError(t, err)
+
+	var m Metrics
+	ReadMetrics(&m)
+
+	load, ok := findOperation(&m, "load_buffer")
+	require.True(t, ok)
+	assert.Equal(t, int64(1), load.Calls)
+	assert.Equal(t, int64(len(buf)), load.BytesIn)
+
+	save, ok := findOperation(&m, "save_jpeg_buffer")
+	require.True(t, ok)
+	assert.Equal(t, int64(1), save.Calls)
+	assert.Equal(t, int64(0), save.Errors)
+	assert.Equal(t, int64(len(out)), save.BytesOut)
+	assert.True(t, save.TotalTime > 0)
+
+	_, ok = findOperation(&m, "resize")
+	assert.True(t, ok)
+
+	assert.True(t, m.TrackedMemoryHighWater >= m.TrackedMemory)
+
+	var stats RuntimeStats
+	ReadRuntimeStats(&stats)
+	assert.Equal(t, int64(1), stats.OperationCounts["save_jpeg_buffer"])
+}
+
+func TestMetrics_Stream(t *testing.T) {
+	require.NoError(t, Startup(nil))
+	enableMetrics(t)
+
+	buf := mustReadFile(t, resources+"png-24bit.png")
+	image, err := LoadImageFromReader(bytes.NewReader(buf), nil)
+	require.NoError(t, err)
+	defer image.Close()
+
+	var w bytes.Buffer
+	require.NoError(t, image.SaveToWriter(&w, ImageTypePNG, nil))
+
+	var m Metrics
+	ReadMetrics(&m)
+
+	load, ok := findOperation(&m, "load_source")
+	require.True(t, ok)
+	assert.Greater(t, load.BytesIn, int64(0))
+	assert.LessOrEqual(t, load.BytesIn, int64(len(buf)))
+
+	save, ok := findOperation(&m, "save_png_target")
+	require.True(t, ok)
+	assert.Equal(t, int64(w.Len()), save.BytesOut)
+}
+
+func TestMetrics_Errors(t *testing.T) {
+	require.NoError(t, Startup(nil))
+	enableMetrics(t)
+
+	_, err := NewImageFromBuffer([]byte("not an image"))
+	require.Error(t, err)
+
+	var m Metrics
+	ReadMetrics(&m)
+	load, ok := findOperation(&m, "load_buffer")
+	require.True(t, ok)
+	assert.Equal(t, int64(1), load.Calls)
+	assert.Equal(t, int64(1), load.Errors)
+}
+
+func TestMetrics_Histogram(t *testing.T) {
+	var m opMetrics
+	m.record(50*time.Microsecond, 0, 0, false)
+	m.record(3*time.Millisecond, 10, 0, false)
+	m.record(time.Minute, 0, 20, true)
+
+	ResetMetrics()
+	defer ResetMetrics()
+	current := map[string]*opMetrics{"test": &m}
+	operationMetrics.Store(&current)
+
+	var out Metrics
+	ReadMetrics(&out)
+	require.Len(t, out.Operations, 1)
+	op := out.Operations[0]
+	assert.Equal(t, int64(3), op.Calls)
+	assert.Equal(t, int64(1), op.Errors)
+	assert.Equal(t, int64(10), op.BytesIn)
+	assert.Equal(t, int64(20), op.BytesOut)
+	assert.Equal(t, time.Minute+3*time.Millisecond+50*time.Microsecond, op.TotalTime)
+
+	require.Len(t, op.Buckets, len(durationBuckets))
+	for _, b := range op.Buckets {
+		switch {
+		case b.UpperBound < 3*time.Millisecond:
+			assert.Equal(t, int64(1), b.Count, "le %v", b.UpperBound)
+		default:
+			// The minute-long call only counts in the implicit +Inf bucket.
+			assert.Equal(t, int64(2), b.Count, "le %v", b.UpperBound)
+		}
+	}
+}
+
+func TestMetrics_Concurrent(t *testing.T) {
+	enableMetrics(t)
+
+	var wg sync.WaitGroup
+	for i := 0; i < 8; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for j := 0; j < 1000; j++ {
+				op := startOp(context.Background(), "concurrent", nil)
+				op.end()
+			}
+		}()
+	}
+	wg.Wait()
+
+	var stats RuntimeStats
+	ReadRuntimeStats(&stats)
+	assert.Equal(t, int64(8000), stats.OperationCounts["concurrent"])
+}
+
+type recordingObserver struct {
+	mu    sync.Mutex
+	names []string
+	errs  int
+}
+
+func (o *recordingObserver) ObserveOperation(name string, _ time.Duration, _, _ int64, err error) {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+	o.names = append(o.names, name)
+	if err != nil {
+		o.errs++
+	}
+}
+
+func TestSetMetricsObserver(t *testing.T) {
+	require.NoError(t, Startup(nil))
+
+	o := &recordingObserver{}
+	SetMetricsObserver(o)
+	defer SetMetricsObserver(nil)
+
+	image, err := NewImageFromFile(resources + "png-24bit.png")
+	require.NoError(t, err)
+	defer image.Close()
+	_, err = NewImageFromBuffer([]byte("not an image"))
+	require.Error(t, err)
+
+	o.mu.Lock()
+	defer o.mu.Unlock()
+	assert.Contains(t, o.names, "load_file")
+	assert.Equal(t, 1, o.errs)
+}
+
+func TestStartOp_Inert(t *testing.T) {
+	was := metricsEnabled.Load()
+	metricsEnabled.Store(false)
+	defer metricsEnabled.Store(was)
+
+	allocs := testing.AllocsPerRun(100, func() {
+		op := startOp(context.Background(), "inert", nil)
+		op.end()
+	})
+	assert.Equal(t, float64(0), allocs)
+}
+
+func TestPublishExpvar(t *testing.T) {
+	require.NoError(t, Startup(nil))
+	enableMetrics(t)
+
+	op := startOp(context.Background(), "expvar_test", nil)
+	op.end()
+
+	PublishExpvar("govips_test")
+	v := expvar.Get("govips_test")
+	require.NotNil(t, v)
+
+	var m Metrics
+	require.NoError(t, json.Unmarshal([]byte(v.String()), &m))
+	_, ok := findOperation(&m, "expvar_test")
+	assert.True(t, ok)
+}
@@ -2,7 +2,7 @@
 
 #include <string.h>
 
-// Option setters shared with the buffer path, defined in foreign.c.
+// Load option setters shared with the buffer path, defined in foreign.c.
 // Reusing them keeps streaming output identical to the buffer output.
 extern int set_jpegload_options(VipsOperation *operation, LoadParams *params);
 extern int set_pngload_options(VipsOperation *operation, LoadParams *params);
@@ -16,13 +16,6 @@ extern int set_jp2kload_options(VipsOperation *operation, LoadParams *params);
 extern int set_jxlload_options(VipsOperation *operation, LoadParams *params);
 extern int set_magickload_options(VipsOperation *operation, LoadParams *params);
 
-extern int set_jpegsave_options(VipsOperation *operation, SaveParams *params);
-extern int set_pngsave_options(VipsOperation *operation, SaveParams *params);
-extern int set_webpsave_options(VipsOperation *operation, SaveParams *params);
-extern int set_heifsave_options(VipsOperation *operation, SaveParams *params);
-extern int set_tiffsave_options(VipsOperation *operation, SaveParams *params);
-extern int set_gifsave_options(VipsOperation *operation, SaveParams *params);
-
 // Trampolines: extract the registry handle from signal user_data and
 // forward to the exported Go callbacks. Buffers are owned by libvips and
 // only valid for the duration of the call.
@@ -185,6 +178,12 @@ int load_from_source(VipsSourceCustom *source, LoadParams *params) {
                     VIPS_FAIL_ON_TRUNCATED, NULL);
   }
 
+  // Explicit loader options go last, so they win over the above.
+  if (apply_load_options(operation, operationName, params)) {
+    g_object_unref(operation);
+    return 1;
+  }
+
   // Build uncached: the cache key includes the unique source object, so
   // a hit is impossible — caching would only pin the source and the
   // lazy image in the operation cache past their natural lifetime.
@@ -236,12 +235,10 @@ int write_image_to_disc(VipsImage *in, const char *path, VipsImage **out) {
   return 0;
 }
 
-typedef int (*SetSaveOptionsFn)(VipsOperation *operation, SaveParams *params);
-
-static int save_target(const char *operationName, SaveParams *params,
-                       VipsTargetCustom *target,
-                       SetSaveOptionsFn setSaveOptions) {
+int save_to_target(SaveParams *params, VipsTargetCustom *target) {
+  char *operationName = g_strdup_printf("%s_target", params->saver);
   VipsOperation *operation = vips_operation_new(operationName);
+  g_free(operationName);
   if (!operation) {
     return 1;
   }
@@ -252,7 +249,7 @@ static int save_target(const char *operationName, SaveParams *params,
     return 1;
   }
 
-  if (setSaveOptions(operation, params)) {
+  if (params->setOptions(operation, params->options)) {
     g_object_unref(operation);
     return 1;
   }
@@ -271,28 +268,8 @@ static int save_target(const char *operationName, SaveParams *params,
   return 0;
 }
 
-int save_jpeg_to_target(SaveParams *params, VipsTargetCustom *target) {
-  return save_target("jpegsave_target", params, target, set_jpegsave_options);
-}
-
-int save_png_to_target(SaveParams *params, VipsTargetCustom *target) {
-  return save_target("pngsave_target", params, target, set_pngsave_options);
-}
-
-int save_webp_to_target(SaveParams *params, VipsTargetCustom *target) {
-  return save_target("webpsave_target", params, target, set_webpsave_options);
-}
-
-int save_heif_to_target(SaveParams *params, VipsTargetCustom *target) {
-  return save_target("heifsave_target", params, target, set_heifsave_options);
-}
-
-// No save_tiff_to_target: libtiff requires seekable output, so the Go
-// side always encodes TIFF through the buffer path.
-
-int save_gif_to_target(SaveParams *params, VipsTargetCustom *target) {
-  return save_target("gifsave_target", params, target, set_gifsave_options);
-}
+// No TIFF target: libtiff requires seekable output, so the Go side
+// always encodes TIFF through the buffer path.
 
 void clear_source(VipsSourceCustom **source) {
   if (source && *source) {
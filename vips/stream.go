@@ -11,6 +11,7 @@ package vips
 import "C"
 
 import (
+	"context"
 	"errors"
 	"fmt"
 	"io"
@@ -36,6 +37,9 @@ type sourceEntry struct {
 	// cached for clamping past-EOF seek targets. Guarded by mu.
 	size      int64
 	sizeKnown bool
+
+	// read counts the bytes read from reader, for metrics. Guarded by mu.
+	read int64
 }
 
 // sourceSizeLocked resolves and caches the source length without
@@ -64,6 +68,10 @@ type targetEntry struct {
 	writer  io.Writer
 	mu      sync.Mutex
 	lastErr error
+
+	// written counts the bytes written to writer, for metrics. Guarded
+	// by mu.
+	written int64
 }
 
 func (e *sourceEntry) takeErr() error {
@@ -74,6 +82,18 @@ func (e *sourceEntry) takeErr() error {
 	return err
 }
 
+func (e *sourceEntry) bytesRead() int64 {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	return e.read
+}
+
+func (e *targetEntry) bytesWritten() int64 {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	return e.written
+}
+
 func (e *targetEntry) takeErr() error {
 	e.mu.Lock()
 	defer e.mu.Unlock()
@@ -210,6 +230,7 @@ func sourceRead(handle int, buf []byte) int64 {
 		n, err := entry.reader.Read(buf)
 		if n > 0 {
 			// A non-EOF error alongside n>0 will surface on the next call.
+			entry.read += int64(n)
 			return int64(n)
 		}
 		if errors.Is(err, io.EOF) {
@@ -302,6 +323,7 @@ func targetWrite(handle int, buf []byte) int64 {
 	defer entry.mu.Unlock()
 
 	n, err := entry.writer.Write(buf)
+	entry.written += int64(n)
 	if err != nil {
 		entry.lastErr = err
 		return -1
@@ -499,8 +521,17 @@ func sequentialAccess(params *ImportParams) bool {
 // errors deterministically, but some recoverable decode warnings that
 // the buffer path would reject are tolerated.
 //
+// As with LoadImageFromBuffer, the header is checked against the import
+// limits (see ImportLimits) before any pixel is decoded or materialized.
+//
 // params may be nil for default import settings.
 func LoadImageFromReader(r io.Reader, params *ImportParams) (*ImageRef, error) {
+	return LoadImageFromReaderContext(context.Background(), r, params)
+}
+
+// LoadImageFromReaderContext is like LoadImageFromReader, tracing the load
+// and the operations on the image under ctx (see Tracer).
+func LoadImageFromReaderContext(ctx context.Context, r io.Reader, params *ImportParams) (*ImageRef, error) {
 	if r == nil {
 		return nil, errors.New("reader is nil")
 	}
@@ -511,15 +542,59 @@ func LoadImageFromReader(r io.Reader, params *ImportParams) (*ImageRef, error) {
 		params = NewImportParams()
 	}
 
-	incOpCounter("load_source")
+	op := startOp(ctx, "load_source", nil)
+	defer op.end()
+
+	lazy, src, format, originalFormat, err := loadFromSource(r, params)
+	if err != nil {
+		return nil, op.fail(err)
+	}
+	if err := checkImportLimits(lazy, importLimits(params)); err != nil {
+		clearImage(lazy)
+		src.release()
+		return nil, op.fail(err)
+	}
+
+	if sequentialAccess(params) {
+		// Only the header has been read so far; the pixels are read, and
+		// not counted here, when the image is consumed.
+		op.loaded(lazy, format, src.entry.bytesRead())
+		ref := newImageRef(lazy, format, originalFormat, nil)
+		ref.streamSource = src
+		ref.ctx = ctx
+		govipsLog("govips", LogLevelDebug, fmt.Sprintf("created sequential imageRef %p from reader", ref))
+		return ref, nil
+	}
+
+	// Default path: materialize now so the source (and the caller's
+	// reader) can be released before returning. Upstream resources
+	// (file handles, HTTP connections) are freed early.
+	out, err := materializeImage(lazy)
+	clearImage(lazy)
+	src.release()
+	op.loaded(out, format, src.entry.bytesRead())
+	if err != nil {
+		return nil, op.fail(wrapStreamError("streaming load", err, src.entry.takeErr()))
+	}
 
+	ref := newImageRef(out, format, originalFormat, nil)
+	ref.ctx = ctx
+	govipsLog("govips", LogLevelDebug, fmt.Sprintf("created imageRef %p from reader", ref))
+	return ref, nil
+}
+
+// loadFromSource opens a lazy image on a streaming source reading r. Only
+// the header has been read when it returns; the caller owns the image and
+// must release the source once the image no longer needs it.
+func loadFromSource(r io.Reader, params *ImportParams) (*C.VipsImage, *streamSourceRef, ImageType, ImageType, error) {
 	handle, entry := registerSource(r)
 
 	source := C.create_source_custom(C.int(handle), C.int(boolToInt(entry.seeker != nil)))
 	if source == nil {
 		deregisterSource(handle)
-		return nil, handleVipsError()
+		return nil, nil, ImageTypeUnknown, ImageTypeUnknown, handleVipsError()
 	}
+	src := &streamSourceRef{handle: handle, entry: entry, source: source}
 
 	// Sniff the signature up front (buffered and rewound, not consumed):
 	// the loader nickname alone cannot express sub-formats — AVIF loads
@@ -528,16 +603,15 @@ func LoadImageFromReader(r io.Reader, params *ImportParams) (*ImageRef, error) {
 	var header [12]byte
 	headerKnown := C.source_sniff_header(source, (*C.uchar)(unsafe.Pointer(&header[0])), C.int(len(header))) == 0
 
-	loadParams := createImportParams(ImageTypeUnknown, params)
+	loadParams, cleanup := createImportParams(ImageTypeUnknown, params)
+	defer cleanup()
 
 	if code := C.load_from_source(source, &loadParams); code != 0 {
 		err := wrapStreamError("streaming load", handleImageError(loadParams.outputImage), entry.takeErr())
-		C.clear_source(&source)
-		deregisterSource(handle)
-		return nil, err
+		src.release()
+		return nil, nil, ImageTypeUnknown, ImageTypeUnknown, err
 	}
 
-	lazy := loadParams.outputImage
 	format := ImageType(loadParams.inputFormat)
 	originalFormat := format
 	if headerKnown && (format == ImageTypeHEIF || format == ImageTypeMagick) {
@@ -548,28 +622,7 @@ func LoadImageFromReader(r io.Reader, params *ImportParams) (*ImageRef, error) {
 			}
 		}
 	}
-
-	if sequentialAccess(params) {
-		ref := newImageRef(lazy, format, originalFormat, nil)
-		ref.streamSource = &streamSourceRef{handle: handle, entry: entry, source: source}
-		govipsLog("govips", LogLevelDebug, fmt.Sprintf("created sequential imageRef %p from reader", ref))
-		return ref, nil
-	}
-
-	// Default path: materialize now so the source (and the caller's
-	// reader) can be released before returning. Upstream resources
-	// (file handles, HTTP connections) are freed early.
-	out, err := materializeImage(lazy)
-	clearImage(lazy)
-	C.clear_source(&source)
-	deregisterSource(handle)
-	if err != nil {
-		return nil, wrapStreamError("streaming load", err, entry.takeErr())
-	}
-
-	ref := newImageRef(out, format, originalFormat, nil)
-	govipsLog("govips", LogLevelDebug, fmt.Sprintf("created imageRef %p from reader", ref))
-	return ref, nil
+	return loadParams.outputImage, src, format, originalFormat, nil
 }
 
 // materialize converts a sequentially stream-loaded image into a
@@ -623,8 +676,8 @@ func (r *ImageRef) materialize() error {
 // (SaveToWriterJpeg, SaveToWriterPng, SaveToWriterWebp, SaveToWriterTiff,
 // SaveToWriterHeif, SaveToWriterGif).
 func (r *ImageRef) SaveToWriter(w io.Writer, format ImageType, params *ExportParams) error {
-	return r.saveToWriter(w, format, func(in *C.VipsImage) (C.struct_SaveParams, func(), error) {
-		return streamSaveParams(in, format, params)
+	return r.saveToWriter(w, format, func() (SaveOptions, error) {
+		return streamSaveOptions(format, params)
 	})
 }
 
@@ -636,8 +689,8 @@ func (r *ImageRef) SaveToWriterJpeg(w io.Writer, params *JpegExportParams) error
 		params = NewJpegExportParams()
 	}
 	p := *params
-	return r.saveToWriter(w, ImageTypeJPEG, func(in *C.VipsImage) (C.struct_SaveParams, func(), error) {
-		return newSaveParamsJPEG(in, p), func() {}, nil
+	return r.saveToWriter(w, ImageTypeJPEG, func() (SaveOptions, error) {
+		return jpegsaveOptions(p), nil
 	})
 }
 
@@ -650,8 +703,8 @@ func (r *ImageRef) SaveToWriterPng(w io.Writer, params *PngExportParams) error {
 		params = NewPngExportParams()
 	}
 	p := *params
-	return r.saveToWriter(w, ImageTypePNG, func(in *C.VipsImage) (C.struct_SaveParams, func(), error) {
-		return newSaveParamsPNG(in, p), func() {}, nil
+	return r.saveToWriter(w, ImageTypePNG, func() (SaveOptions, error) {
+		return pngsaveOptions(p), nil
 	})
 }
 
@@ -664,8 +717,8 @@ func (r *ImageRef) SaveToWriterWebp(w io.Writer, params *WebpExportParams) error
 		params = NewWebpExportParams()
 	}
 	p := *params
-	return r.saveToWriter(w, ImageTypeWEBP, func(in *C.VipsImage) (C.struct_SaveParams, func(), error) {
-		return newSaveParamsWebP(in, p)
+	return r.saveToWriter(w, ImageTypeWEBP, func() (SaveOptions, error) {
+		return webpsaveOptions(p)
 	})
 }
 
@@ -679,8 +732,8 @@ func (r *ImageRef) SaveToWriterTiff(w io.Writer, params *TiffExportParams) error
 		params = NewTiffExportParams()
 	}
 	p := *params
-	return r.saveToWriter(w, ImageTypeTIFF, func(in *C.VipsImage) (C.struct_SaveParams, func(), error) {
-		return newSaveParamsTIFF(in, p), func() {}, nil
+	return r.saveToWriter(w, ImageTypeTIFF, func() (SaveOptions, error) {
+		return tiffsaveOptions(p), nil
 	})
 }
 
@@ -692,8 +745,8 @@ func (r *ImageRef) SaveToWriterHeif(w io.Writer, params *HeifExportParams) error
 		params = NewHeifExportParams()
 	}
 	p := *params
-	return r.saveToWriter(w, ImageTypeHEIF, func(in *C.VipsImage) (C.struct_SaveParams, func(), error) {
-		return newSaveParamsHEIF(in, p), func() {}, nil
+	return r.saveToWriter(w, ImageTypeHEIF, func() (SaveOptions, error) {
+		return heifsaveOptions(p), nil
 	})
 }
 
@@ -706,15 +759,15 @@ func (r *ImageRef) SaveToWriterGif(w io.Writer, params *GifExportParams) error {
 		params = NewGifExportParams()
 	}
 	p := *params
-	return r.saveToWriter(w, ImageTypeGIF, func(in *C.VipsImage) (C.struct_SaveParams, func(), error) {
-		return newSaveParamsGIF(in, p), func() {}, nil
+	return r.saveToWriter(w, ImageTypeGIF, func() (SaveOptions, error) {
+		return gifsaveOptions(p), nil
 	})
 }
 
 // saveToWriter is the shared core of SaveToWriter and its typed
-// variants: it locks the image, builds the C save params via
-// buildParams, and runs the streaming (or, for TIFF, buffered) save.
-func (r *ImageRef) saveToWriter(w io.Writer, format ImageType, buildParams func(*C.VipsImage) (C.struct_SaveParams, func(), error)) error {
+// variants: it locks the image, builds the saver options via
+// buildOptions, and runs the streaming (or, for TIFF, buffered) save.
+func (r *ImageRef) saveToWriter(w io.Writer, format ImageType, buildOptions func() (SaveOptions, error)) error {
 	if w == nil {
 		return errors.New("writer is nil")
 	}
@@ -727,20 +780,21 @@ func (r *ImageRef) saveToWriter(w io.Writer, format ImageType, buildParams func(
 		return errors.New("attempt to save a closed ImageRef")
 	}
 
-	saveParams, cleanup, err := buildParams(r.image)
+	opts, err := buildOptions()
 	if err != nil {
 		return err
 	}
-	defer cleanup()
 
-	incOpCounter("save_" + ImageTypes[format] + "_target")
+	op := startOp(r.Context(), "save_"+ImageTypes[format]+"_target", r.image)
+	op.attrs.Format = format
+	defer op.end()
 
 	if format == ImageTypeTIFF {
 		// libtiff requires a seekable, readable output stream (it
 		// rewrites IFD offsets after encoding), which a plain io.Writer
 		// cannot provide. Encode through the buffer path and emit a
 		// single write; the bytes are identical to ExportTiff.
-		buf, err := vipsSaveToBuffer(saveParams)
+		buf, err := vipsSaveToBuffer(r.image, opts)
 		if err != nil {
 			var ioErr error
 			if r.streamSource != nil {
@@ -748,10 +802,12 @@ func (r *ImageRef) saveToWriter(w io.Writer, format ImageType, buildParams func(
 				// the reader's error like the streaming-target path.
 				ioErr = r.streamSource.entry.takeErr()
 			}
-			return wrapStreamError("streaming save", err, ioErr)
+			return op.fail(wrapStreamError("streaming save", err, ioErr))
 		}
-		if _, err := w.Write(buf); err != nil {
-			return fmt.Errorf("streaming save: writer error: %w", err)
+		n, err := w.Write(buf)
+		op.attrs.BytesOut = int64(n)
+		if err != nil {
+			return op.fail(fmt.Errorf("streaming save: writer error: %w", err))
 		}
 		return nil
 	}
@@ -761,25 +817,16 @@ func (r *ImageRef) saveToWriter(w io.Writer, format ImageType, buildParams func(
 
 	target := C.create_target_custom(C.int(handle))
 	if target == nil {
-		return handleVipsError()
+		return op.fail(handleVipsError())
 	}
 	defer C.clear_target(&target)
 
-	var code C.int
-	switch format {
-	case ImageTypeJPEG:
-		code = C.save_jpeg_to_target(&saveParams, target)
-	case ImageTypePNG:
-		code = C.save_png_to_target(&saveParams, target)
-	case ImageTypeWEBP:
-		code = C.save_webp_to_target(&saveParams, target)
-	case ImageTypeHEIF:
-		code = C.save_heif_to_target(&saveParams, target)
-	// ImageTypeTIFF is handled by the buffer-path early return above.
-	case ImageTypeGIF:
-		code = C.save_gif_to_target(&saveParams, target)
-	}
+	saveParams, cleanup := newSaveParams(r.image, opts)
+	defer cleanup()
 
+	code := C.save_to_target(&saveParams, target)
+
+	op.attrs.BytesOut = entry.bytesWritten()
 	if code != 0 {
 		ioErr := entry.takeErr()
 		if ioErr == nil && r.streamSource != nil {
@@ -787,38 +834,35 @@ func (r *ImageRef) saveToWriter(w io.Writer, format ImageType, buildParams func(
 			// during this save; surface the reader's error too.
 			ioErr = r.streamSource.entry.takeErr()
 		}
-		return wrapStreamError("streaming save", handleVipsError(), ioErr)
+		return op.fail(wrapStreamError("streaming save", handleVipsError(), ioErr))
 	}
 	if ioErr := entry.takeErr(); ioErr != nil {
-		return fmt.Errorf("streaming save: writer error: %w", ioErr)
+		return op.fail(fmt.Errorf("streaming save: writer error: %w", ioErr))
 	}
 	return nil
 }
 
-// streamSaveParams builds the C save parameters for SaveToWriter using
-// the same ExportParams mapping as (*ImageRef).Export (via the shared
-// *ParamsFromExport helpers) and the same C-struct population as the
-// Export* buffer savers, so streaming output stays byte-identical to the
-// buffer path. The returned cleanup must be called after the save
-// completes.
-func streamSaveParams(in *C.VipsImage, format ImageType, params *ExportParams) (C.struct_SaveParams, func(), error) {
-	noop := func() {}
-
+// streamSaveOptions builds the saver options for SaveToWriter using the
+// same ExportParams mapping as (*ImageRef).Export (via the shared
+// *ParamsFromExport helpers) and the same option mapping as the Export*
+// buffer savers, so streaming output stays byte-identical to the buffer
+// path.
+func streamSaveOptions(format ImageType, params *ExportParams) (SaveOptions, error) {
 	switch format {
 	case ImageTypeJPEG:
-		return newSaveParamsJPEG(in, *jpegParamsFromExport(params)), noop, nil
+		return jpegsaveOptions(*jpegParamsFromExport(params)), nil
 	case ImageTypePNG:
-		return newSaveParamsPNG(in, *pngParamsFromExport(params)), noop, nil
+		return pngsaveOptions(*pngParamsFromExport(params)), nil
 	case ImageTypeWEBP:
-		return newSaveParamsWebP(in, *webpParamsFromExport(params))
+		return webpsaveOptions(*webpParamsFromExport(params))
 	case ImageTypeHEIF:
-		return newSaveParamsHEIF(in, *heifParamsFromExport(params)), noop, nil
+		return heifsaveOptions(*heifParamsFromExport(params)), nil
 	case ImageTypeTIFF:
-		return newSaveParamsTIFF(in, *tiffParamsFromExport(params)), noop, nil
+		return tiffsaveOptions(*tiffParamsFromExport(params)), nil
 	case ImageTypeGIF:
-		return newSaveParamsGIF(in, *gifParamsFromExport(params)), noop, nil
+		return gifsaveOptions(*gifParamsFromExport(params)), nil
 	default:
-		return C.struct_SaveParams{}, noop, fmt.Errorf("streaming save does not support format %q", ImageTypes[format])
+		return nil, fmt.Errorf("streaming save does not support format %q", ImageTypes[format])
 	}
 }
 
@@ -63,14 +63,11 @@ int copy_image_to_memory(VipsImage *in, VipsImage **out);
 // Returns non-zero on failure.
 int write_image_to_disc(VipsImage *in, const char *path, VipsImage **out);
 
-// Encode params->inputImage to the target. Both the image and the target
-// are borrowed (the caller retains ownership). Return 0 on success,
-// non-zero on failure with the error in the vips error buffer.
-int save_jpeg_to_target(SaveParams *params, VipsTargetCustom *target);
-int save_png_to_target(SaveParams *params, VipsTargetCustom *target);
-int save_webp_to_target(SaveParams *params, VipsTargetCustom *target);
-int save_heif_to_target(SaveParams *params, VipsTargetCustom *target);
-int save_gif_to_target(SaveParams *params, VipsTargetCustom *target);
+// Encode params->inputImage to the target with the _target variant of
+// params->saver. Both the image and the target are borrowed (the caller
+// retains ownership). Return 0 on success, non-zero on failure with the
+// error in the vips error buffer.
+int save_to_target(SaveParams *params, VipsTargetCustom *target);
 
 // Copies the first len bytes of the source into out without consuming
 // them (vips_source_sniff buffers and rewinds). Returns non-zero if the
@@ -0,0 +1,327 @@
+package vips
+
+import (
+	"errors"
+	"fmt"
+	"math"
+)
+
+// ThumbHash encoding and decoding, following the reference implementation at
+// https://github.com/evanw/thumbhash. Unlike BlurHash, ThumbHash stores the
+// aspect ratio and an alpha channel, so the codec works on packed 8-bit RGBA
+// pixels of at most 100x100.
+
+const thumbHashMaxSize = 100
+
+// ErrInvalidThumbHash is returned when a ThumbHash cannot be decoded.
+var ErrInvalidThumbHash = errors.New("invalid thumbhash")
+
+// thumbHashEncode encodes packed RGBA pixels (4 bytes per pixel, no padding,
+// not premultiplied).
+func thumbHashEncode(rgba []byte, w, h int) ([]byte, error) {
+	if w <= 0 || h <= 0 || w > thumbHashMaxSize || h > thumbHashMaxSize {
+		return nil, fmt.Errorf("thumbhash: %dx%d doesn't fit in %dx%d", w, h, thumbHashMaxSize, thumbHashMaxSize)
+	}
+	if len(rgba) < w*h*4 {
+		return nil, fmt.Errorf("thumbhash: invalid pixel buffer for %dx%d image", w, h)
+	}
+
+	// Determine the average color
+	var avgR, avgG, avgB, avgA float64
+	for i, j := 0, 0; i < w*h; i, j = i+1, j+4 {
+		alpha := float64(rgba[j+3]) / 255
+		avgR += alpha / 255 * float64(rgba[j])
+		avgG += alpha / 255 * float64(rgba[j+1])
+		avgB += alpha / 255 * float64(rgba[j+2])
+		avgA += alpha
+	}
+	if avgA > 0 {
+		avgR /= avgA
+		avgG /= avgA
+		avgB /= avgA
+	}
+
+	hasAlpha := avgA < float64(w*h)
+	lLimit := 7
+	if hasAlpha {
+		// Use fewer luminance bits if there's alpha
+		lLimit = 5
+	}
+	maxWH := float64(max(w, h))
+	lx := max(1, int(math.Round(float64(lLimit*w)/maxWH)))
+	ly := max(1, int(math.Round(float64(lLimit*h)/maxWH)))
+
+	// Convert the image from RGBA to LPQA, composited atop the average color
+	n := w * h
+	l := make([]float64, n)
+	p := make([]float64, n)
+	q := make([]float64, n)
+	a := make([]float64, n)
+	for i, j := 0, 0; i < n; i, j = i+1, j+4 {
+		alpha := float64(rgba[j+3]) / 255
+		r := avgR*(1-alpha) + alpha/255*float64(rgba[j])
+		g := avgG*(1-alpha) + alpha/255*float64(rgba[j+1])
+		b := avgB*(1-alpha) + alpha/255*float64(rgba[j+2])
+		l[i] = (r + g + b) / 3
+		p[i] = (r+g)/2 - b
+		q[i] = r - g
+		a[i] = alpha
+	}
+
+	// Encode using the DCT into DC (constant) and normalized AC (varying) terms
+	encodeChannel := func(channel []float64, nx, ny int) (float64, []float64, float64) {
+		var dc, scale float64
+		var ac []float64
+		fx := make([]float64, w)
+		for cy := 0; cy < ny; cy++ {
+			for cx := 0; cx*ny < nx*(ny-cy); cx++ {
+				var f float64
+				for x := 0; x < w; x++ {
+					fx[x] = math.Cos(math.Pi / float64(w) * float64(cx) * (float64(x) + 0.5))
+				}
+				for y := 0; y < h; y++ {
+					fy := math.Cos(math.Pi / float64(h) * float64(cy) * (float64(y) + 0.5))
+					for x := 0; x < w; x++ {
+						f += channel[x+y*w] * fx[x] * fy
+					}
+				}
+				f /= float64(n)
+				if cx > 0 || cy > 0 {
+					ac = append(ac, f)
+					scale = math.Max(scale, math.Abs(f))
+				} else {
+					dc = f
+				}
+			}
+		}
+		if scale > 0 {
+			for i := range ac {
+				ac[i] = 0.5 + 0.5/scale*ac[i]
+			}
+		}
+		return dc, ac, scale
+	}
+	lDC, lAC, lScale := encodeChannel(l, max(3, lx), max(3, ly))
+	pDC, pAC, pScale := encodeChannel(p, 3, 3)
+	qDC, qAC, qScale := encodeChannel(q, 3, 3)
+	var aDC, aScale float64
+	var aAC []float64
+	if hasAlpha {
+		aDC, aAC, aScale = encodeChannel(a, 5, 5)
+	}
+
+	// Write the constants
+	isLandscape := w > h
+	header24 := uint32(math.Round(63*lDC)) |
+		uint32(math.Round(31.5+31.5*pDC))<<6 |
+		uint32(math.Round(31.5+31.5*qDC))<<12 |
+		uint32(math.Round(31*lScale))<<18
+	if hasAlpha {
+		header24 |= 1 << 23
+	}
+	header16 := uint16(lx)
+	if isLandscape {
+		header16 = uint16(ly)
+	}
+	header16 |= uint16(math.Round(63*pScale))<<3 | uint16(math.Round(63*qScale))<<9
+	if isLandscape {
+		header16 |= 1 << 15
+	}
+
+	channels := [][]float64{lAC, pAC, qAC}
+	acStart := 5
+	if hasAlpha {
+		channels = append(channels, aAC)
+		acStart = 6
+	}
+	acCount := 0
+	for _, ac := range channels {
+		acCount += len(ac)
+	}
+
+	hash := make([]byte, acStart+(acCount+1)/2)
+	hash[0] = byte(header24)
+	hash[1] = byte(header24 >> 8)
+	hash[2] = byte(header24 >> 16)
+	hash[3] = byte(header16)
+	hash[4] = byte(header16 >> 8)
+	if hasAlpha {
+		hash[5] = byte(math.Round(15*aDC)) | byte(math.Round(15*aScale))<<4
+	}
+
+	// Write the varying factors
+	acIndex := 0
+	for _, ac := range channels {
+		for _, f := range ac {
+			hash[acStart+(acIndex>>1)] |= byte(math.Round(15*f)) << ((acIndex & 1) << 2)
+			acIndex++
+		}
+	}
+
+	return hash, nil
+}
+
+// thumbHashDecode renders hash into packed RGBA pixels. The output is at most
+// 32 pixels on its longest side, at the aspect ratio stored in the hash.
+func thumbHashDecode(hash []byte) ([]byte, int, int, error) {
+	if len(hash) < 5 {
+		return nil, 0, 0, fmt.Errorf("%w: too short", ErrInvalidThumbHash)
+	}
+
+	// Read the constants
+	header24 := uint32(hash[0]) | uint32(hash[1])<<8 | uint32(hash[2])<<16
+	header16 := uint16(hash[3]) | uint16(hash[4])<<8
+	lDC := float64(header24&63) / 63
+	pDC := float64((header24>>6)&63)/31.5 - 1
+	qDC := float64((header24>>12)&63)/31.5 - 1
+	lScale := float64((header24>>18)&31) / 31
+	hasAlpha := header24>>23 != 0
+	pScale := float64((header16>>3)&63) / 63
+	qScale := float64((header16>>9)&63) / 63
+	isLandscape := header16>>15 != 0
+
+	lLimit := 7
+	if hasAlpha {
+		lLimit = 5
+	}
+	lx, ly := int(header16&7), lLimit
+	if isLandscape {
+		lx, ly = lLimit, int(header16&7)
+	}
+	if lx == 0 || ly == 0 {
+		return nil, 0, 0, fmt.Errorf("%w: zero-sized luminance grid", ErrInvalidThumbHash)
+	}
+	ratio := float64(lx) / float64(ly)
+	lx, ly = max(3, lx), max(3, ly)
+
+	aDC, aScale := 1.0, 0.0
+	acStart := 5
+	if hasAlpha {
+		if len(hash) < 6 {
+			return nil, 0, 0, fmt.Errorf("%w: too short", ErrInvalidThumbHash)
+		}
+		aDC = float64(hash[5]&15) / 15
+		aScale = float64(hash[5]>>4) / 15
+		acStart = 6
+	}
+
+	// Read the varying factors (boost saturation by 1.25x to compensate for quantization)
+	acIndex := 0
+	decodeChannel := func(nx, ny int, scale float64) ([]float64, error) {
+		var ac []float64
+		for cy := 0; cy < ny; cy++ {
+			cx := 1
+			if cy > 0 {
+				cx = 0
+			}
+			for ; cx*ny < nx*(ny-cy); cx++ {
+				i := acStart + (acIndex >> 1)
+				if i >= len(hash) {
+					return nil, fmt.Errorf("%w: too short", ErrInvalidThumbHash)
+				}
+				v := (hash[i] >> ((acIndex & 1) << 2)) & 15
+				ac = append(ac, (float64(v)/7.5-1)*scale)
+				acIndex++
+			}
+		}
+		return ac, nil
+	}
+	lAC, err := decodeChannel(lx, ly, lScale)
+	if err != nil {
+		return nil, 0, 0, err
+	}
+	pAC, err := decodeChannel(3, 3, pScale*1.25)
+	if err != nil {
+		return nil, 0, 0, err
+	}
+	qAC, err := decodeChannel(3, 3, qScale*1.25)
+	if err != nil {
+		return nil, 0, 0, err
+	}
+	var aAC []float64
+	if hasAlpha {
+		if aAC, err = decodeChannel(5, 5, aScale); err != nil {
+			return nil, 0, 0, err
+		}
+	}
+
+	// Decode using the DCT into RGB
+	w, h := 32, int(math.Round(32/ratio))
+	if ratio <= 1 {
+		w, h = int(math.Round(32*ratio)), 32
+	}
+	rgba := make([]byte, w*h*4)
+	nx, ny := max(lx, 3), max(ly, 3)
+	if hasAlpha {
+		nx, ny = max(lx, 5), max(ly, 5)
+	}
+	fx := make([]float64, nx)
+	fy := make([]float64, ny)
+	clamp := func(v float64) byte {
+		return byte(math.Max(0, 255*math.Min(1, v)))
+	}
+	for y, i := 0, 0; y < h; y++ {
+		for x := 0; x < w; x, i = x+1, i+4 {
+			l, p, q, a := lDC, pDC, qDC, aDC
+
+			// Precompute the coefficients
+			for cx := 0; cx < nx; cx++ {
+				fx[cx] = math.Cos(math.Pi / float64(w) * (float64(x) + 0.5) * float64(cx))
+			}
+			for cy := 0; cy < ny; cy++ {
+				fy[cy] = math.Cos(math.Pi / float64(h) * (float64(y) + 0.5) * float64(cy))
+			}
+
+			// Decode L
+			for cy, j := 0, 0; cy < ly; cy++ {
+				fy2 := fy[cy] * 2
+				cx := 1
+				if cy > 0 {
+					cx = 0
+				}
+				for ; cx*ly < lx*(ly-cy); cx, j = cx+1, j+1 {
+					l += lAC[j] * fx[cx] * fy2
+				}
+			}
+
+			// Decode P and Q
+			for cy, j := 0, 0; cy < 3; cy++ {
+				fy2 := fy[cy] * 2
+				cx := 1
+				if cy > 0 {
+					cx = 0
+				}
+				for ; cx < 3-cy; cx, j = cx+1, j+1 {
+					f := fx[cx] * fy2
+					p += pAC[j] * f
+					q += qAC[j] * f
+				}
+			}
+
+			// Decode A
+			if hasAlpha {
+				for cy, j := 0, 0; cy < 5; cy++ {
+					fy2 := fy[cy] * 2
+					cx := 1
+					if cy > 0 {
+						cx = 0
+					}
+					for ; cx < 5-cy; cx, j = cx+1, j+1 {
+						a += aAC[j] * fx[cx] * fy2
+					}
+				}
+			}
+
+			// Convert to RGB
+			b := l - 2.0/3.0*p
+			r := (3*l - b + q) / 2
+			g := r - q
+			rgba[i] = clamp(r)
+			rgba[i+1] = clamp(g)
+			rgba[i+2] = clamp(b)
+			rgba[i+3] = clamp(a)
+		}
+	}
+
+	return rgba, w, h, nil
+}
@@ -0,0 +1,71 @@
+package vips
+
+import (
+	"context"
+	"sync/atomic"
+)
+
+// Tracer starts a span around every libvips operation run by govips,
+// including loads and saves, for bridging to a tracing system such as
+// OpenTelemetry. StartOperation is called when the operation starts, with
+// the context of the caller: the ctx passed to a ...Context entry point
+// or set on the ImageRef with SetContext, so that spans nest under the
+// caller's span. The returned func is called once when the operation
+// ends. Both are called synchronously on the goroutine that runs the
+// operation and must be safe for concurrent use.
+//
+// Most libvips operations are lazy: they only build the pipeline, and the
+// pixels are computed by the save (or other sink) at the end, so that is
+// where the time shows.
+type Tracer interface {
+	StartOperation(ctx context.Context, name string, attrs OperationAttributes) func(attrs OperationAttributes, err error)
+}
+
+// OperationAttributes describe a traced operation. The attributes passed
+// to StartOperation describe the input image, if the operation has one;
+// the finish func receives them updated with what is known once the
+// operation ends, such as the format, dimensions and encoded size of a
+// load, or the size written by a save. Zero fields are unknown.
+type OperationAttributes struct {
+	// Format is the image format of a load or save.
+	Format ImageType
+	Width  int
+	Height int
+	Bands  int
+	// BytesIn and BytesOut are the encoded bytes read by a load and
+	// written by a save.
+	BytesIn  int64
+	BytesOut int64
+}
+
+type tracerHolder struct {
+	tracer Tracer
+}
+
+var globalTracer atomic.Pointer[tracerHolder]
+
+// SetTracer sets the tracer of operations, or removes it when t is nil.
+// Without a tracer, operations are not traced and cost nothing extra.
+func SetTracer(t Tracer) {
+	if t == nil {
+		globalTracer.Store(nil)
+		return
+	}
+	globalTracer.Store(&tracerHolder{tracer: t})
+}
+
+// SetContext sets the context of the operations on the image, under which
+// they are traced (see Tracer). A nil ctx resets it to the background
+// context. The images loaded by a ...Context entry point carry its ctx.
+func (r *ImageRef) SetContext(ctx context.Context) {
+	r.ctx = ctx
+}
+
+// Context returns the context set by SetContext, or the background
+// context.
+func (r *ImageRef) Context() context.Context {
+	if r.ctx == nil {
+		return context.Background()
+	}
+	return r.ctx
+}
@@ -0,0 +1,169 @@
+package vips
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type traceKey struct{}
+
+type recordedSpan struct {
+	name   string
+	parent interface{}
+	start  OperationAttributes
+	end    OperationAttributes
+	err    error
+	ended  bool
+}
+
+type recordingTracer struct {
+	mu    sync.Mutex
+	spans []*recordedSpan
+}
+
+func (t *recordingTracer) StartOperation(ctx context.Context, name string, attrs OperationAttributes) func(OperationAttributes, error) {
+	span := &recordedSpan{name: name, parent: ctx.Value(traceKey{}), start: attrs}
+	t.mu.Lock()
+	t.spans = append(t.spans, span)
+	t.mu.Unlock()
+	return func(attrs OperationAttributes, err error) {
+		t.mu.Lock()
+		defer t.mu.Unlock()
+		span.end = attrs
+		span.err = err
+		span.ended = true
+	}
+}
+
+func (t *recordingTracer) find(name string) *recordedSpan {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	for _, s := range t.spans {
+		if s.name == name {
+			return s
+		}
+	}
+	return nil
+}
+
+func useTracer(t *testing.T) *recordingTracer {
+	t.Helper()
+	tracer := &recordingTracer{}
+	SetTracer(tracer)
+	t.Cleanup(func() { SetTracer(nil) })
+	return tracer
+}
+
+func TestTracer_LoadOperationsSave(t *testing.T) {
+	require.NoError(t, Startup(nil))
+	tracer := useTracer(t)
+
+	ctx := context.WithValue(context.Background(), traceKey{}, "request")
+	buf := mustReadFile(t, resources+"png-24bit.png")
+	image, err := LoadImageFromBufferContext(ctx, buf, nil)
+	require.NoError(t, err)
+	defer image.Close()
+	assert.Equal(t, ctx, image.Context())
+
+	require.NoError(t, image.Resize(0.5, KernelLinear))
+	out, _, err := image.ExportPng(NewPngExportParams())
+	require.NoError(t, err)
+
+	load := tracer.find("load_buffer")
+	require.NotNil(t, load)
+	assert.Equal(t, "request", load.parent)
+	assert.True(t, load.ended)
+	assert.Equal(t, ImageTypePNG, load.end.Format)
+	assert.Equal(t, 1920, load.end.Width)
+	assert.Equal(t, 1080, load.end.Height)
+	assert.Equal(t, int64(len(buf)), load.end.BytesIn)
+
+	resize := tracer.find("resize")
+	require.NotNil(t, resize)
+	assert.Equal(t, "request", resize.parent)
+	assert.Equal(t, 1920, resize.start.Width)
+	assert.Equal(t, 3, resize.start.Bands)
+
+	save := tracer.find("save_png_buffer")
+	require.NotNil(t, save)
+	assert.Equal(t, "request", save.parent)
+	assert.Equal(t, 960, save.start.Width)
+	assert.Equal(t, ImageTypePNG, save.end.Format)
+	assert.Equal(t, int64(len(out)), save.end.BytesOut)
+	assert.NoError(t, save.err)
+}
+
+func TestTracer_Copy(t *testing.T) {
+	require.NoError(t, Startup(nil))
+	tracer := useTracer(t)
+
+	image, err := NewImageFromFile(resources + "jpg-24bit.jpg")
+	require.NoError(t, err)
+	defer image.Close()
+	assert.Equal(t, context.Background(), image.Context())
+
+	ctx := context.WithValue(context.Background(), traceKey{}, "copy")
+	image.SetContext(ctx)
+	copied, err := image.Copy()
+	require.NoError(t, err)
+	defer copied.Close()
+	assert.Equal(t, ctx, copied.Context())
+
+	require.NoError(t, copied.Flip(DirectionHorizontal))
+	flip := tracer.find("flip")
+	require.NotNil(t, flip)
+	assert.Equal(t, "copy", flip.parent)
+
+	image.SetContext(nil)
+	assert.Equal(t, context.Background(), image.Context())
+}
+
+func TestTracer_Stream(t *testing.T) {
+	require.NoError(t, Startup(nil))
+	tracer := useTracer(t)
+
+	ctx := context.WithValue(context.Background(), traceKey{}, "stream")
+	image, err := LoadImageFromReaderContext(ctx, bytes.NewReader(mustReadFile(t, resources+"jpg-24bit.jpg")), nil)
+	require.NoError(t, err)
+	defer image.Close()
+
+	var w bytes.Buffer
+	require.NoError(t, image.SaveToWriter(&w, ImageTypeWEBP, nil))
+
+	load := tracer.find("load_source")
+	require.NotNil(t, load)
+	assert.Equal(t, "stream", load.parent)
+	assert.Equal(t, ImageTypeJPEG, load.end.Format)
+
+	save := tracer.find("save_webp_target")
+	require.NotNil(t, save)
+	assert.Equal(t, "stream", save.parent)
+	assert.Equal(t, ImageTypeWEBP, save.end.Format)
+	assert.Equal(t, int64(w.Len()), save.end.BytesOut)
+
+	_, err = ProbeContext(ctx, bytes.NewReader(mustReadFile(t, resources+"png-24bit.png")))
+	require.NoError(t, err)
+	probe := tracer.find("probe_source")
+	require.NotNil(t, probe)
+	assert.Equal(t, "stream", probe.parent)
+	assert.Equal(t, ImageTypePNG, probe.end.Format)
+}
+
+func TestTracer_Error(t *testing.T) {
+	require.NoError(t, Startup(nil))
+	tracer := useTracer(t)
+
+	_, err := LoadImageFromBufferContext(context.Background(), []byte("not an image"), nil)
+	require.Error(t, err)
+
+	load := tracer.find("load_buffer")
+	require.NotNil(t, load)
+	assert.True(t, load.ended)
+	assert.True(t, errors.Is(load.err, ErrUnsupportedImageFormat))
+}
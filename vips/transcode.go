@@ -79,10 +79,8 @@ func TranscodeStream(r io.Reader, w io.Writer, opts *TranscodeOptions) error {
 
 	if opts.AutoRotate {
 		// Orientation is header metadata, available before any pixel is
-		// decoded. Orientations 1 (none) and 2 (horizontal flip) are
-		// sequential-safe; everything else transposes or reverses line
-		// order and needs random access.
-		if img.Orientation() >= 3 {
+		// decoded.
+		if orientationNeedsRandomAccess(img.Orientation()) {
 			if err := img.materialize(); err != nil {
 				return fmt.Errorf("transcode: %w", err)
 			}
@@ -102,3 +100,11 @@ func TranscodeStream(r io.Reader, w io.Writer, opts *TranscodeOptions) error {
 	}
 	return nil
 }
+
+// orientationNeedsRandomAccess reports whether applying the EXIF orientation
+// o needs random access to the pixels. Orientations 1 (none) and 2
+// (horizontal flip) are sequential-safe; everything else transposes or
+// reverses line order.
+func orientationNeedsRandomAccess(o int) bool {
+	return o >= 3
+}